@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"uois-gateway/internal/clients/redis"
+	"uois-gateway/internal/config"
+	auditRepo "uois-gateway/internal/repository/audit"
+	auditService "uois-gateway/internal/services/audit"
+	"uois-gateway/internal/services/callback"
+	ondcService "uois-gateway/internal/services/ondc"
+
+	"go.uber.org/zap"
+)
+
+const dlqUsage = "usage: uois-gateway dlq list [-request-id id] [-callback-url url] [-bap-id id] [-from RFC3339] [-to RFC3339] [-limit n] | show <id> | redrive <id>... | purge <id>..."
+
+// runDLQ inspects, redrives or purges the callback DLQ and prints the result as JSON
+// Usage: uois-gateway dlq list|show|redrive|purge ...
+// Redrive signs with the configured ONDC keys and records each attempt in audit.callback_delivery_logs,
+// so it needs the same Postgres and keyring configuration as the server.
+func runDLQ(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf(dlqUsage)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logger := zap.NewNop()
+	redisClient, err := redis.NewClient(fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port), cfg.Redis.Password, cfg.Redis.DB, logger)
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	ctx := context.Background()
+	action, rest := args[0], args[1:]
+
+	var result interface{}
+	switch action {
+	case "list":
+		filter, err := parseDLQListFlags(rest)
+		if err != nil {
+			return err
+		}
+		dlqService := callback.NewDLQService(redisClient.GetClient(), cfg.Callback, nil, nil, logger)
+		result, err = dlqService.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+	case "show":
+		if len(rest) != 1 {
+			return fmt.Errorf(dlqUsage)
+		}
+		dlqService := callback.NewDLQService(redisClient.GetClient(), cfg.Callback, nil, nil, logger)
+		result, err = dlqService.Get(ctx, rest[0])
+		if err != nil {
+			return err
+		}
+	case "redrive":
+		if len(rest) == 0 {
+			return fmt.Errorf(dlqUsage)
+		}
+		dlqService, closeDB, err := newRedriveDLQService(cfg, redisClient, logger)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+		result, err = dlqService.Redrive(ctx, rest)
+		if err != nil {
+			return err
+		}
+	case "purge":
+		if len(rest) == 0 {
+			return fmt.Errorf(dlqUsage)
+		}
+		dlqService := callback.NewDLQService(redisClient.GetClient(), cfg.Callback, nil, nil, logger)
+		deleted, err := dlqService.Purge(ctx, rest)
+		if err != nil {
+			return err
+		}
+		result = map[string]int64{"deleted": deleted}
+	default:
+		return fmt.Errorf(dlqUsage)
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// parseDLQListFlags reads the list filter flags
+func parseDLQListFlags(args []string) (callback.DLQFilter, error) {
+	var filter callback.DLQFilter
+
+	flags := flag.NewFlagSet("dlq list", flag.ContinueOnError)
+	flags.StringVar(&filter.RequestID, "request-id", "", "only entries for this request_id")
+	flags.StringVar(&filter.CallbackURL, "callback-url", "", "only entries for this callback URL")
+	flags.StringVar(&filter.BapID, "bap-id", "", "only entries whose payload context.bap_id matches")
+	from := flags.String("from", "", "only entries dead-lettered at or after this time (RFC3339)")
+	to := flags.String("to", "", "only entries dead-lettered at or before this time (RFC3339)")
+	flags.IntVar(&filter.Limit, "limit", 100, "maximum entries to print")
+	if err := flags.Parse(args); err != nil {
+		return filter, err
+	}
+
+	var err error
+	if *from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, *from); err != nil {
+			return filter, fmt.Errorf("invalid -from: %w", err)
+		}
+	}
+	if *to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, *to); err != nil {
+			return filter, fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// newRedriveDLQService wires the signer and audit log a redrive needs
+func newRedriveDLQService(cfg *config.Config, redisClient *redis.Client, logger *zap.Logger) (*callback.DLQService, func(), error) {
+	authService, err := ondcService.NewONDCAuthService(ondcService.NewHTTPRegistryClient(cfg.ONDC, logger), cfg.ONDC, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	postgresDSN := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.PostgresE.Host, cfg.PostgresE.Port, cfg.PostgresE.User,
+		cfg.PostgresE.Password, cfg.PostgresE.DB, cfg.PostgresE.SSLMode)
+	db, err := sql.Open("postgres", postgresDSN)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open audit database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to ping audit database: %w", err)
+	}
+
+	audit := auditService.NewService(auditRepo.NewRepository(db, *cfg, logger), logger)
+	sender := callback.NewService(cfg.Callback, callback.NewONDCSigner(authService), logger)
+	dlqService := callback.NewDLQService(redisClient.GetClient(), cfg.Callback, sender, audit, logger)
+
+	return dlqService, func() { db.Close() }, nil
+}
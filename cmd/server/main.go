@@ -5,28 +5,41 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	adminClient "uois-gateway/internal/clients/admin"
 	"uois-gateway/internal/clients/order"
+	"uois-gateway/internal/clients/order/orderfake"
 	"uois-gateway/internal/clients/redis"
 	"uois-gateway/internal/config"
+	"uois-gateway/internal/consumers/client_events"
 	"uois-gateway/internal/consumers/event"
+	"uois-gateway/internal/consumers/order_lifecycle"
+	"uois-gateway/internal/consumers/quote_differential"
+	"uois-gateway/internal/consumers/rider_assignment"
+	adminHandler "uois-gateway/internal/handlers/admin"
 	igmHandler "uois-gateway/internal/handlers/igm"
 	"uois-gateway/internal/handlers/ondc"
 	"uois-gateway/internal/middleware"
 	auditRepo "uois-gateway/internal/repository/audit"
 	clientRegistryRepo "uois-gateway/internal/repository/client_registry"
+	codReconciliationRepo "uois-gateway/internal/repository/cod_reconciliation"
+	deliveryPolicyRepo "uois-gateway/internal/repository/delivery_policy"
 	"uois-gateway/internal/repository/issue"
 	"uois-gateway/internal/repository/order_record"
 	auditService "uois-gateway/internal/services/audit"
 	"uois-gateway/internal/services/auth"
 	cacheService "uois-gateway/internal/services/cache"
 	"uois-gateway/internal/services/callback"
+	"uois-gateway/internal/services/cancellation"
 	"uois-gateway/internal/services/client"
+	"uois-gateway/internal/services/deliverypolicy"
 	eventIdempotencyService "uois-gateway/internal/services/eventidempotency"
 	"uois-gateway/internal/services/idempotency"
 	igmService "uois-gateway/internal/services/igm"
@@ -42,6 +55,22 @@ import (
 )
 
 func main() {
+	// Ops subcommand: build and sign the registry /subscribe payload, then exit
+	if len(os.Args) > 1 && os.Args[1] == "subscribe" {
+		if err := runSubscribe(os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("subscribe: %v", err)
+		}
+		return
+	}
+
+	// Ops subcommand: inspect, redrive or purge the callback DLQ, then exit
+	if len(os.Args) > 1 && os.Args[1] == "dlq" {
+		if err := runDLQ(os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("dlq: %v", err)
+		}
+		return
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -82,6 +111,7 @@ func main() {
 	issueRepo := issue.NewRepository(redisClient.GetClient(), *cfg, logger)
 	auditRepoInstance := auditRepo.NewRepository(db, *cfg, logger)
 	clientRegistryRepoInstance := clientRegistryRepo.NewRepository(db, *cfg, logger)
+	codReconciliationRepoInstance := codReconciliationRepo.NewRepository(db, *cfg, logger)
 
 	// Initialize services
 	// Use DB-backed client registry with Redis caching (replaces in-memory implementation)
@@ -89,19 +119,45 @@ func main() {
 	clientAuthService := auth.NewClientAuthService(clientRegistry, logger)
 	rateLimitService := auth.NewRateLimitService(redisClient, cfg.RateLimit, logger)
 
-	_, err = ondcService.NewONDCAuthService(&mockRegistryClient{}, cfg.ONDC, logger)
+	registryClient := ondcService.NewHTTPRegistryClient(cfg.ONDC, logger)
+	ondcAuthService, err := ondcService.NewONDCAuthService(registryClient, cfg.ONDC, logger)
 	if err != nil {
 		logger.Fatal("Failed to initialize ONDC auth service", zap.Error(err))
 	}
 
-	callbackSigner, err := callback.NewONDCSignerFromConfig(cfg.ONDC, logger)
+	callbackSigner := callback.NewONDCSigner(ondcAuthService)
+	// Registry lookups are signed like callbacks, with the active keyring key
+	registryClient.SetSigner(callbackSigner)
+
+	subscriptionService, err := ondcService.NewSubscriptionService(ondcAuthService, cfg.ONDC, logger)
 	if err != nil {
-		logger.Warn("Failed to initialize callback signer, callbacks will be unsigned", zap.Error(err))
-		callbackSigner = nil
+		logger.Fatal("Failed to initialize ONDC subscription service", zap.Error(err))
 	}
 
+	// Initialize metrics service
+	metricsInstance := metricsService.NewService(cfg.ServiceName, cfg.Env)
+	metricsInstance.SetServiceAvailability(true)
+
 	idempotencyService := idempotency.NewService(redisClient.GetClient(), *cfg, logger)
-	orderServiceClient := order.NewClient(cfg.Order, logger)
+	if cfg.Order.UseFake {
+		fakeOrderService := orderfake.NewServer()
+		addr, err := fakeOrderService.Start(net.JoinHostPort(cfg.Order.GRPCHost, strconv.Itoa(cfg.Order.GRPCPort)))
+		if err != nil {
+			logger.Fatal("Failed to start fake Order Service", zap.Error(err))
+		}
+		defer fakeOrderService.Stop()
+		logger.Warn("Using in-process fake Order Service", zap.String("addr", addr))
+	}
+	orderServiceClient, err := order.NewClient(cfg.Order, metricsInstance, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize Order Service client", zap.Error(err))
+	}
+	defer orderServiceClient.Close()
+	adminServiceClient, err := adminClient.NewClient(cfg.Admin, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize Admin Service client", zap.Error(err))
+	}
+	defer adminServiceClient.Close()
 	eventPublisher := redis.NewEventPublisher(redisClient.GetClient(), logger)
 
 	// Create adapters for event consumer and consumer group initialization
@@ -128,23 +184,23 @@ func main() {
 	tracingInstance := tracingService.NewService(cfg.ServiceName)
 	_ = tracingInstance // Will be used when integrating spans into handlers
 
-	// Create event consumer with idempotency support
-	eventConsumer := event.NewConsumerWithIdempotency(streamConsumerAdapter, cfg.Streams, eventIdempotencyInstance, logger)
+	// Create the stream dispatcher (background XREADGROUP through a per-instance group, routes events to waiting requests)
+	streamBlock := time.Duration(cfg.Redis.StreamBlockMS) * time.Millisecond
+	eventDispatcher := event.NewDispatcher(streamConsumerAdapter, cfg.Streams, streamBlock, eventIdempotencyInstance, metricsInstance, logger)
 
 	groService := igmService.NewGROService(logger)
 	auditServiceInstance := auditService.NewService(auditRepoInstance, logger)
 
-	// Initialize metrics service
-	metricsInstance := metricsService.NewService(cfg.ServiceName, cfg.Env)
-	metricsInstance.SetServiceAvailability(true)
-
-	// Create callback service with retry support (after audit service is initialized)
-	callbackService := callback.NewServiceWithRetry(
+	// Create callback service with retry support and the durable callback queue (after audit service is initialized)
+	callbackService := callback.NewQueuedService(
 		cfg.Callback,
 		cfg.Retry,
 		callbackSigner,
 		redisClient.GetClient(),
 		auditServiceInstance,
+		metricsInstance,
+		cfg.Streams.ConsumerGroupName,
+		cfg.Streams.ConsumerID,
 		logger,
 	)
 
@@ -155,6 +211,27 @@ func main() {
 		logger.Warn("Failed to initialize consumer groups", zap.Error(err))
 	}
 
+	// Delivery category policy shared by /search, on_search and /init
+	var deliveryPolicySource deliverypolicy.RuleSource
+	switch cfg.Delivery.Source {
+	case config.DeliveryPolicySourceFile:
+		deliveryPolicySource = deliverypolicy.NewFileSource(cfg.Delivery.File)
+	case config.DeliveryPolicySourceDB:
+		deliveryPolicySource = deliveryPolicyRepo.NewRepository(db, *cfg, logger)
+	default:
+		deliveryPolicySource = deliverypolicy.NewStaticSource(deliverypolicy.DefaultRules())
+	}
+	deliveryPolicyEngine, err := deliverypolicy.NewEngine(initCtx, deliveryPolicySource, cfg.Delivery, logger)
+	if err != nil {
+		logger.Fatal("Failed to load delivery category policy", zap.Error(err), zap.String("source", cfg.Delivery.Source))
+	}
+
+	// Cancellation reason catalog and terms shared by /cancel and seller cancellations
+	cancellationCatalog, err := cancellation.LoadCatalog(cfg.Cancel.CatalogFile)
+	if err != nil {
+		logger.Fatal("Failed to load cancellation catalog", zap.Error(err), zap.String("file", cfg.Cancel.CatalogFile))
+	}
+
 	// Convert services to handler interfaces
 	var (
 		callbackServiceInterface                   ondc.CallbackService                   = callbackService
@@ -162,10 +239,13 @@ func main() {
 		orderServiceClientInterface                ondc.OrderServiceClient                = orderServiceClient
 		orderRecordServiceInterface                ondc.OrderRecordService                = orderRecordRepo
 		eventPublisherInterface                    ondc.EventPublisher                    = eventPublisher
-		eventConsumerInterface                     ondc.EventConsumer                     = eventConsumer
+		eventConsumerInterface                     ondc.EventConsumer                     = eventDispatcher
 		billingStorageServiceInterface             ondc.BillingStorageService             = billingStorageServiceInstance
 		fulfillmentContactsStorageServiceInterface ondc.FulfillmentContactsStorageService = fulfillmentContactsServiceInstance
 		auditServiceInterface                      ondc.AuditService                      = auditServiceInstance
+		deliveryPolicyInterface                    ondc.DeliveryPolicy                    = deliveryPolicyEngine
+		cancellationPolicyInterface                ondc.CancellationPolicy                = cancellationCatalog
+		codReconciliationServiceInterface          ondc.CODReconciliationService          = codReconciliationRepoInstance
 		clientAuthServiceInterface                 middleware.AuthService                 = clientAuthService
 		rateLimitServiceInterface                  middleware.RateLimitService            = rateLimitService
 	)
@@ -174,21 +254,25 @@ func main() {
 	searchHandler := ondc.NewSearchHandler(
 		eventPublisherInterface,
 		eventConsumerInterface,
+		cfg.Streams,
 		callbackServiceInterface,
 		idempotencyServiceInterface,
 		orderRecordServiceInterface,
 		auditServiceInterface,
+		deliveryPolicyInterface,
 		cfg.ONDC.ProviderID,
 		cfg.ONDC.BPPID,
 		cfg.ONDC.BPPURI,
 		cfg.ONDC.BPPName,
 		cfg.ONDC.BPPTermsURL,
+		ondcAuthService,
 		logger,
 	)
 
 	initHandler := ondc.NewInitHandler(
 		eventPublisherInterface,
 		eventConsumerInterface,
+		cfg.Streams,
 		callbackServiceInterface,
 		idempotencyServiceInterface,
 		orderServiceClientInterface,
@@ -196,15 +280,18 @@ func main() {
 		billingStorageServiceInterface,
 		fulfillmentContactsStorageServiceInterface,
 		auditServiceInterface,
+		deliveryPolicyInterface,
 		cfg.ONDC.ProviderID,
 		cfg.ONDC.BPPID,
 		cfg.ONDC.BPPURI,
+		ondcAuthService,
 		logger,
 	)
 
 	confirmHandler := ondc.NewConfirmHandler(
 		eventPublisherInterface,
 		eventConsumerInterface,
+		cfg.Streams,
 		callbackServiceInterface,
 		idempotencyServiceInterface,
 		orderServiceClientInterface,
@@ -212,8 +299,10 @@ func main() {
 		billingStorageServiceInterface,
 		fulfillmentContactsStorageServiceInterface,
 		auditServiceInterface,
+		codReconciliationServiceInterface,
 		cfg.ONDC.BPPID,
 		cfg.ONDC.BPPURI,
+		ondcAuthService,
 		logger,
 	)
 
@@ -228,9 +317,13 @@ func main() {
 		cacheServiceInstance,
 		cfg.ONDC.BPPID,
 		cfg.ONDC.BPPURI,
+		ondcAuthService,
 		logger,
 	)
 
+	// Push unsolicited /on_status callbacks for Order Service state transitions
+	lifecycleConsumer := order_lifecycle.NewConsumer(streamConsumerAdapter, cfg.Streams, streamBlock, orderRecordRepo, statusHandler, metricsInstance, logger)
+
 	// Create track cache with shorter TTL
 	trackCacheService := cacheService.NewService(redisClient.GetClient(), trackCacheTTL, logger)
 
@@ -243,6 +336,7 @@ func main() {
 		trackCacheService,
 		cfg.ONDC.BPPID,
 		cfg.ONDC.BPPURI,
+		ondcAuthService,
 		logger,
 	)
 
@@ -254,8 +348,10 @@ func main() {
 		billingStorageServiceInterface,
 		fulfillmentContactsStorageServiceInterface,
 		auditServiceInterface,
+		cancellationPolicyInterface,
 		cfg.ONDC.BPPID,
 		cfg.ONDC.BPPURI,
+		ondcAuthService,
 		logger,
 	)
 
@@ -267,9 +363,46 @@ func main() {
 		auditServiceInterface,
 		cfg.ONDC.BPPID,
 		cfg.ONDC.BPPURI,
+		ondcAuthService,
+		logger,
+	)
+
+	// Send Agent-assigned /on_update on rider assignment, seller /on_cancel when assignment fails
+	riderConsumer := rider_assignment.NewConsumer(
+		streamConsumerAdapter,
+		cfg.Streams,
+		streamBlock,
+		orderRecordRepo,
+		orderServiceClientInterface,
+		updateHandler,
+		cancelHandler,
+		metricsInstance,
 		logger,
 	)
 
+	// Send the revised quote in /on_update when the package is heavier or bigger than declared
+	differentialConsumer := quote_differential.NewConsumer(streamConsumerAdapter, cfg.Streams, streamBlock, orderRecordRepo, updateHandler, metricsInstance, logger)
+
+	// Keep the client registry in line with Admin Service: client.* events plus a periodic full sync
+	clientEventConsumer := client_events.NewConsumer(streamConsumerAdapter, cfg.Streams, streamBlock, clientRegistry, eventIdempotencyInstance, logger)
+	clientReconciler := client.NewReconciler(adminServiceClient, clientRegistry, cfg.Admin.ReconcileInterval, logger)
+
+	// Reclaim events left pending by crashed instances, skipped direct reads or failed callbacks
+	reclaimHandlers := eventDispatcher.Handlers()
+	if cfg.Streams.ClientEvents != "" {
+		reclaimHandlers[cfg.Streams.ClientEvents] = clientEventConsumer.HandleMessage
+	}
+	if cfg.Streams.OrderLifecycle != "" {
+		reclaimHandlers[cfg.Streams.OrderLifecycle] = lifecycleConsumer.HandleMessage
+	}
+	for _, stream := range riderConsumer.Streams() {
+		reclaimHandlers[stream] = riderConsumer.HandleMessage
+	}
+	if cfg.Streams.QuoteDifferential != "" {
+		reclaimHandlers[cfg.Streams.QuoteDifferential] = differentialConsumer.HandleMessage
+	}
+	pendingReclaimer := event.NewReclaimer(streamConsumerAdapter, cfg.Streams, reclaimHandlers, metricsInstance, logger)
+
 	rtoHandler := ondc.NewRTOHandler(
 		callbackServiceInterface,
 		idempotencyServiceInterface,
@@ -278,6 +411,7 @@ func main() {
 		auditServiceInterface,
 		cfg.ONDC.BPPID,
 		cfg.ONDC.BPPURI,
+		ondcAuthService,
 		logger,
 	)
 
@@ -289,6 +423,7 @@ func main() {
 		groService,
 		cfg.ONDC.BPPID,
 		cfg.ONDC.BPPURI,
+		ondcAuthService,
 		logger,
 	)
 
@@ -299,9 +434,17 @@ func main() {
 		groService,
 		cfg.ONDC.BPPID,
 		cfg.ONDC.BPPURI,
+		ondcAuthService,
 		logger,
 	)
 
+	subscriptionHandler := ondc.NewSubscriptionHandler(subscriptionService, cfg.ONDC.SubscriberID, logger)
+
+	// Operator tooling for callbacks that exhausted their retries
+	dlqService := callback.NewDLQService(redisClient.GetClient(), cfg.Callback, callbackService, auditServiceInstance, logger)
+	dlqHandler := adminHandler.NewDLQHandler(dlqService, logger)
+	bapHealthHandler := adminHandler.NewBAPHealthHandler(callbackService.Breakers(), logger)
+
 	// Initialize HTTP router
 	router := setupRouter(
 		searchHandler,
@@ -314,8 +457,17 @@ func main() {
 		rtoHandler,
 		issueHandler,
 		issueStatusHandler,
+		subscriptionHandler,
+		cfg.ONDC.SubscribePrefix,
+		dlqHandler,
+		bapHealthHandler,
+		cfg.Server.AdminAPIToken,
+		cfg.Callback.AllowedURIPrefixes,
 		clientAuthServiceInterface,
 		rateLimitServiceInterface,
+		ondcAuthService,
+		ondcAuthService,
+		clientAuthService,
 		metricsInstance,
 		logger,
 	)
@@ -341,30 +493,32 @@ func main() {
 
 	// Consumer groups already initialized above
 
-	// Start event consumer (background goroutine)
+	// Start background workers
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// TODO: Start event consumer goroutines for each stream:
-	// - QuoteComputed stream consumer (for /init handler)
-	// - QuoteCreated stream consumer (for /init handler)
-	// - OrderConfirmed stream consumer (for /confirm handler)
-	// Example:
-	// go func() {
-	//     for {
-	//         select {
-	//         case <-ctx.Done():
-	//             return
-	//         default:
-	//             event, err := eventConsumer.ConsumeEvent(ctx, cfg.Streams.QuoteComputed, cfg.Streams.ConsumerGroupName, "", 5*time.Second)
-	//             if err != nil {
-	//                 logger.Error("event consumption error", zap.Error(err))
-	//                 continue
-	//             }
-	//             // Process event...
-	//         }
-	//     }
-	// }()
+	// Hot-reload the signing keyring so key rotations are picked up without a restart
+	go func() {
+		if err := ondcAuthService.WatchKeyring(ctx); err != nil {
+			logger.Error("ONDC keyring watcher stopped", zap.Error(err))
+		}
+	}()
+
+	// Route consumed stream events to the requests waiting on them
+	go eventDispatcher.Run(ctx)
+	go lifecycleConsumer.Run(ctx)
+	go riderConsumer.Run(ctx)
+	go differentialConsumer.Run(ctx)
+	go clientEventConsumer.Run(ctx)
+	go clientReconciler.Run(ctx)
+	go deliveryPolicyEngine.Run(ctx)
+	go pendingReclaimer.Run(ctx)
+
+	// Deliver queued callbacks with a bounded worker pool
+	callbackQueue := callbackService.Queue()
+	if callbackQueue != nil {
+		go callbackQueue.Run(ctx)
+	}
 
 	// Graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -375,17 +529,23 @@ func main() {
 
 	logger.Info("Shutting down...")
 
-	// Cancel event consumer context
-	cancel()
-
 	// Graceful shutdown: give server time to finish current requests
-	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, cfg.Server.ShutdownTimeout)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer shutdownCancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Error("Server shutdown error", zap.Error(err))
 	}
 
+	// Cancel background workers; the callback queue stops reading and drains in-flight deliveries
+	cancel()
+
+	if callbackQueue != nil {
+		if err := callbackQueue.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Callback queue did not drain before shutdown timeout; undelivered callbacks stay queued", zap.Error(err))
+		}
+	}
+
 	logger.Info("Shutdown complete")
 }
 
@@ -401,8 +561,17 @@ func setupRouter(
 	rtoHandler *ondc.RTOHandler,
 	issueHandler *igmHandler.IssueHandler,
 	issueStatusHandler *igmHandler.IssueStatusHandler,
+	subscriptionHandler *ondc.SubscriptionHandler,
+	subscribeRoutePrefix string,
+	dlqHandler *adminHandler.DLQHandler,
+	bapHealthHandler *adminHandler.BAPHealthHandler,
+	adminAPIToken string,
+	allowedCallbackURIPrefixes []string,
 	authService middleware.AuthService,
 	rateLimitService middleware.RateLimitService,
+	signatureVerifier middleware.ONDCSignatureVerifier,
+	responseSigner middleware.ResponseSigner,
+	subscriberAuthService middleware.SubscriberAuthService,
 	metricsService middleware.MetricsRecorder,
 	logger *zap.Logger,
 ) *gin.Engine {
@@ -426,33 +595,42 @@ func setupRouter(
 	// Prometheus metrics endpoint (no auth required)
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// ONDC registry onboarding (called by the registry, no client auth)
+	subscriptionHandler.RegisterRoutes(router, subscribeRoutePrefix)
+
+	// Operator endpoints (bearer ADMIN_API_TOKEN; disabled when unset)
+	adminGroup := router.Group("/admin")
+	adminGroup.Use(middleware.AdminTokenMiddleware(adminAPIToken, logger))
+	adminGroup.GET("/callbacks/dlq", dlqHandler.HandleList)
+	adminGroup.GET("/callbacks/dlq/:id", dlqHandler.HandleGet)
+	adminGroup.POST("/callbacks/dlq/redrive", dlqHandler.HandleRedrive)
+	adminGroup.POST("/callbacks/dlq/purge", dlqHandler.HandlePurge)
+	adminGroup.GET("/callbacks/bap-health", bapHealthHandler.HandleUnreachable)
+
 	// ONDC API routes (require authentication and rate limiting)
+	// ONDC-signed BAPs are verified first; credential-based clients fall through to AuthMiddleware
 	ondcGroup := router.Group("/ondc")
+	ondcGroup.Use(middleware.ONDCSignatureMiddleware(signatureVerifier, responseSigner, subscriberAuthService, logger, middleware.AuthMiddlewareConfig{}))
 	ondcGroup.Use(middleware.AuthMiddleware(authService, rateLimitService, logger))
 
+	// Endpoints that answer with an async callback to context.bap_uri
+	callbackBinding := middleware.CallbackBindingMiddleware(allowedCallbackURIPrefixes, responseSigner, logger)
+
 	// Register ONDC endpoints
-	ondcGroup.POST("/search", searchHandler.HandleSearch)
-	ondcGroup.POST("/init", initHandler.HandleInit)
-	ondcGroup.POST("/confirm", confirmHandler.HandleConfirm)
-	ondcGroup.POST("/status", statusHandler.HandleStatus)
+	ondcGroup.POST("/search", callbackBinding, searchHandler.HandleSearch)
+	ondcGroup.POST("/init", callbackBinding, initHandler.HandleInit)
+	ondcGroup.POST("/confirm", callbackBinding, confirmHandler.HandleConfirm)
+	ondcGroup.POST("/status", callbackBinding, statusHandler.HandleStatus)
 	ondcGroup.POST("/track", trackHandler.HandleTrack)
-	ondcGroup.POST("/cancel", cancelHandler.HandleCancel)
-	ondcGroup.POST("/update", updateHandler.HandleUpdate)
-	ondcGroup.POST("/rto", rtoHandler.HandleRTO)
+	ondcGroup.POST("/cancel", callbackBinding, cancelHandler.HandleCancel)
+	ondcGroup.POST("/update", callbackBinding, updateHandler.HandleUpdate)
+	ondcGroup.POST("/rto", callbackBinding, rtoHandler.HandleRTO)
 
 	// Register IGM endpoints
-	ondcGroup.POST("/issue", issueHandler.HandleIssue)
-	ondcGroup.POST("/issue_status", issueStatusHandler.HandleIssueStatus)
+	ondcGroup.POST("/issue", callbackBinding, issueHandler.HandleIssue)
+	ondcGroup.POST("/issue_status", callbackBinding, issueStatusHandler.HandleIssueStatus)
 	ondcGroup.POST("/on_issue", issueHandler.HandleOnIssue)
 	ondcGroup.POST("/on_issue_status", issueStatusHandler.HandleOnIssueStatus)
 
 	return router
 }
-
-// mockRegistryClient is a placeholder for ONDC registry client
-// TODO: Implement actual registry client for production
-type mockRegistryClient struct{}
-
-func (m *mockRegistryClient) LookupPublicKey(ctx context.Context, subscriberID, ukID string) (string, error) {
-	return "", fmt.Errorf("registry client not implemented")
-}
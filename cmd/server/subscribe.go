@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"uois-gateway/internal/config"
+	ondcService "uois-gateway/internal/services/ondc"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// subscribeOutput is printed by the subscribe subcommand for ops to submit to the registry
+type subscribeOutput struct {
+	RequestID     string          `json:"request_id"`
+	Authorization string          `json:"authorization"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// runSubscribe builds and signs the registry /subscribe payload from config
+// Usage: uois-gateway subscribe [-entity entity.json] [-request-id id] [-uk-id UK2] [-ops-no 2] [-valid-days 365]
+// The gateway must be deployed with ONDC_SUBSCRIBE_REQUEST_ID set to the printed request_id (and
+// ONDC_SUBSCRIBE_UK_ID to the -uk-id, if any) so /ondc-site-verification.html serves the matching
+// signature when the registry checks it.
+func runSubscribe(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("subscribe", flag.ContinueOnError)
+	entityPath := flags.String("entity", "", "JSON file with entity business details (gst, pan, signatory, email_id, mobile_no)")
+	requestID := flags.String("request-id", "", "request_id to use (defaults to ONDC_SUBSCRIBE_REQUEST_ID, else a new UUID)")
+	ukID := flags.String("uk-id", "", "keyring ukId to register (defaults to ONDC_SUBSCRIBE_UK_ID, else the current signing key)")
+	opsNo := flags.Int("ops-no", ondcService.SubscribeOpsSellerNew, "registry operation number")
+	validDays := flags.Int("valid-days", 365, "key pair validity in days")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logger := zap.NewNop()
+	authService, err := ondcService.NewONDCAuthService(ondcService.NewHTTPRegistryClient(cfg.ONDC, logger), cfg.ONDC, logger)
+	if err != nil {
+		return err
+	}
+
+	subscriptionService, err := ondcService.NewSubscriptionService(authService, cfg.ONDC, logger)
+	if err != nil {
+		return err
+	}
+
+	entity, err := loadSubscribeEntity(*entityPath)
+	if err != nil {
+		return err
+	}
+
+	if *requestID == "" {
+		*requestID = cfg.ONDC.SubscribeReqID
+	}
+	if *requestID == "" {
+		*requestID = uuid.New().String()
+	}
+	if *ukID == "" {
+		*ukID = cfg.ONDC.SubscribeUkID
+	}
+
+	request, err := subscriptionService.BuildSubscribeRequest(entity, *requestID, *ukID, *opsNo, time.Now(), time.Duration(*validDays)*24*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	payload, authHeader, err := subscriptionService.SignSubscribeRequest(request)
+	if err != nil {
+		return err
+	}
+
+	// Not indented: the payload must be submitted byte-for-byte as signed
+	return json.NewEncoder(out).Encode(subscribeOutput{
+		RequestID:     *requestID,
+		Authorization: authHeader,
+		Payload:       payload,
+	})
+}
+
+// loadSubscribeEntity reads the ops-supplied entity details, if any
+func loadSubscribeEntity(path string) (ondcService.SubscribeEntity, error) {
+	var entity ondcService.SubscribeEntity
+	if path == "" {
+		return entity, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entity, fmt.Errorf("failed to read entity file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &entity); err != nil {
+		return entity, fmt.Errorf("failed to parse entity file: %w", err)
+	}
+
+	return entity, nil
+}
@@ -0,0 +1,384 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: contracts/admin/admin.proto
+
+package adminv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListClientsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Max clients per page; the server may return fewer (0 uses the server default)
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page, empty for the first page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListClientsRequest) Reset() {
+	*x = ListClientsRequest{}
+	mi := &file_contracts_admin_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListClientsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClientsRequest) ProtoMessage() {}
+
+func (x *ListClientsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_admin_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClientsRequest.ProtoReflect.Descriptor instead.
+func (*ListClientsRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_admin_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListClientsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListClientsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListClientsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clients       []*Client              `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListClientsResponse) Reset() {
+	*x = ListClientsResponse{}
+	mi := &file_contracts_admin_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListClientsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClientsResponse) ProtoMessage() {}
+
+func (x *ListClientsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_admin_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClientsResponse.ProtoReflect.Descriptor instead.
+func (*ListClientsResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_admin_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListClientsResponse) GetClients() []*Client {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+func (x *ListClientsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClientRequest) Reset() {
+	*x = GetClientRequest{}
+	mi := &file_contracts_admin_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClientRequest) ProtoMessage() {}
+
+func (x *GetClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_admin_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClientRequest.ProtoReflect.Descriptor instead.
+func (*GetClientRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_admin_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetClientRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+// Client mirrors a client_registry.clients row
+// Deleted clients are not listed; UOIS revokes local clients missing from a full listing.
+type Client struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ClientId         string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"` // UUID
+	ClientCode       string                 `protobuf:"bytes,2,opt,name=client_code,json=clientCode,proto3" json:"client_code,omitempty"`
+	ClientSecretHash string                 `protobuf:"bytes,3,opt,name=client_secret_hash,json=clientSecretHash,proto3" json:"client_secret_hash,omitempty"` // bcrypt hash, never the secret
+	BapId            string                 `protobuf:"bytes,4,opt,name=bap_id,json=bapId,proto3" json:"bap_id,omitempty"`                                    // Registered ONDC bap_id (empty for credential-only clients)
+	BapUri           string                 `protobuf:"bytes,5,opt,name=bap_uri,json=bapUri,proto3" json:"bap_uri,omitempty"`                                 // Registered ONDC callback base URL
+	AllowedIps       []string               `protobuf:"bytes,6,rep,name=allowed_ips,json=allowedIps,proto3" json:"allowed_ips,omitempty"`                     // CIDRs
+	RateLimit        int64                  `protobuf:"varint,7,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`                       // Requests per window (0 uses the gateway default)
+	Status           string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`                                               // ACTIVE, SUSPENDED, REVOKED
+	Metadata         *structpb.Struct       `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`                                           // auth_mode and other client settings
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Client) Reset() {
+	*x = Client{}
+	mi := &file_contracts_admin_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Client) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Client) ProtoMessage() {}
+
+func (x *Client) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_admin_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Client.ProtoReflect.Descriptor instead.
+func (*Client) Descriptor() ([]byte, []int) {
+	return file_contracts_admin_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Client) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *Client) GetClientCode() string {
+	if x != nil {
+		return x.ClientCode
+	}
+	return ""
+}
+
+func (x *Client) GetClientSecretHash() string {
+	if x != nil {
+		return x.ClientSecretHash
+	}
+	return ""
+}
+
+func (x *Client) GetBapId() string {
+	if x != nil {
+		return x.BapId
+	}
+	return ""
+}
+
+func (x *Client) GetBapUri() string {
+	if x != nil {
+		return x.BapUri
+	}
+	return ""
+}
+
+func (x *Client) GetAllowedIps() []string {
+	if x != nil {
+		return x.AllowedIps
+	}
+	return nil
+}
+
+func (x *Client) GetRateLimit() int64 {
+	if x != nil {
+		return x.RateLimit
+	}
+	return 0
+}
+
+func (x *Client) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Client) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Client) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+var File_contracts_admin_admin_proto protoreflect.FileDescriptor
+
+const file_contracts_admin_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x1bcontracts/admin/admin.proto\x12\x11dispatch.admin.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"P\n" +
+	"\x12ListClientsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"r\n" +
+	"\x13ListClientsResponse\x123\n" +
+	"\aclients\x18\x01 \x03(\v2\x19.dispatch.admin.v1.ClientR\aclients\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"/\n" +
+	"\x10GetClientRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\"\xec\x02\n" +
+	"\x06Client\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x1f\n" +
+	"\vclient_code\x18\x02 \x01(\tR\n" +
+	"clientCode\x12,\n" +
+	"\x12client_secret_hash\x18\x03 \x01(\tR\x10clientSecretHash\x12\x15\n" +
+	"\x06bap_id\x18\x04 \x01(\tR\x05bapId\x12\x17\n" +
+	"\abap_uri\x18\x05 \x01(\tR\x06bapUri\x12\x1f\n" +
+	"\vallowed_ips\x18\x06 \x03(\tR\n" +
+	"allowedIps\x12\x1d\n" +
+	"\n" +
+	"rate_limit\x18\a \x01(\x03R\trateLimit\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x123\n" +
+	"\bmetadata\x18\t \x01(\v2\x17.google.protobuf.StructR\bmetadata\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt2\xb9\x01\n" +
+	"\fAdminService\x12\\\n" +
+	"\vListClients\x12%.dispatch.admin.v1.ListClientsRequest\x1a&.dispatch.admin.v1.ListClientsResponse\x12K\n" +
+	"\tGetClient\x12#.dispatch.admin.v1.GetClientRequest\x1a\x19.dispatch.admin.v1.ClientB&Z$uois-gateway/contracts/admin;adminv1b\x06proto3"
+
+var (
+	file_contracts_admin_admin_proto_rawDescOnce sync.Once
+	file_contracts_admin_admin_proto_rawDescData []byte
+)
+
+func file_contracts_admin_admin_proto_rawDescGZIP() []byte {
+	file_contracts_admin_admin_proto_rawDescOnce.Do(func() {
+		file_contracts_admin_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_contracts_admin_admin_proto_rawDesc), len(file_contracts_admin_admin_proto_rawDesc)))
+	})
+	return file_contracts_admin_admin_proto_rawDescData
+}
+
+var file_contracts_admin_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_contracts_admin_admin_proto_goTypes = []any{
+	(*ListClientsRequest)(nil),    // 0: dispatch.admin.v1.ListClientsRequest
+	(*ListClientsResponse)(nil),   // 1: dispatch.admin.v1.ListClientsResponse
+	(*GetClientRequest)(nil),      // 2: dispatch.admin.v1.GetClientRequest
+	(*Client)(nil),                // 3: dispatch.admin.v1.Client
+	(*structpb.Struct)(nil),       // 4: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+}
+var file_contracts_admin_admin_proto_depIdxs = []int32{
+	3, // 0: dispatch.admin.v1.ListClientsResponse.clients:type_name -> dispatch.admin.v1.Client
+	4, // 1: dispatch.admin.v1.Client.metadata:type_name -> google.protobuf.Struct
+	5, // 2: dispatch.admin.v1.Client.updated_at:type_name -> google.protobuf.Timestamp
+	0, // 3: dispatch.admin.v1.AdminService.ListClients:input_type -> dispatch.admin.v1.ListClientsRequest
+	2, // 4: dispatch.admin.v1.AdminService.GetClient:input_type -> dispatch.admin.v1.GetClientRequest
+	1, // 5: dispatch.admin.v1.AdminService.ListClients:output_type -> dispatch.admin.v1.ListClientsResponse
+	3, // 6: dispatch.admin.v1.AdminService.GetClient:output_type -> dispatch.admin.v1.Client
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_contracts_admin_admin_proto_init() }
+func file_contracts_admin_admin_proto_init() {
+	if File_contracts_admin_admin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contracts_admin_admin_proto_rawDesc), len(file_contracts_admin_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_contracts_admin_admin_proto_goTypes,
+		DependencyIndexes: file_contracts_admin_admin_proto_depIdxs,
+		MessageInfos:      file_contracts_admin_admin_proto_msgTypes,
+	}.Build()
+	File_contracts_admin_admin_proto = out.File
+	file_contracts_admin_admin_proto_goTypes = nil
+	file_contracts_admin_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,1188 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: contracts/order/order.proto
+
+package orderv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ValidateSearchTTLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SearchId      string                 `protobuf:"bytes,1,opt,name=search_id,json=searchId,proto3" json:"search_id,omitempty"` // UUID generated by UOIS for /search
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateSearchTTLRequest) Reset() {
+	*x = ValidateSearchTTLRequest{}
+	mi := &file_contracts_order_order_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateSearchTTLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateSearchTTLRequest) ProtoMessage() {}
+
+func (x *ValidateSearchTTLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateSearchTTLRequest.ProtoReflect.Descriptor instead.
+func (*ValidateSearchTTLRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ValidateSearchTTLRequest) GetSearchId() string {
+	if x != nil {
+		return x.SearchId
+	}
+	return ""
+}
+
+type ValidateQuoteTTLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuoteId       string                 `protobuf:"bytes,1,opt,name=quote_id,json=quoteId,proto3" json:"quote_id,omitempty"` // Order Service-generated quote_id (ONDC-visible)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateQuoteTTLRequest) Reset() {
+	*x = ValidateQuoteTTLRequest{}
+	mi := &file_contracts_order_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateQuoteTTLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateQuoteTTLRequest) ProtoMessage() {}
+
+func (x *ValidateQuoteTTLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateQuoteTTLRequest.ProtoReflect.Descriptor instead.
+func (*ValidateQuoteTTLRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ValidateQuoteTTLRequest) GetQuoteId() string {
+	if x != nil {
+		return x.QuoteId
+	}
+	return ""
+}
+
+type ValidateTTLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`                         // false once the TTL has elapsed
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // When the id expires (or expired)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateTTLResponse) Reset() {
+	*x = ValidateTTLResponse{}
+	mi := &file_contracts_order_order_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTTLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTTLResponse) ProtoMessage() {}
+
+func (x *ValidateTTLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTTLResponse.ProtoReflect.Descriptor instead.
+func (*ValidateTTLResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidateTTLResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateTTLResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type GetOrderRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DispatchOrderId string                 `protobuf:"bytes,1,opt,name=dispatch_order_id,json=dispatchOrderId,proto3" json:"dispatch_order_id,omitempty"` // Order Service execution identifier (internal-only)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_contracts_order_order_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetOrderRequest) GetDispatchOrderId() string {
+	if x != nil {
+		return x.DispatchOrderId
+	}
+	return ""
+}
+
+type Order struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DispatchOrderId string                 `protobuf:"bytes,1,opt,name=dispatch_order_id,json=dispatchOrderId,proto3" json:"dispatch_order_id,omitempty"`
+	State           string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"` // CONFIRMED, RIDER_ASSIGNED, PICKED_UP, IN_TRANSIT, DELIVERED, CANCELLED, RTO_INITIATED, ...
+	RiderId         string                 `protobuf:"bytes,3,opt,name=rider_id,json=riderId,proto3" json:"rider_id,omitempty"`
+	Timeline        []*TimelineEvent       `protobuf:"bytes,4,rep,name=timeline,proto3" json:"timeline,omitempty"`
+	Fulfillment     *Fulfillment           `protobuf:"bytes,5,opt,name=fulfillment,proto3" json:"fulfillment,omitempty"`
+	Agent           *Agent                 `protobuf:"bytes,6,opt,name=agent,proto3" json:"agent,omitempty"` // Set once a rider is assigned
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_contracts_order_order_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Order) GetDispatchOrderId() string {
+	if x != nil {
+		return x.DispatchOrderId
+	}
+	return ""
+}
+
+func (x *Order) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Order) GetRiderId() string {
+	if x != nil {
+		return x.RiderId
+	}
+	return ""
+}
+
+func (x *Order) GetTimeline() []*TimelineEvent {
+	if x != nil {
+		return x.Timeline
+	}
+	return nil
+}
+
+func (x *Order) GetFulfillment() *Fulfillment {
+	if x != nil {
+		return x.Fulfillment
+	}
+	return nil
+}
+
+func (x *Order) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+type TimelineEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Event         string                 `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimelineEvent) Reset() {
+	*x = TimelineEvent{}
+	mi := &file_contracts_order_order_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimelineEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimelineEvent) ProtoMessage() {}
+
+func (x *TimelineEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimelineEvent.ProtoReflect.Descriptor instead.
+func (*TimelineEvent) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TimelineEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *TimelineEvent) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type Fulfillment struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	State           string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`                                              // ONDC fulfillment state code (e.g. "Order-picked-up")
+	ProofOfPickup   string                 `protobuf:"bytes,2,opt,name=proof_of_pickup,json=proofOfPickup,proto3" json:"proof_of_pickup,omitempty"`       // URL
+	ProofOfDelivery string                 `protobuf:"bytes,3,opt,name=proof_of_delivery,json=proofOfDelivery,proto3" json:"proof_of_delivery,omitempty"` // URL
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Fulfillment) Reset() {
+	*x = Fulfillment{}
+	mi := &file_contracts_order_order_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fulfillment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fulfillment) ProtoMessage() {}
+
+func (x *Fulfillment) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fulfillment.ProtoReflect.Descriptor instead.
+func (*Fulfillment) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Fulfillment) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Fulfillment) GetProofOfPickup() string {
+	if x != nil {
+		return x.ProofOfPickup
+	}
+	return ""
+}
+
+func (x *Fulfillment) GetProofOfDelivery() string {
+	if x != nil {
+		return x.ProofOfDelivery
+	}
+	return ""
+}
+
+type Agent struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Name                string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Phone               string                 `protobuf:"bytes,2,opt,name=phone,proto3" json:"phone,omitempty"`
+	VehicleCategory     string                 `protobuf:"bytes,3,opt,name=vehicle_category,json=vehicleCategory,proto3" json:"vehicle_category,omitempty"`
+	VehicleRegistration string                 `protobuf:"bytes,4,opt,name=vehicle_registration,json=vehicleRegistration,proto3" json:"vehicle_registration,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Agent) Reset() {
+	*x = Agent{}
+	mi := &file_contracts_order_order_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Agent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Agent) ProtoMessage() {}
+
+func (x *Agent) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Agent.ProtoReflect.Descriptor instead.
+func (*Agent) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Agent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Agent) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Agent) GetVehicleCategory() string {
+	if x != nil {
+		return x.VehicleCategory
+	}
+	return ""
+}
+
+func (x *Agent) GetVehicleRegistration() string {
+	if x != nil {
+		return x.VehicleRegistration
+	}
+	return ""
+}
+
+type GetOrderTrackingRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DispatchOrderId string                 `protobuf:"bytes,1,opt,name=dispatch_order_id,json=dispatchOrderId,proto3" json:"dispatch_order_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetOrderTrackingRequest) Reset() {
+	*x = GetOrderTrackingRequest{}
+	mi := &file_contracts_order_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderTrackingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderTrackingRequest) ProtoMessage() {}
+
+func (x *GetOrderTrackingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderTrackingRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderTrackingRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetOrderTrackingRequest) GetDispatchOrderId() string {
+	if x != nil {
+		return x.DispatchOrderId
+	}
+	return ""
+}
+
+type OrderTracking struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DispatchOrderId string                 `protobuf:"bytes,1,opt,name=dispatch_order_id,json=dispatchOrderId,proto3" json:"dispatch_order_id,omitempty"`
+	CurrentLocation *Location              `protobuf:"bytes,2,opt,name=current_location,json=currentLocation,proto3" json:"current_location,omitempty"`
+	Eta             *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=eta,proto3" json:"eta,omitempty"`
+	Timeline        []*TimelineEvent       `protobuf:"bytes,4,rep,name=timeline,proto3" json:"timeline,omitempty"`
+	TrackingUrl     string                 `protobuf:"bytes,5,opt,name=tracking_url,json=trackingUrl,proto3" json:"tracking_url,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *OrderTracking) Reset() {
+	*x = OrderTracking{}
+	mi := &file_contracts_order_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderTracking) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderTracking) ProtoMessage() {}
+
+func (x *OrderTracking) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderTracking.ProtoReflect.Descriptor instead.
+func (*OrderTracking) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OrderTracking) GetDispatchOrderId() string {
+	if x != nil {
+		return x.DispatchOrderId
+	}
+	return ""
+}
+
+func (x *OrderTracking) GetCurrentLocation() *Location {
+	if x != nil {
+		return x.CurrentLocation
+	}
+	return nil
+}
+
+func (x *OrderTracking) GetEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Eta
+	}
+	return nil
+}
+
+func (x *OrderTracking) GetTimeline() []*TimelineEvent {
+	if x != nil {
+		return x.Timeline
+	}
+	return nil
+}
+
+func (x *OrderTracking) GetTrackingUrl() string {
+	if x != nil {
+		return x.TrackingUrl
+	}
+	return ""
+}
+
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lat           float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lng           float64                `protobuf:"fixed64,2,opt,name=lng,proto3" json:"lng,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_contracts_order_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Location) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Location) GetLng() float64 {
+	if x != nil {
+		return x.Lng
+	}
+	return 0
+}
+
+type CancelOrderRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DispatchOrderId string                 `protobuf:"bytes,1,opt,name=dispatch_order_id,json=dispatchOrderId,proto3" json:"dispatch_order_id,omitempty"`
+	ReasonCode      string                 `protobuf:"bytes,2,opt,name=reason_code,json=reasonCode,proto3" json:"reason_code,omitempty"` // ONDC cancellation reason code (e.g. "001")
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_contracts_order_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CancelOrderRequest) GetDispatchOrderId() string {
+	if x != nil {
+		return x.DispatchOrderId
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetReasonCode() string {
+	if x != nil {
+		return x.ReasonCode
+	}
+	return ""
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"` // Order state after cancellation
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_contracts_order_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CancelOrderResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type UpdateOrderRequest struct {
+	state           protoimpl.MessageState      `protogen:"open.v1"`
+	DispatchOrderId string                      `protobuf:"bytes,1,opt,name=dispatch_order_id,json=dispatchOrderId,proto3" json:"dispatch_order_id,omitempty"`
+	Updates         *structpb.Struct            `protobuf:"bytes,2,opt,name=updates,proto3" json:"updates,omitempty"`               // Validated ONDC /update fields (fulfillments, payment)
+	Authorizations  []*FulfillmentAuthorization `protobuf:"bytes,3,rep,name=authorizations,proto3" json:"authorizations,omitempty"` // Validated PCC/DCC from fulfillments[].start/end.authorization
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateOrderRequest) Reset() {
+	*x = UpdateOrderRequest{}
+	mi := &file_contracts_order_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderRequest) ProtoMessage() {}
+
+func (x *UpdateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateOrderRequest) GetDispatchOrderId() string {
+	if x != nil {
+		return x.DispatchOrderId
+	}
+	return ""
+}
+
+func (x *UpdateOrderRequest) GetUpdates() *structpb.Struct {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+func (x *UpdateOrderRequest) GetAuthorizations() []*FulfillmentAuthorization {
+	if x != nil {
+		return x.Authorizations
+	}
+	return nil
+}
+
+// FulfillmentAuthorization is the code the rider must collect at pickup (PCC) or delivery (DCC)
+type FulfillmentAuthorization struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"` // pickup (start.authorization, PCC) or delivery (end.authorization, DCC)
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`   // OTP
+	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	ValidFrom     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	ValidTo       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=valid_to,json=validTo,proto3" json:"valid_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FulfillmentAuthorization) Reset() {
+	*x = FulfillmentAuthorization{}
+	mi := &file_contracts_order_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FulfillmentAuthorization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FulfillmentAuthorization) ProtoMessage() {}
+
+func (x *FulfillmentAuthorization) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FulfillmentAuthorization.ProtoReflect.Descriptor instead.
+func (*FulfillmentAuthorization) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FulfillmentAuthorization) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *FulfillmentAuthorization) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *FulfillmentAuthorization) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *FulfillmentAuthorization) GetValidFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return nil
+}
+
+func (x *FulfillmentAuthorization) GetValidTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidTo
+	}
+	return nil
+}
+
+type UpdateOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderResponse) Reset() {
+	*x = UpdateOrderResponse{}
+	mi := &file_contracts_order_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderResponse) ProtoMessage() {}
+
+func (x *UpdateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderResponse.ProtoReflect.Descriptor instead.
+func (*UpdateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateOrderResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type InitiateRTORequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DispatchOrderId string                 `protobuf:"bytes,1,opt,name=dispatch_order_id,json=dispatchOrderId,proto3" json:"dispatch_order_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *InitiateRTORequest) Reset() {
+	*x = InitiateRTORequest{}
+	mi := &file_contracts_order_order_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitiateRTORequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitiateRTORequest) ProtoMessage() {}
+
+func (x *InitiateRTORequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitiateRTORequest.ProtoReflect.Descriptor instead.
+func (*InitiateRTORequest) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *InitiateRTORequest) GetDispatchOrderId() string {
+	if x != nil {
+		return x.DispatchOrderId
+	}
+	return ""
+}
+
+type InitiateRTOResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"` // RTO_INITIATED
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InitiateRTOResponse) Reset() {
+	*x = InitiateRTOResponse{}
+	mi := &file_contracts_order_order_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitiateRTOResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitiateRTOResponse) ProtoMessage() {}
+
+func (x *InitiateRTOResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_order_order_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitiateRTOResponse.ProtoReflect.Descriptor instead.
+func (*InitiateRTOResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_order_order_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *InitiateRTOResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+var File_contracts_order_order_proto protoreflect.FileDescriptor
+
+const file_contracts_order_order_proto_rawDesc = "" +
+	"\n" +
+	"\x1bcontracts/order/order.proto\x12\x11dispatch.order.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"7\n" +
+	"\x18ValidateSearchTTLRequest\x12\x1b\n" +
+	"\tsearch_id\x18\x01 \x01(\tR\bsearchId\"4\n" +
+	"\x17ValidateQuoteTTLRequest\x12\x19\n" +
+	"\bquote_id\x18\x01 \x01(\tR\aquoteId\"f\n" +
+	"\x13ValidateTTLResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"=\n" +
+	"\x0fGetOrderRequest\x12*\n" +
+	"\x11dispatch_order_id\x18\x01 \x01(\tR\x0fdispatchOrderId\"\x94\x02\n" +
+	"\x05Order\x12*\n" +
+	"\x11dispatch_order_id\x18\x01 \x01(\tR\x0fdispatchOrderId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x19\n" +
+	"\brider_id\x18\x03 \x01(\tR\ariderId\x12<\n" +
+	"\btimeline\x18\x04 \x03(\v2 .dispatch.order.v1.TimelineEventR\btimeline\x12@\n" +
+	"\vfulfillment\x18\x05 \x01(\v2\x1e.dispatch.order.v1.FulfillmentR\vfulfillment\x12.\n" +
+	"\x05agent\x18\x06 \x01(\v2\x18.dispatch.order.v1.AgentR\x05agent\"u\n" +
+	"\rTimelineEvent\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x14\n" +
+	"\x05event\x18\x02 \x01(\tR\x05event\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\"w\n" +
+	"\vFulfillment\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12&\n" +
+	"\x0fproof_of_pickup\x18\x02 \x01(\tR\rproofOfPickup\x12*\n" +
+	"\x11proof_of_delivery\x18\x03 \x01(\tR\x0fproofOfDelivery\"\x8f\x01\n" +
+	"\x05Agent\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05phone\x18\x02 \x01(\tR\x05phone\x12)\n" +
+	"\x10vehicle_category\x18\x03 \x01(\tR\x0fvehicleCategory\x121\n" +
+	"\x14vehicle_registration\x18\x04 \x01(\tR\x13vehicleRegistration\"E\n" +
+	"\x17GetOrderTrackingRequest\x12*\n" +
+	"\x11dispatch_order_id\x18\x01 \x01(\tR\x0fdispatchOrderId\"\x92\x02\n" +
+	"\rOrderTracking\x12*\n" +
+	"\x11dispatch_order_id\x18\x01 \x01(\tR\x0fdispatchOrderId\x12F\n" +
+	"\x10current_location\x18\x02 \x01(\v2\x1b.dispatch.order.v1.LocationR\x0fcurrentLocation\x12,\n" +
+	"\x03eta\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03eta\x12<\n" +
+	"\btimeline\x18\x04 \x03(\v2 .dispatch.order.v1.TimelineEventR\btimeline\x12!\n" +
+	"\ftracking_url\x18\x05 \x01(\tR\vtrackingUrl\".\n" +
+	"\bLocation\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lng\x18\x02 \x01(\x01R\x03lng\"a\n" +
+	"\x12CancelOrderRequest\x12*\n" +
+	"\x11dispatch_order_id\x18\x01 \x01(\tR\x0fdispatchOrderId\x12\x1f\n" +
+	"\vreason_code\x18\x02 \x01(\tR\n" +
+	"reasonCode\"+\n" +
+	"\x13CancelOrderResponse\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\"\xc8\x01\n" +
+	"\x12UpdateOrderRequest\x12*\n" +
+	"\x11dispatch_order_id\x18\x01 \x01(\tR\x0fdispatchOrderId\x121\n" +
+	"\aupdates\x18\x02 \x01(\v2\x17.google.protobuf.StructR\aupdates\x12S\n" +
+	"\x0eauthorizations\x18\x03 \x03(\v2+.dispatch.order.v1.FulfillmentAuthorizationR\x0eauthorizations\"\xcc\x01\n" +
+	"\x18FulfillmentAuthorization\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x129\n" +
+	"\n" +
+	"valid_from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tvalidFrom\x125\n" +
+	"\bvalid_to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\avalidTo\"+\n" +
+	"\x13UpdateOrderResponse\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\"@\n" +
+	"\x12InitiateRTORequest\x12*\n" +
+	"\x11dispatch_order_id\x18\x01 \x01(\tR\x0fdispatchOrderId\"+\n" +
+	"\x13InitiateRTOResponse\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state2\xa6\x05\n" +
+	"\fOrderService\x12h\n" +
+	"\x11ValidateSearchTTL\x12+.dispatch.order.v1.ValidateSearchTTLRequest\x1a&.dispatch.order.v1.ValidateTTLResponse\x12f\n" +
+	"\x10ValidateQuoteTTL\x12*.dispatch.order.v1.ValidateQuoteTTLRequest\x1a&.dispatch.order.v1.ValidateTTLResponse\x12H\n" +
+	"\bGetOrder\x12\".dispatch.order.v1.GetOrderRequest\x1a\x18.dispatch.order.v1.Order\x12`\n" +
+	"\x10GetOrderTracking\x12*.dispatch.order.v1.GetOrderTrackingRequest\x1a .dispatch.order.v1.OrderTracking\x12\\\n" +
+	"\vCancelOrder\x12%.dispatch.order.v1.CancelOrderRequest\x1a&.dispatch.order.v1.CancelOrderResponse\x12\\\n" +
+	"\vUpdateOrder\x12%.dispatch.order.v1.UpdateOrderRequest\x1a&.dispatch.order.v1.UpdateOrderResponse\x12\\\n" +
+	"\vInitiateRTO\x12%.dispatch.order.v1.InitiateRTORequest\x1a&.dispatch.order.v1.InitiateRTOResponseB&Z$uois-gateway/contracts/order;orderv1b\x06proto3"
+
+var (
+	file_contracts_order_order_proto_rawDescOnce sync.Once
+	file_contracts_order_order_proto_rawDescData []byte
+)
+
+func file_contracts_order_order_proto_rawDescGZIP() []byte {
+	file_contracts_order_order_proto_rawDescOnce.Do(func() {
+		file_contracts_order_order_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_contracts_order_order_proto_rawDesc), len(file_contracts_order_order_proto_rawDesc)))
+	})
+	return file_contracts_order_order_proto_rawDescData
+}
+
+var file_contracts_order_order_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_contracts_order_order_proto_goTypes = []any{
+	(*ValidateSearchTTLRequest)(nil), // 0: dispatch.order.v1.ValidateSearchTTLRequest
+	(*ValidateQuoteTTLRequest)(nil),  // 1: dispatch.order.v1.ValidateQuoteTTLRequest
+	(*ValidateTTLResponse)(nil),      // 2: dispatch.order.v1.ValidateTTLResponse
+	(*GetOrderRequest)(nil),          // 3: dispatch.order.v1.GetOrderRequest
+	(*Order)(nil),                    // 4: dispatch.order.v1.Order
+	(*TimelineEvent)(nil),            // 5: dispatch.order.v1.TimelineEvent
+	(*Fulfillment)(nil),              // 6: dispatch.order.v1.Fulfillment
+	(*Agent)(nil),                    // 7: dispatch.order.v1.Agent
+	(*GetOrderTrackingRequest)(nil),  // 8: dispatch.order.v1.GetOrderTrackingRequest
+	(*OrderTracking)(nil),            // 9: dispatch.order.v1.OrderTracking
+	(*Location)(nil),                 // 10: dispatch.order.v1.Location
+	(*CancelOrderRequest)(nil),       // 11: dispatch.order.v1.CancelOrderRequest
+	(*CancelOrderResponse)(nil),      // 12: dispatch.order.v1.CancelOrderResponse
+	(*UpdateOrderRequest)(nil),       // 13: dispatch.order.v1.UpdateOrderRequest
+	(*FulfillmentAuthorization)(nil), // 14: dispatch.order.v1.FulfillmentAuthorization
+	(*UpdateOrderResponse)(nil),      // 15: dispatch.order.v1.UpdateOrderResponse
+	(*InitiateRTORequest)(nil),       // 16: dispatch.order.v1.InitiateRTORequest
+	(*InitiateRTOResponse)(nil),      // 17: dispatch.order.v1.InitiateRTOResponse
+	(*timestamppb.Timestamp)(nil),    // 18: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),          // 19: google.protobuf.Struct
+}
+var file_contracts_order_order_proto_depIdxs = []int32{
+	18, // 0: dispatch.order.v1.ValidateTTLResponse.expires_at:type_name -> google.protobuf.Timestamp
+	5,  // 1: dispatch.order.v1.Order.timeline:type_name -> dispatch.order.v1.TimelineEvent
+	6,  // 2: dispatch.order.v1.Order.fulfillment:type_name -> dispatch.order.v1.Fulfillment
+	7,  // 3: dispatch.order.v1.Order.agent:type_name -> dispatch.order.v1.Agent
+	18, // 4: dispatch.order.v1.TimelineEvent.timestamp:type_name -> google.protobuf.Timestamp
+	10, // 5: dispatch.order.v1.OrderTracking.current_location:type_name -> dispatch.order.v1.Location
+	18, // 6: dispatch.order.v1.OrderTracking.eta:type_name -> google.protobuf.Timestamp
+	5,  // 7: dispatch.order.v1.OrderTracking.timeline:type_name -> dispatch.order.v1.TimelineEvent
+	19, // 8: dispatch.order.v1.UpdateOrderRequest.updates:type_name -> google.protobuf.Struct
+	14, // 9: dispatch.order.v1.UpdateOrderRequest.authorizations:type_name -> dispatch.order.v1.FulfillmentAuthorization
+	18, // 10: dispatch.order.v1.FulfillmentAuthorization.valid_from:type_name -> google.protobuf.Timestamp
+	18, // 11: dispatch.order.v1.FulfillmentAuthorization.valid_to:type_name -> google.protobuf.Timestamp
+	0,  // 12: dispatch.order.v1.OrderService.ValidateSearchTTL:input_type -> dispatch.order.v1.ValidateSearchTTLRequest
+	1,  // 13: dispatch.order.v1.OrderService.ValidateQuoteTTL:input_type -> dispatch.order.v1.ValidateQuoteTTLRequest
+	3,  // 14: dispatch.order.v1.OrderService.GetOrder:input_type -> dispatch.order.v1.GetOrderRequest
+	8,  // 15: dispatch.order.v1.OrderService.GetOrderTracking:input_type -> dispatch.order.v1.GetOrderTrackingRequest
+	11, // 16: dispatch.order.v1.OrderService.CancelOrder:input_type -> dispatch.order.v1.CancelOrderRequest
+	13, // 17: dispatch.order.v1.OrderService.UpdateOrder:input_type -> dispatch.order.v1.UpdateOrderRequest
+	16, // 18: dispatch.order.v1.OrderService.InitiateRTO:input_type -> dispatch.order.v1.InitiateRTORequest
+	2,  // 19: dispatch.order.v1.OrderService.ValidateSearchTTL:output_type -> dispatch.order.v1.ValidateTTLResponse
+	2,  // 20: dispatch.order.v1.OrderService.ValidateQuoteTTL:output_type -> dispatch.order.v1.ValidateTTLResponse
+	4,  // 21: dispatch.order.v1.OrderService.GetOrder:output_type -> dispatch.order.v1.Order
+	9,  // 22: dispatch.order.v1.OrderService.GetOrderTracking:output_type -> dispatch.order.v1.OrderTracking
+	12, // 23: dispatch.order.v1.OrderService.CancelOrder:output_type -> dispatch.order.v1.CancelOrderResponse
+	15, // 24: dispatch.order.v1.OrderService.UpdateOrder:output_type -> dispatch.order.v1.UpdateOrderResponse
+	17, // 25: dispatch.order.v1.OrderService.InitiateRTO:output_type -> dispatch.order.v1.InitiateRTOResponse
+	19, // [19:26] is the sub-list for method output_type
+	12, // [12:19] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_contracts_order_order_proto_init() }
+func file_contracts_order_order_proto_init() {
+	if File_contracts_order_order_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contracts_order_order_proto_rawDesc), len(file_contracts_order_order_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_contracts_order_order_proto_goTypes,
+		DependencyIndexes: file_contracts_order_order_proto_depIdxs,
+		MessageInfos:      file_contracts_order_order_proto_msgTypes,
+	}.Build()
+	File_contracts_order_order_proto = out.File
+	file_contracts_order_order_proto_goTypes = nil
+	file_contracts_order_order_proto_depIdxs = nil
+}
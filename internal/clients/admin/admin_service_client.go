@@ -0,0 +1,181 @@
+package admin
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	adminv1 "uois-gateway/contracts/admin"
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultCallTimeout bounds an Admin Service RPC when ADMIN_SERVICE_GRPC_TIMEOUT is unset
+	defaultCallTimeout = 5 * time.Second
+
+	// listPageSize is the number of clients requested per ListClients page
+	listPageSize = 500
+
+	// retryBackoff is the pause between attempts of a failed call
+	retryBackoff = 500 * time.Millisecond
+)
+
+// Client handles Admin Service gRPC calls (contracts/admin/admin.proto)
+// It is only used off the hot path (registry reconciliation), so calls are retried with a fixed
+// backoff and no circuit breaker.
+type Client struct {
+	conn        *grpc.ClientConn // nil when the connection is owned by the caller
+	rpc         adminv1.AdminServiceClient
+	config      config.AdminConfig
+	maxAttempts int
+	logger      *zap.Logger
+}
+
+// NewClient creates an Admin Service client for ADMIN_SERVICE_GRPC_HOST:ADMIN_SERVICE_GRPC_PORT
+// The connection is established lazily on the first call; Close releases it
+func NewClient(cfg config.AdminConfig, logger *zap.Logger) (*Client, error) {
+	target := net.JoinHostPort(cfg.GRPCHost, strconv.Itoa(cfg.GRPCPort))
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "admin service client init failed", target)
+	}
+
+	client := NewClientWithConn(conn, cfg, logger)
+	client.conn = conn
+	return client, nil
+}
+
+// NewClientWithConn creates an Admin Service client over an existing connection (tests)
+func NewClientWithConn(conn grpc.ClientConnInterface, cfg config.AdminConfig, logger *zap.Logger) *Client {
+	return &Client{
+		rpc:         adminv1.NewAdminServiceClient(conn),
+		config:      cfg,
+		maxAttempts: max(cfg.MaxRetries, 1),
+		logger:      logger,
+	}
+}
+
+// Close releases the gRPC connection created by NewClient
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// ListClients returns every client registered upstream, following ListClients pages to the end
+func (c *Client) ListClients(ctx context.Context) ([]*models.Client, error) {
+	var clients []*models.Client
+	pageToken := ""
+	for {
+		var resp *adminv1.ListClientsResponse
+		err := c.call(ctx, "ListClients", func(callCtx context.Context) (err error) {
+			resp, err = c.rpc.ListClients(callCtx, &adminv1.ListClientsRequest{PageSize: listPageSize, PageToken: pageToken})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, client := range resp.GetClients() {
+			clients = append(clients, toClient(client))
+		}
+
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			return clients, nil
+		}
+	}
+}
+
+// GetClient returns a single upstream client
+func (c *Client) GetClient(ctx context.Context, clientID string) (*models.Client, error) {
+	var resp *adminv1.Client
+	err := c.call(ctx, "GetClient", func(callCtx context.Context) (err error) {
+		resp, err = c.rpc.GetClient(callCtx, &adminv1.GetClientRequest{ClientId: clientID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toClient(resp), nil
+}
+
+// call runs one RPC with the configured deadline, retrying transient failures up to ADMIN_SERVICE_MAX_RETRIES attempts
+func (c *Client) call(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		callCtx, cancel := context.WithTimeout(ctx, c.callTimeout())
+		err := fn(callCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		domainErr := mapStatusError(err)
+		if attempt >= c.maxAttempts || !domainErr.Retryable || ctx.Err() != nil {
+			c.logger.Warn("admin service call failed", zap.String("method", method), zap.Int("attempt", attempt), zap.Error(err))
+			return domainErr
+		}
+
+		c.logger.Info("admin service call failed, retrying", zap.String("method", method), zap.Int("attempt", attempt), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return domainErr
+		case <-time.After(retryBackoff):
+		}
+	}
+}
+
+func (c *Client) callTimeout() time.Duration {
+	if c.config.GRPCTimeout > 0 {
+		return c.config.GRPCTimeout
+	}
+	return defaultCallTimeout
+}
+
+// mapStatusError converts a gRPC status into a DomainError
+func mapStatusError(err error) *errors.DomainError {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.NotFound:
+		return errors.WrapDomainError(err, 65006, "client not found", st.Message())
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return errors.WrapDomainError(err, 65011, "admin service unavailable", st.Message()).WithRetryable(true)
+	default:
+		return errors.WrapDomainError(err, 65020, "admin service error", st.Message())
+	}
+}
+
+// toClient maps an upstream client onto the registry model, carrying bap_id, bap_uri and
+// rate_limit in Metadata the same way client.* events do
+func toClient(client *adminv1.Client) *models.Client {
+	result := &models.Client{
+		ID:               client.GetClientId(),
+		ClientCode:       client.GetClientCode(),
+		ClientSecretHash: client.GetClientSecretHash(),
+		AllowedIPs:       client.GetAllowedIps(),
+		Status:           client.GetStatus(),
+		Metadata:         client.GetMetadata().AsMap(),
+	}
+
+	if client.GetBapId() != "" {
+		result.Metadata["bap_id"] = client.GetBapId()
+	}
+	if client.GetBapUri() != "" {
+		result.Metadata["bap_uri"] = client.GetBapUri()
+	}
+	if client.GetRateLimit() > 0 {
+		result.Metadata["rate_limit"] = client.GetRateLimit()
+	}
+
+	return result
+}
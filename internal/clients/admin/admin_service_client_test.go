@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	adminv1 "uois-gateway/contracts/admin"
+	"uois-gateway/internal/config"
+	"uois-gateway/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// fakeAdminService serves a fixed client list one client per page
+type fakeAdminService struct {
+	adminv1.UnimplementedAdminServiceServer
+
+	mu         sync.Mutex
+	clients    []*adminv1.Client
+	failNext   int
+	listCalls  int
+	lastTokens []string
+}
+
+func (f *fakeAdminService) ListClients(ctx context.Context, req *adminv1.ListClientsRequest) (*adminv1.ListClientsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listCalls++
+	if f.failNext > 0 {
+		f.failNext--
+		return nil, status.Error(codes.Unavailable, "admin service restarting")
+	}
+	f.lastTokens = append(f.lastTokens, req.GetPageToken())
+
+	index := 0
+	if req.GetPageToken() != "" {
+		index, _ = strconv.Atoi(req.GetPageToken())
+	}
+	if index >= len(f.clients) {
+		return &adminv1.ListClientsResponse{}, nil
+	}
+	resp := &adminv1.ListClientsResponse{Clients: f.clients[index : index+1]}
+	if index+1 < len(f.clients) {
+		resp.NextPageToken = strconv.Itoa(index + 1)
+	}
+	return resp, nil
+}
+
+func (f *fakeAdminService) GetClient(ctx context.Context, req *adminv1.GetClientRequest) (*adminv1.Client, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, client := range f.clients {
+		if client.GetClientId() == req.GetClientId() {
+			return client, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "unknown client_id")
+}
+
+func newTestClient(t *testing.T, fake *fakeAdminService) *Client {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	adminv1.RegisterAdminServiceServer(server, fake)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	host, port, _ := net.SplitHostPort(lis.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	client, err := NewClient(config.AdminConfig{GRPCHost: host, GRPCPort: portNum, GRPCTimeout: 2 * time.Second, MaxRetries: 2}, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestAdminServiceClient_ListClients_FollowsPages(t *testing.T) {
+	metadata, _ := structpb.NewStruct(map[string]interface{}{"auth_mode": "ONDC_SIGNATURE"})
+	fake := &fakeAdminService{clients: []*adminv1.Client{
+		{ClientId: "client-1", ClientCode: "ONE", Status: "ACTIVE", BapId: "buyer.one", BapUri: "https://buyer.one/ondc", RateLimit: 50, Metadata: metadata},
+		{ClientId: "client-2", ClientCode: "TWO", Status: "SUSPENDED", AllowedIps: []string{"10.0.0.0/8"}},
+	}}
+	client := newTestClient(t, fake)
+
+	clients, err := client.ListClients(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, clients, 2)
+	assert.Equal(t, []string{"", "1"}, fake.lastTokens)
+
+	assert.Equal(t, "client-1", clients[0].ID)
+	assert.Equal(t, "buyer.one", clients[0].BapID())
+	assert.Equal(t, "https://buyer.one/ondc", clients[0].BapURI())
+	assert.Equal(t, int64(50), clients[0].Metadata["rate_limit"])
+	assert.Equal(t, "ONDC_SIGNATURE", clients[0].Metadata["auth_mode"])
+
+	assert.Equal(t, "SUSPENDED", clients[1].Status)
+	assert.Equal(t, []string{"10.0.0.0/8"}, clients[1].AllowedIPs)
+	assert.NotNil(t, clients[1].Metadata)
+}
+
+func TestAdminServiceClient_ListClients_RetriesUnavailable(t *testing.T) {
+	fake := &fakeAdminService{clients: []*adminv1.Client{{ClientId: "client-1"}}, failNext: 1}
+	client := newTestClient(t, fake)
+
+	clients, err := client.ListClients(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, clients, 1)
+	assert.Equal(t, 2, fake.listCalls)
+}
+
+func TestAdminServiceClient_ListClients_GivesUp(t *testing.T) {
+	fake := &fakeAdminService{failNext: 5}
+	client := newTestClient(t, fake)
+
+	_, err := client.ListClients(context.Background())
+
+	domainErr, ok := err.(*errors.DomainError)
+	require.True(t, ok)
+	assert.Equal(t, 65011, domainErr.Code)
+	assert.Equal(t, 2, fake.listCalls)
+}
+
+func TestAdminServiceClient_GetClient_NotFound(t *testing.T) {
+	client := newTestClient(t, &fakeAdminService{})
+
+	_, err := client.GetClient(context.Background(), "missing")
+
+	domainErr, ok := err.(*errors.DomainError)
+	require.True(t, ok)
+	assert.Equal(t, 65006, domainErr.Code)
+}
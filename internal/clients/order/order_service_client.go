@@ -2,99 +2,281 @@ package order
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
+	"net"
+	"strconv"
 	"time"
 
+	orderv1 "uois-gateway/contracts/order"
 	"uois-gateway/internal/config"
 	"uois-gateway/internal/handlers/ondc"
+	"uois-gateway/internal/models"
 	circuitbreaker "uois-gateway/internal/services/circuitbreaker"
+	"uois-gateway/pkg/errors"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// Client handles Order Service gRPC calls
-// TODO: Implement actual gRPC client when Order Service is available
+// defaultCallTimeout bounds an Order Service RPC when ORDER_SERVICE_GRPC_TIMEOUT is unset
+const defaultCallTimeout = 5 * time.Second
+
+// Client handles Order Service gRPC calls (contracts/order/order.proto)
+// Every RPC runs through one circuit breaker; only transport-level failures count against it,
+// so a burst of NOT_FOUND answers for unknown orders never opens the circuit.
+// Transient failures are retried (see call in retry.go).
 type Client struct {
+	conn           *grpc.ClientConn // nil when the connection is owned by the caller
+	rpc            orderv1.OrderServiceClient
 	config         config.OrderConfig
+	maxAttempts    int
+	metrics        CallMetrics // nil disables call duration metrics
 	logger         *zap.Logger
 	circuitBreaker *circuitbreaker.CircuitBreaker
 }
 
-// NewClient creates a new Order Service client
-func NewClient(cfg config.OrderConfig, logger *zap.Logger) *Client {
+// NewClient creates an Order Service client for ORDER_SERVICE_GRPC_HOST:ORDER_SERVICE_GRPC_PORT
+// The connection is established lazily on the first call; Close releases it
+func NewClient(cfg config.OrderConfig, metrics CallMetrics, logger *zap.Logger) (*Client, error) {
+	target := net.JoinHostPort(cfg.GRPCHost, strconv.Itoa(cfg.GRPCPort))
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "order service client init failed", target)
+	}
+
+	client := NewClientWithConn(conn, cfg, metrics, logger)
+	client.conn = conn
+	return client, nil
+}
+
+// NewClientWithConn creates an Order Service client over an existing connection (fake server, tests)
+func NewClientWithConn(conn grpc.ClientConnInterface, cfg config.OrderConfig, metrics CallMetrics, logger *zap.Logger) *Client {
 	cbConfig := circuitbreaker.DefaultConfig()
 	cbConfig.FailureThreshold = 5
 	cbConfig.Timeout = 60 * time.Second
 
 	return &Client{
+		rpc:            orderv1.NewOrderServiceClient(conn),
 		config:         cfg,
+		maxAttempts:    max(cfg.MaxRetries, 0) + 1, // the first call plus ORDER_SERVICE_MAX_RETRIES retries
+		metrics:        metrics,
 		logger:         logger,
 		circuitBreaker: circuitbreaker.NewCircuitBreaker(cbConfig),
 	}
 }
 
+// Close releases the gRPC connection created by NewClient
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
 // ValidateSearchIDTTL validates if search_id TTL is still valid
 func (c *Client) ValidateSearchIDTTL(ctx context.Context, searchID string) (bool, error) {
-	// TODO: Implement gRPC call to Order Service
-	c.logger.Info("validating search_id TTL", zap.String("search_id", searchID))
-	return true, nil
+	var resp *orderv1.ValidateTTLResponse
+	err := c.call(ctx, "ValidateSearchTTL", func(callCtx context.Context) (err error) {
+		resp, err = c.rpc.ValidateSearchTTL(callCtx, &orderv1.ValidateSearchTTLRequest{SearchId: searchID})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetValid(), nil
 }
 
 // ValidateQuoteIDTTL validates if quote_id TTL is still valid
 func (c *Client) ValidateQuoteIDTTL(ctx context.Context, quoteID string) (bool, error) {
-	// TODO: Implement gRPC call to Order Service
-	c.logger.Info("validating quote_id TTL", zap.String("quote_id", quoteID))
-	return true, nil
+	var resp *orderv1.ValidateTTLResponse
+	err := c.call(ctx, "ValidateQuoteTTL", func(callCtx context.Context) (err error) {
+		resp, err = c.rpc.ValidateQuoteTTL(callCtx, &orderv1.ValidateQuoteTTLRequest{QuoteId: quoteID})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetValid(), nil
 }
 
 // GetOrder retrieves order status from Order Service
 func (c *Client) GetOrder(ctx context.Context, dispatchOrderID string) (*ondc.OrderStatus, error) {
-	var result *ondc.OrderStatus
-	err := c.circuitBreaker.Execute(ctx, func() error {
-		// TODO: Implement gRPC call to Order Service
-		c.logger.Info("getting order", zap.String("dispatch_order_id", dispatchOrderID))
-		result = &ondc.OrderStatus{
-			DispatchOrderID: dispatchOrderID,
-			State:           "IN_PROGRESS",
-		}
-		return nil
+	var resp *orderv1.Order
+	err := c.call(ctx, "GetOrder", func(callCtx context.Context) (err error) {
+		resp, err = c.rpc.GetOrder(callCtx, &orderv1.GetOrderRequest{DispatchOrderId: dispatchOrderID})
+		return err
 	})
-	return result, err
+	if err != nil {
+		return nil, err
+	}
+	return toOrderStatus(resp), nil
 }
 
 // GetOrderTracking retrieves order tracking information
 func (c *Client) GetOrderTracking(ctx context.Context, dispatchOrderID string) (*ondc.OrderTracking, error) {
-	var result *ondc.OrderTracking
-	err := c.circuitBreaker.Execute(ctx, func() error {
-		// TODO: Implement gRPC call to Order Service
-		c.logger.Info("getting order tracking", zap.String("dispatch_order_id", dispatchOrderID))
-		result = &ondc.OrderTracking{
-			DispatchOrderID: dispatchOrderID,
-			TrackingURL:     fmt.Sprintf("https://track.example.com/%s", dispatchOrderID),
-			ETA:             time.Now().Add(1 * time.Hour),
-		}
-		return nil
+	var resp *orderv1.OrderTracking
+	err := c.call(ctx, "GetOrderTracking", func(callCtx context.Context) (err error) {
+		resp, err = c.rpc.GetOrderTracking(callCtx, &orderv1.GetOrderTrackingRequest{DispatchOrderId: dispatchOrderID})
+		return err
 	})
-	return result, err
+	if err != nil {
+		return nil, err
+	}
+	return toOrderTracking(resp), nil
 }
 
 // CancelOrder cancels an order
 func (c *Client) CancelOrder(ctx context.Context, dispatchOrderID string, reason string) error {
-	// TODO: Implement gRPC call to Order Service
-	c.logger.Info("cancelling order", zap.String("dispatch_order_id", dispatchOrderID), zap.String("reason", reason))
-	return nil
+	return c.call(ctx, "CancelOrder", func(callCtx context.Context) error {
+		_, err := c.rpc.CancelOrder(callCtx, &orderv1.CancelOrderRequest{DispatchOrderId: dispatchOrderID, ReasonCode: reason})
+		return err
+	})
 }
 
 // UpdateOrder updates an order
-func (c *Client) UpdateOrder(ctx context.Context, dispatchOrderID string, updates map[string]interface{}) error {
-	// TODO: Implement gRPC call to Order Service
-	c.logger.Info("updating order", zap.String("dispatch_order_id", dispatchOrderID))
-	return nil
+// authorizations are the validated PCC/DCC codes, sent as typed fields next to the raw updates
+func (c *Client) UpdateOrder(ctx context.Context, dispatchOrderID string, updates map[string]interface{}, authorizations []models.FulfillmentAuthorization) error {
+	updatesStruct, err := toStruct(updates)
+	if err != nil {
+		return errors.WrapDomainError(err, 65001, "invalid request", "updates are not JSON-serializable")
+	}
+
+	return c.call(ctx, "UpdateOrder", func(callCtx context.Context) error {
+		_, err := c.rpc.UpdateOrder(callCtx, &orderv1.UpdateOrderRequest{
+			DispatchOrderId: dispatchOrderID,
+			Updates:         updatesStruct,
+			Authorizations:  toAuthorizations(authorizations),
+		})
+		return err
+	})
 }
 
 // InitiateRTO initiates Return to Origin for an order
 func (c *Client) InitiateRTO(ctx context.Context, dispatchOrderID string) error {
-	// TODO: Implement gRPC call to Order Service
-	c.logger.Info("initiating RTO", zap.String("dispatch_order_id", dispatchOrderID))
-	return nil
+	return c.call(ctx, "InitiateRTO", func(callCtx context.Context) error {
+		_, err := c.rpc.InitiateRTO(callCtx, &orderv1.InitiateRTORequest{DispatchOrderId: dispatchOrderID})
+		return err
+	})
+}
+
+func (c *Client) callTimeout() time.Duration {
+	if c.config.GRPCTimeout > 0 {
+		return c.config.GRPCTimeout
+	}
+	return defaultCallTimeout
+}
+
+// isServiceFailure reports whether err means the Order Service itself is unhealthy
+func isServiceFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// mapStatusError converts a gRPC status into the ONDC error the handlers return
+func mapStatusError(err error) *errors.DomainError {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.NotFound:
+		return errors.WrapDomainError(err, 65006, "order not found", st.Message())
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return errors.WrapDomainError(err, 65001, "invalid request", st.Message())
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return errors.WrapDomainError(err, 65011, "order service unavailable", st.Message()).WithRetryable(true)
+	default:
+		return errors.WrapDomainError(err, 65020, "order service error", st.Message())
+	}
+}
+
+// toStruct carries the decoded ONDC update fields over the wire as a google.protobuf.Struct
+func toStruct(updates map[string]interface{}) (*structpb.Struct, error) {
+	raw, err := json.Marshal(updates)
+	if err != nil {
+		return nil, err
+	}
+	updatesStruct := &structpb.Struct{}
+	if err := protojson.Unmarshal(raw, updatesStruct); err != nil {
+		return nil, err
+	}
+	return updatesStruct, nil
+}
+
+func toAuthorizations(authorizations []models.FulfillmentAuthorization) []*orderv1.FulfillmentAuthorization {
+	if len(authorizations) == 0 {
+		return nil
+	}
+	result := make([]*orderv1.FulfillmentAuthorization, 0, len(authorizations))
+	for _, authorization := range authorizations {
+		result = append(result, &orderv1.FulfillmentAuthorization{
+			Stage:     authorization.Stage,
+			Type:      authorization.Type,
+			Token:     authorization.Token,
+			ValidFrom: timestamppb.New(authorization.ValidFrom),
+			ValidTo:   timestamppb.New(authorization.ValidTo),
+		})
+	}
+	return result
+}
+
+func toOrderStatus(order *orderv1.Order) *ondc.OrderStatus {
+	result := &ondc.OrderStatus{
+		DispatchOrderID: order.GetDispatchOrderId(),
+		State:           order.GetState(),
+		RiderID:         order.GetRiderId(),
+		Timeline:        toTimeline(order.GetTimeline()),
+		Fulfillment: ondc.FulfillmentStatus{
+			State:           order.GetFulfillment().GetState(),
+			ProofOfPickup:   order.GetFulfillment().GetProofOfPickup(),
+			ProofOfDelivery: order.GetFulfillment().GetProofOfDelivery(),
+		},
+	}
+	if agent := order.GetAgent(); agent != nil {
+		result.Agent = &ondc.AgentDetails{
+			Name:                agent.GetName(),
+			Phone:               agent.GetPhone(),
+			VehicleCategory:     agent.GetVehicleCategory(),
+			VehicleRegistration: agent.GetVehicleRegistration(),
+		}
+	}
+	return result
+}
+
+func toOrderTracking(tracking *orderv1.OrderTracking) *ondc.OrderTracking {
+	result := &ondc.OrderTracking{
+		DispatchOrderID: tracking.GetDispatchOrderId(),
+		CurrentLocation: ondc.Location{
+			Lat: tracking.GetCurrentLocation().GetLat(),
+			Lng: tracking.GetCurrentLocation().GetLng(),
+		},
+		Timeline:    toTimeline(tracking.GetTimeline()),
+		TrackingURL: tracking.GetTrackingUrl(),
+	}
+	if tracking.GetEta() != nil {
+		result.ETA = tracking.GetEta().AsTime()
+	}
+	return result
+}
+
+func toTimeline(events []*orderv1.TimelineEvent) []ondc.OrderTimelineEvent {
+	if len(events) == 0 {
+		return nil
+	}
+	timeline := make([]ondc.OrderTimelineEvent, 0, len(events))
+	for _, event := range events {
+		timeline = append(timeline, ondc.OrderTimelineEvent{
+			Timestamp: event.GetTimestamp().AsTime(),
+			Event:     event.GetEvent(),
+			State:     event.GetState(),
+		})
+	}
+	return timeline
 }
@@ -2,135 +2,249 @@ package order
 
 import (
 	"context"
+	"net"
+	"strconv"
 	"testing"
+	"time"
 
+	orderv1 "uois-gateway/contracts/order"
+	"uois-gateway/internal/clients/order/orderfake"
 	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func TestOrderServiceClient_ValidateSearchIDTTL_Success(t *testing.T) {
-	logger := zap.NewNop()
-	cfg := config.OrderConfig{
-		GRPCHost:    "localhost",
-		GRPCPort:    50052,
-		GRPCTimeout: 5,
+func newTestClient(t *testing.T) (*Client, *orderfake.Server) {
+	fake := orderfake.NewServer()
+	addr, err := fake.Start("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(fake.Stop)
+
+	client, err := NewClient(testOrderConfig(t, addr), nil, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client, fake
+}
+
+func testOrderConfig(t *testing.T, addr string) config.OrderConfig {
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return config.OrderConfig{
+		GRPCHost:    host,
+		GRPCPort:    portNum,
+		GRPCTimeout: 2 * time.Second,
 		MaxRetries:  3,
 	}
+}
+
+func assertDomainCode(t *testing.T, err error, code int) {
+	domainErr, ok := err.(*errors.DomainError)
+	if assert.True(t, ok, "expected DomainError, got %v", err) {
+		assert.Equal(t, code, domainErr.Code)
+	}
+}
 
-	client := NewClient(cfg, logger)
+func TestOrderServiceClient_ValidateSearchIDTTL(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.SetSearchExpiry("expired-search", time.Now().Add(-time.Minute))
 
 	valid, err := client.ValidateSearchIDTTL(context.Background(), "test-search-id")
-
 	assert.NoError(t, err)
 	assert.True(t, valid)
-}
-
-func TestOrderServiceClient_ValidateQuoteIDTTL_Success(t *testing.T) {
-	logger := zap.NewNop()
-	cfg := config.OrderConfig{
-		GRPCHost:    "localhost",
-		GRPCPort:    50052,
-		GRPCTimeout: 5,
-		MaxRetries:  3,
-	}
 
-	client := NewClient(cfg, logger)
+	valid, err = client.ValidateSearchIDTTL(context.Background(), "expired-search")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
 
-	valid, err := client.ValidateQuoteIDTTL(context.Background(), "test-quote-id")
+func TestOrderServiceClient_ValidateQuoteIDTTL(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.SetQuoteExpiry("live-quote", time.Now().Add(10*time.Minute))
+	fake.SetQuoteExpiry("expired-quote", time.Now().Add(-time.Second))
 
+	valid, err := client.ValidateQuoteIDTTL(context.Background(), "live-quote")
 	assert.NoError(t, err)
 	assert.True(t, valid)
+
+	valid, err = client.ValidateQuoteIDTTL(context.Background(), "expired-quote")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = client.ValidateQuoteIDTTL(context.Background(), "")
+	assertDomainCode(t, err, 65001)
 }
 
 func TestOrderServiceClient_GetOrder_Success(t *testing.T) {
-	logger := zap.NewNop()
-	cfg := config.OrderConfig{
-		GRPCHost:    "localhost",
-		GRPCPort:    50052,
-		GRPCTimeout: 5,
-		MaxRetries:  3,
-	}
-
-	client := NewClient(cfg, logger)
+	client, fake := newTestClient(t)
+	pickedUpAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	fake.PutOrder(&orderv1.Order{
+		DispatchOrderId: "DISPATCH123",
+		State:           "PICKED_UP",
+		RiderId:         "rider-7",
+		Timeline: []*orderv1.TimelineEvent{
+			{Timestamp: timestamppb.New(pickedUpAt), Event: "ORDER_PICKED_UP", State: "PICKED_UP"},
+		},
+		Fulfillment: &orderv1.Fulfillment{State: "Order-picked-up", ProofOfPickup: "https://proof.example.com/p.jpg"},
+		Agent:       &orderv1.Agent{Name: "Ravi", Phone: "9886098860", VehicleCategory: "Bike", VehicleRegistration: "KA01AB1234"},
+	})
 
 	order, err := client.GetOrder(context.Background(), "DISPATCH123")
 
-	assert.NoError(t, err)
-	assert.NotNil(t, order)
+	require.NoError(t, err)
 	assert.Equal(t, "DISPATCH123", order.DispatchOrderID)
-	assert.Equal(t, "IN_PROGRESS", order.State)
+	assert.Equal(t, "PICKED_UP", order.State)
+	assert.Equal(t, "rider-7", order.RiderID)
+	assert.Equal(t, "Order-picked-up", order.Fulfillment.State)
+	assert.Equal(t, "https://proof.example.com/p.jpg", order.Fulfillment.ProofOfPickup)
+	require.Len(t, order.Timeline, 1)
+	assert.True(t, pickedUpAt.Equal(order.Timeline[0].Timestamp))
+	require.NotNil(t, order.Agent)
+	assert.Equal(t, "KA01AB1234", order.Agent.VehicleRegistration)
 }
 
-func TestOrderServiceClient_GetOrderTracking_Success(t *testing.T) {
-	logger := zap.NewNop()
-	cfg := config.OrderConfig{
-		GRPCHost:    "localhost",
-		GRPCPort:    50052,
-		GRPCTimeout: 5,
-		MaxRetries:  3,
-	}
+func TestOrderServiceClient_GetOrder_NotFound(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.SetError("GetOrder", status.Error(codes.NotFound, "unknown dispatch_order_id"))
+
+	order, err := client.GetOrder(context.Background(), "DISPATCH404")
 
-	client := NewClient(cfg, logger)
+	assert.Nil(t, order)
+	assertDomainCode(t, err, 65006)
+}
+
+func TestOrderServiceClient_GetOrderTracking_Success(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.TrackingBaseURL = "https://track.dispatch.test/"
+	fake.SetLocation("DISPATCH123", 12.9716, 77.5946)
 
 	tracking, err := client.GetOrderTracking(context.Background(), "DISPATCH123")
 
-	assert.NoError(t, err)
-	assert.NotNil(t, tracking)
+	require.NoError(t, err)
 	assert.Equal(t, "DISPATCH123", tracking.DispatchOrderID)
-	assert.Contains(t, tracking.TrackingURL, "DISPATCH123")
+	assert.Equal(t, "https://track.dispatch.test/DISPATCH123", tracking.TrackingURL)
+	assert.Equal(t, 12.9716, tracking.CurrentLocation.Lat)
+	assert.Equal(t, 77.5946, tracking.CurrentLocation.Lng)
 	assert.NotZero(t, tracking.ETA)
 }
 
-func TestOrderServiceClient_CancelOrder_Success(t *testing.T) {
-	logger := zap.NewNop()
-	cfg := config.OrderConfig{
-		GRPCHost:    "localhost",
-		GRPCPort:    50052,
-		GRPCTimeout: 5,
-		MaxRetries:  3,
-	}
-
-	client := NewClient(cfg, logger)
+func TestOrderServiceClient_CancelOrder(t *testing.T) {
+	client, fake := newTestClient(t)
 
 	err := client.CancelOrder(context.Background(), "DISPATCH123", "001")
 
 	assert.NoError(t, err)
-}
+	assert.Equal(t, orderfake.StateCancelled, fake.Order("DISPATCH123").GetState())
+	assert.Equal(t, "001", fake.CancelReason("DISPATCH123"))
 
-func TestOrderServiceClient_UpdateOrder_Success(t *testing.T) {
-	logger := zap.NewNop()
-	cfg := config.OrderConfig{
-		GRPCHost:    "localhost",
-		GRPCPort:    50052,
-		GRPCTimeout: 5,
-		MaxRetries:  3,
-	}
+	// A cancelled order cannot be cancelled again
+	err = client.CancelOrder(context.Background(), "DISPATCH123", "001")
+	assertDomainCode(t, err, 65001)
+}
 
-	client := NewClient(cfg, logger)
+func TestOrderServiceClient_UpdateOrder(t *testing.T) {
+	client, fake := newTestClient(t)
 
 	updates := map[string]interface{}{
 		"weight": 5.0,
+		"payment": map[string]interface{}{
+			"@ondc/org/authorization": map[string]interface{}{"type": "OTP", "token": "1234"},
+		},
+		"fulfillments": []interface{}{map[string]interface{}{"id": "F1"}},
 	}
 
-	err := client.UpdateOrder(context.Background(), "DISPATCH123", updates)
+	err := client.UpdateOrder(context.Background(), "DISPATCH123", updates, nil)
 
-	assert.NoError(t, err)
+	require.NoError(t, err)
+	received := fake.Updates("DISPATCH123")
+	require.Len(t, received, 1)
+	assert.Equal(t, updates, received[0].AsMap())
 }
 
-func TestOrderServiceClient_InitiateRTO_Success(t *testing.T) {
-	logger := zap.NewNop()
-	cfg := config.OrderConfig{
-		GRPCHost:    "localhost",
-		GRPCPort:    50052,
-		GRPCTimeout: 5,
-		MaxRetries:  3,
+func TestOrderServiceClient_UpdateOrder_Authorizations(t *testing.T) {
+	client, fake := newTestClient(t)
+
+	validFrom := time.Date(2023, 6, 6, 12, 0, 0, 0, time.UTC)
+	validTo := validFrom.Add(2 * time.Hour)
+	updates := map[string]interface{}{"fulfillments": []interface{}{map[string]interface{}{"id": "F1"}}}
+	authorizations := []models.FulfillmentAuthorization{
+		{Stage: models.AuthorizationStageDelivery, Type: models.AuthorizationTypeOTP, Token: "5678", ValidFrom: validFrom, ValidTo: validTo},
 	}
 
-	client := NewClient(cfg, logger)
+	err := client.UpdateOrder(context.Background(), "DISPATCH123", updates, authorizations)
+
+	require.NoError(t, err)
+	received := fake.Authorizations("DISPATCH123")
+	require.Len(t, received, 1)
+	assert.Equal(t, "delivery", received[0].GetStage())
+	assert.Equal(t, "OTP", received[0].GetType())
+	assert.Equal(t, "5678", received[0].GetToken())
+	assert.True(t, validFrom.Equal(received[0].GetValidFrom().AsTime()))
+	assert.True(t, validTo.Equal(received[0].GetValidTo().AsTime()))
+
+	fake.PutOrder(&orderv1.Order{DispatchOrderId: "DISPATCH456", State: orderfake.StateDelivered})
+	err = client.UpdateOrder(context.Background(), "DISPATCH456", updates, authorizations)
+	assertDomainCode(t, err, 65001)
+}
+
+func TestOrderServiceClient_InitiateRTO(t *testing.T) {
+	client, fake := newTestClient(t)
 
 	err := client.InitiateRTO(context.Background(), "DISPATCH123")
 
 	assert.NoError(t, err)
+	assert.Equal(t, orderfake.StateRTOInitiated, fake.Order("DISPATCH123").GetState())
+}
+
+func TestOrderServiceClient_UnavailableOpensCircuit(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.SetError("GetOrder", status.Error(codes.Unavailable, "order service overloaded"))
+
+	for i := 0; i < 5; i++ {
+		_, err := client.GetOrder(context.Background(), "DISPATCH123")
+		assertDomainCode(t, err, 65011)
+	}
+
+	// The circuit is open now: the call fails fast even though the service recovered
+	fake.SetError("GetOrder", nil)
+	_, err := client.GetOrder(context.Background(), "DISPATCH123")
+	assertDomainCode(t, err, 65011)
+	assert.True(t, err.(*errors.DomainError).Retryable)
+}
+
+func TestOrderServiceClient_NotFoundDoesNotOpenCircuit(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.SetError("GetOrder", status.Error(codes.NotFound, "unknown dispatch_order_id"))
+
+	for i := 0; i < 10; i++ {
+		_, err := client.GetOrder(context.Background(), "DISPATCH404")
+		assertDomainCode(t, err, 65006)
+	}
+
+	fake.SetError("GetOrder", nil)
+	_, err := client.GetOrder(context.Background(), "DISPATCH123")
+	assert.NoError(t, err)
+}
+
+func TestOrderServiceClient_ServerDown(t *testing.T) {
+	fake := orderfake.NewServer()
+	addr, err := fake.Start("127.0.0.1:0")
+	require.NoError(t, err)
+	fake.Stop()
+
+	client, err := NewClient(testOrderConfig(t, addr), nil, zap.NewNop())
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.GetOrder(context.Background(), "DISPATCH123")
+
+	assertDomainCode(t, err, 65011)
 }
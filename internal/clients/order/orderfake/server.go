@@ -0,0 +1,423 @@
+// Package orderfake is an in-process Order Service speaking the contracts/order gRPC API
+// It backs handler tests and local runs (ORDER_SERVICE_FAKE=true) so requests go through real
+// protobuf serialization instead of hand-written mocks.
+package orderfake
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	orderv1 "uois-gateway/contracts/order"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Order states used by the fake's command handling
+const (
+	StateConfirmed    = "CONFIRMED"
+	StateDelivered    = "DELIVERED"
+	StateCancelled    = "CANCELLED"
+	StateRTOInitiated = "RTO_INITIATED"
+)
+
+// defaultTrackingURL is the tracking_url prefix when TrackingBaseURL is not set
+const defaultTrackingURL = "http://localhost:8080/track/"
+
+// requestKeyHeader is the metadata header commands are deduplicated on (see order.proto)
+const requestKeyHeader = "idempotency-key"
+
+// injectedError fails the next remaining calls of a method (remaining < 0 fails every call)
+type injectedError struct {
+	err       error
+	remaining int
+}
+
+// Server is an in-memory OrderServiceServer
+// Unknown dispatch_order_ids are provisioned as CONFIRMED orders on first use and unknown
+// search/quote ids are valid, so a local run works without seeding; tests seed explicit state.
+type Server struct {
+	orderv1.UnimplementedOrderServiceServer
+
+	// TrackingBaseURL prefixes the dispatch_order_id in tracking_url
+	TrackingBaseURL string
+
+	mu             sync.Mutex
+	orders         map[string]*orderv1.Order
+	locations      map[string]*orderv1.Location
+	searchExpiry   map[string]time.Time
+	quoteExpiry    map[string]time.Time
+	updates        map[string][]*structpb.Struct
+	authorizations map[string][]*orderv1.FulfillmentAuthorization
+	cancelReasons  map[string]string
+	errs           map[string]*injectedError
+	calls          map[string]int
+	applied        map[string]proto.Message // command responses by method and request key
+	grpcServer     *grpc.Server
+}
+
+// NewServer creates an empty fake Order Service
+func NewServer() *Server {
+	return &Server{
+		TrackingBaseURL: defaultTrackingURL,
+		orders:          make(map[string]*orderv1.Order),
+		locations:       make(map[string]*orderv1.Location),
+		searchExpiry:    make(map[string]time.Time),
+		quoteExpiry:     make(map[string]time.Time),
+		updates:         make(map[string][]*structpb.Struct),
+		authorizations:  make(map[string][]*orderv1.FulfillmentAuthorization),
+		cancelReasons:   make(map[string]string),
+		errs:            make(map[string]*injectedError),
+		calls:           make(map[string]int),
+		applied:         make(map[string]proto.Message),
+	}
+}
+
+// Start serves the fake on address ("127.0.0.1:0" picks a free port) and returns the bound address
+func (s *Server) Start(address string) (string, error) {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return "", err
+	}
+
+	grpcServer := grpc.NewServer()
+	orderv1.RegisterOrderServiceServer(grpcServer, s)
+
+	s.mu.Lock()
+	s.grpcServer = grpcServer
+	s.mu.Unlock()
+
+	go func() { _ = grpcServer.Serve(lis) }()
+	return lis.Addr().String(), nil
+}
+
+// Stop shuts the gRPC server down
+func (s *Server) Stop() {
+	s.mu.Lock()
+	grpcServer := s.grpcServer
+	s.mu.Unlock()
+	if grpcServer != nil {
+		grpcServer.Stop()
+	}
+}
+
+// PutOrder stores (or replaces) an order
+func (s *Server) PutOrder(order *orderv1.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders[order.GetDispatchOrderId()] = proto.Clone(order).(*orderv1.Order)
+}
+
+// SetLocation sets the rider location returned by GetOrderTracking
+func (s *Server) SetLocation(dispatchOrderID string, lat, lng float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locations[dispatchOrderID] = &orderv1.Location{Lat: lat, Lng: lng}
+}
+
+// SetSearchExpiry sets when searchID expires
+func (s *Server) SetSearchExpiry(searchID string, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searchExpiry[searchID] = expiresAt
+}
+
+// SetQuoteExpiry sets when quoteID expires
+func (s *Server) SetQuoteExpiry(quoteID string, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quoteExpiry[quoteID] = expiresAt
+}
+
+// SetError makes every call to method (e.g. "GetOrder") fail with err; nil clears it
+func (s *Server) SetError(method string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		delete(s.errs, method)
+		return
+	}
+	s.errs[method] = &injectedError{err: err, remaining: -1}
+}
+
+// FailNext makes the next n calls to method fail with err
+func (s *Server) FailNext(method string, n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errs[method] = &injectedError{err: err, remaining: n}
+}
+
+// Calls returns how many times method was called, including failed calls
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// Order returns a copy of the stored order, or nil
+func (s *Server) Order(dispatchOrderID string) *orderv1.Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order, ok := s.orders[dispatchOrderID]
+	if !ok {
+		return nil
+	}
+	return proto.Clone(order).(*orderv1.Order)
+}
+
+// Updates returns the UpdateOrder payloads received for dispatchOrderID
+func (s *Server) Updates(dispatchOrderID string) []*structpb.Struct {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*structpb.Struct(nil), s.updates[dispatchOrderID]...)
+}
+
+// Authorizations returns the PCC/DCC codes received through UpdateOrder for dispatchOrderID
+func (s *Server) Authorizations(dispatchOrderID string) []*orderv1.FulfillmentAuthorization {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*orderv1.FulfillmentAuthorization(nil), s.authorizations[dispatchOrderID]...)
+}
+
+// CancelReason returns the reason code of the CancelOrder call for dispatchOrderID
+func (s *Server) CancelReason(dispatchOrderID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cancelReasons[dispatchOrderID]
+}
+
+// ValidateSearchTTL implements orderv1.OrderServiceServer
+func (s *Server) ValidateSearchTTL(ctx context.Context, req *orderv1.ValidateSearchTTLRequest) (*orderv1.ValidateTTLResponse, error) {
+	return s.validateTTL("ValidateSearchTTL", s.searchExpiry, "search_id", req.GetSearchId())
+}
+
+// ValidateQuoteTTL implements orderv1.OrderServiceServer
+func (s *Server) ValidateQuoteTTL(ctx context.Context, req *orderv1.ValidateQuoteTTLRequest) (*orderv1.ValidateTTLResponse, error) {
+	return s.validateTTL("ValidateQuoteTTL", s.quoteExpiry, "quote_id", req.GetQuoteId())
+}
+
+// GetOrder implements orderv1.OrderServiceServer
+func (s *Server) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order, err := s.lookup("GetOrder", req.GetDispatchOrderId())
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(order).(*orderv1.Order), nil
+}
+
+// GetOrderTracking implements orderv1.OrderServiceServer
+func (s *Server) GetOrderTracking(ctx context.Context, req *orderv1.GetOrderTrackingRequest) (*orderv1.OrderTracking, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order, err := s.lookup("GetOrderTracking", req.GetDispatchOrderId())
+	if err != nil {
+		return nil, err
+	}
+
+	location := s.locations[order.GetDispatchOrderId()]
+	if location == nil {
+		location = &orderv1.Location{}
+	}
+	return &orderv1.OrderTracking{
+		DispatchOrderId: order.GetDispatchOrderId(),
+		CurrentLocation: proto.Clone(location).(*orderv1.Location),
+		Eta:             timestamppb.New(time.Now().Add(30 * time.Minute)),
+		Timeline:        cloneTimeline(order.GetTimeline()),
+		TrackingUrl:     s.TrackingBaseURL + order.GetDispatchOrderId(),
+	}, nil
+}
+
+// CancelOrder implements orderv1.OrderServiceServer
+func (s *Server) CancelOrder(ctx context.Context, req *orderv1.CancelOrderRequest) (*orderv1.CancelOrderResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order, err := s.lookup("CancelOrder", req.GetDispatchOrderId())
+	if err != nil {
+		return nil, err
+	}
+	if resp, ok := s.replay(ctx, "CancelOrder"); ok {
+		return resp.(*orderv1.CancelOrderResponse), nil
+	}
+	if order.GetState() == StateDelivered || order.GetState() == StateCancelled {
+		return nil, status.Errorf(codes.FailedPrecondition, "order is %s", order.GetState())
+	}
+
+	s.transition(order, StateCancelled, "ORDER_CANCELLED")
+	s.cancelReasons[order.GetDispatchOrderId()] = req.GetReasonCode()
+	resp := &orderv1.CancelOrderResponse{State: order.GetState()}
+	s.remember(ctx, "CancelOrder", resp)
+	return resp, nil
+}
+
+// UpdateOrder implements orderv1.OrderServiceServer
+func (s *Server) UpdateOrder(ctx context.Context, req *orderv1.UpdateOrderRequest) (*orderv1.UpdateOrderResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order, err := s.lookup("UpdateOrder", req.GetDispatchOrderId())
+	if err != nil {
+		return nil, err
+	}
+	if resp, ok := s.replay(ctx, "UpdateOrder"); ok {
+		return resp.(*orderv1.UpdateOrderResponse), nil
+	}
+	if len(req.GetUpdates().GetFields()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "updates are required")
+	}
+	for _, authorization := range req.GetAuthorizations() {
+		if err := checkAuthorization(order, authorization); err != nil {
+			return nil, err
+		}
+	}
+
+	s.updates[order.GetDispatchOrderId()] = append(s.updates[order.GetDispatchOrderId()], proto.Clone(req.GetUpdates()).(*structpb.Struct))
+	for _, authorization := range req.GetAuthorizations() {
+		s.authorizations[order.GetDispatchOrderId()] = append(s.authorizations[order.GetDispatchOrderId()], proto.Clone(authorization).(*orderv1.FulfillmentAuthorization))
+	}
+	resp := &orderv1.UpdateOrderResponse{State: order.GetState()}
+	s.remember(ctx, "UpdateOrder", resp)
+	return resp, nil
+}
+
+// InitiateRTO implements orderv1.OrderServiceServer
+func (s *Server) InitiateRTO(ctx context.Context, req *orderv1.InitiateRTORequest) (*orderv1.InitiateRTOResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order, err := s.lookup("InitiateRTO", req.GetDispatchOrderId())
+	if err != nil {
+		return nil, err
+	}
+	if resp, ok := s.replay(ctx, "InitiateRTO"); ok {
+		return resp.(*orderv1.InitiateRTOResponse), nil
+	}
+	if order.GetState() == StateDelivered || order.GetState() == StateCancelled {
+		return nil, status.Errorf(codes.FailedPrecondition, "order is %s", order.GetState())
+	}
+
+	s.transition(order, StateRTOInitiated, "RTO_INITIATED")
+	resp := &orderv1.InitiateRTOResponse{State: order.GetState()}
+	s.remember(ctx, "InitiateRTO", resp)
+	return resp, nil
+}
+
+func (s *Server) validateTTL(method string, expiries map[string]time.Time, field, id string) (*orderv1.ValidateTTLResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.begin(method); err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+
+	expiresAt, ok := expiries[id]
+	if !ok {
+		return &orderv1.ValidateTTLResponse{Valid: true}, nil
+	}
+	return &orderv1.ValidateTTLResponse{
+		Valid:     time.Now().Before(expiresAt),
+		ExpiresAt: timestamppb.New(expiresAt),
+	}, nil
+}
+
+// begin counts a call and returns the error injected for it, if any; callers hold s.mu
+func (s *Server) begin(method string) error {
+	s.calls[method]++
+
+	injected, ok := s.errs[method]
+	if !ok {
+		return nil
+	}
+	if injected.remaining > 0 {
+		injected.remaining--
+		if injected.remaining == 0 {
+			delete(s.errs, method)
+		}
+	}
+	return injected.err
+}
+
+// replay returns the response of a command already applied under the call's request key; callers hold s.mu
+func (s *Server) replay(ctx context.Context, method string) (proto.Message, bool) {
+	key := requestKey(ctx)
+	if key == "" {
+		return nil, false
+	}
+	resp, ok := s.applied[method+"|"+key]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(resp), true
+}
+
+// remember stores a command response under the call's request key; callers hold s.mu
+func (s *Server) remember(ctx context.Context, method string, resp proto.Message) {
+	if key := requestKey(ctx); key != "" {
+		s.applied[method+"|"+key] = proto.Clone(resp)
+	}
+}
+
+// lookup returns the stored order, provisioning unknown ids; callers hold s.mu
+func (s *Server) lookup(method, dispatchOrderID string) (*orderv1.Order, error) {
+	if err := s.begin(method); err != nil {
+		return nil, err
+	}
+	if dispatchOrderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "dispatch_order_id is required")
+	}
+
+	order, ok := s.orders[dispatchOrderID]
+	if !ok {
+		order = &orderv1.Order{DispatchOrderId: dispatchOrderID, Fulfillment: &orderv1.Fulfillment{}}
+		s.orders[dispatchOrderID] = order
+		s.transition(order, StateConfirmed, "ORDER_CONFIRMED")
+	}
+	return order, nil
+}
+
+// transition moves order to state and appends the timeline event; callers hold s.mu
+func (s *Server) transition(order *orderv1.Order, state, event string) {
+	order.State = state
+	order.Timeline = append(order.Timeline, &orderv1.TimelineEvent{
+		Timestamp: timestamppb.Now(),
+		Event:     event,
+		State:     state,
+	})
+}
+
+// checkAuthorization rejects malformed PCC/DCC codes and codes for orders that can no longer be handed over
+func checkAuthorization(order *orderv1.Order, authorization *orderv1.FulfillmentAuthorization) error {
+	if authorization.GetStage() != "pickup" && authorization.GetStage() != "delivery" {
+		return status.Errorf(codes.InvalidArgument, "unknown authorization stage %q", authorization.GetStage())
+	}
+	if authorization.GetToken() == "" || !authorization.GetValidTo().AsTime().After(authorization.GetValidFrom().AsTime()) {
+		return status.Errorf(codes.InvalidArgument, "invalid %s authorization", authorization.GetStage())
+	}
+	if order.GetState() == StateDelivered || order.GetState() == StateCancelled {
+		return status.Errorf(codes.FailedPrecondition, "order is %s", order.GetState())
+	}
+	return nil
+}
+
+func requestKey(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, requestKeyHeader); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func cloneTimeline(events []*orderv1.TimelineEvent) []*orderv1.TimelineEvent {
+	timeline := make([]*orderv1.TimelineEvent, 0, len(events))
+	for _, event := range events {
+		timeline = append(timeline, proto.Clone(event).(*orderv1.TimelineEvent))
+	}
+	return timeline
+}
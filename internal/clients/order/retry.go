@@ -0,0 +1,151 @@
+package order
+
+import (
+	"context"
+	stderrors "errors"
+	"math/rand/v2"
+	"time"
+
+	circuitbreaker "uois-gateway/internal/services/circuitbreaker"
+	"uois-gateway/internal/utils"
+	"uois-gateway/pkg/errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// metricsServiceName labels Order Service calls in the gRPC call duration metric
+	metricsServiceName = "order_service"
+
+	// requestKeyHeader carries the ONDC request key so the Order Service applies a retried command once
+	requestKeyHeader = "idempotency-key"
+
+	// Retry backoff: exponential from baseRetryBackoff, capped at maxRetryBackoff, with jitter
+	baseRetryBackoff = 100 * time.Millisecond
+	maxRetryBackoff  = 2 * time.Second
+)
+
+// CallMetrics records the duration of every Order Service RPC attempt
+type CallMetrics interface {
+	RecordGRPCCallDuration(service, method string, duration time.Duration)
+}
+
+// commandMethods change order state; retrying one without a request key could apply it twice
+var commandMethods = map[string]bool{
+	"CancelOrder": true,
+	"UpdateOrder": true,
+	"InitiateRTO": true,
+}
+
+// call runs one RPC with retries
+// Attempts stop after ORDER_SERVICE_MAX_RETRIES retries, on a non-retryable error, or when the next backoff
+// would outlive ctx's deadline (handlers derive it from the ONDC context.ttl). Commands are only
+// retried when ctx carries a request key, which is forwarded so the Order Service can dedupe them.
+func (c *Client) call(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+	requestKey := utils.RequestKeyFromContext(ctx)
+	if requestKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, requestKeyHeader, requestKey)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := c.attempt(ctx, method, fn)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= c.maxAttempts || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+		if commandMethods[method] && requestKey == "" {
+			c.logger.Debug("not retrying order service command without request key", zap.String("method", method))
+			return err
+		}
+
+		backoff := retryBackoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+			c.logger.Warn("request TTL too short to retry order service call",
+				zap.String("method", method),
+				zap.Int("attempt", attempt),
+			)
+			return err
+		}
+
+		c.logger.Info("order service call failed, retrying",
+			zap.String("method", method),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt runs one RPC with the configured deadline behind the circuit breaker and maps its status to a DomainError
+func (c *Client) attempt(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	var rpcErr error
+	err := c.circuitBreaker.Execute(ctx, func() error {
+		callCtx, cancel := context.WithTimeout(ctx, c.callTimeout())
+		defer cancel()
+
+		rpcErr = fn(callCtx)
+		if isServiceFailure(rpcErr) {
+			return rpcErr
+		}
+		return nil
+	})
+	if c.metrics != nil {
+		c.metrics.RecordGRPCCallDuration(metricsServiceName, method, time.Since(start))
+	}
+
+	if rpcErr == nil && err != nil {
+		c.logger.Warn("order service circuit open", zap.String("method", method))
+		return errors.WrapDomainError(err, 65011, "order service unavailable", "circuit breaker open").WithRetryable(true)
+	}
+	if rpcErr != nil {
+		c.logger.Warn("order service call failed", zap.String("method", method), zap.Error(rpcErr))
+		return mapStatusError(rpcErr)
+	}
+	return nil
+}
+
+// isRetryable reports whether another attempt may succeed
+// An open circuit is marked retryable for callers, but retrying it here would only be rejected again
+func isRetryable(err error) bool {
+	if stderrors.Is(err, circuitbreaker.ErrCircuitBreakerOpen) || stderrors.Is(err, circuitbreaker.ErrCircuitBreakerHalfOpen) {
+		return false
+	}
+
+	var domainErr *errors.DomainError
+	if stderrors.As(err, &domainErr) && domainErr.Retryable {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryBackoff doubles from baseRetryBackoff per attempt, capped at maxRetryBackoff
+// Half of the delay is randomized so callers failing together do not retry in lockstep
+func retryBackoff(attempt int) time.Duration {
+	backoff := maxRetryBackoff
+	if shift := attempt - 1; shift < 5 {
+		backoff = min(baseRetryBackoff<<shift, maxRetryBackoff)
+	}
+	half := backoff / 2
+	return half + rand.N(half+1)
+}
@@ -0,0 +1,144 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/clients/order/orderfake"
+	"uois-gateway/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockCallMetrics struct {
+	mock.Mock
+}
+
+func (m *MockCallMetrics) RecordGRPCCallDuration(service, method string, duration time.Duration) {
+	m.Called(service, method, duration)
+}
+
+func TestOrderServiceClient_RetriesTransientFailure(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.FailNext("GetOrder", 2, status.Error(codes.Unavailable, "order service restarting"))
+
+	order, err := client.GetOrder(context.Background(), "DISPATCH123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "DISPATCH123", order.DispatchOrderID)
+	assert.Equal(t, 3, fake.Calls("GetOrder"))
+}
+
+func TestOrderServiceClient_GivesUpAfterMaxRetries(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.FailNext("GetOrder", 4, status.Error(codes.Unavailable, "order service restarting"))
+
+	_, err := client.GetOrder(context.Background(), "DISPATCH123")
+
+	// MaxRetries 3: the first call plus three retries
+	assertDomainCode(t, err, 65011)
+	assert.Equal(t, 4, fake.Calls("GetOrder"))
+}
+
+func TestOrderServiceClient_ZeroMaxRetriesCallsOnce(t *testing.T) {
+	fake := orderfake.NewServer()
+	addr, err := fake.Start("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(fake.Stop)
+
+	cfg := testOrderConfig(t, addr)
+	cfg.MaxRetries = 0
+	client, err := NewClient(cfg, nil, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	fake.FailNext("GetOrder", 1, status.Error(codes.Unavailable, "order service restarting"))
+
+	_, err = client.GetOrder(context.Background(), "DISPATCH123")
+
+	assertDomainCode(t, err, 65011)
+	assert.Equal(t, 1, fake.Calls("GetOrder"))
+}
+
+func TestOrderServiceClient_DoesNotRetryBusinessErrors(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.SetError("GetOrder", status.Error(codes.NotFound, "unknown dispatch_order_id"))
+
+	_, err := client.GetOrder(context.Background(), "DISPATCH404")
+
+	assertDomainCode(t, err, 65006)
+	assert.Equal(t, 1, fake.Calls("GetOrder"))
+}
+
+func TestOrderServiceClient_CommandWithoutRequestKeyIsNotRetried(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.FailNext("CancelOrder", 1, status.Error(codes.Unavailable, "order service restarting"))
+
+	err := client.CancelOrder(context.Background(), "DISPATCH123", "001")
+
+	assertDomainCode(t, err, 65011)
+	assert.Equal(t, 1, fake.Calls("CancelOrder"))
+	assert.NotEqual(t, orderfake.StateCancelled, fake.Order("DISPATCH123").GetState())
+}
+
+func TestOrderServiceClient_CommandWithRequestKeyIsRetried(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.FailNext("CancelOrder", 1, status.Error(codes.Unavailable, "order service restarting"))
+	ctx := utils.WithRequestKey(context.Background(), "txn-1:msg-1")
+
+	err := client.CancelOrder(ctx, "DISPATCH123", "001")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.Calls("CancelOrder"))
+	assert.Equal(t, orderfake.StateCancelled, fake.Order("DISPATCH123").GetState())
+
+	// Replaying the same request key returns the original result instead of applying the cancel again
+	err = client.CancelOrder(ctx, "DISPATCH123", "001")
+	assert.NoError(t, err)
+}
+
+func TestOrderServiceClient_RetryBoundedByDeadline(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.SetError("GetOrder", status.Error(codes.Unavailable, "order service restarting"))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetOrder(ctx, "DISPATCH123")
+
+	assertDomainCode(t, err, 65011)
+	assert.Equal(t, 1, fake.Calls("GetOrder"))
+}
+
+func TestOrderServiceClient_RecordsEveryAttempt(t *testing.T) {
+	fake := orderfake.NewServer()
+	addr, err := fake.Start("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(fake.Stop)
+	fake.FailNext("GetOrderTracking", 1, status.Error(codes.Unavailable, "order service restarting"))
+
+	metrics := new(MockCallMetrics)
+	metrics.On("RecordGRPCCallDuration", "order_service", "GetOrderTracking", mock.AnythingOfType("time.Duration")).Return()
+
+	client, err := NewClient(testOrderConfig(t, addr), metrics, zap.NewNop())
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.GetOrderTracking(context.Background(), "DISPATCH123")
+
+	require.NoError(t, err)
+	metrics.AssertNumberOfCalls(t, "RecordGRPCCallDuration", 2)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	for attempt := 1; attempt <= 8; attempt++ {
+		ceiling := min(baseRetryBackoff<<min(attempt-1, 5), maxRetryBackoff)
+		backoff := retryBackoff(attempt)
+		assert.GreaterOrEqual(t, backoff, ceiling/2, "attempt %d", attempt)
+		assert.LessOrEqual(t, backoff, ceiling, "attempt %d", attempt)
+	}
+}
@@ -26,6 +26,26 @@ func (a *StreamConsumerAdapter) XAck(ctx context.Context, stream, group, id stri
 	return a.client.XAck(ctx, stream, group, id)
 }
 
+// XPending implements PendingEntriesClient interface
+func (a *StreamConsumerAdapter) XPending(ctx context.Context, stream, group string) *redis.XPendingCmd {
+	return a.client.XPending(ctx, stream, group)
+}
+
+// XPendingExt implements PendingEntriesClient interface
+func (a *StreamConsumerAdapter) XPendingExt(ctx context.Context, args *redis.XPendingExtArgs) *redis.XPendingExtCmd {
+	return a.client.XPendingExt(ctx, args)
+}
+
+// XAutoClaim implements PendingEntriesClient interface
+func (a *StreamConsumerAdapter) XAutoClaim(ctx context.Context, args *redis.XAutoClaimArgs) *redis.XAutoClaimCmd {
+	return a.client.XAutoClaim(ctx, args)
+}
+
+// XAdd implements PendingEntriesClient interface (dead-letter writes)
+func (a *StreamConsumerAdapter) XAdd(ctx context.Context, args *redis.XAddArgs) *redis.StringCmd {
+	return a.client.XAdd(ctx, args)
+}
+
 // ConsumerGroupAdapter adapts redis.Client to match ConsumerGroupClient interface
 type ConsumerGroupAdapter struct {
 	client *redis.Client
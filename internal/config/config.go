@@ -23,6 +23,8 @@ type Config struct {
 	Retry       RetryConfig
 	Callback    CallbackConfig
 	ONDC        ONDCConfig
+	Delivery    DeliveryPolicyConfig
+	Cancel      CancellationConfig
 	Zendesk     ZendeskConfig
 	Logging     LoggingConfig
 	Tracing     TracingConfig
@@ -35,6 +37,7 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
+	AdminAPIToken   string // Bearer token for /admin operator endpoints (empty disables them)
 }
 
 type PostgresConfig struct {
@@ -65,14 +68,16 @@ type OrderConfig struct {
 	GRPCHost    string
 	GRPCPort    int
 	GRPCTimeout time.Duration
-	MaxRetries  int
+	MaxRetries  int  // Retries after the first failed call (ORDER_SERVICE_MAX_RETRIES)
+	UseFake     bool // Serve the in-process fake Order Service on GRPCHost:GRPCPort (local runs only)
 }
 
 type AdminConfig struct {
-	GRPCHost    string
-	GRPCPort    int
-	GRPCTimeout time.Duration
-	MaxRetries  int
+	GRPCHost          string
+	GRPCPort          int
+	GRPCTimeout       time.Duration
+	MaxRetries        int
+	ReconcileInterval time.Duration // Period of the full client registry sync against Admin Service (0 syncs only at startup)
 }
 
 type StreamsConfig struct {
@@ -82,11 +87,22 @@ type StreamsConfig struct {
 	QuoteComputed      string
 	QuoteCreated       string
 	QuoteInvalidated   string
+	QuoteError         string // Quote Service QUOTE_ERROR, fails /search with an ONDC error
+	QuoteDifferential  string // Quote Service QUOTE_DIFFERENTIAL, drives weight/dimension differential /on_update
 	OrderConfirmed     string
 	OrderConfirmFailed string
+	OrderLifecycle     string // Order Service state transitions, drives unsolicited /on_status
+	RiderAssigned      string // DroneAI RIDER_ASSIGNED, drives unsolicited /on_update
+	RiderAssignFailed  string // DroneAI RIDER_ASSIGNMENT_FAILED, drives seller-side /on_cancel
 	ClientEvents       string
 	ConsumerGroupName  string
 	ConsumerID         string
+	DispatchBatchSize  int    // Max events per XREADGROUP in the stream dispatcher
+	EarlyBufferTTL     int    // Seconds to hold events that arrive before their request waits
+	ReclaimInterval    int    // Seconds between pending entries (PEL) reclaim passes
+	ReclaimMinIdle     int    // Seconds a pending entry must be idle before it is reclaimed
+	MaxDeliveries      int    // Deliveries before a pending entry is dead-lettered
+	DeadLetterStream   string // Stream receiving events that exceeded MaxDeliveries
 }
 
 type TTLConfig struct {
@@ -109,10 +125,17 @@ type RetryConfig struct {
 }
 
 type CallbackConfig struct {
-	HTTPTimeoutSeconds int
-	MaxConcurrent      int
-	DLQStream          string
-	DLQEnabled         bool
+	HTTPTimeoutSeconds   int
+	MaxConcurrent        int
+	DLQStream            string
+	DLQEnabled           bool
+	QueueStream          string   // Durable callback queue stream (empty delivers inline without a queue)
+	QueueClaimIdle       int      // Seconds before a queued callback held by a dead instance is claimed
+	BreakerThreshold     int      // Consecutive failures to one BAP host before its circuit opens
+	BreakerOpenSeconds   int      // Seconds a BAP circuit stays open before a probe callback is let through
+	BreakerMaxDefer      int      // Seconds a queued callback waits on an open circuit before it is dead-lettered (capped by the callback's TTL)
+	AllowedURIPrefixes   []string // bap_uri prefixes accepted for clients without a registered bap_uri
+	BlockPrivateNetworks bool     // Refuse callback connections to loopback, private and link-local addresses
 }
 
 type ONDCConfig struct {
@@ -121,10 +144,20 @@ type ONDCConfig struct {
 	Country            string // ONDC country (e.g., "IND")
 	CityCode           string // ONDC city code
 	NetworkRegistryURL string
-	RegistryCacheTTL   int // Registry cache TTL in seconds
+	RegistryLookupPath string // Registry lookup path (e.g., "/v2.0/lookup" or "/vlookup")
+	RegistryCacheTTL   int    // Registry cache TTL in seconds
+	RegistryMissTTL    int    // Negative cache TTL in seconds for unknown subscriber/ukId
+	RegistryTimeout    int    // Registry HTTP timeout in seconds
 	PrivateKeyPath     string
 	PublicKeyPath      string
+	KeyringDir         string // Directory of ukId-labelled signing keys (overrides the key paths)
 	TimestampWindow    int
+	SignatureTTL       int    // Outbound signature validity (expires - created) in seconds
+	EncPrivateKeyPath  string // X25519 encryption private key (registry /on_subscribe challenge)
+	RegistryEncKey     string // ONDC registry X25519 encryption public key (base64, raw or DER)
+	SubscribeReqID     string // request_id sent in /subscribe (signed into site verification page)
+	SubscribeUkID      string // keyring ukId registered by /subscribe (signs the site verification page; empty = current signing key)
+	SubscribePrefix    string // Route prefix /on_subscribe is served under (registered as the /subscribe callback_url)
 	SubscriberID       string
 	SubscriberURL      string // ONDC subscriber URL
 	UkID               string
@@ -135,6 +168,24 @@ type ONDCConfig struct {
 	BPPTermsURL        string // Static terms URL
 }
 
+// Delivery category policy sources
+const (
+	DeliveryPolicySourceDefault = "default" // Built-in rules: Immediate Delivery, Standard Delivery up to PT60M
+	DeliveryPolicySourceFile    = "file"    // JSON rules file (DELIVERY_POLICY_FILE)
+	DeliveryPolicySourceDB      = "db"      // ondc_reference.delivery_category_rules
+)
+
+type DeliveryPolicyConfig struct {
+	Source          string
+	File            string
+	RefreshInterval time.Duration // How often file/db rules are reloaded (0 loads only at startup)
+	Timezone        string        // Time zone of rule time-of-day windows
+}
+
+type CancellationConfig struct {
+	CatalogFile string // JSON reason catalog/cancellation terms (empty uses the built-in catalog)
+}
+
 type ZendeskConfig struct {
 	APIURL        string
 	APIEmail      string
@@ -192,6 +243,12 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("CALLBACK_BACKOFF", "1s,2s,4s,8s,15s") // Must sum to ≤ 30s
 	viper.SetDefault("CALLBACK_HTTP_TIMEOUT_SECONDS", 5)
 	viper.SetDefault("CALLBACK_MAX_CONCURRENT", 100)
+	viper.SetDefault("CALLBACK_QUEUE_STREAM", "stream.uois.callbacks")
+	viper.SetDefault("CALLBACK_QUEUE_CLAIM_IDLE_SECONDS", 120)
+	viper.SetDefault("CALLBACK_BREAKER_THRESHOLD", 5)
+	viper.SetDefault("CALLBACK_BREAKER_OPEN_SECONDS", 60)
+	viper.SetDefault("CALLBACK_BREAKER_MAX_DEFER_SECONDS", 900)
+	viper.SetDefault("CALLBACK_BLOCK_PRIVATE_NETWORKS", true)
 	viper.SetDefault("RATE_LIMIT_REQUESTS_PER_MINUTE", 60)
 	viper.SetDefault("RATE_LIMIT_BURST", 10)
 	viper.SetDefault("RATE_LIMIT_WINDOW_SECONDS", 60)
@@ -203,7 +260,19 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("ONDC_COUNTRY", "IND")
 	viper.SetDefault("REGISTRY_CACHE_TTL_SECONDS", 3600) // 1 hour
 	viper.SetDefault("REDIS_STREAM_BLOCK_MS", 5000)      // 5 seconds
+	viper.SetDefault("STREAM_DISPATCH_BATCH_SIZE", 50)
+	viper.SetDefault("STREAM_EARLY_BUFFER_SECONDS", 30)
+	viper.SetDefault("STREAM_RECLAIM_INTERVAL_SECONDS", 30)
+	viper.SetDefault("STREAM_RECLAIM_MIN_IDLE_SECONDS", 60)
+	viper.SetDefault("STREAM_MAX_DELIVERIES", 5)
+	viper.SetDefault("STREAM_DEAD_LETTER", "stream.uois.events_dlq")
 	viper.SetDefault("OTEL_SERVICE_NAME", "uois-gateway")
+	viper.SetDefault("REGISTRY_MISS_TTL_SECONDS", 60)
+	viper.SetDefault("REGISTRY_TIMEOUT_SECONDS", 5)
+	viper.SetDefault("ONDC_REGISTRY_LOOKUP_PATH", "/v2.0/lookup")
+	viper.SetDefault("ONDC_SIGNATURE_TTL_SECONDS", 3600)
+	viper.SetDefault("DELIVERY_POLICY_SOURCE", DeliveryPolicySourceDefault)
+	viper.SetDefault("DELIVERY_POLICY_TIMEZONE", "Asia/Kolkata")
 
 	readTimeout, err := parseDurationWithDefault(viper.GetString("SERVER_READ_TIMEOUT"), 10*time.Second)
 	if err != nil {
@@ -227,6 +296,7 @@ func LoadConfig() (*Config, error) {
 			ReadTimeout:     readTimeout,
 			WriteTimeout:    writeTimeout,
 			ShutdownTimeout: shutdownTimeout,
+			AdminAPIToken:   viper.GetString("ADMIN_API_TOKEN"),
 		},
 		PostgresE: func() PostgresConfig {
 			connMaxLifetime, _ := parseDurationWithDefault(viper.GetString("POSTGRES_E_CONNECTION_MAX_LIFETIME"), time.Hour)
@@ -263,6 +333,7 @@ func LoadConfig() (*Config, error) {
 				GRPCPort:    viper.GetInt("ORDER_SERVICE_GRPC_PORT"),
 				GRPCTimeout: orderTimeout,
 				MaxRetries:  viper.GetInt("ORDER_SERVICE_MAX_RETRIES"),
+				UseFake:     viper.GetBool("ORDER_SERVICE_FAKE"),
 			}
 		}(),
 		Admin: func() AdminConfig {
@@ -270,11 +341,16 @@ func LoadConfig() (*Config, error) {
 			if err != nil {
 				adminTimeout = 5 * time.Second
 			}
+			reconcileInterval, err := parseDurationWithDefault(viper.GetString("ADMIN_CLIENT_RECONCILE_INTERVAL"), 10*time.Minute)
+			if err != nil {
+				reconcileInterval = 10 * time.Minute
+			}
 			return AdminConfig{
-				GRPCHost:    viper.GetString("ADMIN_SERVICE_GRPC_HOST"),
-				GRPCPort:    viper.GetInt("ADMIN_SERVICE_GRPC_PORT"),
-				GRPCTimeout: adminTimeout,
-				MaxRetries:  viper.GetInt("ADMIN_SERVICE_MAX_RETRIES"),
+				GRPCHost:          viper.GetString("ADMIN_SERVICE_GRPC_HOST"),
+				GRPCPort:          viper.GetInt("ADMIN_SERVICE_GRPC_PORT"),
+				GRPCTimeout:       adminTimeout,
+				MaxRetries:        viper.GetInt("ADMIN_SERVICE_MAX_RETRIES"),
+				ReconcileInterval: reconcileInterval,
 			}
 		}(),
 		Streams: func() StreamsConfig {
@@ -289,11 +365,22 @@ func LoadConfig() (*Config, error) {
 				QuoteComputed:      viper.GetString("STREAM_QUOTE_COMPUTED"),
 				QuoteCreated:       viper.GetString("STREAM_QUOTE_CREATED"),
 				QuoteInvalidated:   viper.GetString("STREAM_QUOTE_INVALIDATED"),
+				QuoteError:         viper.GetString("STREAM_QUOTE_ERROR"),
+				QuoteDifferential:  viper.GetString("STREAM_QUOTE_DIFFERENTIAL"),
 				OrderConfirmed:     viper.GetString("STREAM_ORDER_CONFIRMED"),
 				OrderConfirmFailed: viper.GetString("STREAM_ORDER_CONFIRM_FAILED"),
+				OrderLifecycle:     viper.GetString("STREAM_ORDER_LIFECYCLE"),
+				RiderAssigned:      viper.GetString("STREAM_RIDER_ASSIGNED"),
+				RiderAssignFailed:  viper.GetString("STREAM_RIDER_ASSIGNMENT_FAILED"),
 				ClientEvents:       viper.GetString("STREAM_CLIENT_EVENTS"),
 				ConsumerGroupName:  viper.GetString("CONSUMER_GROUP_NAME"),
 				ConsumerID:         consumerID,
+				DispatchBatchSize:  viper.GetInt("STREAM_DISPATCH_BATCH_SIZE"),
+				EarlyBufferTTL:     viper.GetInt("STREAM_EARLY_BUFFER_SECONDS"),
+				ReclaimInterval:    viper.GetInt("STREAM_RECLAIM_INTERVAL_SECONDS"),
+				ReclaimMinIdle:     viper.GetInt("STREAM_RECLAIM_MIN_IDLE_SECONDS"),
+				MaxDeliveries:      viper.GetInt("STREAM_MAX_DELIVERIES"),
+				DeadLetterStream:   viper.GetString("STREAM_DEAD_LETTER"),
 			}
 		}(),
 		TTL: TTLConfig{
@@ -314,10 +401,17 @@ func LoadConfig() (*Config, error) {
 			EventPublishMaxRetries: viper.GetInt("EVENT_PUBLISH_MAX_RETRIES"),
 		},
 		Callback: CallbackConfig{
-			HTTPTimeoutSeconds: viper.GetInt("CALLBACK_HTTP_TIMEOUT_SECONDS"),
-			MaxConcurrent:      viper.GetInt("CALLBACK_MAX_CONCURRENT"),
-			DLQStream:          viper.GetString("CALLBACK_DLQ_STREAM"),
-			DLQEnabled:         viper.GetBool("CALLBACK_DLQ_ENABLED"),
+			HTTPTimeoutSeconds:   viper.GetInt("CALLBACK_HTTP_TIMEOUT_SECONDS"),
+			MaxConcurrent:        viper.GetInt("CALLBACK_MAX_CONCURRENT"),
+			DLQStream:            viper.GetString("CALLBACK_DLQ_STREAM"),
+			DLQEnabled:           viper.GetBool("CALLBACK_DLQ_ENABLED"),
+			QueueStream:          viper.GetString("CALLBACK_QUEUE_STREAM"),
+			QueueClaimIdle:       viper.GetInt("CALLBACK_QUEUE_CLAIM_IDLE_SECONDS"),
+			BreakerThreshold:     viper.GetInt("CALLBACK_BREAKER_THRESHOLD"),
+			BreakerOpenSeconds:   viper.GetInt("CALLBACK_BREAKER_OPEN_SECONDS"),
+			BreakerMaxDefer:      viper.GetInt("CALLBACK_BREAKER_MAX_DEFER_SECONDS"),
+			AllowedURIPrefixes:   parseList(viper.GetString("CALLBACK_ALLOWED_URI_PREFIXES")),
+			BlockPrivateNetworks: viper.GetBool("CALLBACK_BLOCK_PRIVATE_NETWORKS"),
 		},
 		ONDC: ONDCConfig{
 			Domain:             viper.GetString("ONDC_DOMAIN"),
@@ -325,10 +419,20 @@ func LoadConfig() (*Config, error) {
 			Country:            viper.GetString("ONDC_COUNTRY"),
 			CityCode:           viper.GetString("ONDC_CITY_CODE"),
 			NetworkRegistryURL: viper.GetString("ONDC_NETWORK_REGISTRY_URL"),
+			RegistryLookupPath: viper.GetString("ONDC_REGISTRY_LOOKUP_PATH"),
 			RegistryCacheTTL:   viper.GetInt("REGISTRY_CACHE_TTL_SECONDS"),
+			RegistryMissTTL:    viper.GetInt("REGISTRY_MISS_TTL_SECONDS"),
+			RegistryTimeout:    viper.GetInt("REGISTRY_TIMEOUT_SECONDS"),
 			PrivateKeyPath:     viper.GetString("ONDC_PRIVATE_KEY_PATH"),
 			PublicKeyPath:      viper.GetString("ONDC_PUBLIC_KEY_PATH"),
+			KeyringDir:         viper.GetString("ONDC_KEYRING_DIR"),
 			TimestampWindow:    viper.GetInt("ONDC_TIMESTAMP_WINDOW"),
+			SignatureTTL:       viper.GetInt("ONDC_SIGNATURE_TTL_SECONDS"),
+			EncPrivateKeyPath:  viper.GetString("ONDC_ENCRYPTION_PRIVATE_KEY_PATH"),
+			RegistryEncKey:     viper.GetString("ONDC_REGISTRY_ENCRYPTION_PUBLIC_KEY"),
+			SubscribeReqID:     viper.GetString("ONDC_SUBSCRIBE_REQUEST_ID"),
+			SubscribeUkID:      viper.GetString("ONDC_SUBSCRIBE_UK_ID"),
+			SubscribePrefix:    viper.GetString("ONDC_SUBSCRIBE_ROUTE_PREFIX"),
 			SubscriberID:       viper.GetString("ONDC_SUBSCRIBER_ID"),
 			SubscriberURL:      viper.GetString("ONDC_SUBSCRIBER_URL"),
 			UkID:               viper.GetString("ONDC_UK_ID"),
@@ -338,6 +442,21 @@ func LoadConfig() (*Config, error) {
 			BPPName:            viper.GetString("ONDC_BPP_NAME"),
 			BPPTermsURL:        viper.GetString("ONDC_BPP_TERMS_URL"),
 		},
+		Delivery: func() DeliveryPolicyConfig {
+			refreshInterval, err := parseDurationWithDefault(viper.GetString("DELIVERY_POLICY_REFRESH_INTERVAL"), time.Minute)
+			if err != nil {
+				refreshInterval = time.Minute
+			}
+			return DeliveryPolicyConfig{
+				Source:          viper.GetString("DELIVERY_POLICY_SOURCE"),
+				File:            viper.GetString("DELIVERY_POLICY_FILE"),
+				RefreshInterval: refreshInterval,
+				Timezone:        viper.GetString("DELIVERY_POLICY_TIMEZONE"),
+			}
+		}(),
+		Cancel: CancellationConfig{
+			CatalogFile: viper.GetString("CANCELLATION_CATALOG_FILE"),
+		},
 		Zendesk: ZendeskConfig{
 			APIURL:        viper.GetString("ZENDESK_API_URL"),
 			APIEmail:      viper.GetString("ZENDESK_API_EMAIL"),
@@ -401,6 +520,9 @@ func (c *Config) Validate() error {
 	if err := c.validateStreams(); err != nil {
 		return fmt.Errorf("streams config: %w", err)
 	}
+	if err := c.validateDeliveryPolicy(); err != nil {
+		return fmt.Errorf("delivery policy config: %w", err)
+	}
 	return nil
 }
 
@@ -450,6 +572,19 @@ func (c *Config) validateAdminService() error {
 	return nil
 }
 
+func (c *Config) validateDeliveryPolicy() error {
+	switch c.Delivery.Source {
+	case "", DeliveryPolicySourceDefault, DeliveryPolicySourceDB:
+	case DeliveryPolicySourceFile:
+		if c.Delivery.File == "" {
+			return fmt.Errorf("file is required for the file source")
+		}
+	default:
+		return fmt.Errorf("unknown source %q (expected default, file or db)", c.Delivery.Source)
+	}
+	return nil
+}
+
 func (c *Config) validateONDC() error {
 	if c.ONDC.PrivateKeyPath == "" {
 		return fmt.Errorf("private key path is required")
@@ -543,6 +678,17 @@ func (c *Config) validateZendesk() error {
 	return nil
 }
 
+// parseList splits a comma-separated setting, dropping empty entries
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func parseBackoffDurations(backoffStr string) []int {
 	if backoffStr == "" {
 		return []int{1, 2, 4, 8, 15}
@@ -820,3 +820,58 @@ func TestValidate_Zendesk_MissingWebhookSecret(t *testing.T) {
 	assert.Contains(t, err.Error(), "zendesk config")
 	assert.Contains(t, err.Error(), "webhook secret")
 }
+
+func TestValidate_DeliveryPolicy_FileSourceWithoutFile(t *testing.T) {
+	cfg := &Config{
+		PostgresE: PostgresConfig{
+			Host: "localhost",
+			Port: 5432,
+			User: "test_user",
+			DB:   "test_db",
+		},
+		Redis: RedisConfig{
+			Host: "localhost",
+			Port: 6379,
+		},
+		Order: OrderConfig{
+			GRPCHost: "localhost",
+			GRPCPort: 50051,
+		},
+		Admin: AdminConfig{
+			GRPCHost: "localhost",
+			GRPCPort: 50052,
+		},
+		ONDC: ONDCConfig{
+			PrivateKeyPath: "/test/private.pem",
+			PublicKeyPath:  "/test/public.pem",
+			SubscriberID:   "test-subscriber",
+			UkID:           "test-uk",
+			ProviderID:     "test-provider",
+			BPPID:          "test-bpp",
+			BPPURI:         "https://bpp.example.com",
+		},
+		TTL: TTLConfig{
+			ONDCRequestTTL: 30,
+		},
+		Retry: RetryConfig{
+			CallbackMaxRetries: 5,
+			CallbackBackoff:    []int{1, 2, 4, 8, 15},
+		},
+		Callback: CallbackConfig{
+			HTTPTimeoutSeconds: 5,
+			MaxConcurrent:      100,
+		},
+		Streams: StreamsConfig{
+			ConsumerID: "test-consumer-123",
+		},
+		Delivery: DeliveryPolicyConfig{
+			Source: DeliveryPolicySourceFile,
+		},
+	}
+
+	err := cfg.Validate()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "delivery policy config")
+	assert.Contains(t, err.Error(), "file is required")
+}
@@ -3,10 +3,14 @@ package client_events
 import (
 	"context"
 	"encoding/json"
+	"time"
 
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/consumers/event"
 	"uois-gateway/internal/models"
 	"uois-gateway/pkg/errors"
 
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
@@ -33,28 +37,130 @@ type ClientRegistryService interface {
 	UpdateStatus(ctx context.Context, clientID, status string) error
 }
 
+// EventIdempotencyService records applied client events (implemented by eventidempotency.Service)
+type EventIdempotencyService interface {
+	IsProcessed(ctx context.Context, eventID string) (bool, error)
+	CheckAndStore(ctx context.Context, eventID string) (bool, error)
+}
+
 // Consumer handles client events from Admin Service
+//
+// ACK Strategy:
+//   - Events are ACKed once applied to the registry, when already applied (same event_id), or when
+//     they can never be applied (undecodable, status change for a client unknown to the registry)
+//   - Events whose registry write fails stay pending; the Reclaimer re-delivers them
+//   - An event is marked processed only after it is applied, so a crash in between replays it;
+//     upserts and status changes are safe to replay
 type Consumer struct {
-	registry ClientRegistryService
-	logger   *zap.Logger
+	config      config.StreamsConfig
+	registry    ClientRegistryService
+	idempotency EventIdempotencyService
+	logger      *zap.Logger
+	reader      *event.StreamReader
 }
 
 // NewConsumer creates a new client event consumer
-func NewConsumer(registry ClientRegistryService, logger *zap.Logger) *Consumer {
-	return &Consumer{
-		registry: registry,
-		logger:   logger,
+// block is the XREADGROUP block duration (REDIS_STREAM_BLOCK_MS); idempotency may be nil
+func NewConsumer(rdb event.StreamConsumerClient, cfg config.StreamsConfig, block time.Duration, registry ClientRegistryService, idempotency EventIdempotencyService, logger *zap.Logger) *Consumer {
+	c := &Consumer{
+		config:      cfg,
+		registry:    registry,
+		idempotency: idempotency,
+		logger:      logger,
+	}
+	c.reader = event.NewStreamReader(rdb, cfg, block, "client event", c.handle, logger)
+	return c
+}
+
+// Run reads the client events stream until ctx is cancelled
+// It returns immediately when STREAM_CLIENT_EVENTS is not configured
+func (c *Consumer) Run(ctx context.Context) {
+	stream := c.config.ClientEvents
+	if stream == "" {
+		c.logger.Info("client events stream not configured, client registry relies on reconciliation")
+		return
+	}
+
+	c.reader.Run(ctx, stream)
+}
+
+// HandleMessage applies one client event and ACKs it unless the registry write should be retried
+// It matches event.MessageHandler so the Reclaimer can re-deliver pending entries
+func (c *Consumer) HandleMessage(ctx context.Context, stream string, msg redis.XMessage) {
+	c.reader.HandleMessage(ctx, stream, msg)
+}
+
+// handle returns an error only for failures worth retrying (registry writes)
+func (c *Consumer) handle(ctx context.Context, stream string, msg redis.XMessage) error {
+	dataStr, ok := msg.Values["data"].(string)
+	if !ok {
+		c.logger.Error("dropping client event with invalid data", zap.String("stream", stream), zap.String("id", msg.ID))
+		return nil
+	}
+
+	event, err := c.decode([]byte(dataStr))
+	if err != nil {
+		return nil
+	}
+
+	eventID := event.EventID
+	if eventID == "" {
+		eventID = stream + ":" + msg.ID
+	}
+
+	if c.idempotency != nil {
+		processed, err := c.idempotency.IsProcessed(ctx, eventID)
+		if err != nil {
+			c.logger.Warn("event idempotency check failed, applying anyway", zap.Error(err), zap.String("event_id", eventID))
+		} else if processed {
+			c.logger.Debug("client event already applied", zap.String("event_id", eventID), zap.String("event_type", event.EventType))
+			return nil
+		}
+	}
+
+	if err := c.apply(ctx, event); err != nil {
+		if domainErr, ok := err.(*errors.DomainError); ok && domainErr.Code == 65006 {
+			// Status change for a client the registry never saw; reconciliation picks it up
+			c.logger.Warn("client event for unknown client", zap.String("event_id", eventID), zap.String("client_id", event.ClientID))
+			return nil
+		}
+		return err
 	}
+
+	if c.idempotency != nil {
+		if _, err := c.idempotency.CheckAndStore(ctx, eventID); err != nil {
+			c.logger.Warn("failed to record applied client event", zap.Error(err), zap.String("event_id", eventID))
+		}
+	}
+
+	c.logger.Info("client event applied",
+		zap.String("event_id", eventID),
+		zap.String("event_type", event.EventType),
+		zap.String("client_id", event.ClientID),
+	)
+	return nil
 }
 
 // HandleClientEvent processes a client event
 func (c *Consumer) HandleClientEvent(ctx context.Context, eventData []byte) error {
+	event, err := c.decode(eventData)
+	if err != nil {
+		return err
+	}
+	return c.apply(ctx, event)
+}
+
+func (c *Consumer) decode(eventData []byte) (*ClientEvent, error) {
 	var event ClientEvent
 	if err := json.Unmarshal(eventData, &event); err != nil {
 		c.logger.Error("failed to unmarshal client event", zap.Error(err))
-		return errors.WrapDomainError(err, 65020, "client event parsing failed", "invalid JSON")
+		return nil, errors.WrapDomainError(err, 65020, "client event parsing failed", "invalid JSON")
 	}
+	return &event, nil
+}
 
+// apply writes one client event to the registry
+func (c *Consumer) apply(ctx context.Context, event *ClientEvent) error {
 	client := &models.Client{
 		ID:               event.ClientID,
 		ClientCode:       event.ClientCode,
@@ -85,7 +191,8 @@ func (c *Consumer) HandleClientEvent(ctx context.Context, eventData []byte) erro
 		return c.registry.UpsertClient(ctx, client)
 	case "client.suspended":
 		return c.registry.UpdateStatus(ctx, event.ClientID, models.ClientStatusSuspended)
-	case "client.revoked":
+	case "client.revoked", "client.deleted":
+		// Deleted clients are revoked, not removed, so order records keep resolving their client_id
 		return c.registry.UpdateStatus(ctx, event.ClientID, models.ClientStatusRevoked)
 	default:
 		c.logger.Warn("unknown client event type", zap.String("event_type", event.EventType))
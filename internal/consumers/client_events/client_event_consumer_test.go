@@ -0,0 +1,206 @@
+package client_events
+
+import (
+	"context"
+	"testing"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockStreamClient struct {
+	mock.Mock
+}
+
+func (m *mockStreamClient) XReadGroup(ctx context.Context, args *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+	called := m.Called(ctx, args)
+	cmd := redis.NewXStreamSliceCmd(ctx)
+	if streams := called.Get(0); streams != nil {
+		cmd.SetVal(streams.([]redis.XStream))
+	}
+	cmd.SetErr(called.Error(1))
+	return cmd
+}
+
+func (m *mockStreamClient) XAck(ctx context.Context, stream, group, id string) *redis.IntCmd {
+	args := m.Called(ctx, stream, group, id)
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(1)
+	cmd.SetErr(args.Error(0))
+	return cmd
+}
+
+type mockClientRegistry struct {
+	mock.Mock
+}
+
+func (m *mockClientRegistry) UpsertClient(ctx context.Context, client *models.Client) error {
+	args := m.Called(ctx, client)
+	return args.Error(0)
+}
+
+func (m *mockClientRegistry) UpdateStatus(ctx context.Context, clientID, status string) error {
+	args := m.Called(ctx, clientID, status)
+	return args.Error(0)
+}
+
+type mockEventIdempotency struct {
+	mock.Mock
+}
+
+func (m *mockEventIdempotency) IsProcessed(ctx context.Context, eventID string) (bool, error) {
+	args := m.Called(ctx, eventID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockEventIdempotency) CheckAndStore(ctx context.Context, eventID string) (bool, error) {
+	args := m.Called(ctx, eventID)
+	return args.Bool(0), args.Error(1)
+}
+
+const testStream = "stream:admin.client.events"
+
+func testConfig() config.StreamsConfig {
+	return config.StreamsConfig{
+		ClientEvents:      testStream,
+		ConsumerGroupName: "uois-gateway-group",
+		ConsumerID:        "test-consumer-1",
+	}
+}
+
+func clientEventMessage(id, data string) redis.XMessage {
+	return redis.XMessage{ID: id, Values: map[string]interface{}{"data": data}}
+}
+
+const createdEvent = `{
+	"event_type": "client.created",
+	"event_id": "evt-1",
+	"client_id": "client-1",
+	"client_code": "ABC",
+	"client_secret_hash": "hash",
+	"bap_id": "buyer.example.com",
+	"bap_uri": "https://buyer.example.com/ondc",
+	"status": "ACTIVE",
+	"timestamp": "2026-10-16T09:00:00Z"
+}`
+
+func TestConsumer_HandleMessage_AppliesAndAcks(t *testing.T) {
+	rdb := new(mockStreamClient)
+	registry := new(mockClientRegistry)
+	idempotency := new(mockEventIdempotency)
+
+	idempotency.On("IsProcessed", mock.Anything, "evt-1").Return(false, nil)
+	registry.On("UpsertClient", mock.Anything, mock.MatchedBy(func(client *models.Client) bool {
+		return client.ID == "client-1" && client.BapID() == "buyer.example.com" && client.BapURI() == "https://buyer.example.com/ondc"
+	})).Return(nil)
+	idempotency.On("CheckAndStore", mock.Anything, "evt-1").Return(false, nil)
+	rdb.On("XAck", mock.Anything, testStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer := NewConsumer(rdb, testConfig(), 0, registry, idempotency, zap.NewNop())
+	consumer.HandleMessage(context.Background(), testStream, clientEventMessage("1-0", createdEvent))
+
+	registry.AssertExpectations(t)
+	idempotency.AssertExpectations(t)
+	rdb.AssertExpectations(t)
+}
+
+func TestConsumer_HandleMessage_SkipsAppliedEvent(t *testing.T) {
+	rdb := new(mockStreamClient)
+	registry := new(mockClientRegistry)
+	idempotency := new(mockEventIdempotency)
+
+	idempotency.On("IsProcessed", mock.Anything, "evt-1").Return(true, nil)
+	rdb.On("XAck", mock.Anything, testStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer := NewConsumer(rdb, testConfig(), 0, registry, idempotency, zap.NewNop())
+	consumer.HandleMessage(context.Background(), testStream, clientEventMessage("1-0", createdEvent))
+
+	registry.AssertNotCalled(t, "UpsertClient", mock.Anything, mock.Anything)
+	rdb.AssertExpectations(t)
+}
+
+func TestConsumer_HandleMessage_RegistryFailureLeavesPending(t *testing.T) {
+	rdb := new(mockStreamClient)
+	registry := new(mockClientRegistry)
+	idempotency := new(mockEventIdempotency)
+
+	idempotency.On("IsProcessed", mock.Anything, "evt-1").Return(false, nil)
+	registry.On("UpsertClient", mock.Anything, mock.Anything).Return(errors.NewDomainError(65020, "client registry storage failed", "database error"))
+
+	consumer := NewConsumer(rdb, testConfig(), 0, registry, idempotency, zap.NewNop())
+	consumer.HandleMessage(context.Background(), testStream, clientEventMessage("1-0", createdEvent))
+
+	// Not marked processed and not ACKed, so the Reclaimer re-delivers it
+	idempotency.AssertNotCalled(t, "CheckAndStore", mock.Anything, mock.Anything)
+	rdb.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConsumer_HandleMessage_UnknownClientStatusChangeIsAcked(t *testing.T) {
+	rdb := new(mockStreamClient)
+	registry := new(mockClientRegistry)
+
+	registry.On("UpdateStatus", mock.Anything, "client-9", models.ClientStatusRevoked).Return(errors.NewDomainError(65006, "client not found", "client_id client-9 not found"))
+	rdb.On("XAck", mock.Anything, testStream, "uois-gateway-group", "2-0").Return(nil)
+
+	consumer := NewConsumer(rdb, testConfig(), 0, registry, nil, zap.NewNop())
+	consumer.HandleMessage(context.Background(), testStream, clientEventMessage("2-0", `{"event_type":"client.deleted","event_id":"evt-2","client_id":"client-9"}`))
+
+	rdb.AssertExpectations(t)
+}
+
+func TestConsumer_HandleMessage_DropsUndecodableEvent(t *testing.T) {
+	rdb := new(mockStreamClient)
+	registry := new(mockClientRegistry)
+	rdb.On("XAck", mock.Anything, testStream, "uois-gateway-group", "3-0").Return(nil)
+
+	consumer := NewConsumer(rdb, testConfig(), 0, registry, nil, zap.NewNop())
+	consumer.HandleMessage(context.Background(), testStream, clientEventMessage("3-0", "{not json"))
+
+	rdb.AssertExpectations(t)
+	registry.AssertNotCalled(t, "UpsertClient", mock.Anything, mock.Anything)
+}
+
+func TestConsumer_HandleClientEvent_StatusEvents(t *testing.T) {
+	registry := new(mockClientRegistry)
+	registry.On("UpdateStatus", mock.Anything, "client-1", models.ClientStatusSuspended).Return(nil)
+	registry.On("UpdateStatus", mock.Anything, "client-1", models.ClientStatusRevoked).Return(nil)
+
+	consumer := NewConsumer(nil, testConfig(), 0, registry, nil, zap.NewNop())
+
+	assert.NoError(t, consumer.HandleClientEvent(context.Background(), []byte(`{"event_type":"client.suspended","client_id":"client-1"}`)))
+	assert.NoError(t, consumer.HandleClientEvent(context.Background(), []byte(`{"event_type":"client.revoked","client_id":"client-1"}`)))
+	registry.AssertExpectations(t)
+}
+
+func TestConsumer_Run_ReadsUntilCancelled(t *testing.T) {
+	rdb := new(mockStreamClient)
+	registry := new(mockClientRegistry)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	registry.On("UpsertClient", mock.Anything, mock.Anything).Return(nil)
+	rdb.On("XReadGroup", mock.Anything, mock.Anything).Return([]redis.XStream{
+		{Stream: testStream, Messages: []redis.XMessage{clientEventMessage("1-0", createdEvent)}},
+	}, nil).Once()
+	rdb.On("XAck", mock.Anything, testStream, "uois-gateway-group", "1-0").Run(func(mock.Arguments) { cancel() }).Return(nil)
+
+	consumer := NewConsumer(rdb, testConfig(), 0, registry, nil, zap.NewNop())
+	consumer.Run(ctx)
+
+	registry.AssertExpectations(t)
+	rdb.AssertExpectations(t)
+}
+
+func TestConsumer_Run_NotConfigured(t *testing.T) {
+	rdb := new(mockStreamClient)
+	consumer := NewConsumer(rdb, config.StreamsConfig{}, 0, new(mockClientRegistry), nil, zap.NewNop())
+
+	consumer.Run(context.Background())
+
+	rdb.AssertNotCalled(t, "XReadGroup", mock.Anything, mock.Anything)
+}
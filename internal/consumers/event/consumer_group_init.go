@@ -15,7 +15,9 @@ type ConsumerGroupClient interface {
 	XGroupCreate(ctx context.Context, stream, group, start string, mkStream bool) *redis.StatusCmd
 }
 
-// InitializeConsumerGroups creates consumer groups for all configured streams
+// InitializeConsumerGroups creates consumer groups for all configured streams read through a group
+// Streams routed by the Dispatcher get this instance's own group (DispatchGroup), created at the stream
+// tail: earlier events belong to requests served before the instance first started
 func InitializeConsumerGroups(ctx context.Context, rdb ConsumerGroupClient, cfg config.StreamsConfig, logger *zap.Logger) error {
 	groupName := cfg.ConsumerGroupName
 	if groupName == "" {
@@ -23,31 +25,45 @@ func InitializeConsumerGroups(ctx context.Context, rdb ConsumerGroupClient, cfg
 	}
 
 	streams := []string{
-		cfg.QuoteComputed,
-		cfg.QuoteCreated,
-		cfg.QuoteInvalidated,
-		cfg.OrderConfirmed,
-		cfg.OrderConfirmFailed,
+		cfg.QuoteDifferential,
+		cfg.OrderLifecycle,
+		cfg.RiderAssigned,
+		cfg.RiderAssignFailed,
 		cfg.ClientEvents,
 	}
 
 	for _, stream := range streams {
-		if stream == "" {
-			continue
+		if err := createConsumerGroup(ctx, rdb, stream, groupName, "0", logger); err != nil {
+			return err
 		}
+	}
 
-		err := rdb.XGroupCreate(ctx, stream, groupName, "0", true).Err()
-		if err != nil {
-			if err.Error() == "BUSYGROUP Consumer Group name already exists" {
-				logger.Debug("consumer group already exists", zap.String("stream", stream), zap.String("group", groupName))
-				continue
-			}
-			logger.Error("failed to create consumer group", zap.Error(err), zap.String("stream", stream))
-			return fmt.Errorf("failed to create consumer group for stream %s: %w", stream, err)
+	dispatchGroup := DispatchGroup(cfg)
+	for _, stream := range DispatchedStreams(cfg) {
+		if err := createConsumerGroup(ctx, rdb, stream, dispatchGroup, "$", logger); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// createConsumerGroup creates group on stream starting at start; an existing group is left as is
+func createConsumerGroup(ctx context.Context, rdb ConsumerGroupClient, stream, group, start string, logger *zap.Logger) error {
+	if stream == "" {
+		return nil
+	}
 
-		logger.Info("consumer group created", zap.String("stream", stream), zap.String("group", groupName))
+	err := rdb.XGroupCreate(ctx, stream, group, start, true).Err()
+	if err != nil {
+		if err.Error() == "BUSYGROUP Consumer Group name already exists" {
+			logger.Debug("consumer group already exists", zap.String("stream", stream), zap.String("group", group))
+			return nil
+		}
+		logger.Error("failed to create consumer group", zap.Error(err), zap.String("stream", stream))
+		return fmt.Errorf("failed to create consumer group for stream %s: %w", stream, err)
 	}
 
+	logger.Info("consumer group created", zap.String("stream", stream), zap.String("group", group))
 	return nil
 }
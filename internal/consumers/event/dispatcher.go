@@ -0,0 +1,446 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDispatchBatchSize = 50
+	defaultDispatchBlock     = 5 * time.Second
+	defaultEarlyBufferTTL    = 30 * time.Second
+	// maxEarlyBufferedEvents bounds memory if events arrive for requests this instance never waits on
+	maxEarlyBufferedEvents = 10000
+	// dispatchErrorBackoff is the pause after a failed XREADGROUP before reading again
+	dispatchErrorBackoff = time.Second
+	// dispatchPendingID reads this instance's own pending entries (read before a restart, never ACKed)
+	dispatchPendingID = "0"
+)
+
+// EventMetrics records consumed events that are dropped
+type EventMetrics interface {
+	RecordEventRejected(stream, eventType, reason string)
+}
+
+// waiterKey identifies the waiters for a business ID on a stream
+type waiterKey struct {
+	stream     string
+	businessID string
+}
+
+// bufferedEvent is an event that arrived before any request waited for it
+// It is indexed under both its search_id and quote_id; claimed prevents delivering it twice
+type bufferedEvent struct {
+	event     interface{}
+	expiresAt time.Time
+	claimed   bool
+}
+
+// Dispatcher reads the consumed event streams in the background and routes events to waiting requests
+//
+// One goroutine per stream reads batches with XREADGROUP, hands each event to the in-process waiter
+// registered for its search_id/quote_id and ACKs it. Requests never read Redis themselves, so concurrent
+// requests cannot steal or strand each other's events (no head-of-line blocking).
+//
+// Events that arrive before their request starts waiting (e.g. Quote Service answers before the
+// handler reaches ConsumeEvent) are held in a short early buffer and handed over on registration.
+//
+// Fan-out: the request waiting for an event may be on any gateway instance, so each instance reads
+// through its own consumer group (see DispatchGroup) and sees every event; it keeps only the ones its
+// own requests wait for, unclaimed events expire from the early buffer. The group remembers the read
+// position, so events published while the instance starts, restarts or reconnects are read once it
+// is back, and entries read but not ACKed before a crash are re-read from its pending list on start.
+type Dispatcher struct {
+	rdb       StreamConsumerClient
+	config    config.StreamsConfig
+	group     string
+	logger    *zap.Logger
+	metrics   EventMetrics
+	legacy    *Consumer
+	streams   map[string]bool
+	batchSize int64
+	block     time.Duration
+	bufferTTL time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	waiters  map[waiterKey][]chan interface{}
+	buffer   map[waiterKey][]*bufferedEvent
+	buffered int
+}
+
+// NewDispatcher creates a dispatcher for the configured consumed streams
+// block is the XREADGROUP block duration (REDIS_STREAM_BLOCK_MS); metrics may be nil
+// eventIdempotencyService is only used by the direct-read fallback
+func NewDispatcher(rdb StreamConsumerClient, cfg config.StreamsConfig, block time.Duration, eventIdempotencyService EventIdempotencyService, metrics EventMetrics, logger *zap.Logger) *Dispatcher {
+	d := &Dispatcher{
+		rdb:       rdb,
+		config:    cfg,
+		group:     DispatchGroup(cfg),
+		logger:    logger,
+		metrics:   metrics,
+		legacy:    NewConsumerWithIdempotency(rdb, cfg, eventIdempotencyService, logger),
+		streams:   make(map[string]bool),
+		batchSize: int64(cfg.DispatchBatchSize),
+		block:     block,
+		bufferTTL: time.Duration(cfg.EarlyBufferTTL) * time.Second,
+		now:       time.Now,
+		waiters:   make(map[waiterKey][]chan interface{}),
+		buffer:    make(map[waiterKey][]*bufferedEvent),
+	}
+
+	if d.batchSize <= 0 {
+		d.batchSize = defaultDispatchBatchSize
+	}
+	if d.block <= 0 {
+		d.block = defaultDispatchBlock
+	}
+	if d.bufferTTL <= 0 {
+		d.bufferTTL = defaultEarlyBufferTTL
+	}
+
+	for _, stream := range DispatchedStreams(cfg) {
+		d.streams[stream] = true
+	}
+
+	return d
+}
+
+// DispatchedStreams returns the configured streams whose events are routed to waiting requests
+// Published streams and the client events stream (own consumer) are excluded
+func DispatchedStreams(cfg config.StreamsConfig) []string {
+	var streams []string
+	for _, stream := range []string{
+		cfg.QuoteComputed,
+		cfg.QuoteCreated,
+		cfg.QuoteInvalidated,
+		cfg.QuoteError,
+		cfg.OrderConfirmed,
+		cfg.OrderConfirmFailed,
+	} {
+		if stream != "" {
+			streams = append(streams, stream)
+		}
+	}
+	return streams
+}
+
+// DispatchGroup returns this instance's consumer group on the dispatched streams
+// Every instance has its own group so that every instance sees every event. CONSUMER_ID should be
+// stable across restarts (e.g. the pod name) for a restarted instance to resume its group.
+func DispatchGroup(cfg config.StreamsConfig) string {
+	return cfg.ConsumerGroupName + ":" + cfg.ConsumerID
+}
+
+// Handlers returns the message handler for each dispatched stream, used by the Reclaimer to
+// route reclaimed pending entries to waiting requests
+func (d *Dispatcher) Handlers() map[string]MessageHandler {
+	handlers := make(map[string]MessageHandler, len(d.streams))
+	for stream := range d.streams {
+		handlers[stream] = d.HandleMessage
+	}
+	return handlers
+}
+
+// Run reads all dispatched streams until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for stream := range d.streams {
+		wg.Add(1)
+		go func(stream string) {
+			defer wg.Done()
+			d.readStream(ctx, stream)
+		}(stream)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(d.bufferTTL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.pruneBuffer()
+			}
+		}
+	}()
+
+	d.logger.Info("event dispatcher started", zap.Int("streams", len(d.streams)), zap.String("group", d.group))
+	wg.Wait()
+	d.logger.Info("event dispatcher stopped")
+}
+
+// ConsumeEvent waits for the event matching businessID (search_id or quote_id) on stream
+// Keeps the EventConsumer contract: returns (nil, nil) if nothing arrives within timeout.
+// Dispatched streams are read through this instance's own group, so consumerGroup only applies to streams
+// the dispatcher does not read: those fall back to a direct per-request XREADGROUP with consumerGroup.
+func (d *Dispatcher) ConsumeEvent(ctx context.Context, stream, consumerGroup, businessID string, timeout time.Duration) (interface{}, error) {
+	if !d.streams[stream] {
+		d.logger.Warn("stream not dispatched, reading directly", zap.String("stream", stream))
+		return d.legacy.ConsumeEvent(ctx, stream, consumerGroup, businessID, timeout)
+	}
+
+	key := waiterKey{stream: stream, businessID: businessID}
+
+	d.mu.Lock()
+	if event, ok := d.takeBuffered(key); ok {
+		d.mu.Unlock()
+		return event, nil
+	}
+	ch := make(chan interface{}, 1)
+	d.waiters[key] = append(d.waiters[key], ch)
+	d.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case event := <-ch:
+		return event, nil
+	case <-timer.C:
+		return d.cancelWaiter(key, ch), nil
+	case <-ctx.Done():
+		if event := d.cancelWaiter(key, ch); event != nil {
+			return event, nil
+		}
+		return nil, errors.WrapDomainError(ctx.Err(), 65011, "event consumption failed", "request cancelled")
+	}
+}
+
+// cancelWaiter unregisters a waiter, returning an event delivered while it was giving up
+func (d *Dispatcher) cancelWaiter(key waiterKey, ch chan interface{}) interface{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	waiters := d.waiters[key]
+	for i, waiter := range waiters {
+		if waiter == ch {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(d.waiters, key)
+	} else {
+		d.waiters[key] = waiters
+	}
+
+	select {
+	case event := <-ch:
+		return event
+	default:
+		return nil
+	}
+}
+
+// readStream reads batches from one stream until ctx is cancelled
+// It starts with this instance's pending entries and switches to new entries once they are drained
+func (d *Dispatcher) readStream(ctx context.Context, stream string) {
+	args := &redis.XReadGroupArgs{
+		Group:    d.group,
+		Consumer: d.config.ConsumerID,
+		Streams:  []string{stream, dispatchPendingID},
+		Count:    d.batchSize,
+		Block:    d.block,
+	}
+
+	for ctx.Err() == nil {
+		pending := args.Streams[1] != ">"
+		streams, err := d.rdb.XReadGroup(ctx, args).Result()
+		if err == redis.Nil {
+			if pending {
+				args.Streams[1] = ">"
+			}
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			d.logger.Error("failed to read event stream", zap.Error(err), zap.String("stream", stream), zap.String("group", d.group))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(dispatchErrorBackoff):
+			}
+			continue
+		}
+
+		read := 0
+		for _, result := range streams {
+			for _, msg := range result.Messages {
+				d.HandleMessage(ctx, stream, msg)
+				read++
+				// Pending entries are re-read from after the last one, even if its ACK failed
+				if pending {
+					args.Streams[1] = msg.ID
+				}
+			}
+		}
+		if pending && read == 0 {
+			args.Streams[1] = ">"
+		}
+	}
+}
+
+// HandleMessage routes one message and ACKs it
+// Messages that cannot be decoded are ACKed and dropped so they do not stay pending
+func (d *Dispatcher) HandleMessage(ctx context.Context, stream string, msg redis.XMessage) {
+	d.handleMessage(stream, msg)
+
+	if err := d.rdb.XAck(ctx, stream, d.group, msg.ID).Err(); err != nil {
+		d.logger.Warn("failed to ack event", zap.Error(err), zap.String("stream", stream), zap.String("id", msg.ID))
+	}
+}
+
+// handleMessage routes one message; messages that cannot be decoded are dropped
+func (d *Dispatcher) handleMessage(stream string, msg redis.XMessage) {
+	dataStr, ok := msg.Values["data"].(string)
+	if !ok {
+		d.logger.Error("dropping event with invalid data", zap.String("stream", stream), zap.String("id", msg.ID))
+		return
+	}
+
+	var envelope EventEnvelope
+	if err := json.Unmarshal([]byte(dataStr), &envelope); err != nil {
+		d.logger.Error("dropping undecodable event", zap.Error(err), zap.String("stream", stream), zap.String("id", msg.ID))
+		return
+	}
+
+	// Decode into the typed event for its event_type (handlers type-assert e.g. *models.QuoteCreatedEvent)
+	event, err := models.DecodeEvent([]byte(dataStr))
+	if err != nil {
+		// Unknown event_type values come from the publisher, so they are not used as a metric label
+		reason, eventType := "invalid", envelope.EventType
+		if stderrors.Is(err, models.ErrUnknownEventType) {
+			reason, eventType = "unknown_type", "unknown"
+		}
+		if d.metrics != nil {
+			d.metrics.RecordEventRejected(stream, eventType, reason)
+		}
+		d.logger.Error("dropping event that failed decoding",
+			zap.Error(err),
+			zap.String("stream", stream),
+			zap.String("id", msg.ID),
+			zap.String("event_type", envelope.EventType),
+		)
+		return
+	}
+
+	d.dispatch(stream, &envelope, event)
+}
+
+// dispatch hands the event to the first waiter for its search_id, quote_id or an unfiltered waiter,
+// or buffers it for a request that has not started waiting yet
+func (d *Dispatcher) dispatch(stream string, envelope *EventEnvelope, event models.Event) {
+	var keys []waiterKey
+	if envelope.SearchID != "" {
+		keys = append(keys, waiterKey{stream: stream, businessID: envelope.SearchID})
+	}
+	if envelope.QuoteID != "" && envelope.QuoteID != envelope.SearchID {
+		keys = append(keys, waiterKey{stream: stream, businessID: envelope.QuoteID})
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range append(keys, waiterKey{stream: stream}) {
+		if waiters := d.waiters[key]; len(waiters) > 0 {
+			waiters[0] <- event
+			if len(waiters) == 1 {
+				delete(d.waiters, key)
+			} else {
+				d.waiters[key] = waiters[1:]
+			}
+			return
+		}
+	}
+
+	if len(keys) == 0 {
+		d.logger.Debug("event has no business ID and no waiter, dropping", zap.String("stream", stream))
+		return
+	}
+
+	if d.buffered >= maxEarlyBufferedEvents {
+		d.logger.Warn("early event buffer full, dropping event",
+			zap.String("stream", stream),
+			zap.String("search_id", envelope.SearchID),
+			zap.String("quote_id", envelope.QuoteID),
+		)
+		return
+	}
+
+	buffered := &bufferedEvent{event: event, expiresAt: d.now().Add(d.bufferTTL)}
+	for _, key := range keys {
+		d.buffer[key] = append(d.buffer[key], buffered)
+	}
+	d.buffered++
+}
+
+// takeBuffered claims the oldest unexpired buffered event for key (caller holds d.mu)
+// An unfiltered waiter (empty business ID) does not take buffered events
+func (d *Dispatcher) takeBuffered(key waiterKey) (interface{}, bool) {
+	entries := d.buffer[key]
+	now := d.now()
+	for i, entry := range entries {
+		if entry.claimed {
+			continue
+		}
+		if now.After(entry.expiresAt) {
+			entry.claimed = true
+			d.buffered--
+			continue
+		}
+		entry.claimed = true
+		d.buffered--
+		if remaining := entries[i+1:]; len(remaining) > 0 {
+			d.buffer[key] = remaining
+		} else {
+			delete(d.buffer, key)
+		}
+		return entry.event, true
+	}
+	return nil, false
+}
+
+// pruneBuffer drops expired and claimed early events
+func (d *Dispatcher) pruneBuffer() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	for key, entries := range d.buffer {
+		kept := entries[:0]
+		for _, entry := range entries {
+			if entry.claimed {
+				continue
+			}
+			if now.After(entry.expiresAt) {
+				// Count each event once, even though it may be indexed under two keys
+				entry.claimed = true
+				d.buffered--
+				d.logger.Debug("early event expired unclaimed", zap.String("stream", key.stream), zap.String("business_id", key.businessID))
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		if len(kept) == 0 {
+			delete(d.buffer, key)
+		} else {
+			d.buffer[key] = kept
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeStreamClient serves pending entries and queued batches per stream, recording read positions and ACKs
+type fakeStreamClient struct {
+	mu      sync.Mutex
+	pending map[string][]redis.XMessage
+	batches map[string]chan []redis.XMessage
+	readIDs map[string][]string
+	acked   map[string][]string
+	groups  []string
+}
+
+func newFakeStreamClient(streams ...string) *fakeStreamClient {
+	client := &fakeStreamClient{
+		pending: make(map[string][]redis.XMessage),
+		batches: make(map[string]chan []redis.XMessage),
+		readIDs: make(map[string][]string),
+		acked:   make(map[string][]string),
+	}
+	for _, stream := range streams {
+		client.batches[stream] = make(chan []redis.XMessage, 10)
+	}
+	return client
+}
+
+func (f *fakeStreamClient) XReadGroup(ctx context.Context, args *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+	stream, position := args.Streams[0], args.Streams[1]
+	f.mu.Lock()
+	f.readIDs[stream] = append(f.readIDs[stream], position)
+	f.groups = append(f.groups, args.Group)
+	var pending []redis.XMessage
+	if position != ">" {
+		for _, msg := range f.pending[stream] {
+			if msg.ID > position {
+				pending = append(pending, msg)
+			}
+		}
+	}
+	f.mu.Unlock()
+
+	cmd := redis.NewXStreamSliceCmd(ctx)
+	if position != ">" {
+		// A pending read returns immediately, with an empty result once drained
+		cmd.SetVal([]redis.XStream{{Stream: stream, Messages: pending}})
+		return cmd
+	}
+	select {
+	case messages := <-f.batches[stream]:
+		cmd.SetVal([]redis.XStream{{Stream: stream, Messages: messages}})
+	case <-time.After(20 * time.Millisecond):
+		cmd.SetErr(redis.Nil)
+	case <-ctx.Done():
+		cmd.SetErr(ctx.Err())
+	}
+	return cmd
+}
+
+func (f *fakeStreamClient) XAck(ctx context.Context, stream, group, id string) *redis.IntCmd {
+	f.mu.Lock()
+	f.acked[stream] = append(f.acked[stream], id)
+	f.groups = append(f.groups, group)
+	f.mu.Unlock()
+
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(1)
+	return cmd
+}
+
+func (f *fakeStreamClient) readPositions(stream string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.readIDs[stream]...)
+}
+
+func (f *fakeStreamClient) ackedIDs(stream string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.acked[stream]...)
+}
+
+func (f *fakeStreamClient) usedGroups() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.groups...)
+}
+
+func testStreamsConfig() config.StreamsConfig {
+	return config.StreamsConfig{
+		QuoteComputed:     "quote:computed",
+		QuoteCreated:      "stream.uois.quote_created",
+		OrderConfirmed:    "stream.uois.order_confirmed",
+		ConsumerGroupName: "uois-gateway-group",
+		ConsumerID:        "test-consumer-1",
+	}
+}
+
+// testMessage builds a stream message for a valid event; event_type defaults to QUOTE_COMPUTED
+func testMessage(id string, fields map[string]interface{}) redis.XMessage {
+	event := map[string]interface{}{
+		"event_type":  models.EventTypeQuoteComputed,
+		"event_id":    "event-" + id,
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-8f2a1b2c3d4e5f6a-01",
+		"timestamp":   "2025-01-01T00:00:00Z",
+	}
+	for key, value := range fields {
+		event[key] = value
+	}
+	data, _ := json.Marshal(event)
+	return redis.XMessage{ID: id, Values: map[string]interface{}{"data": string(data)}}
+}
+
+func TestDispatcher_ConsumeEvent_DeliversToWaiter(t *testing.T) {
+	client := newFakeStreamClient()
+	dispatcher := NewDispatcher(client, testStreamsConfig(), time.Second, nil, nil, zap.NewNop())
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		dispatcher.handleMessage("quote:computed", testMessage("1-0", map[string]interface{}{
+			"event_type": "QUOTE_COMPUTED",
+			"search_id":  "search-123",
+		}))
+	}()
+
+	event, err := dispatcher.ConsumeEvent(context.Background(), "quote:computed", "uois-gateway-group", "search-123", time.Second)
+
+	require.NoError(t, err)
+	assert.Equal(t, "search-123", event.(*models.QuoteComputedEvent).SearchID)
+}
+
+func TestDispatcher_ConsumeEvent_EarlyEventIsBuffered(t *testing.T) {
+	client := newFakeStreamClient()
+	dispatcher := NewDispatcher(client, testStreamsConfig(), time.Second, nil, nil, zap.NewNop())
+
+	dispatcher.handleMessage("stream.uois.order_confirmed", testMessage("1-0", map[string]interface{}{
+		"event_type":        "ORDER_CONFIRMED",
+		"quote_id":          "quote-123",
+		"dispatch_order_id": "dispatch-123",
+	}))
+
+	event, err := dispatcher.ConsumeEvent(context.Background(), "stream.uois.order_confirmed", "uois-gateway-group", "quote-123", 10*time.Millisecond)
+
+	require.NoError(t, err)
+	assert.Equal(t, "quote-123", event.(*models.OrderConfirmedEvent).QuoteID)
+	assert.Equal(t, 0, dispatcher.buffered)
+
+	// Delivered once only
+	event, err = dispatcher.ConsumeEvent(context.Background(), "stream.uois.order_confirmed", "uois-gateway-group", "quote-123", 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Nil(t, event)
+}
+
+func TestDispatcher_ConsumeEvent_BufferedUnderBothIDs(t *testing.T) {
+	client := newFakeStreamClient()
+	dispatcher := NewDispatcher(client, testStreamsConfig(), time.Second, nil, nil, zap.NewNop())
+
+	dispatcher.handleMessage("stream.uois.quote_created", testMessage("1-0", map[string]interface{}{
+		"event_type": "QUOTE_CREATED",
+		"search_id":  "search-123",
+		"quote_id":   "quote-123",
+	}))
+
+	event, err := dispatcher.ConsumeEvent(context.Background(), "stream.uois.quote_created", "uois-gateway-group", "quote-123", 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.NotNil(t, event)
+
+	event, err = dispatcher.ConsumeEvent(context.Background(), "stream.uois.quote_created", "uois-gateway-group", "search-123", 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Nil(t, event)
+}
+
+func TestDispatcher_ConsumeEvent_ConcurrentWaitersGetOwnEvents(t *testing.T) {
+	client := newFakeStreamClient()
+	dispatcher := NewDispatcher(client, testStreamsConfig(), time.Second, nil, nil, zap.NewNop())
+
+	const waiters = 20
+	results := make([]interface{}, waiters)
+	var wg sync.WaitGroup
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = dispatcher.ConsumeEvent(context.Background(), "quote:computed", "uois-gateway-group", fmt.Sprintf("search-%d", i), time.Second)
+		}(i)
+	}
+
+	// Events arrive in reverse order of the waiters
+	for i := waiters - 1; i >= 0; i-- {
+		dispatcher.handleMessage("quote:computed", testMessage(fmt.Sprintf("%d-0", i), map[string]interface{}{
+			"search_id": fmt.Sprintf("search-%d", i),
+		}))
+	}
+	wg.Wait()
+
+	for i := 0; i < waiters; i++ {
+		require.NotNil(t, results[i], "waiter %d", i)
+		assert.Equal(t, fmt.Sprintf("search-%d", i), results[i].(*models.QuoteComputedEvent).SearchID)
+	}
+}
+
+func TestDispatcher_ConsumeEvent_Timeout(t *testing.T) {
+	dispatcher := NewDispatcher(newFakeStreamClient(), testStreamsConfig(), time.Second, nil, nil, zap.NewNop())
+
+	event, err := dispatcher.ConsumeEvent(context.Background(), "quote:computed", "uois-gateway-group", "search-123", 10*time.Millisecond)
+
+	assert.NoError(t, err)
+	assert.Nil(t, event)
+	assert.Empty(t, dispatcher.waiters)
+}
+
+func TestDispatcher_ConsumeEvent_ContextCancelled(t *testing.T) {
+	dispatcher := NewDispatcher(newFakeStreamClient(), testStreamsConfig(), time.Second, nil, nil, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	event, err := dispatcher.ConsumeEvent(ctx, "quote:computed", "uois-gateway-group", "search-123", time.Second)
+
+	assert.Nil(t, event)
+	domainErr, ok := err.(*errors.DomainError)
+	require.True(t, ok)
+	assert.Equal(t, 65011, domainErr.Code)
+	assert.Empty(t, dispatcher.waiters)
+}
+
+func TestDispatcher_PruneBuffer_ExpiresEarlyEvents(t *testing.T) {
+	dispatcher := NewDispatcher(newFakeStreamClient(), testStreamsConfig(), time.Second, nil, nil, zap.NewNop())
+	now := time.Now()
+	dispatcher.now = func() time.Time { return now }
+
+	dispatcher.handleMessage("quote:computed", testMessage("1-0", map[string]interface{}{"search_id": "search-123"}))
+	require.Equal(t, 1, dispatcher.buffered)
+
+	dispatcher.now = func() time.Time { return now.Add(defaultEarlyBufferTTL + time.Second) }
+	dispatcher.pruneBuffer()
+
+	assert.Equal(t, 0, dispatcher.buffered)
+	assert.Empty(t, dispatcher.buffer)
+}
+
+func TestDispatcher_HandleMessage_InvalidDataIsDropped(t *testing.T) {
+	client := newFakeStreamClient()
+	dispatcher := NewDispatcher(client, testStreamsConfig(), time.Second, nil, nil, zap.NewNop())
+
+	dispatcher.HandleMessage(context.Background(), "quote:computed", redis.XMessage{ID: "1-0", Values: map[string]interface{}{"data": "invalid json {"}})
+	dispatcher.HandleMessage(context.Background(), "quote:computed", redis.XMessage{ID: "2-0", Values: map[string]interface{}{"data": 12345}})
+
+	assert.Equal(t, 0, dispatcher.buffered)
+	// Undecodable events are ACKed so they do not stay pending
+	assert.Equal(t, []string{"1-0", "2-0"}, client.ackedIDs("quote:computed"))
+}
+
+func TestDispatcher_Run_RecoversPendingEntries(t *testing.T) {
+	cfg := testStreamsConfig()
+	client := newFakeStreamClient(DispatchedStreams(cfg)...)
+	// Read by this instance before a restart but never ACKed
+	client.pending["stream.uois.quote_created"] = []redis.XMessage{
+		testMessage("5-0", map[string]interface{}{"event_type": "QUOTE_CREATED", "search_id": "search-5", "quote_id": "quote-5"}),
+	}
+	dispatcher := NewDispatcher(client, cfg, 10*time.Millisecond, nil, nil, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go dispatcher.Run(ctx)
+
+	event, err := dispatcher.ConsumeEvent(context.Background(), "stream.uois.quote_created", "uois-gateway-group", "search-5", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "quote-5", event.(*models.QuoteCreatedEvent).QuoteID)
+
+	require.Eventually(t, func() bool {
+		positions := client.readPositions("stream.uois.quote_created")
+		return len(positions) >= 3 && positions[2] == ">"
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"0", "5-0"}, client.readPositions("stream.uois.quote_created")[:2])
+	assert.Equal(t, []string{"5-0"}, client.ackedIDs("stream.uois.quote_created"))
+}
+
+func TestDispatcher_Handlers_CoverDispatchedStreams(t *testing.T) {
+	cfg := testStreamsConfig()
+	dispatcher := NewDispatcher(newFakeStreamClient(), cfg, time.Second, nil, nil, zap.NewNop())
+
+	handlers := dispatcher.Handlers()
+
+	assert.Len(t, handlers, len(DispatchedStreams(cfg)))
+	for _, stream := range DispatchedStreams(cfg) {
+		assert.Contains(t, handlers, stream)
+	}
+}
+
+func TestDispatcher_ConsumeEvent_UndispatchedStreamFallsBack(t *testing.T) {
+	mockRedis := new(MockRedisClient)
+	streamSliceCmd := redis.NewXStreamSliceCmd(context.Background())
+	streamSliceCmd.SetErr(redis.Nil)
+	mockRedis.On("XReadGroup", mock.Anything, mock.MatchedBy(func(args *redis.XReadGroupArgs) bool {
+		return args.Streams[0] == "stream.other" && args.Group == "uois-gateway-consumers"
+	})).Return(streamSliceCmd)
+	dispatcher := NewDispatcher(mockRedis, testStreamsConfig(), time.Second, nil, nil, zap.NewNop())
+
+	event, err := dispatcher.ConsumeEvent(context.Background(), "stream.other", "uois-gateway-consumers", "search-123", time.Second)
+
+	assert.NoError(t, err)
+	assert.Nil(t, event)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestDispatcher_Run_RoutesStreamEvents(t *testing.T) {
+	cfg := testStreamsConfig()
+	client := newFakeStreamClient(DispatchedStreams(cfg)...)
+	dispatcher := NewDispatcher(client, cfg, 10*time.Millisecond, nil, nil, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+
+	client.batches["stream.uois.quote_created"] <- []redis.XMessage{
+		testMessage("1-0", map[string]interface{}{"event_type": "QUOTE_CREATED", "search_id": "search-1", "quote_id": "quote-1"}),
+		testMessage("2-0", map[string]interface{}{"event_type": "QUOTE_CREATED", "search_id": "search-2", "quote_id": "quote-2"}),
+	}
+
+	second, err := dispatcher.ConsumeEvent(context.Background(), "stream.uois.quote_created", "uois-gateway-group", "search-2", time.Second)
+	require.NoError(t, err)
+	first, err := dispatcher.ConsumeEvent(context.Background(), "stream.uois.quote_created", "uois-gateway-group", "search-1", time.Second)
+	require.NoError(t, err)
+
+	assert.Equal(t, "search-1", first.(*models.QuoteCreatedEvent).SearchID)
+	assert.Equal(t, "search-2", second.(*models.QuoteCreatedEvent).SearchID)
+
+	// The instance reads its pending entries first, then new entries, and ACKs everything it routed
+	require.Eventually(t, func() bool {
+		return len(client.ackedIDs("stream.uois.quote_created")) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"1-0", "2-0"}, client.ackedIDs("stream.uois.quote_created"))
+	positions := client.readPositions("stream.uois.quote_created")
+	assert.Equal(t, []string{"0", ">"}, positions[:2])
+	for _, group := range client.usedGroups() {
+		assert.Equal(t, "uois-gateway-group:test-consumer-1", group)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatcher did not stop")
+	}
+}
+
+type mockEventMetrics struct {
+	mock.Mock
+}
+
+func (m *mockEventMetrics) RecordEventRejected(stream, eventType, reason string) {
+	m.Called(stream, eventType, reason)
+}
+
+func TestDispatcher_HandleMessage_RejectsUnknownAndInvalidEvents(t *testing.T) {
+	client := newFakeStreamClient()
+	metrics := new(mockEventMetrics)
+	metrics.On("RecordEventRejected", "quote:computed", "unknown", "unknown_type").Once()
+	metrics.On("RecordEventRejected", "quote:computed", "QUOTE_COMPUTED", "invalid").Once()
+	dispatcher := NewDispatcher(client, testStreamsConfig(), time.Second, nil, metrics, zap.NewNop())
+
+	dispatcher.handleMessage("quote:computed", testMessage("1-0", map[string]interface{}{"event_type": "SOMETHING_ELSE", "search_id": "search-123"}))
+	dispatcher.handleMessage("quote:computed", testMessage("2-0", map[string]interface{}{"event_id": ""}))
+
+	assert.Equal(t, 0, dispatcher.buffered)
+	metrics.AssertExpectations(t)
+}
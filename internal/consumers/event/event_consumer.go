@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
 	"uois-gateway/pkg/errors"
 
 	"github.com/redis/go-redis/v9"
@@ -55,6 +56,9 @@ type EventIdempotencyService interface {
 //
 // For stronger isolation, consider using Option A: stream keys with business ID
 // (e.g., "stream.uois.quote_created:<quote_id>") instead of filtering.
+//
+// Handlers use Dispatcher, which reads streams in the background; Consumer remains the
+// direct-read fallback for streams the dispatcher does not cover.
 type Consumer struct {
 	rdb                     StreamConsumerClient
 	config                  config.StreamsConfig
@@ -131,17 +135,18 @@ func (c *Consumer) ConsumeEvent(ctx context.Context, stream, consumerGroup, busi
 		return nil, nil
 	}
 
-	// Event matches business ID - unmarshal full event and ACK
-	var event interface{}
-	if err := json.Unmarshal([]byte(dataStr), &event); err != nil {
-		return nil, errors.WrapDomainError(err, 65020, "event deserialization failed", "failed to unmarshal event")
-	}
-
 	// Acknowledge the message only after business ID match confirmed
+	// An event that matches but fails decoding is still ours, so it is ACKed rather than left pending
 	if err := c.rdb.XAck(ctx, stream, consumerGroup, msg.ID).Err(); err != nil {
 		c.logger.Warn("failed to ack event", zap.Error(err), zap.String("stream", stream), zap.String("id", msg.ID))
 	}
 
+	// Decode into the typed event for its event_type
+	event, err := models.DecodeEvent([]byte(dataStr))
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "event deserialization failed", "failed to decode event")
+	}
+
 	return event, nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
 	"uois-gateway/pkg/errors"
 
 	"github.com/redis/go-redis/v9"
@@ -41,8 +42,11 @@ func TestEventConsumer_ConsumeEvent_Success(t *testing.T) {
 
 	// Create mock event data with matching business ID (search_id)
 	eventData := map[string]interface{}{
-		"event_type": "QUOTE_COMPUTED",
-		"search_id":  "search-123",
+		"event_type":  "QUOTE_COMPUTED",
+		"event_id":    "event-1",
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-8f2a1b2c3d4e5f6a-01",
+		"timestamp":   "2025-01-01T00:00:00Z",
+		"search_id":   "search-123",
 	}
 	eventJSON, _ := json.Marshal(eventData)
 
@@ -71,7 +75,7 @@ func TestEventConsumer_ConsumeEvent_Success(t *testing.T) {
 	event, err := consumer.ConsumeEvent(context.Background(), "test-stream", "test-group", "search-123", 5*time.Second)
 
 	assert.NoError(t, err)
-	assert.NotNil(t, event)
+	assert.IsType(t, &models.QuoteComputedEvent{}, event)
 	mockRedis.AssertExpectations(t)
 }
 
@@ -280,8 +284,12 @@ func TestEventConsumer_ConsumeEvent_QuoteIDCorrelation(t *testing.T) {
 
 	// Create mock event data with quote_id as business ID
 	eventData := map[string]interface{}{
-		"event_type": "ORDER_CONFIRMED",
-		"quote_id":   "quote-123",
+		"event_type":        "ORDER_CONFIRMED",
+		"event_id":          "event-1",
+		"traceparent":       "00-4bf92f3577b34da6a3ce929d0e0e4736-8f2a1b2c3d4e5f6a-01",
+		"timestamp":         "2025-01-01T00:00:00Z",
+		"quote_id":          "quote-123",
+		"dispatch_order_id": "dispatch-123",
 	}
 	eventJSON, _ := json.Marshal(eventData)
 
@@ -310,6 +318,43 @@ func TestEventConsumer_ConsumeEvent_QuoteIDCorrelation(t *testing.T) {
 	event, err := consumer.ConsumeEvent(context.Background(), "test-stream", "test-group", "quote-123", 5*time.Second)
 
 	assert.NoError(t, err)
-	assert.NotNil(t, event)
+	assert.IsType(t, &models.OrderConfirmedEvent{}, event)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestEventConsumer_ConsumeEvent_InvalidTypedEvent(t *testing.T) {
+	logger := zap.NewNop()
+	mockRedis := new(MockRedisClient)
+
+	consumer := NewConsumer(mockRedis, config.StreamsConfig{ConsumerID: "test-consumer-1"}, logger)
+
+	// Matches the business ID but is missing required event fields
+	eventJSON, _ := json.Marshal(map[string]interface{}{
+		"event_type": "ORDER_CONFIRMED",
+		"quote_id":   "quote-123",
+	})
+
+	streamSliceCmd := redis.NewXStreamSliceCmd(context.Background())
+	streamSliceCmd.SetVal([]redis.XStream{
+		{
+			Stream: "test-stream",
+			Messages: []redis.XMessage{
+				{ID: "1234567890-0", Values: map[string]interface{}{"data": string(eventJSON)}},
+			},
+		},
+	})
+	mockRedis.On("XReadGroup", mock.Anything, mock.Anything).Return(streamSliceCmd)
+
+	intCmd := redis.NewIntCmd(context.Background())
+	intCmd.SetVal(1)
+	mockRedis.On("XAck", mock.Anything, "test-stream", "test-group", "1234567890-0").Return(intCmd)
+
+	event, err := consumer.ConsumeEvent(context.Background(), "test-stream", "test-group", "quote-123", 5*time.Second)
+
+	assert.Error(t, err)
+	assert.Nil(t, event)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65020, domainErr.Code)
 	mockRedis.AssertExpectations(t)
 }
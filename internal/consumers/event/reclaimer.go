@@ -0,0 +1,299 @@
+package event
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"uois-gateway/internal/config"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultReclaimInterval = 30 * time.Second
+	defaultReclaimMinIdle  = time.Minute
+	defaultMaxDeliveries   = 5
+	// reclaimBatchSize bounds each XPENDING/XAUTOCLAIM page
+	reclaimBatchSize = 100
+)
+
+// PendingEntriesClient interface for Redis consumer group PEL operations
+type PendingEntriesClient interface {
+	XPending(ctx context.Context, stream, group string) *redis.XPendingCmd
+	XPendingExt(ctx context.Context, args *redis.XPendingExtArgs) *redis.XPendingExtCmd
+	XAutoClaim(ctx context.Context, args *redis.XAutoClaimArgs) *redis.XAutoClaimCmd
+	XAck(ctx context.Context, stream, group, id string) *redis.IntCmd
+	XAdd(ctx context.Context, args *redis.XAddArgs) *redis.StringCmd
+}
+
+// ReclaimMetrics records consumer group PEL health
+type ReclaimMetrics interface {
+	SetStreamPendingEntries(stream string, count float64)
+	SetStreamPendingOldestIdle(stream string, idle time.Duration)
+	RecordStreamDeadLettered(stream string)
+}
+
+// MessageHandler processes a reclaimed message and ACKs it
+type MessageHandler func(ctx context.Context, stream string, msg redis.XMessage)
+
+// Reclaimer recovers events that were read but never ACKed
+//
+// Entries stay in the consumer group's pending entries list (PEL) when an instance dies between
+// XREADGROUP and XACK, or when the direct-read Consumer skips a non-matching event. Every interval
+// the reclaimer:
+//   - exports PEL size and oldest-entry idle age per stream
+//   - XAUTOCLAIMs entries idle longer than MinIdle and hands them to the stream's handler
+//     (the dispatcher or the stream's consumer, which processes and ACKs them)
+//   - moves entries delivered more than MaxDeliveries times to the dead-letter stream and ACKs them
+//
+// Dispatched streams are reclaimed in this instance's dispatch group (DispatchGroup), all other
+// streams in the shared ConsumerGroupName group.
+type Reclaimer struct {
+	rdb           PendingEntriesClient
+	config        config.StreamsConfig
+	handlers      map[string]MessageHandler
+	dispatched    map[string]bool
+	metrics       ReclaimMetrics
+	logger        *zap.Logger
+	interval      time.Duration
+	minIdle       time.Duration
+	maxDeliveries int64
+}
+
+// NewReclaimer creates a reclaimer for the consumed streams
+// Streams with a handler are reclaimed; PEL metrics are exported for all consumed streams. metrics may be nil.
+func NewReclaimer(rdb PendingEntriesClient, cfg config.StreamsConfig, handlers map[string]MessageHandler, metrics ReclaimMetrics, logger *zap.Logger) *Reclaimer {
+	r := &Reclaimer{
+		rdb:           rdb,
+		config:        cfg,
+		handlers:      handlers,
+		dispatched:    make(map[string]bool),
+		metrics:       metrics,
+		logger:        logger,
+		interval:      time.Duration(cfg.ReclaimInterval) * time.Second,
+		minIdle:       time.Duration(cfg.ReclaimMinIdle) * time.Second,
+		maxDeliveries: int64(cfg.MaxDeliveries),
+	}
+
+	if r.interval <= 0 {
+		r.interval = defaultReclaimInterval
+	}
+	if r.minIdle <= 0 {
+		r.minIdle = defaultReclaimMinIdle
+	}
+	if r.maxDeliveries <= 0 {
+		r.maxDeliveries = defaultMaxDeliveries
+	}
+
+	for _, stream := range DispatchedStreams(cfg) {
+		r.dispatched[stream] = true
+	}
+
+	return r
+}
+
+// ConsumedStreams returns every configured stream the gateway reads as a consumer group member
+func ConsumedStreams(cfg config.StreamsConfig) []string {
+	streams := DispatchedStreams(cfg)
+	for _, stream := range []string{cfg.OrderLifecycle, cfg.RiderAssigned, cfg.RiderAssignFailed, cfg.QuoteDifferential} {
+		if stream != "" {
+			streams = append(streams, stream)
+		}
+	}
+	if cfg.ClientEvents != "" {
+		streams = append(streams, cfg.ClientEvents)
+	}
+	return streams
+}
+
+// Run reclaims pending entries every interval until ctx is cancelled
+func (r *Reclaimer) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.logger.Info("pending entries reclaimer started",
+		zap.Duration("interval", r.interval),
+		zap.Duration("min_idle", r.minIdle),
+		zap.Int64("max_deliveries", r.maxDeliveries),
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.ReclaimOnce(ctx)
+		}
+	}
+}
+
+// ReclaimOnce runs a single pass over all consumed streams
+func (r *Reclaimer) ReclaimOnce(ctx context.Context) {
+	for _, stream := range ConsumedStreams(r.config) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		group := r.groupFor(stream)
+		r.observePending(ctx, stream, group)
+
+		if handler, ok := r.handlers[stream]; ok {
+			r.reclaimStream(ctx, stream, group, handler)
+		}
+	}
+}
+
+// groupFor returns the consumer group stream is read through
+func (r *Reclaimer) groupFor(stream string) string {
+	if r.dispatched[stream] {
+		return DispatchGroup(r.config)
+	}
+	return r.config.ConsumerGroupName
+}
+
+// observePending exports PEL size and the idle age of the oldest pending entry
+func (r *Reclaimer) observePending(ctx context.Context, stream, group string) {
+	summary, err := r.rdb.XPending(ctx, stream, group).Result()
+	if err != nil {
+		if err != redis.Nil {
+			r.logger.Warn("failed to read pending entries summary", zap.Error(err), zap.String("stream", stream))
+		}
+		return
+	}
+
+	// XPENDING pages are ordered by entry ID, not idle time: a re-delivered entry has its idle time
+	// reset, so the oldest ID is not necessarily the longest-waiting one
+	var oldestIdle time.Duration
+	if summary.Count > 0 {
+		pending, err := r.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: stream,
+			Group:  group,
+			Start:  "-",
+			End:    "+",
+			Count:  reclaimBatchSize,
+		}).Result()
+		if err != nil {
+			r.logger.Warn("failed to read pending entries", zap.Error(err), zap.String("stream", stream))
+		}
+		for _, entry := range pending {
+			if entry.Idle > oldestIdle {
+				oldestIdle = entry.Idle
+			}
+		}
+	}
+
+	if r.metrics != nil {
+		r.metrics.SetStreamPendingEntries(stream, float64(summary.Count))
+		r.metrics.SetStreamPendingOldestIdle(stream, oldestIdle)
+	}
+}
+
+// reclaimStream claims idle entries page by page and re-dispatches or dead-letters them
+func (r *Reclaimer) reclaimStream(ctx context.Context, stream, group string, handler MessageHandler) {
+	start := "0-0"
+	for {
+		messages, next, err := r.rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    group,
+			Consumer: r.config.ConsumerID,
+			MinIdle:  r.minIdle,
+			Start:    start,
+			Count:    reclaimBatchSize,
+		}).Result()
+		if err != nil {
+			r.logger.Warn("failed to claim pending entries", zap.Error(err), zap.String("stream", stream))
+			return
+		}
+
+		for _, msg := range messages {
+			// The count includes this claim; entries no longer pending were ACKed elsewhere meanwhile
+			deliveries, ok := r.deliveryCount(ctx, stream, group, msg.ID)
+			if !ok {
+				continue
+			}
+			if deliveries > r.maxDeliveries {
+				r.deadLetter(ctx, stream, group, msg, deliveries-1)
+				continue
+			}
+
+			r.logger.Info("re-dispatching pending event",
+				zap.String("stream", stream),
+				zap.String("id", msg.ID),
+				zap.Int64("deliveries", deliveries),
+			)
+			handler(ctx, stream, msg)
+		}
+
+		if next == "" || next == "0-0" || ctx.Err() != nil {
+			return
+		}
+		start = next
+	}
+}
+
+// deliveryCount returns the delivery count of a single pending entry
+// ok is false when the entry is no longer pending or the count cannot be read; the entry is then
+// left alone rather than treated as never delivered
+func (r *Reclaimer) deliveryCount(ctx context.Context, stream, group, id string) (int64, bool) {
+	entries, err := r.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil {
+		r.logger.Warn("failed to read delivery count of claimed entry", zap.Error(err), zap.String("stream", stream), zap.String("id", id))
+		return 0, false
+	}
+	if len(entries) == 0 || entries[0].ID != id {
+		r.logger.Debug("claimed entry is no longer pending", zap.String("stream", stream), zap.String("id", id))
+		return 0, false
+	}
+	return entries[0].RetryCount, true
+}
+
+// deadLetter moves an entry that keeps failing to the dead-letter stream and ACKs it
+// The entry stays pending (and is retried next pass) if the dead-letter write fails
+func (r *Reclaimer) deadLetter(ctx context.Context, stream, group string, msg redis.XMessage, deliveries int64) {
+	if r.config.DeadLetterStream == "" {
+		r.logger.Error("event exceeded max deliveries but no dead-letter stream is configured",
+			zap.String("stream", stream),
+			zap.String("id", msg.ID),
+		)
+		return
+	}
+
+	values := map[string]interface{}{
+		"source_stream":    stream,
+		"source_id":        msg.ID,
+		"deliveries":       strconv.FormatInt(deliveries, 10),
+		"reason":           "max_deliveries_exceeded",
+		"dead_lettered_at": time.Now().UTC().Format(time.RFC3339),
+	}
+	if data, ok := msg.Values["data"]; ok {
+		values["data"] = data
+	}
+
+	if err := r.rdb.XAdd(ctx, &redis.XAddArgs{Stream: r.config.DeadLetterStream, Values: values}).Err(); err != nil {
+		r.logger.Error("failed to dead-letter event", zap.Error(err), zap.String("stream", stream), zap.String("id", msg.ID))
+		return
+	}
+
+	if err := r.rdb.XAck(ctx, stream, group, msg.ID).Err(); err != nil {
+		r.logger.Warn("failed to ack dead-lettered event", zap.Error(err), zap.String("stream", stream), zap.String("id", msg.ID))
+	}
+
+	if r.metrics != nil {
+		r.metrics.RecordStreamDeadLettered(stream)
+	}
+
+	r.logger.Warn("event moved to dead-letter stream",
+		zap.String("stream", stream),
+		zap.String("id", msg.ID),
+		zap.Int64("deliveries", deliveries),
+		zap.String("dlq_stream", r.config.DeadLetterStream),
+	)
+}
@@ -0,0 +1,284 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockPendingEntriesClient struct {
+	mock.Mock
+}
+
+func (m *mockPendingEntriesClient) XPending(ctx context.Context, stream, group string) *redis.XPendingCmd {
+	args := m.Called(ctx, stream, group)
+	cmd := redis.NewXPendingCmd(ctx)
+	if pending := args.Get(0); pending != nil {
+		cmd.SetVal(pending.(*redis.XPending))
+	}
+	cmd.SetErr(args.Error(1))
+	return cmd
+}
+
+func (m *mockPendingEntriesClient) XPendingExt(ctx context.Context, a *redis.XPendingExtArgs) *redis.XPendingExtCmd {
+	args := m.Called(ctx, a)
+	cmd := redis.NewXPendingExtCmd(ctx)
+	cmd.SetVal(args.Get(0).([]redis.XPendingExt))
+	cmd.SetErr(args.Error(1))
+	return cmd
+}
+
+func (m *mockPendingEntriesClient) XAutoClaim(ctx context.Context, a *redis.XAutoClaimArgs) *redis.XAutoClaimCmd {
+	args := m.Called(ctx, a)
+	cmd := redis.NewXAutoClaimCmd(ctx)
+	cmd.SetVal(args.Get(0).([]redis.XMessage), args.String(1))
+	cmd.SetErr(args.Error(2))
+	return cmd
+}
+
+func (m *mockPendingEntriesClient) XAck(ctx context.Context, stream, group, id string) *redis.IntCmd {
+	args := m.Called(ctx, stream, group, id)
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(1)
+	cmd.SetErr(args.Error(0))
+	return cmd
+}
+
+func (m *mockPendingEntriesClient) XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd {
+	args := m.Called(ctx, a)
+	cmd := redis.NewStringCmd(ctx)
+	cmd.SetVal("2-0")
+	cmd.SetErr(args.Error(0))
+	return cmd
+}
+
+type mockReclaimMetrics struct {
+	mock.Mock
+}
+
+func (m *mockReclaimMetrics) SetStreamPendingEntries(stream string, count float64) {
+	m.Called(stream, count)
+}
+
+func (m *mockReclaimMetrics) SetStreamPendingOldestIdle(stream string, idle time.Duration) {
+	m.Called(stream, idle)
+}
+
+func (m *mockReclaimMetrics) RecordStreamDeadLettered(stream string) {
+	m.Called(stream)
+}
+
+// newTestReclaimer reclaims only stream.order.lifecycle so expectations stay focused
+func newTestReclaimer(client *mockPendingEntriesClient, handlers map[string]MessageHandler, metrics ReclaimMetrics) *Reclaimer {
+	cfg := config.StreamsConfig{
+		OrderLifecycle:    "stream.order.lifecycle",
+		ConsumerGroupName: "uois-gateway-group",
+		ConsumerID:        "test-consumer-1",
+	}
+	cfg.MaxDeliveries = 3
+	cfg.ReclaimMinIdle = 60
+	cfg.DeadLetterStream = "stream.uois.events_dlq"
+	return NewReclaimer(client, cfg, handlers, metrics, zap.NewNop())
+}
+
+// expectClaim sets up one XAUTOCLAIM page for stream.order.lifecycle and the post-claim delivery
+// count of each claimed entry
+func expectClaim(client *mockPendingEntriesClient, deliveries map[string]int64, claimed []redis.XMessage) {
+	client.On("XAutoClaim", mock.Anything, mock.MatchedBy(func(a *redis.XAutoClaimArgs) bool {
+		return a.Stream == "stream.order.lifecycle" && a.Consumer == "test-consumer-1" && a.MinIdle == time.Minute && a.Start == "0-0"
+	})).Return(claimed, "0-0", nil)
+	for _, msg := range claimed {
+		id := msg.ID
+		var pending []redis.XPendingExt
+		if count, ok := deliveries[id]; ok {
+			pending = []redis.XPendingExt{{ID: id, RetryCount: count}}
+		}
+		client.On("XPendingExt", mock.Anything, mock.MatchedBy(func(a *redis.XPendingExtArgs) bool {
+			return a.Stream == "stream.order.lifecycle" && a.Start == id && a.End == id
+		})).Return(pending, nil)
+	}
+}
+
+func TestReclaimer_ReclaimOnce_ExportsPendingMetrics(t *testing.T) {
+	client := new(mockPendingEntriesClient)
+	metrics := new(mockReclaimMetrics)
+	reclaimer := newTestReclaimer(client, nil, metrics)
+
+	client.On("XPending", mock.Anything, "stream.order.lifecycle", "uois-gateway-group").Return(&redis.XPending{Count: 7}, nil)
+	client.On("XPendingExt", mock.Anything, mock.MatchedBy(func(a *redis.XPendingExtArgs) bool {
+		return a.Start == "-" && a.Count == reclaimBatchSize
+	})).Return([]redis.XPendingExt{
+		{ID: "1-0", Idle: 30 * time.Second, RetryCount: 2},
+		{ID: "2-0", Idle: 90 * time.Second, RetryCount: 1},
+	}, nil)
+	metrics.On("SetStreamPendingEntries", "stream.order.lifecycle", float64(7)).Return()
+	metrics.On("SetStreamPendingOldestIdle", "stream.order.lifecycle", 90*time.Second).Return()
+
+	reclaimer.ReclaimOnce(context.Background())
+
+	metrics.AssertExpectations(t)
+	client.AssertNotCalled(t, "XAutoClaim", mock.Anything, mock.Anything)
+}
+
+func TestReclaimer_ReclaimOnce_RedispatchesIdleEntries(t *testing.T) {
+	client := new(mockPendingEntriesClient)
+	var handled []string
+	handlers := map[string]MessageHandler{
+		"stream.order.lifecycle": func(ctx context.Context, stream string, msg redis.XMessage) {
+			handled = append(handled, stream+"/"+msg.ID)
+		},
+	}
+	reclaimer := newTestReclaimer(client, handlers, nil)
+
+	client.On("XPending", mock.Anything, "stream.order.lifecycle", "uois-gateway-group").Return(&redis.XPending{}, nil)
+	expectClaim(client,
+		map[string]int64{"1-0": 2, "2-0": 3},
+		[]redis.XMessage{testMessage("1-0", nil), testMessage("2-0", nil)},
+	)
+
+	reclaimer.ReclaimOnce(context.Background())
+
+	assert.Equal(t, []string{"stream.order.lifecycle/1-0", "stream.order.lifecycle/2-0"}, handled)
+	client.AssertNotCalled(t, "XAdd", mock.Anything, mock.Anything)
+}
+
+func TestReclaimer_ReclaimOnce_SkipsClaimedEntriesNoLongerPending(t *testing.T) {
+	client := new(mockPendingEntriesClient)
+	var handled []string
+	handlers := map[string]MessageHandler{
+		"stream.order.lifecycle": func(ctx context.Context, stream string, msg redis.XMessage) {
+			handled = append(handled, msg.ID)
+		},
+	}
+	reclaimer := newTestReclaimer(client, handlers, nil)
+
+	client.On("XPending", mock.Anything, "stream.order.lifecycle", "uois-gateway-group").Return(&redis.XPending{}, nil)
+	// 1-0 was ACKed between XAUTOCLAIM and the delivery count lookup
+	expectClaim(client, map[string]int64{"2-0": 2}, []redis.XMessage{testMessage("1-0", nil), testMessage("2-0", nil)})
+
+	reclaimer.ReclaimOnce(context.Background())
+
+	assert.Equal(t, []string{"2-0"}, handled)
+	client.AssertNotCalled(t, "XAdd", mock.Anything, mock.Anything)
+}
+
+func TestReclaimer_ReclaimOnce_DeadLettersAfterMaxDeliveries(t *testing.T) {
+	client := new(mockPendingEntriesClient)
+	metrics := new(mockReclaimMetrics)
+	var handled []string
+	handlers := map[string]MessageHandler{
+		"stream.order.lifecycle": func(ctx context.Context, stream string, msg redis.XMessage) {
+			handled = append(handled, msg.ID)
+		},
+	}
+	reclaimer := newTestReclaimer(client, handlers, metrics)
+	poison := testMessage("1-0", nil)
+
+	client.On("XPending", mock.Anything, "stream.order.lifecycle", "uois-gateway-group").Return(&redis.XPending{}, nil)
+	expectClaim(client, map[string]int64{"1-0": 4}, []redis.XMessage{poison})
+	client.On("XAdd", mock.Anything, mock.MatchedBy(func(a *redis.XAddArgs) bool {
+		values := a.Values.(map[string]interface{})
+		return a.Stream == "stream.uois.events_dlq" &&
+			values["source_stream"] == "stream.order.lifecycle" &&
+			values["source_id"] == "1-0" &&
+			values["deliveries"] == "3" &&
+			values["data"] == poison.Values["data"]
+	})).Return(nil)
+	client.On("XAck", mock.Anything, "stream.order.lifecycle", "uois-gateway-group", "1-0").Return(nil)
+	metrics.On("SetStreamPendingEntries", "stream.order.lifecycle", float64(0)).Return()
+	metrics.On("SetStreamPendingOldestIdle", "stream.order.lifecycle", time.Duration(0)).Return()
+	metrics.On("RecordStreamDeadLettered", "stream.order.lifecycle").Return()
+
+	reclaimer.ReclaimOnce(context.Background())
+
+	assert.Empty(t, handled)
+	client.AssertExpectations(t)
+	metrics.AssertExpectations(t)
+}
+
+func TestReclaimer_ReclaimOnce_KeepsPendingWithoutDeadLetterStream(t *testing.T) {
+	client := new(mockPendingEntriesClient)
+	handlers := map[string]MessageHandler{
+		"stream.order.lifecycle": func(ctx context.Context, stream string, msg redis.XMessage) {
+			t.Fatalf("unexpected re-dispatch of %s", msg.ID)
+		},
+	}
+	reclaimer := newTestReclaimer(client, handlers, nil)
+	reclaimer.config.DeadLetterStream = ""
+
+	client.On("XPending", mock.Anything, "stream.order.lifecycle", "uois-gateway-group").Return(&redis.XPending{}, nil)
+	expectClaim(client, map[string]int64{"1-0": 6}, []redis.XMessage{testMessage("1-0", nil)})
+
+	reclaimer.ReclaimOnce(context.Background())
+
+	client.AssertNotCalled(t, "XAdd", mock.Anything, mock.Anything)
+	client.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReclaimer_ReclaimOnce_FailedDeadLetterStaysPending(t *testing.T) {
+	client := new(mockPendingEntriesClient)
+	handlers := map[string]MessageHandler{
+		"stream.order.lifecycle": func(ctx context.Context, stream string, msg redis.XMessage) {},
+	}
+	reclaimer := newTestReclaimer(client, handlers, nil)
+
+	client.On("XPending", mock.Anything, "stream.order.lifecycle", "uois-gateway-group").Return(&redis.XPending{}, nil)
+	expectClaim(client, map[string]int64{"1-0": 5}, []redis.XMessage{testMessage("1-0", nil)})
+	client.On("XAdd", mock.Anything, mock.Anything).Return(assert.AnError)
+
+	reclaimer.ReclaimOnce(context.Background())
+
+	client.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNewReclaimer_Defaults(t *testing.T) {
+	reclaimer := NewReclaimer(new(mockPendingEntriesClient), testStreamsConfig(), nil, nil, zap.NewNop())
+
+	assert.Equal(t, defaultReclaimInterval, reclaimer.interval)
+	assert.Equal(t, defaultReclaimMinIdle, reclaimer.minIdle)
+	assert.Equal(t, int64(defaultMaxDeliveries), reclaimer.maxDeliveries)
+}
+
+func TestReclaimer_ReclaimOnce_DispatchedStreamUsesDispatchGroup(t *testing.T) {
+	client := new(mockPendingEntriesClient)
+	var handled []string
+	handlers := map[string]MessageHandler{
+		"stream.uois.quote_created": func(ctx context.Context, stream string, msg redis.XMessage) {
+			handled = append(handled, msg.ID)
+		},
+	}
+	cfg := config.StreamsConfig{
+		QuoteCreated:      "stream.uois.quote_created",
+		ConsumerGroupName: "uois-gateway-group",
+		ConsumerID:        "test-consumer-1",
+		ReclaimMinIdle:    60,
+	}
+	reclaimer := NewReclaimer(client, cfg, handlers, nil, zap.NewNop())
+
+	client.On("XPending", mock.Anything, "stream.uois.quote_created", "uois-gateway-group:test-consumer-1").Return(&redis.XPending{}, nil)
+	client.On("XAutoClaim", mock.Anything, mock.MatchedBy(func(a *redis.XAutoClaimArgs) bool {
+		return a.Stream == "stream.uois.quote_created" && a.Group == "uois-gateway-group:test-consumer-1" && a.Consumer == "test-consumer-1"
+	})).Return([]redis.XMessage{testMessage("1-0", nil)}, "0-0", nil)
+	client.On("XPendingExt", mock.Anything, mock.MatchedBy(func(a *redis.XPendingExtArgs) bool {
+		return a.Group == "uois-gateway-group:test-consumer-1" && a.Start == "1-0"
+	})).Return([]redis.XPendingExt{{ID: "1-0", RetryCount: 2}}, nil)
+
+	reclaimer.ReclaimOnce(context.Background())
+
+	assert.Equal(t, []string{"1-0"}, handled)
+	client.AssertExpectations(t)
+}
+
+func TestConsumedStreams_IncludeDispatchedStreams(t *testing.T) {
+	cfg := testStreamsConfig()
+	cfg.OrderLifecycle = "stream.order.lifecycle"
+
+	assert.Equal(t, []string{"quote:computed", "stream.uois.quote_created", "stream.uois.order_confirmed", "stream.order.lifecycle"}, ConsumedStreams(cfg))
+}
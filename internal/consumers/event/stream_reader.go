@@ -0,0 +1,122 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"uois-gateway/internal/config"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultReadBlock = 5 * time.Second
+	readBatchSize    = 50
+	// readErrorBackoff is the pause after a failed XREADGROUP before reading again
+	readErrorBackoff = time.Second
+)
+
+// StreamHandler processes one stream entry
+// It returns an error only for failures worth retrying; the entry then stays pending
+type StreamHandler func(ctx context.Context, stream string, msg redis.XMessage) error
+
+// StreamReader reads streams as a member of the gateway's consumer group and hands each entry to handle
+//
+// ACK Strategy:
+//   - Entries are ACKed once handle returns nil (processed, or dropped as never processable)
+//   - Entries whose handler fails stay pending; the Reclaimer re-delivers them through HandleMessage
+type StreamReader struct {
+	rdb    StreamConsumerClient
+	config config.StreamsConfig
+	name   string
+	handle StreamHandler
+	logger *zap.Logger
+	block  time.Duration
+}
+
+// NewStreamReader creates a consumer group reader
+// name labels log lines (e.g. "order lifecycle"); block is the XREADGROUP block duration (REDIS_STREAM_BLOCK_MS)
+func NewStreamReader(rdb StreamConsumerClient, cfg config.StreamsConfig, block time.Duration, name string, handle StreamHandler, logger *zap.Logger) *StreamReader {
+	if block <= 0 {
+		block = defaultReadBlock
+	}
+
+	return &StreamReader{
+		rdb:    rdb,
+		config: cfg,
+		name:   name,
+		handle: handle,
+		logger: logger,
+		block:  block,
+	}
+}
+
+// Run reads every stream until ctx is cancelled, one reader per stream
+func (r *StreamReader) Run(ctx context.Context, streams ...string) {
+	var wg sync.WaitGroup
+	for _, stream := range streams {
+		wg.Add(1)
+		go func(stream string) {
+			defer wg.Done()
+			r.readStream(ctx, stream)
+		}(stream)
+	}
+
+	r.logger.Info(r.name+" consumer started", zap.Strings("streams", streams))
+	wg.Wait()
+}
+
+// readStream reads batches from one stream until ctx is cancelled
+func (r *StreamReader) readStream(ctx context.Context, stream string) {
+	args := &redis.XReadGroupArgs{
+		Group:    r.config.ConsumerGroupName,
+		Consumer: r.config.ConsumerID,
+		Streams:  []string{stream, ">"},
+		Count:    readBatchSize,
+		Block:    r.block,
+	}
+
+	for ctx.Err() == nil {
+		streams, err := r.rdb.XReadGroup(ctx, args).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.Error("failed to read "+r.name+" stream", zap.Error(err), zap.String("stream", stream))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(readErrorBackoff):
+			}
+			continue
+		}
+
+		for _, result := range streams {
+			for _, msg := range result.Messages {
+				r.HandleMessage(ctx, stream, msg)
+			}
+		}
+	}
+}
+
+// HandleMessage processes one entry and ACKs it unless it should be retried
+// It matches MessageHandler so the Reclaimer can re-deliver pending entries
+func (r *StreamReader) HandleMessage(ctx context.Context, stream string, msg redis.XMessage) {
+	if err := r.handle(ctx, stream, msg); err != nil {
+		r.logger.Warn(r.name+" event left pending for retry",
+			zap.Error(err),
+			zap.String("stream", stream),
+			zap.String("id", msg.ID),
+		)
+		return
+	}
+
+	if err := r.rdb.XAck(ctx, stream, r.config.ConsumerGroupName, msg.ID).Err(); err != nil {
+		r.logger.Warn("failed to ack "+r.name+" event", zap.Error(err), zap.String("stream", stream), zap.String("id", msg.ID))
+	}
+}
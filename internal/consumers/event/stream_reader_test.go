@@ -0,0 +1,89 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func streamReaderConfig() config.StreamsConfig {
+	return config.StreamsConfig{ConsumerGroupName: "uois-gateway-group", ConsumerID: "test-consumer-1"}
+}
+
+func TestStreamReader_HandleMessage_AcksHandledEntries(t *testing.T) {
+	rdb := new(MockRedisClient)
+	reader := NewStreamReader(rdb, streamReaderConfig(), time.Second, "test", func(ctx context.Context, stream string, msg redis.XMessage) error {
+		return nil
+	}, zap.NewNop())
+
+	rdb.On("XAck", mock.Anything, "stream.test", "uois-gateway-group", "1-0").Return(redis.NewIntCmd(context.Background()))
+
+	reader.HandleMessage(context.Background(), "stream.test", redis.XMessage{ID: "1-0"})
+
+	rdb.AssertExpectations(t)
+}
+
+func TestStreamReader_HandleMessage_FailedEntriesStayPending(t *testing.T) {
+	rdb := new(MockRedisClient)
+	reader := NewStreamReader(rdb, streamReaderConfig(), time.Second, "test", func(ctx context.Context, stream string, msg redis.XMessage) error {
+		return assert.AnError
+	}, zap.NewNop())
+
+	reader.HandleMessage(context.Background(), "stream.test", redis.XMessage{ID: "1-0"})
+
+	rdb.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStreamReader_Run_ReadsEachStreamAsGroupMember(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rdb := new(MockRedisClient)
+	handled := make(chan string, 2)
+	reader := NewStreamReader(rdb, streamReaderConfig(), 0, "test", func(ctx context.Context, stream string, msg redis.XMessage) error {
+		handled <- stream + "/" + msg.ID
+		return nil
+	}, zap.NewNop())
+
+	// Each stream's second read waits for the other stream's first batch before stopping the reader
+	var firstBatches sync.WaitGroup
+	firstBatches.Add(2)
+	for _, stream := range []string{"stream.a", "stream.b"} {
+		stream := stream
+		batch := redis.NewXStreamSliceCmd(ctx)
+		batch.SetVal([]redis.XStream{{Stream: stream, Messages: []redis.XMessage{{ID: "1-0"}}}})
+		rdb.On("XReadGroup", mock.Anything, mock.MatchedBy(func(args *redis.XReadGroupArgs) bool {
+			return args.Streams[0] == stream && args.Streams[1] == ">" &&
+				args.Group == "uois-gateway-group" && args.Consumer == "test-consumer-1" && args.Block == defaultReadBlock
+		})).Return(batch).Once()
+
+		done := redis.NewXStreamSliceCmd(ctx)
+		done.SetErr(context.Canceled)
+		rdb.On("XReadGroup", mock.Anything, mock.MatchedBy(func(args *redis.XReadGroupArgs) bool {
+			return args.Streams[0] == stream
+		})).Run(func(mock.Arguments) {
+			firstBatches.Done()
+			firstBatches.Wait()
+			cancel()
+		}).Return(done)
+
+		rdb.On("XAck", mock.Anything, stream, "uois-gateway-group", "1-0").Return(redis.NewIntCmd(ctx))
+	}
+
+	reader.Run(ctx, "stream.a", "stream.b")
+
+	close(handled)
+	var got []string
+	for entry := range handled {
+		got = append(got, entry)
+	}
+	assert.ElementsMatch(t, []string{"stream.a/1-0", "stream.b/1-0"}, got)
+}
@@ -0,0 +1,164 @@
+package order_lifecycle
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/consumers/event"
+	"uois-gateway/internal/handlers/ondc"
+	"uois-gateway/internal/models"
+	"uois-gateway/internal/utils"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// OrderRecordLookup resolves an Order Service dispatch_order_id to the gateway's order record
+type OrderRecordLookup interface {
+	GetOrderRecordByDispatchOrderID(ctx context.Context, dispatchOrderID string) (*ondc.OrderRecord, error)
+}
+
+// StatusNotifier sends unsolicited /on_status callbacks (implemented by ondc.StatusHandler)
+type StatusNotifier interface {
+	SendUnsolicitedStatus(ctx context.Context, orderRecord *ondc.OrderRecord, orderStatus *ondc.OrderStatus, traceID string) error
+}
+
+// EventMetrics records consumed events that are dropped
+type EventMetrics interface {
+	RecordEventRejected(stream, eventType, reason string)
+}
+
+// Consumer turns order.lifecycle state transitions into unsolicited /on_status callbacks
+//
+// ACK Strategy:
+//   - Events are ACKed once the callback is delivered, or when they can never be delivered
+//     (undecodable, state not reported to the BAP, order unknown to this gateway)
+//   - Events whose callback fails stay pending; the Reclaimer re-delivers them and moves them to
+//     the dead-letter stream after STREAM_MAX_DELIVERIES attempts
+type Consumer struct {
+	config       config.StreamsConfig
+	orderRecords OrderRecordLookup
+	notifier     StatusNotifier
+	metrics      EventMetrics
+	logger       *zap.Logger
+	reader       *event.StreamReader
+}
+
+// NewConsumer creates a new order lifecycle consumer
+// block is the XREADGROUP block duration (REDIS_STREAM_BLOCK_MS); metrics may be nil
+func NewConsumer(rdb event.StreamConsumerClient, cfg config.StreamsConfig, block time.Duration, orderRecords OrderRecordLookup, notifier StatusNotifier, metrics EventMetrics, logger *zap.Logger) *Consumer {
+	c := &Consumer{
+		config:       cfg,
+		orderRecords: orderRecords,
+		notifier:     notifier,
+		metrics:      metrics,
+		logger:       logger,
+	}
+	c.reader = event.NewStreamReader(rdb, cfg, block, "order lifecycle", c.handle, logger)
+	return c
+}
+
+// Run reads the order lifecycle stream until ctx is cancelled
+// It returns immediately when STREAM_ORDER_LIFECYCLE is not configured
+func (c *Consumer) Run(ctx context.Context) {
+	stream := c.config.OrderLifecycle
+	if stream == "" {
+		c.logger.Info("order lifecycle stream not configured, unsolicited /on_status disabled")
+		return
+	}
+
+	c.reader.Run(ctx, stream)
+}
+
+// HandleMessage processes one lifecycle event and ACKs it unless the callback should be retried
+// It matches event.MessageHandler so the Reclaimer can re-deliver pending entries
+func (c *Consumer) HandleMessage(ctx context.Context, stream string, msg redis.XMessage) {
+	c.reader.HandleMessage(ctx, stream, msg)
+}
+
+// handle returns an error only for failures worth retrying (Redis errors, callback delivery)
+func (c *Consumer) handle(ctx context.Context, stream string, msg redis.XMessage) error {
+	dataStr, ok := msg.Values["data"].(string)
+	if !ok {
+		c.logger.Error("dropping order lifecycle event with invalid data", zap.String("stream", stream), zap.String("id", msg.ID))
+		return nil
+	}
+
+	decoded, err := models.DecodeEvent([]byte(dataStr))
+	if err != nil {
+		c.reject(stream, msg.ID, err)
+		return nil
+	}
+
+	event, ok := decoded.(*models.OrderLifecycleEvent)
+	if !ok {
+		c.reject(stream, msg.ID, stderrors.New("unexpected event type on order lifecycle stream"))
+		return nil
+	}
+
+	traceID := utils.ExtractTraceID(event.Traceparent)
+
+	stateCode, ok := ondc.FulfillmentStateCode(event.State)
+	if !ok {
+		c.logger.Debug("order state not reported to BAP",
+			zap.String("trace_id", traceID),
+			zap.String("dispatch_order_id", event.DispatchOrderID),
+			zap.String("state", event.State),
+		)
+		return nil
+	}
+
+	orderRecord, err := c.orderRecords.GetOrderRecordByDispatchOrderID(ctx, event.DispatchOrderID)
+	if err != nil {
+		if domainErr, ok := err.(*errors.DomainError); ok && domainErr.Code == 65006 {
+			// Orders created outside ONDC (or expired records) have no BAP to notify
+			c.logger.Debug("no order record for lifecycle event", zap.String("trace_id", traceID), zap.String("dispatch_order_id", event.DispatchOrderID))
+			return nil
+		}
+		return err
+	}
+
+	orderStatus := &ondc.OrderStatus{
+		DispatchOrderID: event.DispatchOrderID,
+		State:           event.State,
+		RiderID:         event.RiderID,
+		Fulfillment: ondc.FulfillmentStatus{
+			State: stateCode,
+		},
+	}
+
+	if err := c.notifier.SendUnsolicitedStatus(ctx, orderRecord, orderStatus, traceID); err != nil {
+		if domainErr, ok := err.(*errors.DomainError); ok && domainErr.Code == 65006 {
+			c.logger.Warn("order record has no callback target, skipping unsolicited /on_status",
+				zap.String("trace_id", traceID),
+				zap.String("dispatch_order_id", event.DispatchOrderID),
+			)
+			return nil
+		}
+		return err
+	}
+
+	c.logger.Info("sent unsolicited /on_status",
+		zap.String("trace_id", traceID),
+		zap.String("dispatch_order_id", event.DispatchOrderID),
+		zap.String("order.id", orderRecord.OrderID),
+		zap.String("state", event.State),
+		zap.String("fulfillment_state", stateCode),
+	)
+	return nil
+}
+
+// reject logs and counts an event that can never be processed
+func (c *Consumer) reject(stream, id string, err error) {
+	reason := "invalid"
+	if stderrors.Is(err, models.ErrUnknownEventType) {
+		reason = "unknown_type"
+	}
+	if c.metrics != nil {
+		c.metrics.RecordEventRejected(stream, models.EventTypeOrderLifecycle, reason)
+	}
+	c.logger.Error("dropping order lifecycle event that failed decoding", zap.Error(err), zap.String("stream", stream), zap.String("id", id))
+}
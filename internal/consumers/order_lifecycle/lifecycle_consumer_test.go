@@ -0,0 +1,245 @@
+package order_lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/handlers/ondc"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockStreamClient struct {
+	mock.Mock
+}
+
+func (m *mockStreamClient) XReadGroup(ctx context.Context, args *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+	called := m.Called(ctx, args)
+	cmd := redis.NewXStreamSliceCmd(ctx)
+	if streams := called.Get(0); streams != nil {
+		cmd.SetVal(streams.([]redis.XStream))
+	}
+	cmd.SetErr(called.Error(1))
+	return cmd
+}
+
+func (m *mockStreamClient) XAck(ctx context.Context, stream, group, id string) *redis.IntCmd {
+	args := m.Called(ctx, stream, group, id)
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(1)
+	cmd.SetErr(args.Error(0))
+	return cmd
+}
+
+type mockOrderRecordLookup struct {
+	mock.Mock
+}
+
+func (m *mockOrderRecordLookup) GetOrderRecordByDispatchOrderID(ctx context.Context, dispatchOrderID string) (*ondc.OrderRecord, error) {
+	args := m.Called(ctx, dispatchOrderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ondc.OrderRecord), args.Error(1)
+}
+
+type mockStatusNotifier struct {
+	mock.Mock
+}
+
+func (m *mockStatusNotifier) SendUnsolicitedStatus(ctx context.Context, orderRecord *ondc.OrderRecord, orderStatus *ondc.OrderStatus, traceID string) error {
+	args := m.Called(ctx, orderRecord, orderStatus, traceID)
+	return args.Error(0)
+}
+
+type mockEventMetrics struct {
+	mock.Mock
+}
+
+func (m *mockEventMetrics) RecordEventRejected(stream, eventType, reason string) {
+	m.Called(stream, eventType, reason)
+}
+
+const testStream = "stream.order.lifecycle"
+
+func testConfig() config.StreamsConfig {
+	return config.StreamsConfig{
+		OrderLifecycle:    testStream,
+		ConsumerGroupName: "uois-gateway-group",
+		ConsumerID:        "test-consumer-1",
+	}
+}
+
+// lifecycleMessage builds an order.lifecycle stream message for dispatch-123
+func lifecycleMessage(id, state string) redis.XMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"event_type":        "order.lifecycle",
+		"event_version":     1,
+		"event_id":          "event-" + id,
+		"dispatch_order_id": "dispatch-123",
+		"rider_id":          "rider-1",
+		"state":             state,
+		"previous_state":    "CONFIRMED",
+		"traceparent":       "00-4bf92f3577b34da6a3ce929d0e0e4736-8f2a1b2c3d4e5f6a-01",
+		"timestamp":         "2025-01-01T00:00:00Z",
+	})
+	return redis.XMessage{ID: id, Values: map[string]interface{}{"data": string(data)}}
+}
+
+func testOrderRecord() *ondc.OrderRecord {
+	return &ondc.OrderRecord{
+		DispatchOrderID: "dispatch-123",
+		OrderID:         "order-123",
+		TransactionID:   "txn-123",
+		BapURI:          "https://buyer.example.com",
+	}
+}
+
+func TestConsumer_HandleMessage_SendsUnsolicitedStatus(t *testing.T) {
+	tests := []struct {
+		state    string
+		wantCode string
+	}{
+		{"ASSIGNED", "Agent-assigned"},
+		{"PICKED_UP", "Order-picked-up"},
+		{"DELIVERED", "Order-delivered"},
+		{"RTO_INITIATED", "RTO-Initiated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			rdb := new(mockStreamClient)
+			orderRecords := new(mockOrderRecordLookup)
+			notifier := new(mockStatusNotifier)
+			consumer := NewConsumer(rdb, testConfig(), time.Second, orderRecords, notifier, nil, zap.NewNop())
+
+			orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+			notifier.On("SendUnsolicitedStatus", mock.Anything, testOrderRecord(), mock.MatchedBy(func(status *ondc.OrderStatus) bool {
+				return status.State == tt.state && status.Fulfillment.State == tt.wantCode && status.RiderID == "rider-1"
+			}), "4bf92f3577b34da6a3ce929d0e0e4736").Return(nil)
+			rdb.On("XAck", mock.Anything, testStream, "uois-gateway-group", "1-0").Return(nil)
+
+			consumer.HandleMessage(context.Background(), testStream, lifecycleMessage("1-0", tt.state))
+
+			notifier.AssertExpectations(t)
+			rdb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConsumer_HandleMessage_UnreportedStateIsAcked(t *testing.T) {
+	rdb := new(mockStreamClient)
+	orderRecords := new(mockOrderRecordLookup)
+	notifier := new(mockStatusNotifier)
+	consumer := NewConsumer(rdb, testConfig(), time.Second, orderRecords, notifier, nil, zap.NewNop())
+
+	rdb.On("XAck", mock.Anything, testStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.HandleMessage(context.Background(), testStream, lifecycleMessage("1-0", "QUOTE_VALIDATED"))
+
+	rdb.AssertExpectations(t)
+	orderRecords.AssertNotCalled(t, "GetOrderRecordByDispatchOrderID", mock.Anything, mock.Anything)
+	notifier.AssertNotCalled(t, "SendUnsolicitedStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConsumer_HandleMessage_UnknownOrderIsAcked(t *testing.T) {
+	rdb := new(mockStreamClient)
+	orderRecords := new(mockOrderRecordLookup)
+	notifier := new(mockStatusNotifier)
+	consumer := NewConsumer(rdb, testConfig(), time.Second, orderRecords, notifier, nil, zap.NewNop())
+
+	orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(nil, errors.NewDomainError(65006, "order not found", "order_id not found"))
+	rdb.On("XAck", mock.Anything, testStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.HandleMessage(context.Background(), testStream, lifecycleMessage("1-0", "PICKED_UP"))
+
+	rdb.AssertExpectations(t)
+	notifier.AssertNotCalled(t, "SendUnsolicitedStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConsumer_HandleMessage_RetryableFailuresStayPending(t *testing.T) {
+	tests := []struct {
+		name      string
+		lookupErr error
+		sendErr   error
+	}{
+		{"order record lookup fails", errors.NewDomainError(65011, "order record retrieval failed", "redis error"), nil},
+		{"callback fails", nil, errors.NewDomainError(65020, "callback delivery failed", "unexpected status: 503")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb := new(mockStreamClient)
+			orderRecords := new(mockOrderRecordLookup)
+			notifier := new(mockStatusNotifier)
+			consumer := NewConsumer(rdb, testConfig(), time.Second, orderRecords, notifier, nil, zap.NewNop())
+
+			if tt.lookupErr != nil {
+				orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(nil, tt.lookupErr)
+			} else {
+				orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+				notifier.On("SendUnsolicitedStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.sendErr)
+			}
+
+			consumer.HandleMessage(context.Background(), testStream, lifecycleMessage("1-0", "DELIVERED"))
+
+			rdb.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestConsumer_HandleMessage_InvalidEventIsRejected(t *testing.T) {
+	rdb := new(mockStreamClient)
+	metrics := new(mockEventMetrics)
+	notifier := new(mockStatusNotifier)
+	consumer := NewConsumer(rdb, testConfig(), time.Second, new(mockOrderRecordLookup), notifier, metrics, zap.NewNop())
+
+	rdb.On("XAck", mock.Anything, testStream, "uois-gateway-group", "1-0").Return(nil)
+	metrics.On("RecordEventRejected", testStream, "order.lifecycle", "invalid").Return()
+
+	consumer.HandleMessage(context.Background(), testStream, redis.XMessage{ID: "1-0", Values: map[string]interface{}{
+		"data": `{"event_type":"order.lifecycle","event_id":"e1","traceparent":"00-4bf92f3577b34da6a3ce929d0e0e4736-8f2a1b2c3d4e5f6a-01","timestamp":"2025-01-01T00:00:00Z"}`,
+	}})
+
+	rdb.AssertExpectations(t)
+	metrics.AssertExpectations(t)
+	notifier.AssertNotCalled(t, "SendUnsolicitedStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConsumer_Run_ReadsLifecycleStream(t *testing.T) {
+	rdb := new(mockStreamClient)
+	orderRecords := new(mockOrderRecordLookup)
+	notifier := new(mockStatusNotifier)
+	consumer := NewConsumer(rdb, testConfig(), time.Second, orderRecords, notifier, nil, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rdb.On("XReadGroup", mock.Anything, mock.MatchedBy(func(args *redis.XReadGroupArgs) bool {
+		return args.Streams[0] == testStream && args.Group == "uois-gateway-group"
+	})).Return([]redis.XStream{{Stream: testStream, Messages: []redis.XMessage{lifecycleMessage("1-0", "ASSIGNED")}}}, nil).Once()
+	rdb.On("XReadGroup", mock.Anything, mock.Anything).Run(func(mock.Arguments) { cancel() }).Return(nil, context.Canceled)
+	orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+	notifier.On("SendUnsolicitedStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	rdb.On("XAck", mock.Anything, testStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.Run(ctx)
+
+	notifier.AssertExpectations(t)
+	rdb.AssertExpectations(t)
+}
+
+func TestConsumer_Run_DisabledWithoutStream(t *testing.T) {
+	rdb := new(mockStreamClient)
+	consumer := NewConsumer(rdb, config.StreamsConfig{}, time.Second, nil, nil, nil, zap.NewNop())
+
+	consumer.Run(context.Background())
+
+	rdb.AssertNotCalled(t, "XReadGroup", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,166 @@
+package quote_differential
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/consumers/event"
+	"uois-gateway/internal/handlers/ondc"
+	"uois-gateway/internal/models"
+	"uois-gateway/internal/utils"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// OrderRecordService resolves and updates the gateway's order record for a dispatch_order_id
+type OrderRecordService interface {
+	GetOrderRecordByDispatchOrderID(ctx context.Context, dispatchOrderID string) (*ondc.OrderRecord, error)
+	UpdateOrderRecord(ctx context.Context, record *ondc.OrderRecord) error
+}
+
+// UpdateNotifier sends the unsolicited differential /on_update (implemented by ondc.UpdateHandler)
+type UpdateNotifier interface {
+	SendQuoteDifferentialUpdate(ctx context.Context, orderRecord *ondc.OrderRecord, differential *models.QuoteDifferentialEvent, traceID string) error
+}
+
+// EventMetrics records consumed events that are dropped
+type EventMetrics interface {
+	RecordEventRejected(stream, eventType, reason string)
+}
+
+// Consumer turns Quote Service QUOTE_DIFFERENTIAL events (package heavier or bigger than declared)
+// into a revised quote: the difference to the /init quote is persisted on the order record as
+// diff/tax_diff breakup lines and sent to the BAP in an unsolicited /on_update
+//
+// ACK Strategy matches the order lifecycle consumer: failed callbacks stay pending for the Reclaimer.
+// Re-delivered events recompute the same diff lines, so a retry never charges twice; a later event that
+// no longer costs more than the agreed quote removes the diff lines and sends the restored quote.
+type Consumer struct {
+	config       config.StreamsConfig
+	orderRecords OrderRecordService
+	updates      UpdateNotifier
+	metrics      EventMetrics
+	logger       *zap.Logger
+	reader       *event.StreamReader
+}
+
+// NewConsumer creates a new quote differential consumer
+// block is the XREADGROUP block duration (REDIS_STREAM_BLOCK_MS); metrics may be nil
+func NewConsumer(rdb event.StreamConsumerClient, cfg config.StreamsConfig, block time.Duration, orderRecords OrderRecordService, updates UpdateNotifier, metrics EventMetrics, logger *zap.Logger) *Consumer {
+	c := &Consumer{
+		config:       cfg,
+		orderRecords: orderRecords,
+		updates:      updates,
+		metrics:      metrics,
+		logger:       logger,
+	}
+	c.reader = event.NewStreamReader(rdb, cfg, block, "quote differential", c.handle, logger)
+	return c
+}
+
+// Run reads the quote differential stream until ctx is cancelled
+// It returns immediately when STREAM_QUOTE_DIFFERENTIAL is not configured
+func (c *Consumer) Run(ctx context.Context) {
+	stream := c.config.QuoteDifferential
+	if stream == "" {
+		c.logger.Info("quote differential stream not configured, differential /on_update disabled")
+		return
+	}
+
+	c.reader.Run(ctx, stream)
+}
+
+// HandleMessage processes one differential event and ACKs it unless it should be retried
+// It matches event.MessageHandler so the Reclaimer can re-deliver pending entries
+func (c *Consumer) HandleMessage(ctx context.Context, stream string, msg redis.XMessage) {
+	c.reader.HandleMessage(ctx, stream, msg)
+}
+
+// handle returns an error only for failures worth retrying (Redis errors, callback delivery)
+func (c *Consumer) handle(ctx context.Context, stream string, msg redis.XMessage) error {
+	dataStr, ok := msg.Values["data"].(string)
+	if !ok {
+		c.logger.Error("dropping quote differential event with invalid data", zap.String("stream", stream), zap.String("id", msg.ID))
+		return nil
+	}
+
+	decoded, err := models.DecodeEvent([]byte(dataStr))
+	if err != nil {
+		c.reject(stream, msg.ID, err)
+		return nil
+	}
+
+	event, ok := decoded.(*models.QuoteDifferentialEvent)
+	if !ok {
+		c.reject(stream, msg.ID, stderrors.New("unexpected event type on quote differential stream"))
+		return nil
+	}
+
+	traceID := utils.ExtractTraceID(event.Traceparent)
+
+	orderRecord, err := c.orderRecords.GetOrderRecordByDispatchOrderID(ctx, event.DispatchOrderID)
+	if err != nil {
+		if domainErr, ok := err.(*errors.DomainError); ok && domainErr.Code == 65006 {
+			// Orders created outside ONDC (or expired records) have no BAP to notify
+			c.logger.Debug("no order record for quote differential event", zap.String("trace_id", traceID), zap.String("dispatch_order_id", event.DispatchOrderID))
+			return nil
+		}
+		return err
+	}
+
+	revised, err := ondc.ApplyQuoteDifferential(orderRecord, event.RevisedBreakup)
+	if err != nil {
+		c.logger.Warn("dropping quote differential for order without agreed quote",
+			zap.Error(err),
+			zap.String("trace_id", traceID),
+			zap.String("dispatch_order_id", event.DispatchOrderID),
+		)
+		return nil
+	}
+	if !revised {
+		c.logger.Info("quote differential does not increase the quote, nothing to charge",
+			zap.String("trace_id", traceID),
+			zap.String("dispatch_order_id", event.DispatchOrderID),
+		)
+		return nil
+	}
+
+	if err := c.orderRecords.UpdateOrderRecord(ctx, orderRecord); err != nil {
+		return err
+	}
+
+	if err := c.updates.SendQuoteDifferentialUpdate(ctx, orderRecord, event, traceID); err != nil {
+		if domainErr, ok := err.(*errors.DomainError); ok && domainErr.Code == 65006 {
+			c.logger.Warn("order record has no callback target, skipping differential /on_update",
+				zap.String("trace_id", traceID),
+				zap.String("dispatch_order_id", event.DispatchOrderID),
+			)
+			return nil
+		}
+		return err
+	}
+
+	c.logger.Info("sent differential /on_update",
+		zap.String("trace_id", traceID),
+		zap.String("dispatch_order_id", event.DispatchOrderID),
+		zap.String("order.id", orderRecord.OrderID),
+		zap.Float64("quote_value", orderRecord.QuotePrice.Value),
+	)
+	return nil
+}
+
+// reject logs and counts an event that can never be processed
+func (c *Consumer) reject(stream, id string, err error) {
+	reason := "invalid"
+	if stderrors.Is(err, models.ErrUnknownEventType) {
+		reason = "unknown_type"
+	}
+	if c.metrics != nil {
+		c.metrics.RecordEventRejected(stream, models.EventTypeQuoteDifferential, reason)
+	}
+	c.logger.Error("dropping quote differential event that failed decoding", zap.Error(err), zap.String("stream", stream), zap.String("id", id))
+}
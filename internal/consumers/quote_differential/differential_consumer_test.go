@@ -0,0 +1,271 @@
+package quote_differential
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/handlers/ondc"
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockStreamClient struct {
+	mock.Mock
+}
+
+func (m *mockStreamClient) XReadGroup(ctx context.Context, args *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+	called := m.Called(ctx, args)
+	cmd := redis.NewXStreamSliceCmd(ctx)
+	if streams := called.Get(0); streams != nil {
+		cmd.SetVal(streams.([]redis.XStream))
+	}
+	cmd.SetErr(called.Error(1))
+	return cmd
+}
+
+func (m *mockStreamClient) XAck(ctx context.Context, stream, group, id string) *redis.IntCmd {
+	args := m.Called(ctx, stream, group, id)
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(1)
+	cmd.SetErr(args.Error(0))
+	return cmd
+}
+
+type mockOrderRecordService struct {
+	mock.Mock
+}
+
+func (m *mockOrderRecordService) GetOrderRecordByDispatchOrderID(ctx context.Context, dispatchOrderID string) (*ondc.OrderRecord, error) {
+	args := m.Called(ctx, dispatchOrderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ondc.OrderRecord), args.Error(1)
+}
+
+func (m *mockOrderRecordService) UpdateOrderRecord(ctx context.Context, record *ondc.OrderRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+type mockUpdateNotifier struct {
+	mock.Mock
+}
+
+func (m *mockUpdateNotifier) SendQuoteDifferentialUpdate(ctx context.Context, orderRecord *ondc.OrderRecord, differential *models.QuoteDifferentialEvent, traceID string) error {
+	args := m.Called(ctx, orderRecord, differential, traceID)
+	return args.Error(0)
+}
+
+type mockEventMetrics struct {
+	mock.Mock
+}
+
+func (m *mockEventMetrics) RecordEventRejected(stream, eventType, reason string) {
+	m.Called(stream, eventType, reason)
+}
+
+const differentialStream = "stream.quote.differential"
+
+type testMocks struct {
+	rdb          *mockStreamClient
+	orderRecords *mockOrderRecordService
+	updates      *mockUpdateNotifier
+}
+
+func newTestConsumer(metrics EventMetrics) (*Consumer, *testMocks) {
+	m := &testMocks{
+		rdb:          new(mockStreamClient),
+		orderRecords: new(mockOrderRecordService),
+		updates:      new(mockUpdateNotifier),
+	}
+	cfg := config.StreamsConfig{
+		QuoteDifferential: differentialStream,
+		ConsumerGroupName: "uois-gateway-group",
+		ConsumerID:        "test-consumer-1",
+	}
+	return NewConsumer(m.rdb, cfg, time.Second, m.orderRecords, m.updates, metrics, zap.NewNop()), m
+}
+
+func differentialMessage(id string, delivery, tax float64) redis.XMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"event_type":        "QUOTE_DIFFERENTIAL",
+		"event_id":          "event-" + id,
+		"traceparent":       "00-4bf92f3577b34da6a3ce929d0e0e4736-8f2a1b2c3d4e5f6a-01",
+		"timestamp":         "2025-01-01T00:00:00Z",
+		"dispatch_order_id": "dispatch-123",
+		"weight":            map[string]interface{}{"unit": "kilogram", "value": 1.5},
+		"revised_breakup": []map[string]interface{}{
+			{"@ondc/org/item_id": "I1", "@ondc/org/title_type": "delivery", "price": map[string]interface{}{"currency": "INR", "value": delivery}},
+			{"@ondc/org/item_id": "I1", "@ondc/org/title_type": "tax", "price": map[string]interface{}{"currency": "INR", "value": tax}},
+		},
+	})
+	return redis.XMessage{ID: id, Values: map[string]interface{}{"data": string(data)}}
+}
+
+func testOrderRecord() *ondc.OrderRecord {
+	return &ondc.OrderRecord{
+		DispatchOrderID: "dispatch-123",
+		OrderID:         "order-123",
+		TransactionID:   "txn-123",
+		BapURI:          "https://buyer.example.com",
+		QuotePrice:      models.Price{Value: 59, Currency: "INR"},
+		QuoteBreakup: []models.BreakupItem{
+			{ItemID: "I1", TitleType: "delivery", Price: models.Price{Value: 50, Currency: "INR"}},
+			{ItemID: "I1", TitleType: "tax", Price: models.Price{Value: 9, Currency: "INR"}},
+		},
+	}
+}
+
+func TestConsumer_HandleMessage_SendsRevisedQuote(t *testing.T) {
+	consumer, m := newTestConsumer(nil)
+
+	revised := mock.MatchedBy(func(record *ondc.OrderRecord) bool {
+		return record.QuotePrice.Value == 88.5 && len(record.QuoteBreakup) == 4
+	})
+	m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+	m.orderRecords.On("UpdateOrderRecord", mock.Anything, revised).Return(nil)
+	m.updates.On("SendQuoteDifferentialUpdate", mock.Anything, revised, mock.MatchedBy(func(event *models.QuoteDifferentialEvent) bool {
+		return event.Weight != nil && event.Weight.Value == 1.5
+	}), "4bf92f3577b34da6a3ce929d0e0e4736").Return(nil)
+	m.rdb.On("XAck", mock.Anything, differentialStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.HandleMessage(context.Background(), differentialStream, differentialMessage("1-0", 75, 13.5))
+
+	m.orderRecords.AssertExpectations(t)
+	m.updates.AssertExpectations(t)
+	m.rdb.AssertExpectations(t)
+}
+
+func TestConsumer_HandleMessage_LaterLighterMeasurementRestoresQuote(t *testing.T) {
+	consumer, m := newTestConsumer(nil)
+
+	revisedRecord := testOrderRecord()
+	revisedRecord.QuotePrice.Value = 88.5
+	revisedRecord.QuoteRevised = true
+	revisedRecord.QuoteBreakup = append(revisedRecord.QuoteBreakup,
+		models.BreakupItem{ItemID: "I1", TitleType: "diff", Price: models.Price{Value: 25, Currency: "INR"}},
+		models.BreakupItem{ItemID: "I1", TitleType: "tax_diff", Price: models.Price{Value: 4.5, Currency: "INR"}},
+	)
+
+	restored := mock.MatchedBy(func(record *ondc.OrderRecord) bool {
+		return record.QuotePrice.Value == 59 && len(record.QuoteBreakup) == 2
+	})
+	m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(revisedRecord, nil)
+	m.orderRecords.On("UpdateOrderRecord", mock.Anything, restored).Return(nil)
+	m.updates.On("SendQuoteDifferentialUpdate", mock.Anything, restored, mock.Anything, mock.Anything).Return(nil)
+	m.rdb.On("XAck", mock.Anything, differentialStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.HandleMessage(context.Background(), differentialStream, differentialMessage("1-0", 50, 9))
+
+	m.orderRecords.AssertExpectations(t)
+	m.updates.AssertExpectations(t)
+	m.rdb.AssertExpectations(t)
+}
+
+func TestConsumer_HandleMessage_NothingToChargeIsAcked(t *testing.T) {
+	tests := []struct {
+		name   string
+		record *ondc.OrderRecord
+		err    error
+	}{
+		{name: "quote not increased", record: testOrderRecord()},
+		{name: "order without agreed quote", record: &ondc.OrderRecord{DispatchOrderID: "dispatch-123", OrderID: "order-123"}},
+		{name: "unknown order", err: errors.NewDomainError(65006, "order not found", "order_id not found")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer, m := newTestConsumer(nil)
+
+			if tt.record != nil {
+				m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(tt.record, nil)
+			} else {
+				m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(nil, tt.err)
+			}
+			m.rdb.On("XAck", mock.Anything, differentialStream, "uois-gateway-group", "1-0").Return(nil)
+
+			consumer.HandleMessage(context.Background(), differentialStream, differentialMessage("1-0", 50, 9))
+
+			m.rdb.AssertExpectations(t)
+			m.orderRecords.AssertNotCalled(t, "UpdateOrderRecord", mock.Anything, mock.Anything)
+			m.updates.AssertNotCalled(t, "SendQuoteDifferentialUpdate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestConsumer_HandleMessage_RetryableFailuresStayPending(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m *testMocks)
+	}{
+		{
+			name: "order record lookup fails",
+			setup: func(m *testMocks) {
+				m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(nil, errors.NewDomainError(65011, "order record storage failed", "redis error"))
+			},
+		},
+		{
+			name: "order record update fails",
+			setup: func(m *testMocks) {
+				m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+				m.orderRecords.On("UpdateOrderRecord", mock.Anything, mock.Anything).Return(errors.NewDomainError(65011, "order record storage failed", "redis error"))
+			},
+		},
+		{
+			name: "on_update callback fails",
+			setup: func(m *testMocks) {
+				m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+				m.orderRecords.On("UpdateOrderRecord", mock.Anything, mock.Anything).Return(nil)
+				m.updates.On("SendQuoteDifferentialUpdate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.NewDomainError(65020, "callback delivery failed", "unexpected status: 503"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer, m := newTestConsumer(nil)
+			tt.setup(m)
+
+			consumer.HandleMessage(context.Background(), differentialStream, differentialMessage("1-0", 75, 13.5))
+
+			m.rdb.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestConsumer_HandleMessage_InvalidEventIsRejected(t *testing.T) {
+	metrics := new(mockEventMetrics)
+	consumer, m := newTestConsumer(metrics)
+
+	m.rdb.On("XAck", mock.Anything, differentialStream, "uois-gateway-group", "1-0").Return(nil)
+	metrics.On("RecordEventRejected", differentialStream, "QUOTE_DIFFERENTIAL", "invalid").Return()
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"event_type":        "QUOTE_DIFFERENTIAL",
+		"event_id":          "e1",
+		"traceparent":       "00-4bf92f3577b34da6a3ce929d0e0e4736-8f2a1b2c3d4e5f6a-01",
+		"timestamp":         "2025-01-01T00:00:00Z",
+		"dispatch_order_id": "dispatch-123",
+	})
+	consumer.HandleMessage(context.Background(), differentialStream, redis.XMessage{ID: "1-0", Values: map[string]interface{}{"data": string(data)}})
+
+	m.rdb.AssertExpectations(t)
+	metrics.AssertExpectations(t)
+	m.orderRecords.AssertNotCalled(t, "GetOrderRecordByDispatchOrderID", mock.Anything, mock.Anything)
+}
+
+func TestConsumer_Run_DisabledWithoutStream(t *testing.T) {
+	rdb := new(mockStreamClient)
+	consumer := NewConsumer(rdb, config.StreamsConfig{}, time.Second, nil, nil, nil, zap.NewNop())
+
+	consumer.Run(context.Background())
+
+	rdb.AssertNotCalled(t, "XReadGroup", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,301 @@
+package rider_assignment
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/consumers/event"
+	"uois-gateway/internal/handlers/ondc"
+	"uois-gateway/internal/models"
+	"uois-gateway/internal/utils"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// ReasonCodeDeliveryNotPossible is the ONDC logistics cancellation reason sent when no rider
+	// could be assigned ("order delivery delayed or not possible")
+	ReasonCodeDeliveryNotPossible = "017"
+)
+
+// OrderRecordService resolves and updates the gateway's order record for a dispatch_order_id
+type OrderRecordService interface {
+	GetOrderRecordByDispatchOrderID(ctx context.Context, dispatchOrderID string) (*ondc.OrderRecord, error)
+	UpdateOrderRecord(ctx context.Context, record *ondc.OrderRecord) error
+}
+
+// OrderServiceClient is the subset of Order Service calls used on rider assignment outcomes
+type OrderServiceClient interface {
+	GetOrder(ctx context.Context, dispatchOrderID string) (*ondc.OrderStatus, error)
+	CancelOrder(ctx context.Context, dispatchOrderID string, reason string) error
+}
+
+// UpdateNotifier sends the unsolicited Agent-assigned /on_update (implemented by ondc.UpdateHandler)
+type UpdateNotifier interface {
+	SendAgentAssignedUpdate(ctx context.Context, orderRecord *ondc.OrderRecord, traceID string) error
+}
+
+// CancellationNotifier sends the seller-side /on_cancel (implemented by ondc.CancelHandler)
+type CancellationNotifier interface {
+	SendSellerCancellation(ctx context.Context, orderRecord *ondc.OrderRecord, reasonCode, preCancelState, traceID string) error
+}
+
+// EventMetrics records consumed events that are dropped
+type EventMetrics interface {
+	RecordEventRejected(stream, eventType, reason string)
+}
+
+// Consumer turns DroneAI rider assignment outcomes into ONDC callbacks
+//
+//   - RIDER_ASSIGNED: persists the rider and agent details on the order record and sends an
+//     unsolicited /on_update with fulfillment state Agent-assigned, agent and vehicle
+//   - RIDER_ASSIGNMENT_FAILED (no retry recommended): cancels the order in Order Service and sends
+//     a seller-side /on_cancel with ReasonCodeDeliveryNotPossible
+//
+// ACK Strategy matches the order lifecycle consumer: failed callbacks stay pending for the Reclaimer.
+type Consumer struct {
+	config       config.StreamsConfig
+	orderRecords OrderRecordService
+	orderService OrderServiceClient
+	updates      UpdateNotifier
+	cancels      CancellationNotifier
+	metrics      EventMetrics
+	logger       *zap.Logger
+	reader       *event.StreamReader
+}
+
+// NewConsumer creates a new rider assignment consumer
+// block is the XREADGROUP block duration (REDIS_STREAM_BLOCK_MS); metrics may be nil
+func NewConsumer(
+	rdb event.StreamConsumerClient,
+	cfg config.StreamsConfig,
+	block time.Duration,
+	orderRecords OrderRecordService,
+	orderService OrderServiceClient,
+	updates UpdateNotifier,
+	cancels CancellationNotifier,
+	metrics EventMetrics,
+	logger *zap.Logger,
+) *Consumer {
+	c := &Consumer{
+		config:       cfg,
+		orderRecords: orderRecords,
+		orderService: orderService,
+		updates:      updates,
+		cancels:      cancels,
+		metrics:      metrics,
+		logger:       logger,
+	}
+	c.reader = event.NewStreamReader(rdb, cfg, block, "rider assignment", c.handle, logger)
+	return c
+}
+
+// Streams returns the configured rider assignment streams
+func (c *Consumer) Streams() []string {
+	var streams []string
+	for _, stream := range []string{c.config.RiderAssigned, c.config.RiderAssignFailed} {
+		if stream != "" {
+			streams = append(streams, stream)
+		}
+	}
+	return streams
+}
+
+// Run reads the rider assignment streams until ctx is cancelled
+func (c *Consumer) Run(ctx context.Context) {
+	streams := c.Streams()
+	if len(streams) == 0 {
+		c.logger.Info("rider assignment streams not configured, agent /on_update disabled")
+		return
+	}
+
+	c.reader.Run(ctx, streams...)
+}
+
+// HandleMessage processes one rider assignment event and ACKs it unless it should be retried
+// It matches event.MessageHandler so the Reclaimer can re-deliver pending entries
+func (c *Consumer) HandleMessage(ctx context.Context, stream string, msg redis.XMessage) {
+	c.reader.HandleMessage(ctx, stream, msg)
+}
+
+// handle returns an error only for failures worth retrying (Redis, Order Service, callback delivery)
+func (c *Consumer) handle(ctx context.Context, stream string, msg redis.XMessage) error {
+	dataStr, ok := msg.Values["data"].(string)
+	if !ok {
+		c.logger.Error("dropping rider assignment event with invalid data", zap.String("stream", stream), zap.String("id", msg.ID))
+		return nil
+	}
+
+	decoded, err := models.DecodeEvent([]byte(dataStr))
+	if err != nil {
+		c.reject(stream, msg.ID, err)
+		return nil
+	}
+
+	switch event := decoded.(type) {
+	case *models.RiderAssignedEvent:
+		return c.handleAssigned(ctx, event)
+	case *models.RiderAssignmentFailedEvent:
+		return c.handleAssignmentFailed(ctx, event)
+	default:
+		c.reject(stream, msg.ID, stderrors.New("unexpected event type on rider assignment stream"))
+		return nil
+	}
+}
+
+// handleAssigned persists the assignment and notifies the BAP
+func (c *Consumer) handleAssigned(ctx context.Context, event *models.RiderAssignedEvent) error {
+	traceID := utils.ExtractTraceID(event.Traceparent)
+
+	orderRecord, err := c.lookupOrderRecord(ctx, event.DispatchOrderID, traceID)
+	if err != nil || orderRecord == nil {
+		return err
+	}
+
+	orderRecord.RiderID = event.RiderID
+
+	// Agent name/phone/vehicle come from Order Service; DroneAI only publishes rider_id
+	if c.orderService != nil {
+		orderStatus, err := c.orderService.GetOrder(ctx, event.DispatchOrderID)
+		if err != nil {
+			c.logger.Warn("failed to fetch agent details, sending rider_id only", zap.Error(err), zap.String("trace_id", traceID), zap.String("dispatch_order_id", event.DispatchOrderID))
+		} else if orderStatus != nil && orderStatus.Agent != nil {
+			orderRecord.Agent = orderStatus.Agent
+		}
+	}
+
+	if err := c.orderRecords.UpdateOrderRecord(ctx, orderRecord); err != nil {
+		return err
+	}
+
+	if err := c.updates.SendAgentAssignedUpdate(ctx, orderRecord, traceID); err != nil {
+		return c.skipUndeliverable(err, event.DispatchOrderID, traceID)
+	}
+
+	c.logger.Info("sent Agent-assigned /on_update",
+		zap.String("trace_id", traceID),
+		zap.String("dispatch_order_id", event.DispatchOrderID),
+		zap.String("order.id", orderRecord.OrderID),
+		zap.String("rider_id", event.RiderID),
+	)
+	return nil
+}
+
+// handleAssignmentFailed runs the seller-side cancellation once DroneAI gives up on the order
+func (c *Consumer) handleAssignmentFailed(ctx context.Context, event *models.RiderAssignmentFailedEvent) error {
+	traceID := utils.ExtractTraceID(event.Traceparent)
+
+	if event.RetryRecommended {
+		c.logger.Info("rider assignment failed, DroneAI retrying",
+			zap.String("trace_id", traceID),
+			zap.String("dispatch_order_id", event.DispatchOrderID),
+			zap.String("reason", event.Reason),
+			zap.Int("attempt_count", event.AttemptCount),
+		)
+		return nil
+	}
+
+	if c.orderService == nil {
+		c.logger.Error("order service not configured, cannot cancel order after rider assignment failure",
+			zap.String("trace_id", traceID),
+			zap.String("dispatch_order_id", event.DispatchOrderID),
+		)
+		return nil
+	}
+
+	orderRecord, err := c.lookupOrderRecord(ctx, event.DispatchOrderID, traceID)
+	if err != nil || orderRecord == nil {
+		return err
+	}
+
+	// The cancellation is recorded before /on_cancel is sent, so a retry after a failed callback
+	// only re-sends the callback instead of cancelling a cancelled order
+	if orderRecord.SellerCancelReason == "" {
+		if err := c.cancelOrder(ctx, event.DispatchOrderID, traceID); err != nil {
+			return err
+		}
+		orderRecord.SellerCancelReason = ReasonCodeDeliveryNotPossible
+		if err := c.orderRecords.UpdateOrderRecord(ctx, orderRecord); err != nil {
+			return err
+		}
+	}
+
+	// No rider was ever assigned, so the order was cancelled in its confirmed (Pending) state
+	preCancelState, _ := ondc.FulfillmentStateCode(models.OrderStateConfirmed)
+	if err := c.cancels.SendSellerCancellation(ctx, orderRecord, ReasonCodeDeliveryNotPossible, preCancelState, traceID); err != nil {
+		return c.skipUndeliverable(err, event.DispatchOrderID, traceID)
+	}
+
+	c.logger.Info("cancelled order after rider assignment failure",
+		zap.String("trace_id", traceID),
+		zap.String("dispatch_order_id", event.DispatchOrderID),
+		zap.String("order.id", orderRecord.OrderID),
+		zap.String("reason", event.Reason),
+		zap.String("reason_code", ReasonCodeDeliveryNotPossible),
+	)
+	return nil
+}
+
+// cancelOrder cancels the order in Order Service; an order that is already cancelled counts as success
+// (Order Service rejects the repeated cancel as a failed precondition, mapped to 65001)
+func (c *Consumer) cancelOrder(ctx context.Context, dispatchOrderID, traceID string) error {
+	err := c.orderService.CancelOrder(ctx, dispatchOrderID, ReasonCodeDeliveryNotPossible)
+	if err == nil {
+		return nil
+	}
+	if domainErr, ok := err.(*errors.DomainError); !ok || domainErr.Code != 65001 {
+		return err
+	}
+
+	orderStatus, statusErr := c.orderService.GetOrder(ctx, dispatchOrderID)
+	if statusErr != nil || orderStatus == nil || orderStatus.State != models.OrderStateCancelled {
+		return err
+	}
+	c.logger.Info("order already cancelled in Order Service", zap.String("trace_id", traceID), zap.String("dispatch_order_id", dispatchOrderID))
+	return nil
+}
+
+// lookupOrderRecord returns nil, nil for orders this gateway never confirmed (nothing to notify)
+func (c *Consumer) lookupOrderRecord(ctx context.Context, dispatchOrderID, traceID string) (*ondc.OrderRecord, error) {
+	orderRecord, err := c.orderRecords.GetOrderRecordByDispatchOrderID(ctx, dispatchOrderID)
+	if err != nil {
+		if domainErr, ok := err.(*errors.DomainError); ok && domainErr.Code == 65006 {
+			c.logger.Debug("no order record for rider assignment event", zap.String("trace_id", traceID), zap.String("dispatch_order_id", dispatchOrderID))
+			return nil, nil
+		}
+		return nil, err
+	}
+	return orderRecord, nil
+}
+
+// skipUndeliverable drops callbacks that can never be delivered and keeps the rest for retry
+func (c *Consumer) skipUndeliverable(err error, dispatchOrderID, traceID string) error {
+	if domainErr, ok := err.(*errors.DomainError); ok && domainErr.Code == 65006 {
+		c.logger.Warn("order record has no callback target, skipping callback",
+			zap.String("trace_id", traceID),
+			zap.String("dispatch_order_id", dispatchOrderID),
+		)
+		return nil
+	}
+	return err
+}
+
+// reject logs and counts an event that can never be processed
+func (c *Consumer) reject(stream, id string, err error) {
+	reason := "invalid"
+	if stderrors.Is(err, models.ErrUnknownEventType) {
+		reason = "unknown_type"
+	}
+	eventType := models.EventTypeRiderAssigned
+	if stream == c.config.RiderAssignFailed {
+		eventType = models.EventTypeRiderAssignFailed
+	}
+	if c.metrics != nil {
+		c.metrics.RecordEventRejected(stream, eventType, reason)
+	}
+	c.logger.Error("dropping rider assignment event that failed decoding", zap.Error(err), zap.String("stream", stream), zap.String("id", id))
+}
@@ -0,0 +1,398 @@
+package rider_assignment
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/handlers/ondc"
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockStreamClient struct {
+	mock.Mock
+}
+
+func (m *mockStreamClient) XReadGroup(ctx context.Context, args *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+	called := m.Called(ctx, args)
+	cmd := redis.NewXStreamSliceCmd(ctx)
+	if streams := called.Get(0); streams != nil {
+		cmd.SetVal(streams.([]redis.XStream))
+	}
+	cmd.SetErr(called.Error(1))
+	return cmd
+}
+
+func (m *mockStreamClient) XAck(ctx context.Context, stream, group, id string) *redis.IntCmd {
+	args := m.Called(ctx, stream, group, id)
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(1)
+	cmd.SetErr(args.Error(0))
+	return cmd
+}
+
+type mockOrderRecordService struct {
+	mock.Mock
+}
+
+func (m *mockOrderRecordService) GetOrderRecordByDispatchOrderID(ctx context.Context, dispatchOrderID string) (*ondc.OrderRecord, error) {
+	args := m.Called(ctx, dispatchOrderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ondc.OrderRecord), args.Error(1)
+}
+
+func (m *mockOrderRecordService) UpdateOrderRecord(ctx context.Context, record *ondc.OrderRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+type mockOrderServiceClient struct {
+	mock.Mock
+}
+
+func (m *mockOrderServiceClient) GetOrder(ctx context.Context, dispatchOrderID string) (*ondc.OrderStatus, error) {
+	args := m.Called(ctx, dispatchOrderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ondc.OrderStatus), args.Error(1)
+}
+
+func (m *mockOrderServiceClient) CancelOrder(ctx context.Context, dispatchOrderID string, reason string) error {
+	args := m.Called(ctx, dispatchOrderID, reason)
+	return args.Error(0)
+}
+
+type mockUpdateNotifier struct {
+	mock.Mock
+}
+
+func (m *mockUpdateNotifier) SendAgentAssignedUpdate(ctx context.Context, orderRecord *ondc.OrderRecord, traceID string) error {
+	args := m.Called(ctx, orderRecord, traceID)
+	return args.Error(0)
+}
+
+type mockCancellationNotifier struct {
+	mock.Mock
+}
+
+func (m *mockCancellationNotifier) SendSellerCancellation(ctx context.Context, orderRecord *ondc.OrderRecord, reasonCode, preCancelState, traceID string) error {
+	args := m.Called(ctx, orderRecord, reasonCode, preCancelState, traceID)
+	return args.Error(0)
+}
+
+type mockEventMetrics struct {
+	mock.Mock
+}
+
+func (m *mockEventMetrics) RecordEventRejected(stream, eventType, reason string) {
+	m.Called(stream, eventType, reason)
+}
+
+const (
+	assignedStream     = "stream.droneai.order.assigned"
+	assignFailedStream = "stream.droneai.order.assign_failed"
+)
+
+func testConfig() config.StreamsConfig {
+	return config.StreamsConfig{
+		RiderAssigned:     assignedStream,
+		RiderAssignFailed: assignFailedStream,
+		ConsumerGroupName: "uois-gateway-group",
+		ConsumerID:        "test-consumer-1",
+	}
+}
+
+type testMocks struct {
+	rdb          *mockStreamClient
+	orderRecords *mockOrderRecordService
+	orderService *mockOrderServiceClient
+	updates      *mockUpdateNotifier
+	cancels      *mockCancellationNotifier
+}
+
+func newTestConsumer(metrics EventMetrics) (*Consumer, *testMocks) {
+	m := &testMocks{
+		rdb:          new(mockStreamClient),
+		orderRecords: new(mockOrderRecordService),
+		orderService: new(mockOrderServiceClient),
+		updates:      new(mockUpdateNotifier),
+		cancels:      new(mockCancellationNotifier),
+	}
+	consumer := NewConsumer(m.rdb, testConfig(), time.Second, m.orderRecords, m.orderService, m.updates, m.cancels, metrics, zap.NewNop())
+	return consumer, m
+}
+
+func streamMessage(id string, event map[string]interface{}) redis.XMessage {
+	data, _ := json.Marshal(event)
+	return redis.XMessage{ID: id, Values: map[string]interface{}{"data": string(data)}}
+}
+
+func assignedMessage(id string) redis.XMessage {
+	return streamMessage(id, map[string]interface{}{
+		"event_type":        "RIDER_ASSIGNED",
+		"event_id":          "event-" + id,
+		"dispatch_order_id": "dispatch-123",
+		"rider_id":          "rider-1",
+		"assignment_id":     "assignment-1",
+		"eta_to_pickup":     300,
+		"timestamp":         "2025-01-01T00:00:00Z",
+	})
+}
+
+func assignFailedMessage(id string, retryRecommended bool) redis.XMessage {
+	return streamMessage(id, map[string]interface{}{
+		"event_type":        "RIDER_ASSIGNMENT_FAILED",
+		"event_id":          "event-" + id,
+		"dispatch_order_id": "dispatch-123",
+		"reason":            "NO_RIDERS_AVAILABLE",
+		"attempt_count":     3,
+		"retry_recommended": retryRecommended,
+		"timestamp":         "2025-01-01T00:00:00Z",
+	})
+}
+
+func testOrderRecord() *ondc.OrderRecord {
+	return &ondc.OrderRecord{
+		DispatchOrderID: "dispatch-123",
+		OrderID:         "order-123",
+		TransactionID:   "txn-123",
+		BapURI:          "https://buyer.example.com",
+	}
+}
+
+func testAgent() *ondc.AgentDetails {
+	return &ondc.AgentDetails{Name: "Ravi", Phone: "9886098860", VehicleCategory: "Bike", VehicleRegistration: "KA01AB1234"}
+}
+
+func TestConsumer_HandleMessage_RiderAssignedSendsAgentUpdate(t *testing.T) {
+	consumer, m := newTestConsumer(nil)
+
+	m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+	m.orderService.On("GetOrder", mock.Anything, "dispatch-123").Return(&ondc.OrderStatus{Agent: testAgent()}, nil)
+	m.orderRecords.On("UpdateOrderRecord", mock.Anything, mock.MatchedBy(func(record *ondc.OrderRecord) bool {
+		return record.RiderID == "rider-1" && record.Agent != nil && record.Agent.Phone == "9886098860"
+	})).Return(nil)
+	m.updates.On("SendAgentAssignedUpdate", mock.Anything, mock.MatchedBy(func(record *ondc.OrderRecord) bool {
+		return record.RiderID == "rider-1" && record.Agent.Name == "Ravi"
+	}), "").Return(nil)
+	m.rdb.On("XAck", mock.Anything, assignedStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.HandleMessage(context.Background(), assignedStream, assignedMessage("1-0"))
+
+	m.orderRecords.AssertExpectations(t)
+	m.updates.AssertExpectations(t)
+	m.rdb.AssertExpectations(t)
+}
+
+func TestConsumer_HandleMessage_RiderAssignedWithoutAgentDetails(t *testing.T) {
+	consumer, m := newTestConsumer(nil)
+
+	m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+	m.orderService.On("GetOrder", mock.Anything, "dispatch-123").Return(nil, errors.NewDomainError(65011, "order service unavailable", "timeout"))
+	m.orderRecords.On("UpdateOrderRecord", mock.Anything, mock.MatchedBy(func(record *ondc.OrderRecord) bool {
+		return record.RiderID == "rider-1" && record.Agent == nil
+	})).Return(nil)
+	m.updates.On("SendAgentAssignedUpdate", mock.Anything, mock.Anything, "").Return(nil)
+	m.rdb.On("XAck", mock.Anything, assignedStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.HandleMessage(context.Background(), assignedStream, assignedMessage("1-0"))
+
+	m.updates.AssertExpectations(t)
+	m.rdb.AssertExpectations(t)
+}
+
+func TestConsumer_HandleMessage_UnknownOrderIsAcked(t *testing.T) {
+	consumer, m := newTestConsumer(nil)
+
+	m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(nil, errors.NewDomainError(65006, "order not found", "order_id not found"))
+	m.rdb.On("XAck", mock.Anything, assignedStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.HandleMessage(context.Background(), assignedStream, assignedMessage("1-0"))
+
+	m.rdb.AssertExpectations(t)
+	m.updates.AssertNotCalled(t, "SendAgentAssignedUpdate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConsumer_HandleMessage_AssignmentFailedCancelsOrder(t *testing.T) {
+	consumer, m := newTestConsumer(nil)
+
+	cancelled := mock.MatchedBy(func(record *ondc.OrderRecord) bool {
+		return record.SellerCancelReason == ReasonCodeDeliveryNotPossible
+	})
+	m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+	m.orderService.On("CancelOrder", mock.Anything, "dispatch-123", ReasonCodeDeliveryNotPossible).Return(nil)
+	m.orderRecords.On("UpdateOrderRecord", mock.Anything, cancelled).Return(nil)
+	m.cancels.On("SendSellerCancellation", mock.Anything, cancelled, ReasonCodeDeliveryNotPossible, "Pending", "").Return(nil)
+	m.rdb.On("XAck", mock.Anything, assignFailedStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.HandleMessage(context.Background(), assignFailedStream, assignFailedMessage("1-0", false))
+
+	m.orderService.AssertExpectations(t)
+	m.orderRecords.AssertExpectations(t)
+	m.cancels.AssertExpectations(t)
+	m.rdb.AssertExpectations(t)
+}
+
+func TestConsumer_HandleMessage_AssignmentFailedRetryDoesNotCancelAgain(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m *testMocks)
+	}{
+		{
+			name: "cancellation recorded on the order record",
+			setup: func(m *testMocks) {
+				record := testOrderRecord()
+				record.SellerCancelReason = ReasonCodeDeliveryNotPossible
+				m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(record, nil)
+			},
+		},
+		{
+			name: "order already cancelled in Order Service",
+			setup: func(m *testMocks) {
+				m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+				m.orderService.On("CancelOrder", mock.Anything, "dispatch-123", ReasonCodeDeliveryNotPossible).Return(errors.NewDomainError(65001, "invalid request", "order already cancelled"))
+				m.orderService.On("GetOrder", mock.Anything, "dispatch-123").Return(&ondc.OrderStatus{State: models.OrderStateCancelled}, nil)
+				m.orderRecords.On("UpdateOrderRecord", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer, m := newTestConsumer(nil)
+			tt.setup(m)
+			m.cancels.On("SendSellerCancellation", mock.Anything, mock.Anything, ReasonCodeDeliveryNotPossible, "Pending", "").Return(nil)
+			m.rdb.On("XAck", mock.Anything, assignFailedStream, "uois-gateway-group", "1-0").Return(nil)
+
+			consumer.HandleMessage(context.Background(), assignFailedStream, assignFailedMessage("1-0", false))
+
+			m.orderService.AssertExpectations(t)
+			m.cancels.AssertExpectations(t)
+			m.rdb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConsumer_HandleMessage_AssignmentFailedWithoutOrderServiceIsAcked(t *testing.T) {
+	m := &testMocks{
+		rdb:          new(mockStreamClient),
+		orderRecords: new(mockOrderRecordService),
+		cancels:      new(mockCancellationNotifier),
+	}
+	consumer := NewConsumer(m.rdb, testConfig(), time.Second, m.orderRecords, nil, nil, m.cancels, nil, zap.NewNop())
+	m.rdb.On("XAck", mock.Anything, assignFailedStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.HandleMessage(context.Background(), assignFailedStream, assignFailedMessage("1-0", false))
+
+	m.rdb.AssertExpectations(t)
+	m.cancels.AssertNotCalled(t, "SendSellerCancellation", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConsumer_HandleMessage_AssignmentFailedWithRetryIsAcked(t *testing.T) {
+	consumer, m := newTestConsumer(nil)
+
+	m.rdb.On("XAck", mock.Anything, assignFailedStream, "uois-gateway-group", "1-0").Return(nil)
+
+	consumer.HandleMessage(context.Background(), assignFailedStream, assignFailedMessage("1-0", true))
+
+	m.rdb.AssertExpectations(t)
+	m.orderService.AssertNotCalled(t, "CancelOrder", mock.Anything, mock.Anything, mock.Anything)
+	m.cancels.AssertNotCalled(t, "SendSellerCancellation", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConsumer_HandleMessage_RetryableFailuresStayPending(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		msg    redis.XMessage
+		setup  func(m *testMocks)
+	}{
+		{
+			name:   "order record update fails",
+			stream: assignedStream,
+			msg:    assignedMessage("1-0"),
+			setup: func(m *testMocks) {
+				m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+				m.orderService.On("GetOrder", mock.Anything, "dispatch-123").Return(&ondc.OrderStatus{}, nil)
+				m.orderRecords.On("UpdateOrderRecord", mock.Anything, mock.Anything).Return(errors.NewDomainError(65011, "order record storage failed", "redis error"))
+			},
+		},
+		{
+			name:   "on_update callback fails",
+			stream: assignedStream,
+			msg:    assignedMessage("1-0"),
+			setup: func(m *testMocks) {
+				m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+				m.orderService.On("GetOrder", mock.Anything, "dispatch-123").Return(&ondc.OrderStatus{}, nil)
+				m.orderRecords.On("UpdateOrderRecord", mock.Anything, mock.Anything).Return(nil)
+				m.updates.On("SendAgentAssignedUpdate", mock.Anything, mock.Anything, mock.Anything).Return(errors.NewDomainError(65020, "callback delivery failed", "unexpected status: 503"))
+			},
+		},
+		{
+			name:   "order service cancel fails",
+			stream: assignFailedStream,
+			msg:    assignFailedMessage("1-0", false),
+			setup: func(m *testMocks) {
+				m.orderRecords.On("GetOrderRecordByDispatchOrderID", mock.Anything, "dispatch-123").Return(testOrderRecord(), nil)
+				m.orderService.On("CancelOrder", mock.Anything, "dispatch-123", ReasonCodeDeliveryNotPossible).Return(errors.NewDomainError(65011, "order service unavailable", "timeout"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer, m := newTestConsumer(nil)
+			tt.setup(m)
+
+			consumer.HandleMessage(context.Background(), tt.stream, tt.msg)
+
+			m.rdb.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			m.cancels.AssertNotCalled(t, "SendSellerCancellation", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestConsumer_HandleMessage_InvalidEventIsRejected(t *testing.T) {
+	metrics := new(mockEventMetrics)
+	consumer, m := newTestConsumer(metrics)
+
+	m.rdb.On("XAck", mock.Anything, assignFailedStream, "uois-gateway-group", "1-0").Return(nil)
+	metrics.On("RecordEventRejected", assignFailedStream, "RIDER_ASSIGNMENT_FAILED", "invalid").Return()
+
+	consumer.HandleMessage(context.Background(), assignFailedStream, streamMessage("1-0", map[string]interface{}{
+		"event_type": "RIDER_ASSIGNMENT_FAILED",
+		"event_id":   "e1",
+		"timestamp":  "2025-01-01T00:00:00Z",
+	}))
+
+	m.rdb.AssertExpectations(t)
+	metrics.AssertExpectations(t)
+}
+
+func TestConsumer_Streams(t *testing.T) {
+	consumer, _ := newTestConsumer(nil)
+	assert.Equal(t, []string{assignedStream, assignFailedStream}, consumer.Streams())
+
+	disabled := NewConsumer(new(mockStreamClient), config.StreamsConfig{}, time.Second, nil, nil, nil, nil, nil, zap.NewNop())
+	assert.Empty(t, disabled.Streams())
+}
+
+func TestConsumer_Run_DisabledWithoutStreams(t *testing.T) {
+	rdb := new(mockStreamClient)
+	consumer := NewConsumer(rdb, config.StreamsConfig{}, time.Second, nil, nil, nil, nil, nil, zap.NewNop())
+
+	consumer.Run(context.Background())
+
+	rdb.AssertNotCalled(t, "XReadGroup", mock.Anything, mock.Anything)
+}
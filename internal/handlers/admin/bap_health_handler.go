@@ -0,0 +1,42 @@
+package admin
+
+import (
+	"net/http"
+
+	"uois-gateway/internal/services/callback"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BAPHealthService interface for the per-BAP callback circuit state
+type BAPHealthService interface {
+	Unreachable() []callback.BAPHealth
+}
+
+// BAPHealthResponse is returned by GET /admin/callbacks/bap-health
+type BAPHealthResponse struct {
+	Unreachable []callback.BAPHealth `json:"unreachable"`
+	Count       int                  `json:"count"`
+}
+
+// BAPHealthHandler shows which buyer apps currently have an open (or probing) callback circuit
+// Circuit state is per gateway instance; each instance reports the BAPs it has failed to reach
+type BAPHealthHandler struct {
+	healthService BAPHealthService
+	logger        *zap.Logger
+}
+
+// NewBAPHealthHandler creates a new BAP health handler
+func NewBAPHealthHandler(healthService BAPHealthService, logger *zap.Logger) *BAPHealthHandler {
+	return &BAPHealthHandler{
+		healthService: healthService,
+		logger:        logger,
+	}
+}
+
+// HandleUnreachable handles GET /admin/callbacks/bap-health
+func (h *BAPHealthHandler) HandleUnreachable(c *gin.Context) {
+	unreachable := h.healthService.Unreachable()
+	c.JSON(http.StatusOK, BAPHealthResponse{Unreachable: unreachable, Count: len(unreachable)})
+}
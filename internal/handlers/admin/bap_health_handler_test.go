@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/services/callback"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockBAPHealthService struct {
+	mock.Mock
+}
+
+func (m *mockBAPHealthService) Unreachable() []callback.BAPHealth {
+	args := m.Called()
+	return args.Get(0).([]callback.BAPHealth)
+}
+
+func TestBAPHealthHandler_HandleUnreachable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := new(mockBAPHealthService)
+	service.On("Unreachable").Return([]callback.BAPHealth{
+		{Host: "bap.example.com", State: "open", Failures: 5, LastError: "http request failed", LastFailureAt: time.Now()},
+	})
+
+	router := gin.New()
+	router.GET("/admin/callbacks/bap-health", NewBAPHealthHandler(service, zap.NewNop()).HandleUnreachable)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/callbacks/bap-health", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response BAPHealthResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, "bap.example.com", response.Unreachable[0].Host)
+}
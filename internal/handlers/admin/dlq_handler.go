@@ -0,0 +1,169 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"uois-gateway/internal/services/callback"
+	"uois-gateway/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DLQService interface for inspecting and redriving the callback DLQ
+type DLQService interface {
+	List(ctx context.Context, filter callback.DLQFilter) ([]callback.DLQEntry, error)
+	Get(ctx context.Context, id string) (*callback.DLQEntry, error)
+	Redrive(ctx context.Context, ids []string) ([]callback.DLQRedriveResult, error)
+	Purge(ctx context.Context, ids []string) (int64, error)
+}
+
+// DLQEntriesRequest selects DLQ entries by stream ID for redrive or purge
+type DLQEntriesRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1"`
+}
+
+// DLQListResponse is returned by GET /admin/callbacks/dlq
+type DLQListResponse struct {
+	Entries []callback.DLQEntry `json:"entries"`
+	Count   int                 `json:"count"`
+}
+
+// DLQRedriveResponse is returned by POST /admin/callbacks/dlq/redrive
+type DLQRedriveResponse struct {
+	Results []callback.DLQRedriveResult `json:"results"`
+}
+
+// DLQPurgeResponse is returned by POST /admin/callbacks/dlq/purge
+type DLQPurgeResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// DLQHandler serves the operator endpoints for callbacks that exhausted their retries
+type DLQHandler struct {
+	dlqService DLQService
+	logger     *zap.Logger
+}
+
+// NewDLQHandler creates a new DLQ handler
+func NewDLQHandler(dlqService DLQService, logger *zap.Logger) *DLQHandler {
+	return &DLQHandler{
+		dlqService: dlqService,
+		logger:     logger,
+	}
+}
+
+// HandleList handles GET /admin/callbacks/dlq
+// Query: request_id, callback_url, bap_id, from, to (RFC3339), limit
+func (h *DLQHandler) HandleList(c *gin.Context) {
+	filter, err := parseDLQFilter(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	entries, err := h.dlqService.List(c.Request.Context(), filter)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, DLQListResponse{Entries: entries, Count: len(entries)})
+}
+
+// HandleGet handles GET /admin/callbacks/dlq/:id
+func (h *DLQHandler) HandleGet(c *gin.Context) {
+	entry, err := h.dlqService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// HandleRedrive handles POST /admin/callbacks/dlq/redrive
+func (h *DLQHandler) HandleRedrive(c *gin.Context) {
+	var req DLQEntriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.NewDomainError(65001, "invalid request", err.Error()))
+		return
+	}
+
+	results, err := h.dlqService.Redrive(c.Request.Context(), req.IDs)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.logger.Info("callback DLQ redrive requested", zap.Strings("ids", req.IDs))
+	c.JSON(http.StatusOK, DLQRedriveResponse{Results: results})
+}
+
+// HandlePurge handles POST /admin/callbacks/dlq/purge
+func (h *DLQHandler) HandlePurge(c *gin.Context) {
+	var req DLQEntriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.NewDomainError(65001, "invalid request", err.Error()))
+		return
+	}
+
+	deleted, err := h.dlqService.Purge(c.Request.Context(), req.IDs)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, DLQPurgeResponse{Deleted: deleted})
+}
+
+// parseDLQFilter reads the list filter from query parameters
+func parseDLQFilter(c *gin.Context) (callback.DLQFilter, error) {
+	filter := callback.DLQFilter{
+		RequestID:   c.Query("request_id"),
+		CallbackURL: c.Query("callback_url"),
+		BapID:       c.Query("bap_id"),
+	}
+
+	for param, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errors.NewDomainError(65001, "invalid request", param+" must be RFC3339")
+		}
+		*target = parsed
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return filter, errors.NewDomainError(65001, "invalid request", "limit must be a positive integer")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
+
+func (h *DLQHandler) respondError(c *gin.Context, err error) {
+	domainErr, ok := err.(*errors.DomainError)
+	if !ok {
+		domainErr = errors.WrapDomainError(err, 65020, "internal error", "dlq error")
+	}
+
+	if errors.GetHTTPStatus(domainErr) >= http.StatusInternalServerError {
+		h.logger.Error("callback DLQ request failed", zap.Error(err))
+	}
+
+	c.JSON(errors.GetHTTPStatus(domainErr), gin.H{
+		"code":    domainErr.Code,
+		"error":   domainErr.Message,
+		"details": domainErr.Details,
+	})
+}
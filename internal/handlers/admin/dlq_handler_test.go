@@ -0,0 +1,149 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/services/callback"
+	"uois-gateway/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type mockDLQService struct {
+	mock.Mock
+}
+
+func (m *mockDLQService) List(ctx context.Context, filter callback.DLQFilter) ([]callback.DLQEntry, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]callback.DLQEntry), args.Error(1)
+}
+
+func (m *mockDLQService) Get(ctx context.Context, id string) (*callback.DLQEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*callback.DLQEntry), args.Error(1)
+}
+
+func (m *mockDLQService) Redrive(ctx context.Context, ids []string) ([]callback.DLQRedriveResult, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]callback.DLQRedriveResult), args.Error(1)
+}
+
+func (m *mockDLQService) Purge(ctx context.Context, ids []string) (int64, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newDLQTestRouter(service *mockDLQService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewDLQHandler(service, zap.NewNop())
+	router := gin.New()
+	router.GET("/admin/callbacks/dlq", handler.HandleList)
+	router.GET("/admin/callbacks/dlq/:id", handler.HandleGet)
+	router.POST("/admin/callbacks/dlq/redrive", handler.HandleRedrive)
+	router.POST("/admin/callbacks/dlq/purge", handler.HandlePurge)
+	return router
+}
+
+func TestDLQHandler_HandleList(t *testing.T) {
+	service := new(mockDLQService)
+	router := newDLQTestRouter(service)
+
+	expectedFilter := callback.DLQFilter{
+		RequestID: "req-1",
+		BapID:     "bap.example.com",
+		From:      time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Limit:     10,
+	}
+	service.On("List", mock.Anything, expectedFilter).Return([]callback.DLQEntry{{ID: "1-0", RequestID: "req-1"}}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/callbacks/dlq?request_id=req-1&bap_id=bap.example.com&from=2026-10-01T00:00:00Z&limit=10", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response DLQListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, "1-0", response.Entries[0].ID)
+	service.AssertExpectations(t)
+}
+
+func TestDLQHandler_HandleList_InvalidTime(t *testing.T) {
+	service := new(mockDLQService)
+	router := newDLQTestRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/callbacks/dlq?to=yesterday", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	service.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestDLQHandler_HandleGet_NotFound(t *testing.T) {
+	service := new(mockDLQService)
+	router := newDLQTestRouter(service)
+	service.On("Get", mock.Anything, "9-0").Return(nil, errors.NewDomainError(65006, "dlq entry not found", "9-0"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/callbacks/dlq/9-0", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDLQHandler_HandleRedrive(t *testing.T) {
+	service := new(mockDLQService)
+	router := newDLQTestRouter(service)
+	service.On("Redrive", mock.Anything, []string{"1-0", "2-0"}).Return([]callback.DLQRedriveResult{
+		{ID: "1-0", RequestID: "req-1", Status: callback.DLQRedriveStatusSuccess},
+		{ID: "2-0", RequestID: "req-2", Status: callback.DLQRedriveStatusFailed, Error: "unexpected status: 503"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/callbacks/dlq/redrive", bytes.NewReader([]byte(`{"ids":["1-0","2-0"]}`))))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response DLQRedriveResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Results, 2)
+	assert.Equal(t, callback.DLQRedriveStatusFailed, response.Results[1].Status)
+}
+
+func TestDLQHandler_HandleRedrive_MissingIDs(t *testing.T) {
+	service := new(mockDLQService)
+	router := newDLQTestRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/callbacks/dlq/redrive", bytes.NewReader([]byte(`{"ids":[]}`))))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	service.AssertNotCalled(t, "Redrive", mock.Anything, mock.Anything)
+}
+
+func TestDLQHandler_HandlePurge(t *testing.T) {
+	service := new(mockDLQService)
+	router := newDLQTestRouter(service)
+	service.On("Purge", mock.Anything, []string{"1-0"}).Return(int64(1), nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/callbacks/dlq/purge", bytes.NewReader([]byte(`{"ids":["1-0"]}`))))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"deleted":1}`, w.Body.String())
+}
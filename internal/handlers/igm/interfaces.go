@@ -16,8 +16,9 @@ type IssueRepository interface {
 }
 
 // CallbackService sends HTTP callbacks to client callback URLs
+// SendCallback returns the callback queue's delivery id, or "" when the callback was delivered inline
 type CallbackService interface {
-	SendCallback(ctx context.Context, callbackURL string, payload interface{}) error
+	SendCallback(ctx context.Context, callbackURL string, payload interface{}) (string, error)
 }
 
 // IdempotencyService handles request idempotency checks and storage
@@ -26,8 +27,12 @@ type IdempotencyService interface {
 	StoreIdempotency(ctx context.Context, key string, responseBytes []byte, ttl time.Duration) error
 }
 
+// ResponseSigner signs synchronous response bytes (ONDC Authorization header)
+type ResponseSigner interface {
+	SignResponse(payload []byte) (string, error)
+}
+
 // GROService provides GRO (Grievance Redressal Officer) details
 type GROService interface {
 	GetGRODetails(ctx context.Context, issueType models.IssueType) (*models.GRO, error)
 }
-
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 
+	"uois-gateway/internal/middleware"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/utils"
 	"uois-gateway/pkg/errors"
@@ -23,6 +25,7 @@ type IssueHandler struct {
 	groService         GROService
 	bppID              string
 	bppURI             string
+	responseSigner     ResponseSigner
 	logger             *zap.Logger
 }
 
@@ -34,6 +37,7 @@ func NewIssueHandler(
 	groService GROService,
 	bppID string,
 	bppURI string,
+	responseSigner ResponseSigner,
 	logger *zap.Logger,
 ) *IssueHandler {
 	return &IssueHandler{
@@ -43,6 +47,7 @@ func NewIssueHandler(
 		groService:         groService,
 		bppID:              bppID,
 		bppURI:             bppURI,
+		responseSigner:     responseSigner,
 		logger:             logger,
 	}
 }
@@ -71,9 +76,9 @@ func (h *IssueHandler) HandleIssue(c *gin.Context) {
 
 	idempotencyKey := h.buildIdempotencyKey(req.Context.TransactionID, req.Context.MessageID)
 	if existingResponseBytes, exists, err := h.idempotencyService.CheckIdempotency(ctx, idempotencyKey); err == nil && exists {
-		var existingResponse models.ONDCACKResponse
-		if err := json.Unmarshal(existingResponseBytes, &existingResponse); err == nil {
-			h.respondACK(c, existingResponse)
+		// Serve the stored bytes verbatim so the signature covers the original response
+		if json.Valid(existingResponseBytes) {
+			middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, existingResponseBytes)
 			return
 		}
 	}
@@ -106,9 +111,9 @@ func (h *IssueHandler) HandleIssue(c *gin.Context) {
 	responseBytes, _ := json.Marshal(response)
 	_ = h.idempotencyService.StoreIdempotency(ctx, idempotencyKey, responseBytes, 24*time.Hour)
 
-	go h.sendIssueCallback(ctx, &req, issue, traceID)
+	go h.sendIssueCallback(context.WithoutCancel(ctx), &req, issue, traceID)
 
-	h.respondACK(c, response)
+	middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, responseBytes)
 }
 
 func (h *IssueHandler) extractIssueData(req *models.ONDCRequest) (*models.Issue, error) {
@@ -173,8 +178,11 @@ func (h *IssueHandler) sendIssueCallback(ctx context.Context, req *models.ONDCRe
 	callbackPayload := h.buildOnIssueCallback(req, issue, gro)
 	callbackURL := h.buildCallbackURL(req.Context.BapURI, "on_issue")
 
-	if err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload); err != nil {
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
 		h.logger.Error("failed to send issue callback", zap.Error(err), zap.String("trace_id", traceID))
+	} else {
+		h.logger.Info("issue callback accepted for delivery", zap.String("trace_id", traceID), zap.String("delivery_id", deliveryID))
 	}
 }
 
@@ -215,7 +223,7 @@ func (h *IssueHandler) buildIdempotencyKey(transactionID, messageID string) stri
 }
 
 func (h *IssueHandler) respondACK(c *gin.Context, response interface{}) {
-	c.JSON(200, response)
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, http.StatusOK, response)
 }
 
 // HandleOnIssue handles POST /on_issue callbacks from Buyer NPs
@@ -334,7 +342,7 @@ func (h *IssueHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 		}
 	}
 
-	c.JSON(errors.GetHTTPStatus(err), models.ONDCResponse{
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, errors.GetHTTPStatus(err), models.ONDCResponse{
 		Context: ctx,
 		Error: &models.ONDCError{
 			Type:    "DOMAIN_ERROR",
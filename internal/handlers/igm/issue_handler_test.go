@@ -49,9 +49,9 @@ type MockCallbackService struct {
 	mock.Mock
 }
 
-func (m *MockCallbackService) SendCallback(ctx context.Context, callbackURL string, payload interface{}) error {
+func (m *MockCallbackService) SendCallback(ctx context.Context, callbackURL string, payload interface{}) (string, error) {
 	args := m.Called(ctx, callbackURL, payload)
-	return args.Error(0)
+	return args.String(0), args.Error(1)
 }
 
 type MockIdempotencyService struct {
@@ -89,7 +89,7 @@ func TestIssueHandler_HandleIssue_Success(t *testing.T) {
 	mockGRO := new(MockGROService)
 
 	logger := zap.NewNop()
-	handler := NewIssueHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", logger)
+	handler := NewIssueHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", nil, logger)
 
 	reqBody := map[string]interface{}{
 		"context": map[string]interface{}{
@@ -118,7 +118,7 @@ func TestIssueHandler_HandleIssue_Success(t *testing.T) {
 	mockIdempotency.On("StoreIdempotency", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockRepo.On("StoreIssue", mock.Anything, mock.Anything).Return(nil)
 	mockGRO.On("GetGRODetails", mock.Anything, models.IssueTypeIssue).Return(&models.GRO{Level: "L1"}, nil)
-	mockCallback.On("SendCallback", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockCallback.On("SendCallback", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 
 	bodyBytes, _ := json.Marshal(reqBody)
 	w := httptest.NewRecorder()
@@ -150,7 +150,7 @@ func TestIssueHandler_HandleOnIssue_Success(t *testing.T) {
 	mockGRO := new(MockGROService)
 
 	logger := zap.NewNop()
-	handler := NewIssueHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", logger)
+	handler := NewIssueHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", nil, logger)
 
 	existingIssue := &models.Issue{
 		IssueID:       "issue-123",
@@ -216,7 +216,7 @@ func TestIssueHandler_HandleOnIssue_IssueNotFound(t *testing.T) {
 	mockGRO := new(MockGROService)
 
 	logger := zap.NewNop()
-	handler := NewIssueHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", logger)
+	handler := NewIssueHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", nil, logger)
 
 	reqBody := map[string]interface{}{
 		"context": map[string]interface{}{
@@ -260,7 +260,7 @@ func TestIssueHandler_HandleIssue_InvalidRequest(t *testing.T) {
 	mockGRO := new(MockGROService)
 
 	logger := zap.NewNop()
-	handler := NewIssueHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", logger)
+	handler := NewIssueHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", nil, logger)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -270,3 +270,69 @@ func TestIssueHandler_HandleIssue_InvalidRequest(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+type MockResponseSigner struct {
+	mock.Mock
+}
+
+func (m *MockResponseSigner) SignResponse(payload []byte) (string, error) {
+	args := m.Called(payload)
+	return args.String(0), args.Error(1)
+}
+
+func TestIssueHandler_HandleIssue_IdempotentReplaySignedVerbatim(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockIssueRepository)
+	mockIdempotency := new(MockIdempotencyService)
+	mockSigner := new(MockResponseSigner)
+	handler := NewIssueHandler(mockRepo, new(MockCallbackService), mockIdempotency, new(MockGROService), "bpp-1", "https://bpp.example.com", mockSigner, zap.NewNop())
+
+	stored := []byte(`{"message": {"ack": {"status": "ACK"}}}`)
+	mockIdempotency.On("CheckIdempotency", mock.Anything, "issue:txn-123:msg-456").Return(stored, true, nil)
+	mockSigner.On("SignResponse", stored).Return("issue-sig", nil)
+
+	reqBody := map[string]interface{}{
+		"context": map[string]interface{}{
+			"domain":         "nic2004:60232",
+			"action":         "issue",
+			"bap_id":         "buyer-1",
+			"bap_uri":        "https://buyer.example.com",
+			"transaction_id": "txn-123",
+			"message_id":     "msg-456",
+			"timestamp":      time.Now().Format(time.RFC3339),
+		},
+		"message": map[string]interface{}{},
+	}
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/issue", bytes.NewReader(bodyBytes))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.HandleIssue(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, stored, w.Body.Bytes())
+	assert.Equal(t, "issue-sig", w.Header().Get("Authorization"))
+	mockRepo.AssertNotCalled(t, "StoreIssue", mock.Anything, mock.Anything)
+}
+
+func TestIssueHandler_RespondNACK_Signed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSigner := new(MockResponseSigner)
+	handler := NewIssueHandler(new(MockIssueRepository), new(MockCallbackService), new(MockIdempotencyService), new(MockGROService), "bpp-1", "https://bpp.example.com", mockSigner, zap.NewNop())
+	mockSigner.On("SignResponse", mock.Anything).Return("nack-sig", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/issue", bytes.NewReader([]byte(`not-json`)))
+
+	handler.HandleIssue(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "nack-sig", w.Header().Get("Authorization"))
+	assert.Equal(t, w.Body.Bytes(), mockSigner.Calls[0].Arguments.Get(0).([]byte))
+}
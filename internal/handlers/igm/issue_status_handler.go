@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 
+	"uois-gateway/internal/middleware"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/utils"
 	"uois-gateway/pkg/errors"
@@ -22,6 +24,7 @@ type IssueStatusHandler struct {
 	groService         GROService
 	bppID              string
 	bppURI             string
+	responseSigner     ResponseSigner
 	logger             *zap.Logger
 }
 
@@ -33,6 +36,7 @@ func NewIssueStatusHandler(
 	groService GROService,
 	bppID string,
 	bppURI string,
+	responseSigner ResponseSigner,
 	logger *zap.Logger,
 ) *IssueStatusHandler {
 	return &IssueStatusHandler{
@@ -42,6 +46,7 @@ func NewIssueStatusHandler(
 		groService:         groService,
 		bppID:              bppID,
 		bppURI:             bppURI,
+		responseSigner:     responseSigner,
 		logger:             logger,
 	}
 }
@@ -70,9 +75,9 @@ func (h *IssueStatusHandler) HandleIssueStatus(c *gin.Context) {
 
 	idempotencyKey := h.buildIdempotencyKey(req.Context.TransactionID, req.Context.MessageID)
 	if existingResponseBytes, exists, err := h.idempotencyService.CheckIdempotency(ctx, idempotencyKey); err == nil && exists {
-		var existingResponse models.ONDCACKResponse
-		if err := json.Unmarshal(existingResponseBytes, &existingResponse); err == nil {
-			h.respondACK(c, existingResponse)
+		// Serve the stored bytes verbatim so the signature covers the original response
+		if json.Valid(existingResponseBytes) {
+			middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, existingResponseBytes)
 			return
 		}
 	}
@@ -96,9 +101,9 @@ func (h *IssueStatusHandler) HandleIssueStatus(c *gin.Context) {
 	responseBytes, _ := json.Marshal(response)
 	_ = h.idempotencyService.StoreIdempotency(ctx, idempotencyKey, responseBytes, 24*time.Hour)
 
-	go h.sendIssueStatusCallback(ctx, &req, issue, gro, traceID)
+	go h.sendIssueStatusCallback(context.WithoutCancel(ctx), &req, issue, gro, traceID)
 
-	h.respondACK(c, response)
+	middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, responseBytes)
 }
 
 func (h *IssueStatusHandler) extractIssueID(req *models.ONDCRequest) (string, error) {
@@ -131,8 +136,11 @@ func (h *IssueStatusHandler) sendIssueStatusCallback(ctx context.Context, req *m
 	callbackPayload := h.buildOnIssueStatusCallback(req, issue, gro)
 	callbackURL := h.buildCallbackURL(req.Context.BapURI, "on_issue_status")
 
-	if err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload); err != nil {
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
 		h.logger.Error("failed to send issue status callback", zap.Error(err), zap.String("trace_id", traceID))
+	} else {
+		h.logger.Info("issue status callback accepted for delivery", zap.String("trace_id", traceID), zap.String("delivery_id", deliveryID))
 	}
 }
 
@@ -188,7 +196,7 @@ func (h *IssueStatusHandler) buildIdempotencyKey(transactionID, messageID string
 }
 
 func (h *IssueStatusHandler) respondACK(c *gin.Context, response interface{}) {
-	c.JSON(200, response)
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, http.StatusOK, response)
 }
 
 // HandleOnIssueStatus handles POST /on_issue_status callbacks from Buyer NPs
@@ -318,7 +326,7 @@ func (h *IssueStatusHandler) respondNACK(c *gin.Context, err *errors.DomainError
 		}
 	}
 
-	c.JSON(errors.GetHTTPStatus(err), models.ONDCResponse{
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, errors.GetHTTPStatus(err), models.ONDCResponse{
 		Context: ctx,
 		Error: &models.ONDCError{
 			Type:    "DOMAIN_ERROR",
@@ -26,7 +26,7 @@ func TestIssueStatusHandler_HandleIssueStatus_Success(t *testing.T) {
 	mockGRO := new(MockGROService)
 
 	logger := zap.NewNop()
-	handler := NewIssueStatusHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", logger)
+	handler := NewIssueStatusHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", nil, logger)
 
 	issue := &models.Issue{
 		IssueID:       "issue-123",
@@ -59,7 +59,7 @@ func TestIssueStatusHandler_HandleIssueStatus_Success(t *testing.T) {
 	mockIdempotency.On("StoreIdempotency", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockRepo.On("GetIssue", mock.Anything, "issue-123").Return(issue, nil)
 	mockGRO.On("GetGRODetails", mock.Anything, models.IssueTypeIssue).Return(&models.GRO{Level: "L1"}, nil)
-	mockCallback.On("SendCallback", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockCallback.On("SendCallback", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 
 	bodyBytes, _ := json.Marshal(reqBody)
 	w := httptest.NewRecorder()
@@ -89,7 +89,7 @@ func TestIssueStatusHandler_HandleIssueStatus_IssueNotFound(t *testing.T) {
 	mockGRO := new(MockGROService)
 
 	logger := zap.NewNop()
-	handler := NewIssueStatusHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", logger)
+	handler := NewIssueStatusHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", nil, logger)
 
 	reqBody := map[string]interface{}{
 		"context": map[string]interface{}{
@@ -132,7 +132,7 @@ func TestIssueStatusHandler_HandleOnIssueStatus_Success(t *testing.T) {
 	mockGRO := new(MockGROService)
 
 	logger := zap.NewNop()
-	handler := NewIssueStatusHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", logger)
+	handler := NewIssueStatusHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", nil, logger)
 
 	existingIssue := &models.Issue{
 		IssueID:       "issue-123",
@@ -199,7 +199,7 @@ func TestIssueStatusHandler_HandleOnIssueStatus_IssueNotFound(t *testing.T) {
 	mockGRO := new(MockGROService)
 
 	logger := zap.NewNop()
-	handler := NewIssueStatusHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", logger)
+	handler := NewIssueStatusHandler(mockRepo, mockCallback, mockIdempotency, mockGRO, "bpp-1", "https://bpp.example.com", nil, logger)
 
 	reqBody := map[string]interface{}{
 		"context": map[string]interface{}{
@@ -0,0 +1,62 @@
+package ondc
+
+import (
+	"time"
+
+	"uois-gateway/internal/models"
+)
+
+// recordAuthorizations stores PCC/DCC codes accepted in /update on the order record
+// A later code for the same stage replaces the earlier one
+func recordAuthorizations(orderRecord *OrderRecord, authorizations []models.FulfillmentAuthorization) {
+	for i := range authorizations {
+		authorization := authorizations[i]
+		switch authorization.Stage {
+		case models.AuthorizationStagePickup:
+			orderRecord.PickupAuthorization = &authorization
+		case models.AuthorizationStageDelivery:
+			orderRecord.DeliveryAuthorization = &authorization
+		}
+	}
+}
+
+// authorizationVerified reports whether the Order Service state shows the rider already collected the stage's code
+// orderState is empty when the state is not known (e.g. /on_update), in which case no code counts as verified
+func authorizationVerified(stage, orderState string) bool {
+	switch orderState {
+	case "DELIVERED", "COMPLETED":
+		return true
+	case "PICKED_UP", "IN_TRANSIT":
+		return stage == models.AuthorizationStagePickup
+	default:
+		return false
+	}
+}
+
+// applyFulfillmentAuthorizations sets fulfillment start.authorization (PCC) and end.authorization (DCC)
+// from the order record, with the status of each code (PENDING, VERIFIED or EXPIRED)
+func applyFulfillmentAuthorizations(fulfillment map[string]interface{}, orderRecord *OrderRecord, orderState string, now time.Time) {
+	for _, stop := range []struct {
+		key           string
+		authorization *models.FulfillmentAuthorization
+	}{
+		{"start", orderRecord.PickupAuthorization},
+		{"end", orderRecord.DeliveryAuthorization},
+	} {
+		if stop.authorization == nil {
+			continue
+		}
+		location, ok := fulfillment[stop.key].(map[string]interface{})
+		if !ok {
+			location = make(map[string]interface{})
+			fulfillment[stop.key] = location
+		}
+		location["authorization"] = map[string]interface{}{
+			"type":       stop.authorization.Type,
+			"token":      stop.authorization.Token,
+			"valid_from": stop.authorization.ValidFrom.UTC().Format(time.RFC3339),
+			"valid_to":   stop.authorization.ValidTo.UTC().Format(time.RFC3339),
+			"status":     stop.authorization.StatusAt(now, authorizationVerified(stop.authorization.Stage, orderState)),
+		}
+	}
+}
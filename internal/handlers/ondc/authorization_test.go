@@ -0,0 +1,218 @@
+package ondc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestApplyFulfillmentAuthorizations(t *testing.T) {
+	now := time.Date(2023, 6, 6, 13, 0, 0, 0, time.UTC)
+	orderRecord := &OrderRecord{}
+	recordAuthorizations(orderRecord, []models.FulfillmentAuthorization{
+		{Stage: models.AuthorizationStagePickup, Type: "OTP", Token: "1234", ValidFrom: now.Add(-time.Hour), ValidTo: now.Add(time.Hour)},
+		{Stage: models.AuthorizationStageDelivery, Type: "OTP", Token: "5678", ValidFrom: now.Add(-2 * time.Hour), ValidTo: now.Add(-time.Minute)},
+	})
+	require.NotNil(t, orderRecord.PickupAuthorization)
+	require.NotNil(t, orderRecord.DeliveryAuthorization)
+
+	statuses := func(orderState string) (string, string) {
+		fulfillment := map[string]interface{}{"start": map[string]interface{}{"location": map[string]interface{}{"gps": "12.9,77.6"}}}
+		applyFulfillmentAuthorizations(fulfillment, orderRecord, orderState, now)
+		start := fulfillment["start"].(map[string]interface{})
+		end := fulfillment["end"].(map[string]interface{})
+		assert.Contains(t, start, "location")
+		return start["authorization"].(map[string]interface{})["status"].(string), end["authorization"].(map[string]interface{})["status"].(string)
+	}
+
+	pickup, delivery := statuses("")
+	assert.Equal(t, models.AuthorizationStatusPending, pickup)
+	assert.Equal(t, models.AuthorizationStatusExpired, delivery)
+
+	pickup, delivery = statuses("PICKED_UP")
+	assert.Equal(t, models.AuthorizationStatusVerified, pickup)
+	assert.Equal(t, models.AuthorizationStatusExpired, delivery)
+
+	pickup, delivery = statuses("DELIVERED")
+	assert.Equal(t, models.AuthorizationStatusVerified, pickup)
+	assert.Equal(t, models.AuthorizationStatusVerified, delivery)
+
+	fulfillment := map[string]interface{}{}
+	applyFulfillmentAuthorizations(fulfillment, &OrderRecord{}, "", now)
+	assert.Empty(t, fulfillment)
+}
+
+func newAuthorizationUpdateRequest(t *testing.T, w *httptest.ResponseRecorder, orderID string, authorization map[string]interface{}) *gin.Context {
+	requestBody := map[string]interface{}{
+		"context": map[string]interface{}{
+			"domain":         "nic2004:60232",
+			"action":         "update",
+			"transaction_id": uuid.New().String(),
+			"message_id":     uuid.New().String(),
+			"timestamp":      time.Now().UTC().Format(time.RFC3339),
+			"ttl":            "PT30S",
+			"bap_uri":        "https://buyer.example.com",
+		},
+		"message": map[string]interface{}{
+			"order": map[string]interface{}{
+				"id": orderID,
+				"fulfillments": []map[string]interface{}{
+					{"id": "F1", "end": map[string]interface{}{"authorization": authorization}},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/update", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("client", &models.Client{ID: "test-client", ClientCode: "test-client"})
+	return c
+}
+
+func TestUpdateHandler_DeliveryAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	callbackService := new(mockCallbackService)
+	idempotencyService := new(mockIdempotencyService)
+	orderServiceClient := new(mockOrderServiceClient)
+	orderRecordService := new(mockOrderRecordService)
+	auditService := new(mockAuditService)
+	handler := NewUpdateHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, auditService, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	validFrom := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+	validTo := validFrom.Add(2 * time.Hour)
+	orderRecord := &OrderRecord{DispatchOrderID: "dispatch-123", OrderID: "order-123", ClientID: "test-client", FulfillmentID: "F1"}
+
+	idempotencyService.On("CheckIdempotency", mock.Anything, mock.Anything).Return(nil, false, nil)
+	idempotencyService.On("StoreIdempotency", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil).Maybe()
+	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil).Maybe()
+	orderRecordService.On("GetOrderRecordByOrderID", mock.Anything, "test-client", "order-123").Return(orderRecord, nil)
+
+	expected := []models.FulfillmentAuthorization{{
+		Stage:     models.AuthorizationStageDelivery,
+		Type:      models.AuthorizationTypeOTP,
+		Token:     "5678",
+		ValidFrom: validFrom,
+		ValidTo:   validTo,
+	}}
+	orderServiceClient.On("UpdateOrder", mock.Anything, "dispatch-123", mock.Anything, expected).Return(nil)
+	orderRecordService.On("UpdateOrderRecord", mock.Anything, mock.MatchedBy(func(record *OrderRecord) bool {
+		return record.DeliveryAuthorization != nil && record.DeliveryAuthorization.Token == "5678" && record.PickupAuthorization == nil
+	})).Return(nil)
+
+	callbackSent := make(chan models.ONDCResponse, 1)
+	callbackService.On("SendCallback", mock.Anything, "https://buyer.example.com/on_update", mock.Anything).Run(func(args mock.Arguments) {
+		callbackSent <- args.Get(2).(models.ONDCResponse)
+	}).Return("", nil)
+
+	w := httptest.NewRecorder()
+	c := newAuthorizationUpdateRequest(t, w, "order-123", map[string]interface{}{
+		"type":       "OTP",
+		"token":      "5678",
+		"valid_from": validFrom.Format(time.RFC3339),
+		"valid_to":   validTo.Format(time.RFC3339),
+	})
+	handler.HandleUpdate(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var payload models.ONDCResponse
+	select {
+	case payload = <-callbackSent:
+	case <-time.After(time.Second):
+		t.Fatal("/on_update callback not sent")
+	}
+	fulfillment := payload.Message["order"].(map[string]interface{})["fulfillments"].([]map[string]interface{})[0]
+	authorization := fulfillment["end"].(map[string]interface{})["authorization"].(map[string]interface{})
+	assert.Equal(t, "5678", authorization["token"])
+	assert.Equal(t, models.AuthorizationStatusPending, authorization["status"])
+
+	orderServiceClient.AssertExpectations(t)
+	orderRecordService.AssertExpectations(t)
+}
+
+func TestUpdateHandler_ExpiredAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	idempotencyService := new(mockIdempotencyService)
+	orderServiceClient := new(mockOrderServiceClient)
+	orderRecordService := new(mockOrderRecordService)
+	handler := NewUpdateHandler(nil, idempotencyService, orderServiceClient, orderRecordService, nil, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	idempotencyService.On("CheckIdempotency", mock.Anything, mock.Anything).Return(nil, false, nil)
+
+	w := httptest.NewRecorder()
+	c := newAuthorizationUpdateRequest(t, w, "order-123", map[string]interface{}{
+		"type":       "OTP",
+		"token":      "5678",
+		"valid_from": time.Now().UTC().Add(-2 * time.Hour).Format(time.RFC3339),
+		"valid_to":   time.Now().UTC().Add(-time.Hour).Format(time.RFC3339),
+	})
+
+	handler.HandleUpdate(c)
+
+	var response models.ONDCResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "65001", response.Error.Code)
+	orderServiceClient.AssertNotCalled(t, "UpdateOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	orderRecordService.AssertNotCalled(t, "GetOrderRecordByOrderID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStatusHandler_SendUnsolicitedStatus_Authorizations(t *testing.T) {
+	callbackService := new(mockCallbackService)
+	billingStorageService := new(mockBillingStorageService)
+	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
+	handler := NewStatusHandler(callbackService, nil, nil, nil, billingStorageService, fulfillmentContactsStorageService, nil, nil, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	validFrom := time.Now().UTC().Add(-time.Hour)
+	orderRecord := &OrderRecord{
+		DispatchOrderID:       "dispatch-123",
+		OrderID:               "order-123",
+		ClientID:              "test-client",
+		TransactionID:         "txn-123",
+		FulfillmentID:         "F1",
+		BapID:                 "buyer.example.com",
+		BapURI:                "https://buyer.example.com",
+		Domain:                "nic2004:60232",
+		PickupAuthorization:   &models.FulfillmentAuthorization{Stage: models.AuthorizationStagePickup, Type: "OTP", Token: "1234", ValidFrom: validFrom, ValidTo: validFrom.Add(2 * time.Hour)},
+		DeliveryAuthorization: &models.FulfillmentAuthorization{Stage: models.AuthorizationStageDelivery, Type: "OTP", Token: "5678", ValidFrom: validFrom, ValidTo: validFrom.Add(4 * time.Hour)},
+	}
+	orderStatus := &OrderStatus{DispatchOrderID: "dispatch-123", State: "PICKED_UP", Fulfillment: FulfillmentStatus{State: "Order-picked-up"}}
+
+	billingStorageService.On("GetBilling", mock.Anything, "txn-123").Return(nil, nil)
+	fulfillmentContactsStorageService.On("GetFulfillmentContacts", mock.Anything, "txn-123").Return(nil, nil)
+
+	var payload models.ONDCResponse
+	callbackService.On("SendCallback", mock.Anything, "https://buyer.example.com/on_status", mock.Anything).Run(func(args mock.Arguments) {
+		payload = args.Get(2).(models.ONDCResponse)
+	}).Return("", nil)
+
+	require.NoError(t, handler.SendUnsolicitedStatus(context.Background(), orderRecord, orderStatus, "trace-1"))
+
+	fulfillment := payload.Message["order"].(map[string]interface{})["fulfillments"].([]map[string]interface{})[0]
+	pickup := fulfillment["start"].(map[string]interface{})["authorization"].(map[string]interface{})
+	delivery := fulfillment["end"].(map[string]interface{})["authorization"].(map[string]interface{})
+	assert.Equal(t, "1234", pickup["token"])
+	assert.Equal(t, models.AuthorizationStatusVerified, pickup["status"])
+	assert.Equal(t, "5678", delivery["token"])
+	assert.Equal(t, models.AuthorizationStatusPending, delivery["status"])
+	assert.Equal(t, validFrom.Format(time.RFC3339), delivery["valid_from"])
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"uois-gateway/internal/middleware"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/services/audit"
 	"uois-gateway/internal/utils"
@@ -27,8 +28,10 @@ type CancelHandler struct {
 	billingStorageService             BillingStorageService
 	fulfillmentContactsStorageService FulfillmentContactsStorageService
 	auditService                      AuditService
+	cancellationPolicy                CancellationPolicy
 	bppID                             string // BPP ID (ONDC-registered Seller NP identity)
 	bppURI                            string // BPP URI
+	responseSigner                    ResponseSigner
 	logger                            *zap.Logger
 }
 
@@ -41,8 +44,10 @@ func NewCancelHandler(
 	billingStorageService BillingStorageService,
 	fulfillmentContactsStorageService FulfillmentContactsStorageService,
 	auditService AuditService,
+	cancellationPolicy CancellationPolicy,
 	bppID string,
 	bppURI string,
+	responseSigner ResponseSigner,
 	logger *zap.Logger,
 ) *CancelHandler {
 	return &CancelHandler{
@@ -53,8 +58,10 @@ func NewCancelHandler(
 		billingStorageService:             billingStorageService,
 		fulfillmentContactsStorageService: fulfillmentContactsStorageService,
 		auditService:                      auditService,
+		cancellationPolicy:                cancellationPolicy,
 		bppID:                             bppID,
 		bppURI:                            bppURI,
+		responseSigner:                    responseSigner,
 		logger:                            logger,
 	}
 }
@@ -84,17 +91,17 @@ func (h *CancelHandler) HandleCancel(c *gin.Context) {
 
 	idempotencyKey := h.buildIdempotencyKey(req.Context.TransactionID, req.Context.MessageID)
 	if existingResponseBytes, exists, err := h.idempotencyService.CheckIdempotency(ctx, idempotencyKey); err == nil && exists {
-		var existingResponse models.ONDCACKResponse
-		if err := json.Unmarshal(existingResponseBytes, &existingResponse); err == nil {
-			h.respondACK(c, existingResponse)
+		// Serve the stored bytes verbatim so the signature covers the original response
+		if json.Valid(existingResponseBytes) {
+			middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, existingResponseBytes)
 			return
 		}
 	}
 
 	// Extract order.id (ONDC) from request (echoed from /on_confirm - seller-generated)
-	orderID, reason, err := h.extractCancelData(&req)
-	if err != nil {
-		h.respondNACK(c, errors.NewDomainError(65001, "invalid request", err.Error()))
+	orderID, reason, domainErr := h.extractCancelData(&req)
+	if domainErr != nil {
+		h.respondNACK(c, domainErr)
 		return
 	}
 
@@ -120,8 +127,30 @@ func (h *CancelHandler) HandleCancel(c *gin.Context) {
 		return
 	}
 
-	if err := h.orderServiceClient.CancelOrder(ctx, dispatchOrderID, reason); err != nil {
-		h.logger.Error("failed to cancel order", zap.Error(err), zap.String("trace_id", traceID), zap.String("dispatch_order_id", dispatchOrderID))
+	// Bound the Order Service call by the request TTL; the request key lets commands be retried safely
+	orderCtx, cancelOrderCtx := utils.WithONDCDeadline(utils.WithRequestKey(ctx, idempotencyKey), req.Context.Timestamp, req.Context.TTL)
+	defer cancelOrderCtx()
+
+	// The pre-cancel fulfillment state decides between cancel and RTO and prices the cancellation
+	orderStatus, err := h.orderServiceClient.GetOrder(orderCtx, dispatchOrderID)
+	if err != nil || orderStatus == nil {
+		h.logger.Error("failed to get order state", zap.Error(err), zap.String("trace_id", traceID), zap.String("dispatch_order_id", dispatchOrderID))
+		if domainErr, ok := err.(*errors.DomainError); ok {
+			h.respondNACK(c, domainErr)
+		} else {
+			h.respondNACK(c, errors.NewDomainError(65020, "internal error", "failed to get order state"))
+		}
+		return
+	}
+	outcome := h.cancelOutcome(orderRecord, reason.Code, preCancelState(orderStatus), req.Context.BapID)
+
+	if outcome.rto {
+		err = h.orderServiceClient.InitiateRTO(orderCtx, dispatchOrderID)
+	} else {
+		err = h.orderServiceClient.CancelOrder(orderCtx, dispatchOrderID, reason.Code)
+	}
+	if err != nil {
+		h.logger.Error("failed to cancel order", zap.Error(err), zap.String("trace_id", traceID), zap.String("dispatch_order_id", dispatchOrderID), zap.Bool("rto", outcome.rto))
 		domainErr, ok := err.(*errors.DomainError)
 		if ok {
 			h.respondNACK(c, domainErr)
@@ -139,31 +168,80 @@ func (h *CancelHandler) HandleCancel(c *gin.Context) {
 	// Log request/response audit
 	h.logRequestResponse(ctx, &req, response, nil, orderRecord, clientID, traceID)
 
-	go h.sendCancelCallback(ctx, &req, orderRecord, traceID)
+	go h.sendCancelCallback(context.WithoutCancel(ctx), &req, orderRecord, outcome, traceID)
 
-	h.respondACK(c, response)
+	middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, responseBytes)
 }
 
-func (h *CancelHandler) extractCancelData(req *models.ONDCRequest) (string, string, error) {
+func (h *CancelHandler) extractCancelData(req *models.ONDCRequest) (string, *models.CancellationReason, *errors.DomainError) {
 	order, ok := req.Message["order"].(map[string]interface{})
 	if !ok {
-		return "", "", errors.NewDomainError(65001, "invalid request", "missing order")
+		return "", nil, errors.NewDomainError(65001, "invalid request", "missing order")
 	}
 
 	orderID, ok := order["id"].(string)
 	if !ok || orderID == "" {
-		return "", "", errors.NewDomainError(65001, "invalid request", "missing order.id")
+		return "", nil, errors.NewDomainError(65001, "invalid request", "missing order.id")
 	}
 
-	// Extract cancellation_reason_id (ONDC reason code, not free text)
-	reason := "001" // Default: "Buyer cancelled"
-	if reasonID, ok := req.Message["cancellation_reason_id"].(string); ok && reasonID != "" {
-		reason = reasonID
+	// cancellation_reason_id is an ONDC reason code (not free text) the buyer side may use
+	reasonID, ok := req.Message["cancellation_reason_id"].(string)
+	if !ok || reasonID == "" {
+		return "", nil, errors.NewDomainError(65001, "invalid request", "missing cancellation_reason_id")
+	}
+	reason, domainErr := h.cancellationPolicy.Reason(reasonID, models.CancellationInitiatorBuyer)
+	if domainErr != nil {
+		return "", nil, domainErr
 	}
 
 	return orderID, reason, nil
 }
 
+// cancellationOutcome is what a cancellation did, rendered in /on_cancel
+type cancellationOutcome struct {
+	reasonCode     string
+	cancelledBy    string  // Subscriber ID of the cancelling side
+	preCancelState string  // ONDC fulfillment state before cancellation, empty when not known
+	rto            bool    // Package returns to origin instead of being cancelled outright
+	fee            float64 // Cancellation fee per the cancellation terms
+}
+
+func (h *CancelHandler) cancelOutcome(orderRecord *OrderRecord, reasonCode, preCancelState, cancelledBy string) cancellationOutcome {
+	fee, _ := h.cancellationPolicy.Fee(preCancelState, reasonCode, orderValue(orderRecord))
+	return cancellationOutcome{
+		reasonCode:     reasonCode,
+		cancelledBy:    cancelledBy,
+		preCancelState: preCancelState,
+		rto:            h.cancellationPolicy.TriggersRTO(reasonCode, preCancelState),
+		fee:            fee,
+	}
+}
+
+// preCancelState is the ONDC fulfillment state of the order before cancellation
+// Falls back to the state derived from the order state; empty when neither is known
+func preCancelState(orderStatus *OrderStatus) string {
+	if orderStatus.Fulfillment.State != "" {
+		return orderStatus.Fulfillment.State
+	}
+	state, _ := FulfillmentStateCode(orderStatus.State)
+	return state
+}
+
+// orderValue is the order value minus taxes that cancellation fee percentages apply to
+func orderValue(orderRecord *OrderRecord) float64 {
+	if len(orderRecord.QuoteBreakup) == 0 {
+		return orderRecord.QuotePrice.Value
+	}
+	var value float64
+	for _, item := range orderRecord.QuoteBreakup {
+		if item.TitleType == "tax" || item.TitleType == "tax_diff" {
+			continue
+		}
+		value += item.Price.Value
+	}
+	return value
+}
+
 func (h *CancelHandler) composeCancelResponse(req *models.ONDCRequest) models.ONDCACKResponse {
 	return models.ONDCACKResponse{
 		Message: models.ONDCACKMessage{
@@ -174,19 +252,49 @@ func (h *CancelHandler) composeCancelResponse(req *models.ONDCRequest) models.ON
 	}
 }
 
-func (h *CancelHandler) sendCancelCallback(ctx context.Context, req *models.ONDCRequest, orderRecord *OrderRecord, traceID string) {
+func (h *CancelHandler) sendCancelCallback(ctx context.Context, req *models.ONDCRequest, orderRecord *OrderRecord, outcome cancellationOutcome, traceID string) {
 	callbackURL := req.Context.BapURI + "/on_cancel"
-	callbackPayload := h.buildOnCancelCallback(ctx, req, orderRecord)
+	callbackPayload := h.buildOnCancelCallback(ctx, req, orderRecord, outcome)
 
-	if err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload); err != nil {
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
 		h.logger.Error("failed to send /on_cancel callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "failed", err.Error())
 	} else {
+		h.logger.Info("/on_cancel callback accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "success", "")
 	}
 }
 
-func (h *CancelHandler) buildOnCancelCallback(ctx context.Context, req *models.ONDCRequest, orderRecord *OrderRecord) models.ONDCResponse {
+// SendSellerCancellation pushes an unsolicited /on_cancel for an order the seller (this BPP) cancelled
+// The order must already be cancelled in Order Service; reasonCode is a seller-side ONDC cancellation reason id
+// and preCancelState the ONDC fulfillment state the order was cancelled in (it prices the cancellation)
+func (h *CancelHandler) SendSellerCancellation(ctx context.Context, orderRecord *OrderRecord, reasonCode, preCancelState, traceID string) error {
+	if _, domainErr := h.cancellationPolicy.Reason(reasonCode, models.CancellationInitiatorSeller); domainErr != nil {
+		return domainErr
+	}
+
+	req, err := unsolicitedRequest(orderRecord, "on_cancel")
+	if err != nil {
+		return err
+	}
+
+	callbackURL := orderRecord.BapURI + "/on_cancel"
+	callbackPayload := h.buildOnCancelCallback(ctx, req, orderRecord, h.cancelOutcome(orderRecord, reasonCode, preCancelState, h.bppID))
+
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
+		h.logger.Error("failed to send seller /on_cancel callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL))
+		h.logCallbackDelivery(ctx, orderRecord.TransactionID, callbackURL, 1, "failed", err.Error())
+		return err
+	}
+
+	h.logger.Info("seller /on_cancel callback accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
+	h.logCallbackDelivery(ctx, orderRecord.TransactionID, callbackURL, 1, "success", "")
+	return nil
+}
+
+func (h *CancelHandler) buildOnCancelCallback(ctx context.Context, req *models.ONDCRequest, orderRecord *OrderRecord, outcome cancellationOutcome) models.ONDCResponse {
 	callbackCtx := req.Context
 	callbackCtx.MessageID = uuid.New().String()
 	callbackCtx.Timestamp = time.Now().UTC()
@@ -223,6 +331,30 @@ func (h *CancelHandler) buildOnCancelCallback(ctx context.Context, req *models.O
 			},
 		},
 	}
+	if outcome.preCancelState != "" {
+		fulfillment["tags"] = []map[string]interface{}{
+			{
+				"code": "precancel_state",
+				"list": []map[string]interface{}{
+					{"code": "fulfillment_state", "value": outcome.preCancelState},
+				},
+			},
+		}
+	}
+	fulfillments := []map[string]interface{}{fulfillment}
+
+	// Cancelling a picked-up package returns it to origin: the RTO fulfillment carries the return leg
+	if outcome.rto {
+		fulfillments = append(fulfillments, map[string]interface{}{
+			"id":   fulfillmentID + "-RTO",
+			"type": "RTO",
+			"state": map[string]interface{}{
+				"descriptor": map[string]interface{}{
+					"code": "RTO-Initiated",
+				},
+			},
+		})
+	}
 
 	// Retrieve billing: first from request, then from Redis (stored during /init)
 	billing := h.getBilling(ctx, req)
@@ -230,7 +362,14 @@ func (h *CancelHandler) buildOnCancelCallback(ctx context.Context, req *models.O
 	orderMap := map[string]interface{}{
 		"id":           orderID,
 		"state":        "CANCELLED", // Order-level cancellation state
-		"fulfillments": []map[string]interface{}{fulfillment},
+		"fulfillments": fulfillments,
+		"cancellation": map[string]interface{}{
+			"cancelled_by": outcome.cancelledBy,
+			"reason": map[string]interface{}{
+				"id": outcome.reasonCode,
+			},
+		},
+		"quote": cancellationQuote(orderRecord, fulfillmentID, outcome.fee),
 	}
 
 	// Add billing if available (ONDC requirement)
@@ -248,6 +387,41 @@ func (h *CancelHandler) buildOnCancelCallback(ctx context.Context, req *models.O
 	}
 }
 
+// cancellationQuote is the updated quote after cancellation: the /init breakup lines zeroed out plus
+// a cancellation line carrying the fee, which is the new quote total
+func cancellationQuote(orderRecord *OrderRecord, fulfillmentID string, fee float64) map[string]interface{} {
+	currency := orderRecord.QuotePrice.Currency
+	if currency == "" {
+		currency = "INR"
+	}
+	price := func(value float64) map[string]interface{} {
+		return map[string]interface{}{"value": value, "currency": currency}
+	}
+
+	itemID := fulfillmentID
+	breakup := make([]map[string]interface{}, 0, len(orderRecord.QuoteBreakup)+1)
+	for _, item := range orderRecord.QuoteBreakup {
+		if item.ItemID != "" {
+			itemID = item.ItemID
+		}
+		breakup = append(breakup, map[string]interface{}{
+			"@ondc/org/item_id":    item.ItemID,
+			"@ondc/org/title_type": item.TitleType,
+			"price":                price(0),
+		})
+	}
+	breakup = append(breakup, map[string]interface{}{
+		"@ondc/org/item_id":    itemID,
+		"@ondc/org/title_type": "cancellation",
+		"price":                price(fee),
+	})
+
+	return map[string]interface{}{
+		"price":   price(fee),
+		"breakup": breakup,
+	}
+}
+
 // buildFulfillmentWithContacts builds fulfillment structure with contacts
 func (h *CancelHandler) buildFulfillmentWithContacts(ctx context.Context, req *models.ONDCRequest, fulfillmentID string) map[string]interface{} {
 	fulfillment := map[string]interface{}{
@@ -358,10 +532,6 @@ func (h *CancelHandler) buildIdempotencyKey(transactionID, messageID string) str
 	return "cancel:" + transactionID + ":" + messageID
 }
 
-func (h *CancelHandler) respondACK(c *gin.Context, response interface{}) {
-	c.JSON(http.StatusOK, response)
-}
-
 func (h *CancelHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 	ctx := c.Request.Context()
 	traceID := utils.ExtractTraceID(utils.EnsureTraceparent(c.GetHeader("traceparent")))
@@ -394,7 +564,7 @@ func (h *CancelHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 		h.logRequestResponse(ctx, req, response, nil, nil, clientID, traceID)
 	}
 
-	c.JSON(errors.GetHTTPStatus(err), response)
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, errors.GetHTTPStatus(err), response)
 }
 
 func (h *CancelHandler) logRequestResponse(ctx context.Context, req *models.ONDCRequest, ackResponse interface{}, callbackPayload interface{}, orderRecord *OrderRecord, clientID, traceID string) {
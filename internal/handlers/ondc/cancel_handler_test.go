@@ -2,6 +2,7 @@ package ondc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -10,14 +11,22 @@ import (
 	"time"
 
 	"uois-gateway/internal/models"
+	"uois-gateway/internal/services/cancellation"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
+func testCancellationPolicy(t *testing.T) CancellationPolicy {
+	catalog, err := cancellation.LoadCatalog("")
+	require.NoError(t, err)
+	return catalog
+}
+
 func TestCancelHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	logger := zap.NewNop()
@@ -30,7 +39,7 @@ func TestCancelHandler_Success(t *testing.T) {
 	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
 	auditService := new(mockAuditService)
 
-	handler := NewCancelHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewCancelHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, testCancellationPolicy(t), "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	clientOrderID := uuid.New().String()
 	dispatchOrderID := uuid.New().String()
@@ -54,14 +63,15 @@ func TestCancelHandler_Success(t *testing.T) {
 	}
 	orderRecordService.On("GetOrderRecordByOrderID", mock.Anything, "test-client", clientOrderID).Return(orderRecord, nil)
 
-	orderServiceClient.On("CancelOrder", mock.Anything, dispatchOrderID, mock.AnythingOfType("string")).Return(nil)
+	orderServiceClient.On("GetOrder", mock.Anything, dispatchOrderID).Return(&OrderStatus{DispatchOrderID: dispatchOrderID, State: "PENDING", Fulfillment: FulfillmentStatus{State: "Pending"}}, nil)
+	orderServiceClient.On("CancelOrder", mock.Anything, dispatchOrderID, "001").Return(nil)
 
 	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil).Maybe()
 	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil).Maybe()
 
 	callbackService.On("SendCallback", mock.Anything, mock.MatchedBy(func(url string) bool {
 		return strings.HasSuffix(url, "/on_cancel")
-	}), mock.Anything).Return(nil).Maybe()
+	}), mock.Anything).Return("", nil).Maybe()
 
 	requestBody := map[string]interface{}{
 		"context": map[string]interface{}{
@@ -107,3 +117,240 @@ func TestCancelHandler_Success(t *testing.T) {
 	orderRecordService.AssertExpectations(t)
 	auditService.AssertExpectations(t)
 }
+
+func TestCancelHandler_SendSellerCancellation(t *testing.T) {
+	callbackService := new(mockCallbackService)
+	billingStorageService := new(mockBillingStorageService)
+	auditService := new(mockAuditService)
+	handler := NewCancelHandler(callbackService, nil, nil, nil, billingStorageService, nil, auditService, testCancellationPolicy(t), "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	orderRecord := &OrderRecord{
+		DispatchOrderID: "dispatch-123",
+		OrderID:         "order-123",
+		TransactionID:   "txn-123",
+		FulfillmentID:   "F1",
+		BapID:           "buyer.example.com",
+		BapURI:          "https://buyer.example.com",
+		QuotePrice:      models.Price{Value: 141.6, Currency: "INR"},
+		QuoteBreakup: []models.BreakupItem{
+			{ItemID: "I1", TitleType: "delivery", Price: models.Price{Value: 120, Currency: "INR"}},
+			{ItemID: "I1", TitleType: "tax", Price: models.Price{Value: 21.6, Currency: "INR"}},
+		},
+	}
+
+	billingStorageService.On("GetBilling", mock.Anything, "txn-123").Return(nil, nil)
+	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	var payload models.ONDCResponse
+	callbackService.On("SendCallback", mock.Anything, "https://buyer.example.com/on_cancel", mock.Anything).Run(func(args mock.Arguments) {
+		payload = args.Get(2).(models.ONDCResponse)
+	}).Return("", nil)
+
+	err := handler.SendSellerCancellation(context.Background(), orderRecord, "013", "Out-for-delivery", "trace-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "on_cancel", payload.Context.Action)
+	assert.Equal(t, "txn-123", payload.Context.TransactionID)
+
+	order := payload.Message["order"].(map[string]interface{})
+	assert.Equal(t, "order-123", order["id"])
+	assert.Equal(t, "CANCELLED", order["state"])
+	assert.Equal(t, map[string]interface{}{
+		"cancelled_by": "test-bpp-id",
+		"reason":       map[string]interface{}{"id": "013"},
+	}, order["cancellation"])
+	// Refused delivery is priced by the pre-cancel state and reported in the precancel_state tag
+	assert.Equal(t, 120.0, order["quote"].(map[string]interface{})["price"].(map[string]interface{})["value"])
+	fulfillments := order["fulfillments"].([]map[string]interface{})
+	precancel := fulfillments[0]["tags"].([]map[string]interface{})[0]
+	assert.Equal(t, "precancel_state", precancel["code"])
+	assert.Equal(t, "Out-for-delivery", precancel["list"].([]map[string]interface{})[0]["value"])
+}
+
+func TestCancelHandler_SendSellerCancellation_BuyerReason(t *testing.T) {
+	callbackService := new(mockCallbackService)
+	handler := NewCancelHandler(callbackService, nil, nil, nil, nil, nil, nil, testCancellationPolicy(t), "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	err := handler.SendSellerCancellation(context.Background(), &OrderRecord{OrderID: "order-123", BapURI: "https://buyer.example.com"}, "001", "Pending", "trace-1")
+
+	require.Error(t, err)
+	callbackService.AssertNotCalled(t, "SendCallback", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCancelHandler_SendSellerCancellation_MissingCallbackTarget(t *testing.T) {
+	callbackService := new(mockCallbackService)
+	handler := NewCancelHandler(callbackService, nil, nil, nil, nil, nil, nil, testCancellationPolicy(t), "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	err := handler.SendSellerCancellation(context.Background(), &OrderRecord{OrderID: "order-123"}, "017", "Pending", "trace-1")
+
+	assert.Error(t, err)
+	callbackService.AssertNotCalled(t, "SendCallback", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func newCancelRequest(t *testing.T, w *httptest.ResponseRecorder, orderID string, reasonID interface{}) *gin.Context {
+	message := map[string]interface{}{
+		"order": map[string]interface{}{"id": orderID},
+	}
+	if reasonID != nil {
+		message["cancellation_reason_id"] = reasonID
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"context": map[string]interface{}{
+			"domain":         "nic2004:60232",
+			"action":         "cancel",
+			"bap_id":         "buyer.example.com",
+			"bap_uri":        "https://buyer.example.com",
+			"transaction_id": uuid.New().String(),
+			"message_id":     uuid.New().String(),
+			"timestamp":      time.Now().UTC().Format(time.RFC3339),
+			"ttl":            "PT30S",
+		},
+		"message": message,
+	})
+	require.NoError(t, err)
+
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/cancel", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("client", &models.Client{ID: "test-client", ClientCode: "test-client"})
+	return c
+}
+
+func TestCancelHandler_InvalidReason(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		reasonID interface{}
+		wantCode string
+	}{
+		{name: "missing reason", reasonID: nil, wantCode: "65001"},
+		{name: "unknown reason", reasonID: "999", wantCode: "60009"},
+		{name: "free text reason", reasonID: "changed my mind", wantCode: "60009"},
+		{name: "seller-side reason", reasonID: "013", wantCode: "60009"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idempotencyService := new(mockIdempotencyService)
+			orderServiceClient := new(mockOrderServiceClient)
+			orderRecordService := new(mockOrderRecordService)
+			handler := NewCancelHandler(nil, idempotencyService, orderServiceClient, orderRecordService, nil, nil, nil, testCancellationPolicy(t), "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+			idempotencyService.On("CheckIdempotency", mock.Anything, mock.Anything).Return(nil, false, nil)
+
+			w := httptest.NewRecorder()
+			handler.HandleCancel(newCancelRequest(t, w, "order-123", tt.reasonID))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response models.ONDCResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.NotNil(t, response.Error)
+			assert.Equal(t, tt.wantCode, response.Error.Code)
+			orderRecordService.AssertNotCalled(t, "GetOrderRecordByOrderID", mock.Anything, mock.Anything, mock.Anything)
+			orderServiceClient.AssertNotCalled(t, "CancelOrder", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCancelHandler_CancellationCharges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orderRecord := &OrderRecord{
+		DispatchOrderID: "dispatch-123",
+		OrderID:         "order-123",
+		ClientID:        "test-client",
+		TransactionID:   "txn-123",
+		FulfillmentID:   "F1",
+		QuotePrice:      models.Price{Value: 141.6, Currency: "INR"},
+		QuoteBreakup: []models.BreakupItem{
+			{ItemID: "I1", TitleType: "delivery", Price: models.Price{Value: 120, Currency: "INR"}},
+			{ItemID: "I1", TitleType: "tax", Price: models.Price{Value: 21.6, Currency: "INR"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		orderState string
+		state      string
+		wantState  string
+		wantCall   string
+		wantFee    float64
+		wantRTO    bool
+	}{
+		{name: "before rider assignment", state: "Pending", wantState: "Pending", wantCall: "CancelOrder", wantFee: 0},
+		{name: "rider assigned", state: "Agent-assigned", wantState: "Agent-assigned", wantCall: "CancelOrder", wantFee: 50},
+		{name: "picked up turns into RTO", state: "Order-picked-up", wantState: "Order-picked-up", wantCall: "InitiateRTO", wantFee: 120, wantRTO: true},
+		{name: "state derived from order state", orderState: models.OrderStateAssigned, wantState: "Agent-assigned", wantCall: "CancelOrder", wantFee: 50},
+		{name: "unknown state is not charged", orderState: "UNKNOWN", wantCall: "CancelOrder", wantFee: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callbackService := new(mockCallbackService)
+			idempotencyService := new(mockIdempotencyService)
+			orderServiceClient := new(mockOrderServiceClient)
+			orderRecordService := new(mockOrderRecordService)
+			billingStorageService := new(mockBillingStorageService)
+			handler := NewCancelHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, nil, nil, testCancellationPolicy(t), "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+			idempotencyService.On("CheckIdempotency", mock.Anything, mock.Anything).Return(nil, false, nil)
+			idempotencyService.On("StoreIdempotency", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			billingStorageService.On("GetBilling", mock.Anything, mock.Anything).Return(nil, nil)
+			orderRecordService.On("GetOrderRecordByOrderID", mock.Anything, "test-client", "order-123").Return(orderRecord, nil)
+			orderServiceClient.On("GetOrder", mock.Anything, "dispatch-123").Return(&OrderStatus{DispatchOrderID: "dispatch-123", State: tt.orderState, Fulfillment: FulfillmentStatus{State: tt.state}}, nil)
+			if tt.wantCall == "InitiateRTO" {
+				orderServiceClient.On("InitiateRTO", mock.Anything, "dispatch-123").Return(nil)
+			} else {
+				orderServiceClient.On("CancelOrder", mock.Anything, "dispatch-123", "010").Return(nil)
+			}
+
+			callbackSent := make(chan models.ONDCResponse, 1)
+			callbackService.On("SendCallback", mock.Anything, "https://buyer.example.com/on_cancel", mock.Anything).Run(func(args mock.Arguments) {
+				callbackSent <- args.Get(2).(models.ONDCResponse)
+			}).Return("", nil)
+
+			w := httptest.NewRecorder()
+			handler.HandleCancel(newCancelRequest(t, w, "order-123", "010"))
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var payload models.ONDCResponse
+			select {
+			case payload = <-callbackSent:
+			case <-time.After(time.Second):
+				t.Fatal("/on_cancel callback not sent")
+			}
+
+			order := payload.Message["order"].(map[string]interface{})
+			assert.Equal(t, map[string]interface{}{
+				"cancelled_by": "buyer.example.com",
+				"reason":       map[string]interface{}{"id": "010"},
+			}, order["cancellation"])
+
+			quote := order["quote"].(map[string]interface{})
+			assert.Equal(t, map[string]interface{}{"value": tt.wantFee, "currency": "INR"}, quote["price"])
+			breakup := quote["breakup"].([]map[string]interface{})
+			require.Len(t, breakup, 3)
+			assert.Equal(t, 0.0, breakup[0]["price"].(map[string]interface{})["value"])
+			assert.Equal(t, "cancellation", breakup[2]["@ondc/org/title_type"])
+			assert.Equal(t, "I1", breakup[2]["@ondc/org/item_id"])
+			assert.Equal(t, tt.wantFee, breakup[2]["price"].(map[string]interface{})["value"])
+
+			fulfillments := order["fulfillments"].([]map[string]interface{})
+			if tt.wantState != "" {
+				precancel := fulfillments[0]["tags"].([]map[string]interface{})[0]
+				assert.Equal(t, "precancel_state", precancel["code"])
+				assert.Equal(t, tt.wantState, precancel["list"].([]map[string]interface{})[0]["value"])
+			} else {
+				assert.NotContains(t, fulfillments[0], "tags")
+			}
+			if tt.wantRTO {
+				require.Len(t, fulfillments, 2)
+				assert.Equal(t, "F1-RTO", fulfillments[1]["id"])
+				assert.Equal(t, "RTO", fulfillments[1]["type"])
+			} else {
+				assert.Len(t, fulfillments, 1)
+			}
+
+			orderServiceClient.AssertExpectations(t)
+		})
+	}
+}
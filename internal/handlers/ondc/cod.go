@@ -0,0 +1,103 @@
+package ondc
+
+import (
+	"context"
+	"time"
+
+	"uois-gateway/internal/models"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// codReconciliationRetries bounds the background retries of a failed reconciliation insert
+	codReconciliationRetries = 5
+	// codReconciliationRetryBackoff is the first retry backoff; it doubles on every retry
+	codReconciliationRetryBackoff = 2 * time.Second
+)
+
+// codAllowed reports whether cash on delivery (ON-FULFILLMENT) is enabled for both the client
+// (Metadata["cod_enabled"]) and the requested delivery category (delivery policy "cod")
+func codAllowed(client *models.Client, policy DeliveryPolicy, req models.DeliveryPolicyRequest) bool {
+	return client != nil && client.CODEnabled() && policy.AllowsCOD(req)
+}
+
+// buildCODPayment builds order.payment of /on_init and /on_confirm for a COD order
+// The LSP (BPP) collects the cash at delivery and remits it to the buyer app, using the
+// settlement details the buyer app sent in its payment block (ONDC v1.2.0 payment notes)
+func buildCODPayment(paymentInfo map[string]interface{}, collectionAmount string) map[string]interface{} {
+	payment := map[string]interface{}{
+		"type":                        models.PaymentTypeOnFulfillment,
+		"collected_by":                "BPP",
+		"@ondc/org/collection_amount": collectionAmount,
+	}
+
+	details, _ := paymentInfo["@ondc/org/settlement_details"].([]interface{})
+	settlements := make([]map[string]interface{}, 0, len(details))
+	for _, detail := range details {
+		source, ok := detail.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		settlement := make(map[string]interface{}, len(source)+2)
+		for key, value := range source {
+			settlement[key] = value
+		}
+		// Collected cash is the sale amount, owed by the LSP to the buyer app
+		settlement["settlement_counterparty"] = "buyer-app"
+		settlement["settlement_phase"] = "sale-amount"
+		settlements = append(settlements, settlement)
+	}
+	if len(settlements) > 0 {
+		payment["@ondc/org/settlement_details"] = settlements
+	}
+
+	return payment
+}
+
+// buildCODTags builds the order tags announcing a COD order and its collection amount
+func buildCODTags(collectionAmount string) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"code": "cod",
+			"list": []map[string]interface{}{
+				{"code": "collection_amount", "value": collectionAmount},
+				{"code": "collected_by", "value": "BPP"},
+			},
+		},
+	}
+}
+
+// retryCODReconciliation retries the reconciliation insert of a confirmed COD order with exponential backoff
+// The insert is idempotent per client_id + order.id, so a retry after a lost success is harmless
+func (h *ConfirmHandler) retryCODReconciliation(ctx context.Context, record *models.CODReconciliation, traceID string) {
+	backoff := h.codRetryBackoff
+	var err error
+	for retry := 1; retry <= codReconciliationRetries; retry++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		if err = h.codReconciliationService.CreateReconciliation(ctx, record); err == nil {
+			h.logger.Info("cod reconciliation record created after retry",
+				zap.String("trace_id", traceID),
+				zap.String("order.id", record.OrderID),
+				zap.Int("retry", retry),
+			)
+			return
+		}
+	}
+
+	// Retries exhausted: the record must be backfilled from the order
+	h.logger.Error("failed to create cod reconciliation record",
+		zap.Error(err),
+		zap.String("trace_id", traceID),
+		zap.String("order.id", record.OrderID),
+		zap.String("client_id", record.ClientID),
+		zap.String("dispatch_order_id", record.DispatchOrderID),
+		zap.String("collection_amount", record.CollectionAmount),
+	)
+}
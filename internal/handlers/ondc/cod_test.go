@@ -0,0 +1,308 @@
+package ondc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockCODReconciliationService struct {
+	mock.Mock
+}
+
+func (m *mockCODReconciliationService) CreateReconciliation(ctx context.Context, record *models.CODReconciliation) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+var codRules = []models.DeliveryCategoryRule{
+	{CategoryID: "Immediate Delivery", MaxTAT: "PT60M", COD: true},
+	{CategoryID: "Standard Delivery", MaxTAT: "PT60M", RequireTAT: true},
+}
+
+func TestCODAllowed(t *testing.T) {
+	policy := testDeliveryPolicy(t, codRules...)
+	codClient := &models.Client{ID: "client-1", Metadata: map[string]interface{}{"cod_enabled": true}}
+	prepaidClient := &models.Client{ID: "client-2"}
+
+	assert.True(t, codAllowed(codClient, policy, models.DeliveryPolicyRequest{CategoryID: "Immediate Delivery"}))
+	assert.False(t, codAllowed(codClient, policy, models.DeliveryPolicyRequest{CategoryID: "Standard Delivery"}))
+	assert.False(t, codAllowed(prepaidClient, policy, models.DeliveryPolicyRequest{CategoryID: "Immediate Delivery"}))
+	assert.False(t, codAllowed(nil, policy, models.DeliveryPolicyRequest{CategoryID: "Immediate Delivery"}))
+}
+
+func TestSearchHandler_CODNotEnabledForClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	eventPublisher := new(mockEventPublisher)
+	auditService := new(mockAuditService)
+	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil).Maybe()
+	handler := NewSearchHandler(eventPublisher, nil, testStreamsConfig(), nil, nil, nil, auditService, testDeliveryPolicy(t, codRules...), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "", nil, zap.NewNop())
+
+	requestBody := map[string]interface{}{
+		"context": map[string]interface{}{
+			"domain":         "nic2004:60232",
+			"action":         "search",
+			"transaction_id": uuid.New().String(),
+			"message_id":     uuid.New().String(),
+			"timestamp":      time.Now().UTC().Format(time.RFC3339),
+			"ttl":            "PT30S",
+			"bap_uri":        "https://buyer.example.com",
+		},
+		"message": map[string]interface{}{
+			"intent": map[string]interface{}{
+				"category": map[string]interface{}{"id": "Immediate Delivery"},
+				"payment":  map[string]interface{}{"type": "ON-FULFILLMENT", "@ondc/org/collection_amount": "300.00"},
+			},
+		},
+	}
+
+	body, _ := json.Marshal(requestBody)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/search", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("client", &models.Client{ID: "test-client", ClientCode: "test-client"})
+
+	handler.HandleSearch(c)
+
+	var response models.ONDCResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "65004", response.Error.Code)
+	eventPublisher.AssertNotCalled(t, "PublishEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBuildCODPayment(t *testing.T) {
+	paymentInfo := map[string]interface{}{
+		"type":                        "ON-FULFILLMENT",
+		"@ondc/org/collection_amount": "300.00",
+		"@ondc/org/settlement_details": []interface{}{
+			map[string]interface{}{"settlement_counterparty": "buyer", "settlement_type": "upi", "upi_address": "seller@upi"},
+		},
+	}
+
+	payment := buildCODPayment(paymentInfo, "300.00")
+
+	assert.Equal(t, "ON-FULFILLMENT", payment["type"])
+	assert.Equal(t, "BPP", payment["collected_by"])
+	assert.Equal(t, "300.00", payment["@ondc/org/collection_amount"])
+	assert.Equal(t, []map[string]interface{}{{
+		"settlement_counterparty": "buyer-app",
+		"settlement_phase":        "sale-amount",
+		"settlement_type":         "upi",
+		"upi_address":             "seller@upi",
+	}}, payment["@ondc/org/settlement_details"])
+
+	_, ok := buildCODPayment(nil, "300.00")["@ondc/org/settlement_details"]
+	assert.False(t, ok)
+}
+
+func TestValidatePaymentTerms(t *testing.T) {
+	codRecord := &OrderRecord{PaymentType: "ON-FULFILLMENT", CollectionAmount: "300.00"}
+	prepaidRecord := &OrderRecord{PaymentType: "ON-ORDER"}
+
+	assert.Nil(t, validatePaymentTerms(codRecord, "ON-FULFILLMENT", "300"))
+	assert.Nil(t, validatePaymentTerms(prepaidRecord, "ON-ORDER", ""))
+	assert.Nil(t, validatePaymentTerms(&OrderRecord{}, "POST-FULFILLMENT", ""))
+
+	err := validatePaymentTerms(prepaidRecord, "ON-FULFILLMENT", "300.00")
+	require.NotNil(t, err)
+	assert.Equal(t, 65004, err.Code)
+
+	err = validatePaymentTerms(codRecord, "ON-ORDER", "")
+	require.NotNil(t, err)
+	assert.Equal(t, 65001, err.Code)
+
+	err = validatePaymentTerms(codRecord, "ON-FULFILLMENT", "350.00")
+	require.NotNil(t, err)
+	assert.Equal(t, 65001, err.Code)
+}
+
+func TestConfirmHandler_CODCreatesReconciliation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	eventPublisher := new(mockEventPublisher)
+	eventConsumer := new(mockEventConsumer)
+	callbackService := new(mockCallbackService)
+	idempotencyService := new(mockIdempotencyService)
+	orderServiceClient := new(mockOrderServiceClient)
+	orderRecordService := new(mockOrderRecordService)
+	billingStorageService := new(mockBillingStorageService)
+	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
+	auditService := new(mockAuditService)
+	codReconciliationService := new(mockCODReconciliationService)
+
+	handler := NewConfirmHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, codReconciliationService, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	quoteID := uuid.New().String()
+	transactionID := uuid.New().String()
+	clientOrderID := uuid.New().String()
+	dispatchOrderID := uuid.New().String()
+
+	idempotencyService.On("CheckIdempotency", mock.Anything, mock.AnythingOfType("string")).Return(nil, false, nil)
+	idempotencyService.On("StoreIdempotency", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("time.Duration")).Return(nil)
+	billingStorageService.On("GetBilling", mock.Anything, mock.AnythingOfType("string")).Return(nil, nil).Maybe()
+	fulfillmentContactsStorageService.On("GetFulfillmentContacts", mock.Anything, mock.AnythingOfType("string")).Return(nil, nil).Maybe()
+	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil).Maybe()
+	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil).Maybe()
+	callbackService.On("SendCallback", mock.Anything, mock.Anything, mock.Anything).Return("", nil).Maybe()
+
+	orderServiceClient.On("ValidateQuoteIDTTL", mock.Anything, quoteID).Return(true, nil)
+	orderRecordService.On("GetOrderRecordByQuoteID", mock.Anything, quoteID).Return(&OrderRecord{
+		QuoteID:          quoteID,
+		TransactionID:    transactionID,
+		PaymentType:      "ON-FULFILLMENT",
+		CollectionAmount: "300.00",
+	}, nil)
+	orderRecordService.On("UpdateOrderRecord", mock.Anything, mock.MatchedBy(func(record *OrderRecord) bool {
+		return record.OrderID == clientOrderID && record.PaymentType == "ON-FULFILLMENT" && record.CollectionAmount == "300.00"
+	})).Return(nil)
+	eventPublisher.On("PublishEvent", mock.Anything, "stream.uois.confirm_requested", mock.MatchedBy(func(event *models.ConfirmRequestedEvent) bool {
+		return event.PaymentType == "ON-FULFILLMENT" && event.CollectionAmount == "300.00"
+	})).Return(nil)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.order_confirmed", "uois-gateway-consumers", quoteID, mock.AnythingOfType("time.Duration")).Return(&models.OrderConfirmedEvent{
+		QuoteID:         quoteID,
+		DispatchOrderID: dispatchOrderID,
+	}, nil)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.order_confirm_failed", "uois-gateway-consumers", quoteID, mock.AnythingOfType("time.Duration")).Return(nil, nil)
+	codReconciliationService.On("CreateReconciliation", mock.Anything, mock.MatchedBy(func(record *models.CODReconciliation) bool {
+		return record.OrderID == clientOrderID &&
+			record.ClientID == "cod-client" &&
+			record.DispatchOrderID == dispatchOrderID &&
+			record.CollectionAmount == "300.00"
+	})).Return(nil)
+
+	requestBody := map[string]interface{}{
+		"context": map[string]interface{}{
+			"domain":         "nic2004:60232",
+			"action":         "confirm",
+			"transaction_id": transactionID,
+			"message_id":     uuid.New().String(),
+			"timestamp":      time.Now().Format(time.RFC3339),
+			"ttl":            "PT30S",
+			"bap_uri":        "https://buyer.example.com",
+		},
+		"message": map[string]interface{}{
+			"order": map[string]interface{}{
+				"id":      clientOrderID,
+				"quote":   map[string]interface{}{"id": quoteID},
+				"payment": map[string]interface{}{"type": "ON-FULFILLMENT", "collected_by": "BPP", "@ondc/org/collection_amount": "300.00"},
+			},
+		},
+	}
+
+	body, _ := json.Marshal(requestBody)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/confirm", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("client", &models.Client{ID: "cod-client", ClientCode: "cod-client", Metadata: map[string]interface{}{"cod_enabled": true}})
+
+	handler.HandleConfirm(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	eventPublisher.AssertExpectations(t)
+	orderRecordService.AssertExpectations(t)
+	codReconciliationService.AssertExpectations(t)
+}
+
+func TestConfirmHandler_RetryCODReconciliation(t *testing.T) {
+	codReconciliationService := new(mockCODReconciliationService)
+	handler := NewConfirmHandler(nil, nil, testStreamsConfig(), nil, nil, nil, nil, nil, nil, nil, codReconciliationService, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+	handler.codRetryBackoff = time.Millisecond
+
+	record := &models.CODReconciliation{OrderID: "order-123", ClientID: "cod-client", CollectionAmount: "300.00"}
+	codReconciliationService.On("CreateReconciliation", mock.Anything, record).Return(assert.AnError).Once()
+	codReconciliationService.On("CreateReconciliation", mock.Anything, record).Return(nil).Once()
+
+	handler.retryCODReconciliation(context.Background(), record, "trace-1")
+
+	codReconciliationService.AssertExpectations(t)
+	codReconciliationService.AssertNumberOfCalls(t, "CreateReconciliation", 2)
+}
+
+func TestConfirmHandler_RetryCODReconciliation_GivesUp(t *testing.T) {
+	codReconciliationService := new(mockCODReconciliationService)
+	handler := NewConfirmHandler(nil, nil, testStreamsConfig(), nil, nil, nil, nil, nil, nil, nil, codReconciliationService, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+	handler.codRetryBackoff = time.Millisecond
+
+	codReconciliationService.On("CreateReconciliation", mock.Anything, mock.Anything).Return(assert.AnError)
+
+	handler.retryCODReconciliation(context.Background(), &models.CODReconciliation{OrderID: "order-123"}, "trace-1")
+
+	codReconciliationService.AssertNumberOfCalls(t, "CreateReconciliation", codReconciliationRetries)
+}
+
+func TestConfirmHandler_BuildOnConfirmCallback_COD(t *testing.T) {
+	billingStorageService := new(mockBillingStorageService)
+	billingStorageService.On("GetBilling", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
+	fulfillmentContactsStorageService.On("GetFulfillmentContacts", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	handler := NewConfirmHandler(nil, nil, testStreamsConfig(), nil, nil, nil, nil, billingStorageService, fulfillmentContactsStorageService, nil, nil, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	req := &models.ONDCRequest{
+		Context: models.ONDCContext{TransactionID: "txn-1"},
+		Message: map[string]interface{}{
+			"order": map[string]interface{}{
+				"payment": map[string]interface{}{
+					"type":                         "ON-FULFILLMENT",
+					"@ondc/org/settlement_details": []interface{}{map[string]interface{}{"settlement_type": "neft", "settlement_bank_account_no": "XXXXXXXXXX"}},
+				},
+			},
+		},
+	}
+	orderRecord := &OrderRecord{OrderID: "order-1", FulfillmentID: "F1", PaymentType: "ON-FULFILLMENT", CollectionAmount: "300.00"}
+
+	resp := handler.buildOnConfirmCallback(context.Background(), req, &models.OrderConfirmedEvent{DispatchOrderID: "dispatch-1"}, orderRecord)
+
+	require.Nil(t, resp.Error)
+	order := resp.Message["order"].(map[string]interface{})
+	payment := order["payment"].(map[string]interface{})
+	assert.Equal(t, "300.00", payment["@ondc/org/collection_amount"])
+	assert.Equal(t, "BPP", payment["collected_by"])
+	settlements := payment["@ondc/org/settlement_details"].([]map[string]interface{})
+	require.Len(t, settlements, 1)
+	assert.Equal(t, "buyer-app", settlements[0]["settlement_counterparty"])
+	assert.Equal(t, buildCODTags("300.00"), order["tags"])
+
+	orderRecord.PaymentType, orderRecord.CollectionAmount = "ON-ORDER", ""
+	resp = handler.buildOnConfirmCallback(context.Background(), req, &models.OrderConfirmedEvent{DispatchOrderID: "dispatch-1"}, orderRecord)
+	assert.NotContains(t, resp.Message["order"], "payment")
+}
+
+func TestInitHandler_BuildOnInitCallback_COD(t *testing.T) {
+	handler := NewInitHandler(nil, nil, testStreamsConfig(), nil, nil, nil, nil, nil, nil, nil, testDeliveryPolicy(t, codRules...), "P1", "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	req := &models.ONDCRequest{
+		Context: models.ONDCContext{TransactionID: "txn-1"},
+		Message: map[string]interface{}{
+			"order": map[string]interface{}{
+				"payment": map[string]interface{}{"type": "ON-FULFILLMENT", "@ondc/org/collection_amount": "300.00"},
+			},
+		},
+	}
+	quoteCreated := &models.QuoteCreatedEvent{QuoteID: "quote-1", Price: models.Price{Value: 60, Currency: "INR"}, TTL: "PT15M"}
+
+	resp := handler.buildOnInitCallback(context.Background(), req, quoteCreated, "F1")
+
+	require.Nil(t, resp.Error)
+	order := resp.Message["order"].(map[string]interface{})
+	payment := order["payment"].(map[string]interface{})
+	assert.Equal(t, "ON-FULFILLMENT", payment["type"])
+	assert.Equal(t, "300.00", payment["@ondc/org/collection_amount"])
+	assert.Equal(t, buildCODTags("300.00"), order["tags"])
+}
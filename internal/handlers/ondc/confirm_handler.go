@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/middleware"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/services/audit"
 	"uois-gateway/internal/utils"
@@ -23,6 +26,7 @@ import (
 type ConfirmHandler struct {
 	eventPublisher                    EventPublisher
 	eventConsumer                     EventConsumer
+	streams                           config.StreamsConfig // Consumed stream names and consumer group (STREAM_*, CONSUMER_GROUP_NAME)
 	callbackService                   CallbackService
 	idempotencyService                IdempotencyService
 	orderServiceClient                OrderServiceClient
@@ -30,8 +34,11 @@ type ConfirmHandler struct {
 	billingStorageService             BillingStorageService
 	fulfillmentContactsStorageService FulfillmentContactsStorageService
 	auditService                      AuditService
+	codReconciliationService          CODReconciliationService
+	codRetryBackoff                   time.Duration
 	bppID                             string // BPP ID (ONDC-registered Seller NP identity)
 	bppURI                            string // BPP URI
+	responseSigner                    ResponseSigner
 	logger                            *zap.Logger
 }
 
@@ -39,6 +46,7 @@ type ConfirmHandler struct {
 func NewConfirmHandler(
 	eventPublisher EventPublisher,
 	eventConsumer EventConsumer,
+	streams config.StreamsConfig,
 	callbackService CallbackService,
 	idempotencyService IdempotencyService,
 	orderServiceClient OrderServiceClient,
@@ -46,13 +54,16 @@ func NewConfirmHandler(
 	billingStorageService BillingStorageService,
 	fulfillmentContactsStorageService FulfillmentContactsStorageService,
 	auditService AuditService,
+	codReconciliationService CODReconciliationService,
 	bppID string,
 	bppURI string,
+	responseSigner ResponseSigner,
 	logger *zap.Logger,
 ) *ConfirmHandler {
 	return &ConfirmHandler{
 		eventPublisher:                    eventPublisher,
 		eventConsumer:                     eventConsumer,
+		streams:                           streams,
 		callbackService:                   callbackService,
 		idempotencyService:                idempotencyService,
 		orderServiceClient:                orderServiceClient,
@@ -60,8 +71,11 @@ func NewConfirmHandler(
 		billingStorageService:             billingStorageService,
 		fulfillmentContactsStorageService: fulfillmentContactsStorageService,
 		auditService:                      auditService,
+		codReconciliationService:          codReconciliationService,
+		codRetryBackoff:                   codReconciliationRetryBackoff,
 		bppID:                             bppID,
 		bppURI:                            bppURI,
+		responseSigner:                    responseSigner,
 		logger:                            logger,
 	}
 }
@@ -92,21 +106,36 @@ func (h *ConfirmHandler) HandleConfirm(c *gin.Context) {
 		return
 	}
 
-	// Validate payment type (Dispatch does not support COD)
+	// Get client ID from context
+	client, _ := c.Get("client")
+	cl, _ := client.(*models.Client)
+	var clientID string
+	if cl != nil {
+		clientID = cl.ID
+	}
+
+	// Validate payment type (COD only for opted-in clients; the category was checked in /init)
 	order, _ := req.Message["order"].(map[string]interface{})
 	paymentInfo, _ := order["payment"].(map[string]interface{})
-	if err := utils.ValidatePaymentType(paymentInfo); err != nil {
+	if err := utils.ValidatePaymentType(paymentInfo, cl != nil && cl.CODEnabled()); err != nil {
 		h.logger.Warn("payment type validation failed", zap.Error(err), zap.String("trace_id", traceID))
 		h.respondNACK(c, err)
 		return
 	}
+	paymentType, _ := paymentInfo["type"].(string)
+	collectionAmount, domainErr := utils.ExtractCollectionAmount(paymentInfo)
+	if domainErr != nil {
+		h.logger.Warn("invalid COD collection amount", zap.Error(domainErr), zap.String("trace_id", traceID))
+		h.respondNACK(c, domainErr)
+		return
+	}
 
 	// Check idempotency
 	idempotencyKey := h.buildIdempotencyKey(req.Context.TransactionID, req.Context.MessageID)
 	if existingResponseBytes, exists, err := h.idempotencyService.CheckIdempotency(ctx, idempotencyKey); err == nil && exists {
-		var existingResponse models.ONDCACKResponse
-		if err := json.Unmarshal(existingResponseBytes, &existingResponse); err == nil {
-			h.respondACK(c, existingResponse)
+		// Serve the stored bytes verbatim so the signature covers the original response
+		if json.Valid(existingResponseBytes) {
+			middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, existingResponseBytes)
 			return
 		}
 	}
@@ -127,8 +156,22 @@ func (h *ConfirmHandler) HandleConfirm(c *gin.Context) {
 		return
 	}
 
+	// Payment terms must match those agreed in /init; without a payment block the /init terms apply
+	if paymentType == "" {
+		paymentType = orderRecord.PaymentType
+		collectionAmount = orderRecord.CollectionAmount
+	}
+	if err := validatePaymentTerms(orderRecord, paymentType, collectionAmount); err != nil {
+		h.logger.Warn("payment terms do not match /init", zap.Error(err), zap.String("trace_id", traceID), zap.String("quote_id", quoteID))
+		h.respondNACK(c, err)
+		return
+	}
+
 	// Validate quote_id TTL via Order Service
-	valid, err := h.orderServiceClient.ValidateQuoteIDTTL(ctx, quoteID)
+	// Retries of the Order Service call stop once the request TTL runs out
+	orderCtx, cancelOrderCtx := utils.WithONDCDeadline(utils.WithRequestKey(ctx, idempotencyKey), req.Context.Timestamp, req.Context.TTL)
+	defer cancelOrderCtx()
+	valid, err := h.orderServiceClient.ValidateQuoteIDTTL(orderCtx, quoteID)
 	if err != nil {
 		h.logger.Error("failed to validate quote_id TTL", zap.Error(err), zap.String("trace_id", traceID), zap.String("quote_id", quoteID))
 		domainErr, ok := err.(*errors.DomainError)
@@ -144,15 +187,8 @@ func (h *ConfirmHandler) HandleConfirm(c *gin.Context) {
 		return
 	}
 
-	// Get client ID from context
-	client, _ := c.Get("client")
-	var clientID string
-	if cl, ok := client.(*models.Client); ok {
-		clientID = cl.ID
-	}
-
 	// Publish CONFIRM_REQUESTED event
-	confirmEvent := h.buildConfirmRequestedEvent(quoteID, clientID, paymentInfo, traceparent)
+	confirmEvent := h.buildConfirmRequestedEvent(quoteID, clientID, paymentInfo, paymentType, collectionAmount, traceparent)
 	if err := h.eventPublisher.PublishEvent(ctx, "stream.uois.confirm_requested", confirmEvent); err != nil {
 		h.logger.Error("failed to publish CONFIRM_REQUESTED event", zap.Error(err), zap.String("trace_id", traceID), zap.String("quote_id", quoteID))
 		h.respondNACK(c, errors.NewDomainError(65020, "internal error", "failed to publish event"))
@@ -168,18 +204,13 @@ func (h *ConfirmHandler) HandleConfirm(c *gin.Context) {
 		h.respondNACK(c, domainErr)
 		return
 	}
-	var orderEvent interface{}
 
-	// Try ORDER_CONFIRMED first
-	orderEvent, err = h.eventConsumer.ConsumeEvent(ctx, "stream.uois.order_confirmed", "uois-gateway-consumers", quoteID, ttlDuration)
+	// Wait for ORDER_CONFIRMED or ORDER_CONFIRM_FAILED, whichever arrives first
+	orderEvent, err := consumeFirstEvent(ctx, h.eventConsumer, []string{h.streams.OrderConfirmed, h.streams.OrderConfirmFailed}, h.streams.ConsumerGroupName, quoteID, ttlDuration)
 	if err != nil {
-		// Fallback to ORDER_CONFIRM_FAILED stream
-		orderEvent, err = h.eventConsumer.ConsumeEvent(ctx, "stream.uois.order_confirm_failed", "uois-gateway-consumers", quoteID, ttlDuration)
-		if err != nil {
-			h.logger.Error("failed to consume order event from both streams", zap.Error(err), zap.String("trace_id", traceID), zap.String("quote_id", quoteID))
-			h.respondNACK(c, errors.NewDomainError(65020, "internal error", "failed to consume event"))
-			return
-		}
+		h.logger.Error("failed to consume order event from both streams", zap.Error(err), zap.String("trace_id", traceID), zap.String("quote_id", quoteID))
+		h.respondNACK(c, errors.NewDomainError(65020, "internal error", "failed to consume event"))
+		return
 	}
 
 	// Update order record with dispatch_order_id and order.id if ORDER_CONFIRMED
@@ -202,9 +233,30 @@ func (h *ConfirmHandler) HandleConfirm(c *gin.Context) {
 		orderRecord.DispatchOrderID = orderConfirmed.DispatchOrderID
 		orderRecord.OrderID = orderID // Use buyer-provided order.id (or generated fallback)
 		orderRecord.ClientID = clientID
+		orderRecord.BapID = req.Context.BapID
+		orderRecord.BapURI = req.Context.BapURI
+		orderRecord.Domain = req.Context.Domain
+		orderRecord.PaymentType = paymentType
+		orderRecord.CollectionAmount = collectionAmount
 		if err := h.orderRecordService.UpdateOrderRecord(ctx, orderRecord); err != nil {
 			h.logger.Warn("failed to update order record with dispatch_order_id and order.id", zap.Error(err), zap.String("trace_id", traceID), zap.String("quote_id", quoteID), zap.String("dispatch_order_id", orderConfirmed.DispatchOrderID), zap.String("order.id", orderID))
 		}
+
+		// Track the cash the rider will collect until it is remitted to the buyer app
+		if paymentType == models.PaymentTypeOnFulfillment && h.codReconciliationService != nil {
+			reconciliation := &models.CODReconciliation{
+				OrderID:          orderID,
+				ClientID:         clientID,
+				DispatchOrderID:  orderConfirmed.DispatchOrderID,
+				TransactionID:    req.Context.TransactionID,
+				CollectionAmount: collectionAmount,
+			}
+			if err := h.codReconciliationService.CreateReconciliation(ctx, reconciliation); err != nil {
+				// Order is already confirmed by Order Service, so the insert is retried instead of failing /confirm
+				h.logger.Warn("failed to create cod reconciliation record, retrying in background", zap.Error(err), zap.String("trace_id", traceID), zap.String("order.id", orderID), zap.String("dispatch_order_id", orderConfirmed.DispatchOrderID))
+				go h.retryCODReconciliation(context.WithoutCancel(ctx), reconciliation, traceID)
+			}
+		}
 	}
 
 	// Extract fulfillment contacts and store in Redis (if not already stored by /init)
@@ -234,10 +286,10 @@ func (h *ConfirmHandler) HandleConfirm(c *gin.Context) {
 	h.logRequestResponse(ctx, &req, response, nil, "", auditQuoteID, auditOrderID, auditDispatchOrderID, clientID, traceID)
 
 	// Send callback asynchronously (pass orderRecord for order.id retrieval)
-	go h.sendConfirmCallback(ctx, &req, orderEvent, orderRecord, traceID)
+	go h.sendConfirmCallback(context.WithoutCancel(ctx), &req, orderEvent, orderRecord, traceID)
 
 	// Return ACK
-	h.respondACK(c, response)
+	middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, responseBytes)
 }
 
 func (h *ConfirmHandler) extractConfirmData(req *models.ONDCRequest) (string, string, map[string]interface{}, error) {
@@ -270,7 +322,32 @@ func (h *ConfirmHandler) extractConfirmData(req *models.ONDCRequest) (string, st
 	return quoteID, orderID, paymentInfo, nil
 }
 
-func (h *ConfirmHandler) buildConfirmRequestedEvent(quoteID, clientID string, paymentInfo map[string]interface{}, traceparent string) *models.ConfirmRequestedEvent {
+// validatePaymentTerms checks the /confirm payment against the terms agreed in /init
+// COD can only be confirmed for a COD /init, with the same collection amount
+func validatePaymentTerms(orderRecord *OrderRecord, paymentType, collectionAmount string) *errors.DomainError {
+	isCOD := paymentType == models.PaymentTypeOnFulfillment
+	wasCOD := orderRecord.PaymentType == models.PaymentTypeOnFulfillment
+
+	if isCOD && !wasCOD {
+		return errors.NewDomainError(65004, "not serviceable", "Payment type ON-FULFILLMENT (COD) was not agreed in /init")
+	}
+	if wasCOD && !isCOD {
+		return errors.NewDomainError(65001, "invalid request", fmt.Sprintf("payment.type %s does not match ON-FULFILLMENT agreed in /init", paymentType))
+	}
+	if isCOD && !sameAmount(collectionAmount, orderRecord.CollectionAmount) {
+		return errors.NewDomainError(65001, "invalid request", fmt.Sprintf("payment.@ondc/org/collection_amount %s does not match %s agreed in /init", collectionAmount, orderRecord.CollectionAmount))
+	}
+	return nil
+}
+
+// sameAmount compares decimal amounts numerically ("300" equals "300.00")
+func sameAmount(a, b string) bool {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	return errA == nil && errB == nil && x == y
+}
+
+func (h *ConfirmHandler) buildConfirmRequestedEvent(quoteID, clientID string, paymentInfo map[string]interface{}, paymentType, collectionAmount, traceparent string) *models.ConfirmRequestedEvent {
 	traceparent = utils.EnsureTraceparent(traceparent)
 
 	return &models.ConfirmRequestedEvent{
@@ -280,9 +357,11 @@ func (h *ConfirmHandler) buildConfirmRequestedEvent(quoteID, clientID string, pa
 			Traceparent: traceparent,
 			Timestamp:   time.Now(),
 		},
-		QuoteID:     quoteID,
-		ClientID:    clientID,
-		PaymentInfo: paymentInfo,
+		QuoteID:          quoteID,
+		ClientID:         clientID,
+		PaymentInfo:      paymentInfo,
+		PaymentType:      paymentType,
+		CollectionAmount: collectionAmount,
 	}
 }
 
@@ -342,10 +421,12 @@ func (h *ConfirmHandler) sendConfirmCallback(ctx context.Context, req *models.ON
 	callbackURL := req.Context.BapURI + "/on_confirm"
 	callbackPayload := h.buildOnConfirmCallback(ctx, req, orderEvent, orderRecord)
 
-	if err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload); err != nil {
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
 		h.logger.Error("failed to send /on_confirm callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "failed", err.Error())
 	} else {
+		h.logger.Info("/on_confirm callback accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "success", "")
 	}
 }
@@ -401,6 +482,14 @@ func (h *ConfirmHandler) buildOnConfirmCallback(ctx context.Context, req *models
 			orderMap["billing"] = billing
 		}
 
+		// COD: collection amount agreed in /init, settled to the buyer app's account
+		if orderRecord.PaymentType == models.PaymentTypeOnFulfillment && orderRecord.CollectionAmount != "" {
+			order, _ := req.Message["order"].(map[string]interface{})
+			paymentInfo, _ := order["payment"].(map[string]interface{})
+			orderMap["payment"] = buildCODPayment(paymentInfo, orderRecord.CollectionAmount)
+			orderMap["tags"] = buildCODTags(orderRecord.CollectionAmount)
+		}
+
 		message := map[string]interface{}{
 			"order": orderMap,
 		}
@@ -552,10 +641,6 @@ func (h *ConfirmHandler) buildIdempotencyKey(transactionID, messageID string) st
 	return "confirm:" + transactionID + ":" + messageID
 }
 
-func (h *ConfirmHandler) respondACK(c *gin.Context, response interface{}) {
-	c.JSON(http.StatusOK, response)
-}
-
 func (h *ConfirmHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 	ctx := c.Request.Context()
 	traceID := utils.ExtractTraceID(utils.EnsureTraceparent(c.GetHeader("traceparent")))
@@ -588,7 +673,7 @@ func (h *ConfirmHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 		h.logRequestResponse(ctx, req, response, nil, "", "", "", "", clientID, traceID)
 	}
 
-	c.JSON(errors.GetHTTPStatus(err), response)
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, errors.GetHTTPStatus(err), response)
 }
 
 func (h *ConfirmHandler) logRequestResponse(ctx context.Context, req *models.ONDCRequest, ackResponse interface{}, callbackPayload interface{}, searchID, quoteID, orderID, dispatchOrderID, clientID, traceID string) {
@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"uois-gateway/internal/models"
-	"uois-gateway/pkg/errors"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -33,7 +32,7 @@ func TestConfirmHandler_Success(t *testing.T) {
 	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
 	auditService := new(mockAuditService)
 
-	handler := NewConfirmHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewConfirmHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, nil, "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	quoteID := uuid.New().String()
 	transactionID := uuid.New().String()
@@ -70,6 +69,7 @@ func TestConfirmHandler_Success(t *testing.T) {
 		RiderID:         riderID,
 	}
 	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.order_confirmed", "uois-gateway-consumers", quoteID, mock.AnythingOfType("time.Duration")).Return(orderConfirmedEvent, nil)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.order_confirm_failed", "uois-gateway-consumers", quoteID, mock.AnythingOfType("time.Duration")).Return(nil, nil)
 
 	// Mock order record service - get order record by quote_id
 	orderRecord := &OrderRecord{
@@ -95,7 +95,7 @@ func TestConfirmHandler_Success(t *testing.T) {
 	// Mock successful callback
 	callbackService.On("SendCallback", mock.Anything, mock.MatchedBy(func(url string) bool {
 		return strings.HasSuffix(url, "/on_confirm")
-	}), mock.Anything).Return(nil).Maybe()
+	}), mock.Anything).Return("", nil).Maybe()
 
 	// Create request
 	requestBody := map[string]interface{}{
@@ -168,7 +168,7 @@ func TestConfirmHandler_InvalidQuoteID(t *testing.T) {
 	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
 
 	auditService := new(mockAuditService)
-	handler := NewConfirmHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewConfirmHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, nil, "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	transactionID := uuid.New().String()
 	messageID := uuid.New().String()
@@ -226,7 +226,7 @@ func TestConfirmHandler_OrderConfirmFailed(t *testing.T) {
 	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
 
 	auditService := new(mockAuditService)
-	handler := NewConfirmHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewConfirmHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, nil, "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	quoteID := uuid.New().String()
 	transactionID := uuid.New().String()
@@ -259,14 +259,14 @@ func TestConfirmHandler_OrderConfirmFailed(t *testing.T) {
 		Reason:  "Order confirmation failed",
 	}
 
-	// Mock ORDER_CONFIRMED consumption failure (will try ORDER_CONFIRM_FAILED next)
-	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.order_confirmed", "uois-gateway-consumers", quoteID, mock.AnythingOfType("time.Duration")).Return(nil, errors.NewDomainError(65020, "event not found", "no order confirmed"))
+	// No ORDER_CONFIRMED within the TTL: the failure is still picked up (both streams are awaited together)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.order_confirmed", "uois-gateway-consumers", quoteID, mock.AnythingOfType("time.Duration")).Return(nil, nil)
 
 	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.order_confirm_failed", "uois-gateway-consumers", quoteID, mock.AnythingOfType("time.Duration")).Return(orderConfirmFailedEvent, nil)
 
 	callbackService.On("SendCallback", mock.Anything, mock.MatchedBy(func(url string) bool {
 		return strings.HasSuffix(url, "/on_confirm")
-	}), mock.Anything).Return(nil).Maybe()
+	}), mock.Anything).Return("", nil).Maybe()
 
 	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil)
 	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil).Maybe()
@@ -0,0 +1,23 @@
+package ondc
+
+import "uois-gateway/internal/models"
+
+// fulfillmentStateCodes maps Order Service lifecycle states to ONDC logistics fulfillment state codes
+// States without an entry (pre-confirmation, COMPLETED, EXPIRED) are not reported to the BAP
+var fulfillmentStateCodes = map[string]string{
+	models.OrderStateConfirmed:                "Pending",
+	models.OrderStateAssigned:                 "Agent-assigned",
+	models.OrderStateSoftArrivedAtOrigin:      "At-pickup",
+	models.OrderStatePickedUp:                 "Order-picked-up",
+	models.OrderStateSoftArrivedAtDestination: "At-delivery",
+	models.OrderStateDelivered:                "Order-delivered",
+	models.OrderStateCancelled:                "Cancelled",
+	models.OrderStateRTOInitiated:             "RTO-Initiated",
+	models.OrderStateRTODelivered:             "RTO-Delivered",
+}
+
+// FulfillmentStateCode returns the ONDC fulfillment state code for an Order Service lifecycle state
+func FulfillmentStateCode(orderState string) (string, bool) {
+	code, ok := fulfillmentStateCodes[orderState]
+	return code, ok
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/middleware"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/services/audit"
 	"uois-gateway/internal/utils"
@@ -23,6 +25,7 @@ import (
 type InitHandler struct {
 	eventPublisher                    EventPublisher
 	eventConsumer                     EventConsumer
+	streams                           config.StreamsConfig // Consumed stream names and consumer group (STREAM_*, CONSUMER_GROUP_NAME)
 	callbackService                   CallbackService
 	idempotencyService                IdempotencyService
 	orderServiceClient                OrderServiceClient
@@ -30,9 +33,11 @@ type InitHandler struct {
 	billingStorageService             BillingStorageService
 	fulfillmentContactsStorageService FulfillmentContactsStorageService
 	auditService                      AuditService
+	deliveryPolicy                    DeliveryPolicy
 	providerID                        string // Stable provider identifier (e.g., "P1")
 	bppID                             string // BPP ID (ONDC-registered Seller NP identity)
 	bppURI                            string // BPP URI
+	responseSigner                    ResponseSigner
 	logger                            *zap.Logger
 }
 
@@ -40,6 +45,7 @@ type InitHandler struct {
 func NewInitHandler(
 	eventPublisher EventPublisher,
 	eventConsumer EventConsumer,
+	streams config.StreamsConfig,
 	callbackService CallbackService,
 	idempotencyService IdempotencyService,
 	orderServiceClient OrderServiceClient,
@@ -47,14 +53,17 @@ func NewInitHandler(
 	billingStorageService BillingStorageService,
 	fulfillmentContactsStorageService FulfillmentContactsStorageService,
 	auditService AuditService,
+	deliveryPolicy DeliveryPolicy,
 	providerID string,
 	bppID string,
 	bppURI string,
+	responseSigner ResponseSigner,
 	logger *zap.Logger,
 ) *InitHandler {
 	return &InitHandler{
 		eventPublisher:                    eventPublisher,
 		eventConsumer:                     eventConsumer,
+		streams:                           streams,
 		callbackService:                   callbackService,
 		idempotencyService:                idempotencyService,
 		orderServiceClient:                orderServiceClient,
@@ -62,9 +71,11 @@ func NewInitHandler(
 		billingStorageService:             billingStorageService,
 		fulfillmentContactsStorageService: fulfillmentContactsStorageService,
 		auditService:                      auditService,
+		deliveryPolicy:                    deliveryPolicy,
 		providerID:                        providerID,
 		bppID:                             bppID,
 		bppURI:                            bppURI,
+		responseSigner:                    responseSigner,
 		logger:                            logger,
 	}
 }
@@ -95,20 +106,37 @@ func (h *InitHandler) HandleInit(c *gin.Context) {
 		return
 	}
 
-	// Validate payment type (Dispatch does not support COD)
+	// Get client ID for delivery policy and audit logging
+	client, _ := c.Get("client")
+	cl, _ := client.(*models.Client)
+	var clientID string
+	if cl != nil {
+		clientID = cl.ID
+	}
+
 	order, _ := req.Message["order"].(map[string]interface{})
+	categoryID := utils.ExtractCategoryIDFromOrder(order)
+	timeDuration := utils.ExtractTimeDurationFromOrder(order)
+	policyReq := models.DeliveryPolicyRequest{CategoryID: categoryID, TAT: timeDuration, CityCode: req.Context.City, ClientID: clientID}
+
+	// Validate payment type (COD only for clients and categories that opted in)
 	paymentInfo, _ := order["payment"].(map[string]interface{})
-	if err := utils.ValidatePaymentType(paymentInfo); err != nil {
+	if err := utils.ValidatePaymentType(paymentInfo, codAllowed(cl, h.deliveryPolicy, policyReq)); err != nil {
 		h.logger.Warn("payment type validation failed", zap.Error(err), zap.String("trace_id", traceID))
 		h.respondNACK(c, err)
 		return
 	}
+	paymentType, _ := paymentInfo["type"].(string)
+	collectionAmount, domainErr := utils.ExtractCollectionAmount(paymentInfo)
+	if domainErr != nil {
+		h.logger.Warn("invalid COD collection amount", zap.Error(domainErr), zap.String("trace_id", traceID))
+		h.respondNACK(c, domainErr)
+		return
+	}
 
-	// Validate delivery category (Dispatch only supports Immediate Delivery or Standard Delivery with immediate subcategory)
-	categoryID := utils.ExtractCategoryIDFromOrder(order)
-	timeDuration := utils.ExtractTimeDurationFromOrder(order)
-	if err := utils.ValidateDeliveryCategory(categoryID, timeDuration); err != nil {
-		h.logger.Warn("delivery category validation failed", zap.Error(err), zap.String("trace_id", traceID), zap.String("category_id", categoryID), zap.String("time_duration", timeDuration))
+	// Validate delivery category against the delivery policy (same rules as /search)
+	if err := h.deliveryPolicy.Validate(policyReq); err != nil {
+		h.logger.Warn("delivery category validation failed", zap.Error(err), zap.String("trace_id", traceID), zap.String("category_id", categoryID), zap.String("time_duration", timeDuration), zap.String("city", req.Context.City))
 		h.respondNACK(c, err)
 		return
 	}
@@ -116,9 +144,9 @@ func (h *InitHandler) HandleInit(c *gin.Context) {
 	// Check idempotency
 	idempotencyKey := h.buildIdempotencyKey(req.Context.TransactionID, req.Context.MessageID)
 	if existingResponseBytes, exists, err := h.idempotencyService.CheckIdempotency(ctx, idempotencyKey); err == nil && exists {
-		var existingResponse models.ONDCACKResponse
-		if err := json.Unmarshal(existingResponseBytes, &existingResponse); err == nil {
-			h.respondACK(c, existingResponse)
+		// Serve the stored bytes verbatim so the signature covers the original response
+		if json.Valid(existingResponseBytes) {
+			middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, existingResponseBytes)
 			return
 		}
 	}
@@ -157,7 +185,10 @@ func (h *InitHandler) HandleInit(c *gin.Context) {
 	// Validate search_id TTL via Order Service
 	// TODO: Architecturally, search TTL validation should belong to Quote/Location service layer,
 	// not Order Service. This is acceptable for now but should be refactored later.
-	valid, err := h.orderServiceClient.ValidateSearchIDTTL(ctx, searchID)
+	// Retries of the Order Service call stop once the request TTL runs out
+	orderCtx, cancelOrderCtx := utils.WithONDCDeadline(utils.WithRequestKey(ctx, idempotencyKey), req.Context.Timestamp, req.Context.TTL)
+	defer cancelOrderCtx()
+	valid, err := h.orderServiceClient.ValidateSearchIDTTL(orderCtx, searchID)
 	if err != nil {
 		h.logger.Error("failed to validate search_id TTL", zap.Error(err), zap.String("trace_id", traceID), zap.String("search_id", searchID))
 		domainErr, ok := err.(*errors.DomainError)
@@ -203,7 +234,7 @@ func (h *InitHandler) HandleInit(c *gin.Context) {
 	}
 
 	// Publish INIT_REQUESTED event
-	initEvent := h.buildInitRequestedEvent(searchID, originLat, originLng, destLat, destLng, originAddr, destAddr, packageInfo, traceparent)
+	initEvent := h.buildInitRequestedEvent(searchID, originLat, originLng, destLat, destLng, originAddr, destAddr, packageInfo, paymentType, collectionAmount, traceparent)
 	if err := h.eventPublisher.PublishEvent(ctx, "stream.uois.init_requested", initEvent); err != nil {
 		h.logger.Error("failed to publish INIT_REQUESTED event", zap.Error(err), zap.String("trace_id", traceID), zap.String("search_id", searchID))
 		h.respondNACK(c, errors.NewDomainError(65020, "internal error", "failed to publish event"))
@@ -217,19 +248,22 @@ func (h *InitHandler) HandleInit(c *gin.Context) {
 		return
 	}
 
-	// Consume QUOTE_CREATED or QUOTE_INVALIDATED event
-	var quoteEvent interface{}
-
-	// Try QUOTE_CREATED first
-	quoteEvent, err = h.eventConsumer.ConsumeEvent(ctx, "stream.uois.quote_created", "uois-gateway-consumers", searchID, ttlDuration)
+	// Consume QUOTE_CREATED, QUOTE_INVALIDATED or QUOTE_ERROR event, whichever arrives first
+	quoteEvent, err := consumeFirstEvent(ctx, h.eventConsumer, []string{h.streams.QuoteCreated, h.streams.QuoteInvalidated, h.streams.QuoteError}, h.streams.ConsumerGroupName, searchID, ttlDuration)
 	if err != nil {
-		// Fallback to QUOTE_INVALIDATED stream
-		quoteEvent, err = h.eventConsumer.ConsumeEvent(ctx, "stream.uois.quote_invalidated", "uois-gateway-consumers", searchID, ttlDuration)
-		if err != nil {
-			h.logger.Error("failed to consume quote event from both streams", zap.Error(err), zap.String("trace_id", traceID), zap.String("search_id", searchID))
-			h.respondNACK(c, errors.NewDomainError(65020, "internal error", "failed to consume event"))
-			return
-		}
+		h.logger.Error("failed to consume quote event from all streams", zap.Error(err), zap.String("trace_id", traceID), zap.String("search_id", searchID))
+		h.respondNACK(c, errors.NewDomainError(65020, "internal error", "failed to consume event"))
+		return
+	}
+
+	if quoteError, ok := quoteEvent.(*models.QuoteErrorEvent); ok {
+		h.logger.Warn("quote creation failed",
+			zap.String("trace_id", traceID),
+			zap.String("search_id", searchID),
+			zap.String("stage", quoteError.Stage),
+			zap.String("code", quoteError.Code),
+			zap.String("message", quoteError.Message),
+		)
 	}
 
 	// Update order record with quote_id and fulfillment_id if QUOTE_CREATED
@@ -238,6 +272,12 @@ func (h *InitHandler) HandleInit(c *gin.Context) {
 	var fulfillmentID string
 	if quoteCreated, ok := quoteEvent.(*models.QuoteCreatedEvent); ok {
 		orderRecord.QuoteID = quoteCreated.QuoteID
+		// The agreed quote prices cancellation fees after /confirm
+		orderRecord.QuotePrice = quoteCreated.Price
+		orderRecord.QuoteBreakup = quoteCreated.Breakup
+		// Payment terms agreed here are enforced in /confirm
+		orderRecord.PaymentType = paymentType
+		orderRecord.CollectionAmount = collectionAmount
 		// Generate stable fulfillment ID (reused in /confirm)
 		if orderRecord.FulfillmentID == "" {
 			fulfillmentID = uuid.New().String()
@@ -257,13 +297,6 @@ func (h *InitHandler) HandleInit(c *gin.Context) {
 	responseBytes, _ := json.Marshal(response)
 	_ = h.idempotencyService.StoreIdempotency(ctx, idempotencyKey, responseBytes, 24*time.Hour)
 
-	// Get client ID for audit logging
-	client, _ := c.Get("client")
-	var clientID string
-	if cl, ok := client.(*models.Client); ok {
-		clientID = cl.ID
-	}
-
 	// Extract quote_id from quoteEvent for audit logging
 	var quoteID string
 	if quoteCreated, ok := quoteEvent.(*models.QuoteCreatedEvent); ok {
@@ -274,10 +307,10 @@ func (h *InitHandler) HandleInit(c *gin.Context) {
 	h.logRequestResponse(ctx, &req, response, nil, searchID, quoteID, clientID, traceID)
 
 	// Send callback asynchronously (pass fulfillmentID for stable reuse)
-	go h.sendInitCallback(ctx, &req, quoteEvent, fulfillmentID, traceID)
+	go h.sendInitCallback(context.WithoutCancel(ctx), &req, quoteEvent, fulfillmentID, traceID)
 
 	// Return ACK
-	h.respondACK(c, response)
+	middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, responseBytes)
 }
 
 func (h *InitHandler) validateProviderID(req *models.ONDCRequest) error {
@@ -526,7 +559,7 @@ func (h *InitHandler) parseGPS(gps string) (float64, float64, error) {
 	return lat, lng, nil
 }
 
-func (h *InitHandler) buildInitRequestedEvent(searchID string, originLat, originLng, destLat, destLng float64, originAddr, destAddr, packageInfo map[string]interface{}, traceparent string) *models.InitRequestedEvent {
+func (h *InitHandler) buildInitRequestedEvent(searchID string, originLat, originLng, destLat, destLng float64, originAddr, destAddr, packageInfo map[string]interface{}, paymentType, collectionAmount, traceparent string) *models.InitRequestedEvent {
 	traceparent = utils.EnsureTraceparent(traceparent)
 
 	return &models.InitRequestedEvent{
@@ -544,6 +577,8 @@ func (h *InitHandler) buildInitRequestedEvent(searchID string, originLat, origin
 		DestinationLng:     destLng,
 		DestinationAddress: destAddr,
 		PackageInfo:        packageInfo,
+		PaymentType:        paymentType,
+		CollectionAmount:   collectionAmount,
 	}
 }
 
@@ -596,10 +631,12 @@ func (h *InitHandler) sendInitCallback(ctx context.Context, req *models.ONDCRequ
 	callbackURL := req.Context.BapURI + "/on_init"
 	callbackPayload := h.buildOnInitCallback(ctx, req, quoteEvent, fulfillmentID)
 
-	if err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload); err != nil {
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
 		h.logger.Error("failed to send /on_init callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "failed", err.Error())
 	} else {
+		h.logger.Info("/on_init callback accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "success", "")
 	}
 }
@@ -638,15 +675,25 @@ func (h *InitHandler) buildOnInitCallback(ctx context.Context, req *models.ONDCR
 		if len(quoteCreated.Breakup) > 0 {
 			quoteMap["breakup"] = h.convertBreakupToMap(quoteCreated.Breakup)
 		}
-		message := map[string]interface{}{
-			"order": map[string]interface{}{
-				"provider": map[string]interface{}{
-					"id": h.bppID,
-				},
-				"quote":        quoteMap,
-				"items":        h.buildItemsWithFulfillment(items, fulfillmentID, quoteCreated),
-				"fulfillments": []map[string]interface{}{fulfillment},
+		orderMap := map[string]interface{}{
+			"provider": map[string]interface{}{
+				"id": h.bppID,
 			},
+			"quote":        quoteMap,
+			"items":        h.buildItemsWithFulfillment(items, fulfillmentID, quoteCreated),
+			"fulfillments": []map[string]interface{}{fulfillment},
+		}
+
+		// COD: echo the collection amount and the buyer app's settlement details
+		order, _ := req.Message["order"].(map[string]interface{})
+		paymentInfo, _ := order["payment"].(map[string]interface{})
+		if collectionAmount, err := utils.ExtractCollectionAmount(paymentInfo); err == nil && collectionAmount != "" {
+			orderMap["payment"] = buildCODPayment(paymentInfo, collectionAmount)
+			orderMap["tags"] = buildCODTags(collectionAmount)
+		}
+
+		message := map[string]interface{}{
+			"order": orderMap,
 		}
 
 		return models.ONDCResponse{
@@ -660,11 +707,15 @@ func (h *InitHandler) buildOnInitCallback(ctx context.Context, req *models.ONDCR
 		// Error case: QUOTE_INVALIDATED
 		return models.ONDCResponse{
 			Context: callbackCtx,
-			Error: &models.ONDCError{
-				Type:    "CONTEXT_ERROR",
-				Code:    "65005",
-				Message: map[string]string{"en": quoteInvalidated.Message},
-			},
+			Error:   quoteInvalidatedToONDCError(quoteInvalidated),
+		}
+	}
+
+	if quoteError, ok := quoteEvent.(*models.QuoteErrorEvent); ok {
+		// Error case: QUOTE_ERROR
+		return models.ONDCResponse{
+			Context: callbackCtx,
+			Error:   quoteErrorToONDCError(quoteError),
 		}
 	}
 
@@ -719,10 +770,6 @@ func (h *InitHandler) buildIdempotencyKey(transactionID, messageID string) strin
 	return "init:" + transactionID + ":" + messageID
 }
 
-func (h *InitHandler) respondACK(c *gin.Context, response interface{}) {
-	c.JSON(http.StatusOK, response)
-}
-
 func (h *InitHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 	ctx := c.Request.Context()
 	traceID := utils.ExtractTraceID(utils.EnsureTraceparent(c.GetHeader("traceparent")))
@@ -755,7 +802,7 @@ func (h *InitHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 		h.logRequestResponse(ctx, req, response, nil, "", "", clientID, traceID)
 	}
 
-	c.JSON(errors.GetHTTPStatus(err), response)
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, errors.GetHTTPStatus(err), response)
 }
 
 func (h *InitHandler) logRequestResponse(ctx context.Context, req *models.ONDCRequest, ackResponse interface{}, callbackPayload interface{}, searchID, quoteID, clientID, traceID string) {
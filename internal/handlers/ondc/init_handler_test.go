@@ -55,8 +55,8 @@ func (m *mockOrderServiceClient) CancelOrder(ctx context.Context, dispatchOrderI
 	return args.Error(0)
 }
 
-func (m *mockOrderServiceClient) UpdateOrder(ctx context.Context, dispatchOrderID string, updates map[string]interface{}) error {
-	args := m.Called(ctx, dispatchOrderID, updates)
+func (m *mockOrderServiceClient) UpdateOrder(ctx context.Context, dispatchOrderID string, updates map[string]interface{}, authorizations []models.FulfillmentAuthorization) error {
+	args := m.Called(ctx, dispatchOrderID, updates, authorizations)
 	return args.Error(0)
 }
 
@@ -169,7 +169,7 @@ func TestInitHandler_Success(t *testing.T) {
 	billingStorageService := new(mockBillingStorageService)
 	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
 	auditService := new(mockAuditService)
-	handler := NewInitHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, "P1", "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewInitHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, testDeliveryPolicy(t), "P1", "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	searchID := uuid.New().String()
 	transactionID := uuid.New().String()
@@ -222,6 +222,8 @@ func TestInitHandler_Success(t *testing.T) {
 		ETADestination:              timePtr(time.Now().Add(30 * time.Minute)),
 	}
 	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.quote_created", "uois-gateway-consumers", searchID, mock.AnythingOfType("time.Duration")).Return(quoteCreatedEvent, nil)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.quote_invalidated", "uois-gateway-consumers", searchID, mock.AnythingOfType("time.Duration")).Return(nil, nil)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "quote:error", "uois-gateway-consumers", searchID, mock.AnythingOfType("time.Duration")).Return(nil, nil)
 
 	// Mock order record update - store quote_id and fulfillment_id alongside search_id
 	orderRecordService.On("UpdateOrderRecord", mock.Anything, mock.MatchedBy(func(record *OrderRecord) bool {
@@ -234,7 +236,7 @@ func TestInitHandler_Success(t *testing.T) {
 	// Mock successful callback
 	callbackService.On("SendCallback", mock.Anything, mock.MatchedBy(func(url string) bool {
 		return strings.HasSuffix(url, "/on_init")
-	}), mock.Anything).Return(nil).Maybe()
+	}), mock.Anything).Return("", nil).Maybe()
 
 	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil)
 	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil).Maybe()
@@ -325,7 +327,7 @@ func TestInitHandler_InvalidSearchID(t *testing.T) {
 	billingStorageService := new(mockBillingStorageService)
 	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
 	auditService := new(mockAuditService)
-	handler := NewInitHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, "P1", "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewInitHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, testDeliveryPolicy(t), "P1", "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	searchID := uuid.New().String()
 	transactionID := uuid.New().String()
@@ -408,7 +410,7 @@ func TestInitHandler_QuoteInvalidated(t *testing.T) {
 	billingStorageService := new(mockBillingStorageService)
 	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
 	auditService := new(mockAuditService)
-	handler := NewInitHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, "P1", "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewInitHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, testDeliveryPolicy(t), "P1", "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	searchID := uuid.New().String()
 	transactionID := uuid.New().String()
@@ -429,7 +431,7 @@ func TestInitHandler_QuoteInvalidated(t *testing.T) {
 
 	eventPublisher.On("PublishEvent", mock.Anything, "stream.uois.init_requested", mock.AnythingOfType("*models.InitRequestedEvent")).Return(nil)
 
-	// Mock QUOTE_CREATED consumption failure (will try QUOTE_INVALIDATED next)
+	// Mock QUOTE_CREATED consumption failure (QUOTE_INVALIDATED is awaited alongside it)
 	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.quote_created", "uois-gateway-consumers", searchID, mock.AnythingOfType("time.Duration")).Return(nil, errors.NewDomainError(65020, "event not found", "no quote created"))
 
 	// Mock QUOTE_INVALIDATED event consumption
@@ -447,10 +449,11 @@ func TestInitHandler_QuoteInvalidated(t *testing.T) {
 		RequiresResearch: true,
 	}
 	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.quote_invalidated", "uois-gateway-consumers", searchID, mock.AnythingOfType("time.Duration")).Return(quoteInvalidatedEvent, nil)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "quote:error", "uois-gateway-consumers", searchID, mock.AnythingOfType("time.Duration")).Return(nil, nil)
 
 	callbackService.On("SendCallback", mock.Anything, mock.MatchedBy(func(url string) bool {
 		return strings.HasSuffix(url, "/on_init")
-	}), mock.Anything).Return(nil).Maybe()
+	}), mock.Anything).Return("", nil).Maybe()
 
 	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil)
 	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil).Maybe()
@@ -4,7 +4,10 @@ import (
 	"context"
 	"time"
 
+	"uois-gateway/internal/models"
 	"uois-gateway/internal/services/audit"
+	ondcService "uois-gateway/internal/services/ondc"
+	"uois-gateway/pkg/errors"
 )
 
 // EventPublisher publishes events to Redis streams
@@ -20,8 +23,21 @@ type EventConsumer interface {
 }
 
 // CallbackService sends HTTP callbacks to client callback URLs
+// SendCallback returns the callback queue's delivery id, or "" when the callback was delivered inline
 type CallbackService interface {
-	SendCallback(ctx context.Context, callbackURL string, payload interface{}) error
+	SendCallback(ctx context.Context, callbackURL string, payload interface{}) (string, error)
+}
+
+// ResponseSigner signs synchronous response bytes (ONDC Authorization header)
+type ResponseSigner interface {
+	SignResponse(payload []byte) (string, error)
+}
+
+// SubscriptionService handles ONDC registry onboarding (challenge and site verification)
+type SubscriptionService interface {
+	DecryptChallenge(challenge string) (string, error)
+	SiteVerificationHTML() (string, error)
+	SigningKeys() []ondcService.PublicKeyInfo
 }
 
 // CacheService provides caching functionality
@@ -44,7 +60,7 @@ type OrderServiceClient interface {
 	GetOrder(ctx context.Context, dispatchOrderID string) (*OrderStatus, error)
 	GetOrderTracking(ctx context.Context, dispatchOrderID string) (*OrderTracking, error)
 	CancelOrder(ctx context.Context, dispatchOrderID string, reason string) error
-	UpdateOrder(ctx context.Context, dispatchOrderID string, updates map[string]interface{}) error
+	UpdateOrder(ctx context.Context, dispatchOrderID string, updates map[string]interface{}, authorizations []models.FulfillmentAuthorization) error
 	InitiateRTO(ctx context.Context, dispatchOrderID string) error
 }
 
@@ -52,14 +68,27 @@ type OrderServiceClient interface {
 // Identifiers are stored together on the same order record for correlation only.
 // No identifier represents, replaces, or derives another.
 type OrderRecord struct {
-	SearchID        string // UOIS Gateway-generated (internal-only, for /search and /init correlation)
-	QuoteID         string // Order Service-generated (ONDC-visible, for /init and /confirm correlation)
-	DispatchOrderID string // Order Service-generated (internal-only, execution identifier)
-	OrderID         string // Seller-generated ONDC order.id (network-facing, sent in /on_confirm)
-	ClientID        string // Client identifier (for multi-tenant lookup)
-	TransactionID   string // ONDC transaction_id (for /init correlation lookup)
-	MessageID       string // ONDC message_id (for /init correlation lookup)
-	FulfillmentID   string // UOIS Gateway-generated (ONDC-visible, stable per order, used in /init and /confirm)
+	SearchID              string                           // UOIS Gateway-generated (internal-only, for /search and /init correlation)
+	QuoteID               string                           // Order Service-generated (ONDC-visible, for /init and /confirm correlation)
+	DispatchOrderID       string                           // Order Service-generated (internal-only, execution identifier)
+	OrderID               string                           // Seller-generated ONDC order.id (network-facing, sent in /on_confirm)
+	ClientID              string                           // Client identifier (for multi-tenant lookup)
+	TransactionID         string                           // ONDC transaction_id (for /init correlation lookup)
+	MessageID             string                           // ONDC message_id (for /init correlation lookup)
+	FulfillmentID         string                           // UOIS Gateway-generated (ONDC-visible, stable per order, used in /init and /confirm)
+	BapID                 string                           // ONDC bap_id from /confirm (target of unsolicited callbacks)
+	BapURI                string                           // ONDC bap_uri from /confirm (target of unsolicited callbacks)
+	Domain                string                           // ONDC domain from /confirm (context of unsolicited callbacks)
+	RiderID               string                           // DroneAI-assigned rider (set on RIDER_ASSIGNED)
+	Agent                 *AgentDetails                    // Agent and vehicle details (set on RIDER_ASSIGNED)
+	PaymentType           string                           // ONDC payment.type agreed in /init
+	CollectionAmount      string                           // COD amount agreed in /init (ON-FULFILLMENT only)
+	QuotePrice            models.Price                     // Quote total agreed in /init
+	QuoteBreakup          []models.BreakupItem             // Quote breakup agreed in /init (prices cancellation fees)
+	PickupAuthorization   *models.FulfillmentAuthorization // PCC from /update (start.authorization)
+	DeliveryAuthorization *models.FulfillmentAuthorization // DCC from /update (end.authorization)
+	SellerCancelReason    string                           // Reason code once the seller side has cancelled the order in Order Service
+	QuoteRevised          bool                             // Set once a weight/dimension differential has revised the quote
 }
 
 // OrderRecordService handles order record storage and retrieval
@@ -113,6 +142,25 @@ type FulfillmentContactsStorageService interface {
 	DeleteFulfillmentContacts(ctx context.Context, transactionID string) error
 }
 
+// DeliveryPolicy decides which delivery categories are accepted and offered (implemented by deliverypolicy.Engine)
+type DeliveryPolicy interface {
+	Validate(req models.DeliveryPolicyRequest) *errors.DomainError
+	Categories(req models.DeliveryPolicyRequest) []string
+	AllowsCOD(req models.DeliveryPolicyRequest) bool
+}
+
+// CancellationPolicy validates cancellation reason codes and prices cancellations (implemented by cancellation.Catalog)
+type CancellationPolicy interface {
+	Reason(code, initiatedBy string) (*models.CancellationReason, *errors.DomainError)
+	TriggersRTO(code, fulfillmentState string) bool
+	Fee(fulfillmentState, code string, orderValue float64) (float64, *models.CancellationTerm)
+}
+
+// CODReconciliationService records the cash the LSP collects for cash on delivery orders
+type CODReconciliationService interface {
+	CreateReconciliation(ctx context.Context, record *models.CODReconciliation) error
+}
+
 // OrderStatus represents order status from Order Service
 type OrderStatus struct {
 	DispatchOrderID string
@@ -120,6 +168,15 @@ type OrderStatus struct {
 	RiderID         string
 	Timeline        []OrderTimelineEvent
 	Fulfillment     FulfillmentStatus
+	Agent           *AgentDetails // Assigned rider's details, when Order Service has them
+}
+
+// AgentDetails describes the delivery agent and vehicle shown to the BAP in fulfillment.agent/vehicle
+type AgentDetails struct {
+	Name                string
+	Phone               string
+	VehicleCategory     string
+	VehicleRegistration string
 }
 
 // OrderTimelineEvent represents a timeline event
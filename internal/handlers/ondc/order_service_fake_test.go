@@ -0,0 +1,132 @@
+package ondc_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/clients/order"
+	"uois-gateway/internal/clients/order/orderfake"
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/handlers/ondc"
+	"uois-gateway/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// These tests drive handlers against the fake Order Service over gRPC instead of a mocked client,
+// so the protobuf mapping in internal/clients/order is exercised end to end.
+// They live in ondc_test because internal/clients/order imports this package.
+
+type noopIdempotency struct{}
+
+func (noopIdempotency) CheckIdempotency(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+func (noopIdempotency) StoreIdempotency(ctx context.Context, key string, responseBytes []byte, ttl time.Duration) error {
+	return nil
+}
+
+// staticOrderRecords serves a single order record by order.id
+type staticOrderRecords struct {
+	ondc.OrderRecordService
+	record *ondc.OrderRecord
+}
+
+func (s staticOrderRecords) GetOrderRecordByOrderID(ctx context.Context, clientID, orderID string) (*ondc.OrderRecord, error) {
+	if s.record.ClientID != clientID || s.record.OrderID != orderID {
+		return nil, nil
+	}
+	return s.record, nil
+}
+
+func newFakeOrderServiceClient(t *testing.T) (*order.Client, *orderfake.Server) {
+	fake := orderfake.NewServer()
+	addr, err := fake.Start("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(fake.Stop)
+
+	host, port, _ := net.SplitHostPort(addr)
+	portNum, _ := strconv.Atoi(port)
+	client, err := order.NewClient(config.OrderConfig{GRPCHost: host, GRPCPort: portNum, GRPCTimeout: 2 * time.Second}, nil, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client, fake
+}
+
+func serveTrack(handler *ondc.TrackHandler, orderID string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]interface{}{
+		"context": map[string]interface{}{
+			"domain":         "nic2004:52110",
+			"action":         "track",
+			"transaction_id": "txn-1",
+			"message_id":     "msg-" + orderID,
+			"timestamp":      time.Now().Format(time.RFC3339),
+			"ttl":            "PT30S",
+			"bap_uri":        "https://buyer.example.com",
+		},
+		"message": map[string]interface{}{"order": map[string]interface{}{"id": orderID}},
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/track", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("client", &models.Client{ID: "test-client", ClientCode: "test-client"})
+	handler.HandleTrack(c)
+	return w
+}
+
+func TestTrackHandler_FakeOrderService(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	orderClient, fake := newFakeOrderServiceClient(t)
+	fake.TrackingBaseURL = "https://track.dispatch.test/"
+	fake.SetLocation("DISPATCH-1", 12.9716, 77.5946)
+
+	records := staticOrderRecords{record: &ondc.OrderRecord{
+		DispatchOrderID: "DISPATCH-1",
+		OrderID:         "ORDER-1",
+		ClientID:        "test-client",
+		FulfillmentID:   "F1",
+	}}
+	handler := ondc.NewTrackHandler(nil, noopIdempotency{}, orderClient, records, nil, nil, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	w := serveTrack(handler, "ORDER-1")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.ONDCResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	fulfillments := response.Message["order"].(map[string]interface{})["fulfillments"].([]interface{})
+	fulfillment := fulfillments[0].(map[string]interface{})
+	assert.Equal(t, "F1", fulfillment["id"])
+	assert.Equal(t, "https://track.dispatch.test/DISPATCH-1", fulfillment["tracking_url"])
+}
+
+func TestTrackHandler_FakeOrderService_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	orderClient, fake := newFakeOrderServiceClient(t)
+	fake.SetError("GetOrderTracking", status.Error(codes.Unavailable, "order service draining"))
+
+	records := staticOrderRecords{record: &ondc.OrderRecord{DispatchOrderID: "DISPATCH-1", OrderID: "ORDER-1", ClientID: "test-client"}}
+	handler := ondc.NewTrackHandler(nil, noopIdempotency{}, orderClient, records, nil, nil, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	w := serveTrack(handler, "ORDER-1")
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var response models.ONDCResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "65011", response.Error.Code)
+}
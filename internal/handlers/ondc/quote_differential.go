@@ -0,0 +1,144 @@
+package ondc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"uois-gateway/internal/models"
+)
+
+// Quote breakup title types added by a weight/dimension differential
+const (
+	breakupTitleDiff    = "diff"
+	breakupTitleTaxDiff = "tax_diff"
+)
+
+// ApplyQuoteDifferential charges a re-priced quote as diff/tax_diff lines on top of the quote agreed in /init
+// The differential is computed against the agreed lines only, so applying the same re-priced breakup twice
+// replaces the earlier diff lines instead of adding to them, and a re-measurement that no longer costs more
+// removes them again. Only increases are charged. It returns false when the quote was never revised and the
+// re-priced breakup costs no more than the agreed quote (order record left unchanged); once the quote has
+// been revised every differential returns true, so the recomputed quote is persisted and sent to the BAP
+func ApplyQuoteDifferential(orderRecord *OrderRecord, revised []models.BreakupItem) (bool, error) {
+	var agreed []models.BreakupItem
+	for _, item := range orderRecord.QuoteBreakup {
+		if item.TitleType != breakupTitleDiff && item.TitleType != breakupTitleTaxDiff {
+			agreed = append(agreed, item)
+		}
+	}
+	if len(agreed) == 0 {
+		return false, fmt.Errorf("order record has no agreed quote breakup")
+	}
+
+	// Records revised before QuoteRevised existed still carry their diff lines
+	wasRevised := orderRecord.QuoteRevised || len(agreed) != len(orderRecord.QuoteBreakup)
+
+	agreedCharges, agreedTax := sumBreakup(agreed)
+	revisedCharges, revisedTax := sumBreakup(revised)
+	diff := roundAmount(revisedCharges - agreedCharges)
+	taxDiff := roundAmount(revisedTax - agreedTax)
+	if diff <= 0 && taxDiff <= 0 && !wasRevised {
+		return false, nil
+	}
+
+	// Same item id as the agreed lines: the differential is an additional cost of the same logistics service
+	itemID := agreed[0].ItemID
+	currency := orderRecord.QuotePrice.Currency
+	if currency == "" {
+		currency = agreed[0].Price.Currency
+	}
+
+	breakup := agreed
+	if diff > 0 {
+		breakup = append(breakup, models.BreakupItem{ItemID: itemID, TitleType: breakupTitleDiff, Price: models.Price{Value: diff, Currency: currency}})
+	}
+	if taxDiff > 0 {
+		breakup = append(breakup, models.BreakupItem{ItemID: itemID, TitleType: breakupTitleTaxDiff, Price: models.Price{Value: taxDiff, Currency: currency}})
+	}
+
+	var total float64
+	for _, item := range breakup {
+		total += item.Price.Value
+	}
+	orderRecord.QuoteBreakup = breakup
+	orderRecord.QuotePrice = models.Price{Value: roundAmount(total), Currency: currency}
+	orderRecord.QuoteRevised = true
+	return true, nil
+}
+
+// sumBreakup splits a breakup into charges and taxes, ignoring earlier differential lines
+func sumBreakup(breakup []models.BreakupItem) (charges, tax float64) {
+	for _, item := range breakup {
+		switch item.TitleType {
+		case breakupTitleDiff, breakupTitleTaxDiff:
+		case "tax":
+			tax += item.Price.Value
+		default:
+			charges += item.Price.Value
+		}
+	}
+	return charges, tax
+}
+
+func roundAmount(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// quoteToMap renders the order record's quote (price and breakup) in ONDC format
+func quoteToMap(price models.Price, breakup []models.BreakupItem) map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(breakup))
+	for _, item := range breakup {
+		items = append(items, map[string]interface{}{
+			"@ondc/org/item_id":    item.ItemID,
+			"@ondc/org/title_type": item.TitleType,
+			"price": map[string]interface{}{
+				"value":    item.Price.Value,
+				"currency": item.Price.Currency,
+			},
+		})
+	}
+	return map[string]interface{}{
+		"price": map[string]interface{}{
+			"value":    price.Value,
+			"currency": price.Currency,
+		},
+		"breakup": items,
+	}
+}
+
+// differentialTags builds the linked_order_diff tag (measured weight/dimensions) and, when the
+// measurement comes with images, the linked_order_diff_proof tag
+func differentialTags(differential *models.QuoteDifferentialEvent) []map[string]interface{} {
+	var diffList []map[string]interface{}
+	add := func(list []map[string]interface{}, code, value string) []map[string]interface{} {
+		return append(list, map[string]interface{}{"code": code, "value": value})
+	}
+	formatMeasure := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	if differential.LinkedOrderID != "" {
+		diffList = add(diffList, "id", differential.LinkedOrderID)
+	}
+	if weight := differential.Weight; weight != nil {
+		diffList = add(diffList, "weight_unit", weight.Unit)
+		diffList = add(diffList, "weight_value", formatMeasure(weight.Value))
+	}
+	if dimensions := differential.Dimensions; dimensions != nil {
+		diffList = add(diffList, "dim_unit", dimensions.Unit)
+		diffList = add(diffList, "length", formatMeasure(dimensions.Length))
+		diffList = add(diffList, "breadth", formatMeasure(dimensions.Breadth))
+		diffList = add(diffList, "height", formatMeasure(dimensions.Height))
+	}
+	tags := []map[string]interface{}{{"code": "linked_order_diff", "list": diffList}}
+
+	if len(differential.ProofURLs) > 0 {
+		proofList := add(nil, "type", "image")
+		for _, url := range differential.ProofURLs {
+			proofList = add(proofList, "url", url)
+		}
+		tags = append(tags, map[string]interface{}{"code": "linked_order_diff_proof", "list": proofList})
+	}
+	return tags
+}
@@ -0,0 +1,135 @@
+package ondc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func differentialOrderRecord() *OrderRecord {
+	return &OrderRecord{
+		DispatchOrderID: "dispatch-123",
+		OrderID:         "order-123",
+		TransactionID:   "txn-123",
+		FulfillmentID:   "F1",
+		BapID:           "buyer.example.com",
+		BapURI:          "https://buyer.example.com",
+		Domain:          "nic2004:60232",
+		QuotePrice:      models.Price{Value: 59, Currency: "INR"},
+		QuoteBreakup: []models.BreakupItem{
+			{ItemID: "I1", TitleType: "delivery", Price: models.Price{Value: 50, Currency: "INR"}},
+			{ItemID: "I1", TitleType: "tax", Price: models.Price{Value: 9, Currency: "INR"}},
+		},
+	}
+}
+
+func revisedBreakup(delivery, tax float64) []models.BreakupItem {
+	return []models.BreakupItem{
+		{ItemID: "I1", TitleType: "delivery", Price: models.Price{Value: delivery, Currency: "INR"}},
+		{ItemID: "I1", TitleType: "tax", Price: models.Price{Value: tax, Currency: "INR"}},
+	}
+}
+
+func TestApplyQuoteDifferential(t *testing.T) {
+	orderRecord := differentialOrderRecord()
+
+	charged, err := ApplyQuoteDifferential(orderRecord, revisedBreakup(75, 13.5))
+	require.NoError(t, err)
+	assert.True(t, charged)
+	assert.Equal(t, models.Price{Value: 88.5, Currency: "INR"}, orderRecord.QuotePrice)
+	require.Len(t, orderRecord.QuoteBreakup, 4)
+	assert.Equal(t, models.BreakupItem{ItemID: "I1", TitleType: "diff", Price: models.Price{Value: 25, Currency: "INR"}}, orderRecord.QuoteBreakup[2])
+	assert.Equal(t, models.BreakupItem{ItemID: "I1", TitleType: "tax_diff", Price: models.Price{Value: 4.5, Currency: "INR"}}, orderRecord.QuoteBreakup[3])
+
+	// A re-delivered or re-measured differential replaces the diff lines
+	charged, err = ApplyQuoteDifferential(orderRecord, revisedBreakup(75, 13.5))
+	require.NoError(t, err)
+	assert.True(t, charged)
+	assert.Equal(t, 88.5, orderRecord.QuotePrice.Value)
+	assert.Len(t, orderRecord.QuoteBreakup, 4)
+
+	// A later re-measurement that no longer costs more removes the diff lines
+	charged, err = ApplyQuoteDifferential(orderRecord, revisedBreakup(40, 7.2))
+	require.NoError(t, err)
+	assert.True(t, charged)
+	assert.Equal(t, differentialOrderRecord().QuoteBreakup, orderRecord.QuoteBreakup)
+	assert.Equal(t, differentialOrderRecord().QuotePrice, orderRecord.QuotePrice)
+	assert.True(t, orderRecord.QuoteRevised)
+
+	// A lighter package is not refunded
+	orderRecord = differentialOrderRecord()
+	charged, err = ApplyQuoteDifferential(orderRecord, revisedBreakup(40, 7.2))
+	require.NoError(t, err)
+	assert.False(t, charged)
+	assert.Equal(t, differentialOrderRecord().QuoteBreakup, orderRecord.QuoteBreakup)
+	assert.False(t, orderRecord.QuoteRevised)
+
+	_, err = ApplyQuoteDifferential(&OrderRecord{}, revisedBreakup(75, 13.5))
+	assert.Error(t, err)
+}
+
+func TestUpdateHandler_SendQuoteDifferentialUpdate(t *testing.T) {
+	callbackService := new(mockCallbackService)
+	auditService := new(mockAuditService)
+	handler := NewUpdateHandler(callbackService, nil, nil, nil, auditService, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	orderRecord := differentialOrderRecord()
+	_, err := ApplyQuoteDifferential(orderRecord, revisedBreakup(75, 13.5))
+	require.NoError(t, err)
+
+	measuredAt := time.Date(2023, 6, 7, 23, 0, 30, 0, time.UTC)
+	differential := &models.QuoteDifferentialEvent{
+		BaseEvent:       models.BaseEvent{EventType: models.EventTypeQuoteDifferential, Timestamp: measuredAt},
+		DispatchOrderID: "dispatch-123",
+		LinkedOrderID:   "O1",
+		Weight:          &models.PackageWeight{Unit: "kilogram", Value: 1.5},
+		Dimensions:      &models.PackageDimensions{Unit: "centimeter", Length: 30, Breadth: 20, Height: 12.5},
+		ProofURLs:       []string{"https://lsp.example.com/sorter/1.png"},
+	}
+
+	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil)
+	var payload models.ONDCResponse
+	callbackService.On("SendCallback", mock.Anything, "https://buyer.example.com/on_update", mock.Anything).Run(func(args mock.Arguments) {
+		payload = args.Get(2).(models.ONDCResponse)
+	}).Return("", nil)
+
+	require.NoError(t, handler.SendQuoteDifferentialUpdate(context.Background(), orderRecord, differential, "trace-1"))
+
+	assert.Equal(t, "on_update", payload.Context.Action)
+	order := payload.Message["order"].(map[string]interface{})
+	assert.Equal(t, "order-123", order["id"])
+	assert.Equal(t, "2023-06-07T23:00:30Z", order["updated_at"])
+
+	quote := order["quote"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"value": 88.5, "currency": "INR"}, quote["price"])
+	breakup := quote["breakup"].([]map[string]interface{})
+	require.Len(t, breakup, 4)
+	assert.Equal(t, "diff", breakup[2]["@ondc/org/title_type"])
+	assert.Equal(t, "tax_diff", breakup[3]["@ondc/org/title_type"])
+
+	tags := order["tags"].([]map[string]interface{})
+	require.Len(t, tags, 2)
+	assert.Equal(t, map[string]interface{}{
+		"code": "linked_order_diff",
+		"list": []map[string]interface{}{
+			{"code": "id", "value": "O1"},
+			{"code": "weight_unit", "value": "kilogram"},
+			{"code": "weight_value", "value": "1.5"},
+			{"code": "dim_unit", "value": "centimeter"},
+			{"code": "length", "value": "30"},
+			{"code": "breadth", "value": "20"},
+			{"code": "height", "value": "12.5"},
+		},
+	}, tags[0])
+	assert.Equal(t, "linked_order_diff_proof", tags[1]["code"])
+
+	fulfillments := order["fulfillments"].([]map[string]interface{})
+	assert.Equal(t, "F1", fulfillments[0]["id"])
+}
@@ -0,0 +1,103 @@
+package ondc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"uois-gateway/internal/models"
+)
+
+// consumeFirstEvent waits on several streams for the same business ID and returns the first event
+// Success and failure outcomes are published to different streams; waiting on them together means a
+// failure is reported as soon as it arrives instead of after the success wait times out.
+// Keeps the EventConsumer contract: returns (nil, nil) if nothing arrives within timeout.
+// Streams that are not configured (empty names) are skipped.
+func consumeFirstEvent(ctx context.Context, consumer EventConsumer, streams []string, consumerGroup, businessID string, timeout time.Duration) (interface{}, error) {
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	configured := streams[:0:0]
+	for _, stream := range streams {
+		if stream != "" {
+			configured = append(configured, stream)
+		}
+	}
+	streams = configured
+
+	type result struct {
+		event interface{}
+		err   error
+	}
+	results := make(chan result, len(streams))
+	for _, stream := range streams {
+		go func(stream string) {
+			event, err := consumer.ConsumeEvent(waitCtx, stream, consumerGroup, businessID, timeout)
+			results <- result{event: event, err: err}
+		}(stream)
+	}
+
+	var firstErr error
+	for range streams {
+		r := <-results
+		if r.event != nil {
+			return r.event, nil
+		}
+		if r.err != nil && firstErr == nil {
+			firstErr = r.err
+		}
+	}
+	return nil, firstErr
+}
+
+// quoteErrorCodes maps the Quote Service processing stage to the ONDC error code sent to the buyer
+// Quote Service codes are internal; the stage decides whether the buyer can fix the request or retry
+var quoteErrorCodes = map[string]string{
+	models.QuoteErrorStageValidation:         "65001",
+	models.QuoteErrorStageAdminConfigFetch:   "65011",
+	models.QuoteErrorStagePricingComputation: "65020",
+	models.QuoteErrorStageCacheWrite:         "65020",
+	models.QuoteErrorStageEventPublish:       "65020",
+}
+
+// quoteErrorToONDCError builds the /on_search or /on_init error block for a QUOTE_ERROR event
+func quoteErrorToONDCError(event *models.QuoteErrorEvent) *models.ONDCError {
+	code, ok := quoteErrorCodes[event.Stage]
+	if !ok {
+		code = "65020"
+	}
+
+	message := "Service temporarily unavailable. Please try again."
+	if code == "65001" {
+		message = "invalid request"
+	}
+
+	return &models.ONDCError{
+		Type:    "CONTEXT_ERROR",
+		Code:    code,
+		Message: map[string]string{"en": message},
+	}
+}
+
+// quoteInvalidatedToONDCError builds the /on_init error block for a QUOTE_INVALIDATED event
+// When the quote cannot be revalidated (RequiresResearch) the buyer is told to start again with /search
+func quoteInvalidatedToONDCError(event *models.QuoteInvalidatedEvent) *models.ONDCError {
+	code := "65005"
+	if strings.Contains(strings.ToUpper(event.Error), "EXPIRED") {
+		code = "65004"
+	}
+
+	message := event.Message
+	if message == "" {
+		message = "quote invalid"
+	}
+	if event.RequiresResearch {
+		message += "; please search again"
+	}
+
+	return &models.ONDCError{
+		Type:    "CONTEXT_ERROR",
+		Code:    code,
+		Message: map[string]string{"en": message},
+	}
+}
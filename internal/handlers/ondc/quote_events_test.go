@@ -0,0 +1,172 @@
+package ondc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestConsumeFirstEvent_ReturnsFailureEvent(t *testing.T) {
+	eventConsumer := new(mockEventConsumer)
+	quoteError := &models.QuoteErrorEvent{SearchID: "search-1", Stage: models.QuoteErrorStageValidation, Code: "65001"}
+
+	eventConsumer.On("ConsumeEvent", mock.Anything, "quote:computed", "uois-gateway-consumers", "search-1", time.Second).Return(nil, nil)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "quote:error", "uois-gateway-consumers", "search-1", time.Second).Return(quoteError, nil)
+
+	event, err := consumeFirstEvent(context.Background(), eventConsumer, []string{"quote:computed", "quote:error"}, "uois-gateway-consumers", "search-1", time.Second)
+
+	assert.NoError(t, err)
+	assert.Same(t, quoteError, event)
+}
+
+func TestConsumeFirstEvent_SkipsUnconfiguredStreams(t *testing.T) {
+	eventConsumer := new(mockEventConsumer)
+	quoteComputed := &models.QuoteComputedEvent{SearchID: "search-1"}
+	streams := testStreamsConfig()
+	streams.QuoteComputed = "stream.quote.computed.v2"
+	streams.QuoteError = ""
+	streams.ConsumerGroupName = "uois-gateway-group"
+
+	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.quote.computed.v2", "uois-gateway-group", "search-1", time.Second).Return(quoteComputed, nil)
+
+	event, err := consumeFirstEvent(context.Background(), eventConsumer, []string{streams.QuoteComputed, streams.QuoteError}, streams.ConsumerGroupName, "search-1", time.Second)
+
+	assert.NoError(t, err)
+	assert.Same(t, quoteComputed, event)
+	eventConsumer.AssertNumberOfCalls(t, "ConsumeEvent", 1)
+}
+
+func TestConsumeFirstEvent_CancelsRemainingWaits(t *testing.T) {
+	eventConsumer := new(mockEventConsumer)
+	quoteCreated := &models.QuoteCreatedEvent{SearchID: "search-1", QuoteID: "quote-1"}
+
+	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.quote_created", "uois-gateway-consumers", "search-1", time.Minute).Return(quoteCreated, nil)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "stream.uois.quote_invalidated", "uois-gateway-consumers", "search-1", time.Minute).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil, nil)
+
+	done := make(chan interface{})
+	go func() {
+		event, _ := consumeFirstEvent(context.Background(), eventConsumer, []string{"stream.uois.quote_created", "stream.uois.quote_invalidated"}, "uois-gateway-consumers", "search-1", time.Minute)
+		done <- event
+	}()
+
+	select {
+	case event := <-done:
+		assert.Same(t, quoteCreated, event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("consumeFirstEvent did not return after the first event")
+	}
+}
+
+func TestConsumeFirstEvent_NoEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		errs    []error
+		wantErr bool
+	}{
+		{"timeout on every stream", []error{nil, nil}, false},
+		{"one stream fails", []error{errors.NewDomainError(65011, "event consumption failed", "redis error"), nil}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventConsumer := new(mockEventConsumer)
+			eventConsumer.On("ConsumeEvent", mock.Anything, "stream-a", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.errs[0])
+			eventConsumer.On("ConsumeEvent", mock.Anything, "stream-b", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.errs[1])
+
+			event, err := consumeFirstEvent(context.Background(), eventConsumer, []string{"stream-a", "stream-b"}, "uois-gateway-consumers", "search-1", time.Second)
+
+			assert.Nil(t, event)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestQuoteErrorToONDCError(t *testing.T) {
+	tests := []struct {
+		stage    string
+		wantCode string
+	}{
+		{models.QuoteErrorStageValidation, "65001"},
+		{models.QuoteErrorStageAdminConfigFetch, "65011"},
+		{models.QuoteErrorStagePricingComputation, "65020"},
+		{models.QuoteErrorStageEventPublish, "65020"},
+		{"UNKNOWN_STAGE", "65020"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stage, func(t *testing.T) {
+			ondcErr := quoteErrorToONDCError(&models.QuoteErrorEvent{Stage: tt.stage, Code: "Q-1", Message: "internal detail"})
+
+			assert.Equal(t, tt.wantCode, ondcErr.Code)
+			assert.Equal(t, "CONTEXT_ERROR", ondcErr.Type)
+			assert.NotContains(t, ondcErr.Message["en"], "internal detail")
+		})
+	}
+}
+
+func TestQuoteInvalidatedToONDCError(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       *models.QuoteInvalidatedEvent
+		wantCode    string
+		wantMessage string
+	}{
+		{"invalid", &models.QuoteInvalidatedEvent{Error: "PRICE_CHANGED", Message: "price changed"}, "65005", "price changed"},
+		{"expired", &models.QuoteInvalidatedEvent{Error: "QUOTE_EXPIRED", Message: "quote expired"}, "65004", "quote expired"},
+		{"requires research", &models.QuoteInvalidatedEvent{Error: "NOT_SERVICEABLE", Message: "location not serviceable", RequiresResearch: true}, "65005", "location not serviceable; please search again"},
+		{"no message", &models.QuoteInvalidatedEvent{}, "65005", "quote invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ondcErr := quoteInvalidatedToONDCError(tt.event)
+
+			assert.Equal(t, tt.wantCode, ondcErr.Code)
+			assert.Equal(t, tt.wantMessage, ondcErr.Message["en"])
+		})
+	}
+}
+
+func TestSearchHandler_BuildOnSearchCallback_QuoteError(t *testing.T) {
+	handler := NewSearchHandler(nil, nil, testStreamsConfig(), nil, nil, nil, nil, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", nil, zap.NewNop())
+	req := &models.ONDCRequest{Context: models.ONDCContext{Action: "search", TransactionID: "txn-1", MessageID: "msg-1"}}
+
+	callback := handler.buildOnSearchCallback(req, "", &models.QuoteErrorEvent{SearchID: uuid.New().String(), Stage: models.QuoteErrorStageAdminConfigFetch, Code: "65001"})
+
+	assert.Nil(t, callback.Message)
+	if assert.NotNil(t, callback.Error) {
+		assert.Equal(t, "65011", callback.Error.Code)
+	}
+	assert.Equal(t, "txn-1", callback.Context.TransactionID)
+	assert.NotEqual(t, "msg-1", callback.Context.MessageID)
+	assert.Equal(t, "bpp.example.com", callback.Context.BppID)
+}
+
+func TestInitHandler_BuildOnInitCallback_QuoteError(t *testing.T) {
+	handler := NewInitHandler(nil, nil, testStreamsConfig(), nil, nil, nil, nil, nil, nil, nil, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", nil, zap.NewNop())
+	req := &models.ONDCRequest{Context: models.ONDCContext{Action: "init", TransactionID: "txn-1", MessageID: "msg-1"}}
+
+	callback := handler.buildOnInitCallback(context.Background(), req, &models.QuoteErrorEvent{SearchID: uuid.New().String(), Stage: models.QuoteErrorStageValidation, Code: "QS_400"}, "")
+
+	assert.Nil(t, callback.Message)
+	if assert.NotNil(t, callback.Error) {
+		assert.Equal(t, "65001", callback.Error.Code)
+	}
+	assert.Equal(t, "txn-1", callback.Context.TransactionID)
+	assert.NotEqual(t, "msg-1", callback.Context.MessageID)
+	assert.Equal(t, "bpp.example.com", callback.Context.BppID)
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"uois-gateway/internal/middleware"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/services/audit"
 	"uois-gateway/internal/utils"
@@ -26,6 +27,7 @@ type RTOHandler struct {
 	auditService       AuditService
 	bppID              string // BPP ID (ONDC-registered Seller NP identity)
 	bppURI             string // BPP URI
+	responseSigner     ResponseSigner
 	logger             *zap.Logger
 }
 
@@ -38,6 +40,7 @@ func NewRTOHandler(
 	auditService AuditService,
 	bppID string,
 	bppURI string,
+	responseSigner ResponseSigner,
 	logger *zap.Logger,
 ) *RTOHandler {
 	return &RTOHandler{
@@ -48,6 +51,7 @@ func NewRTOHandler(
 		auditService:       auditService,
 		bppID:              bppID,
 		bppURI:             bppURI,
+		responseSigner:     responseSigner,
 		logger:             logger,
 	}
 }
@@ -77,9 +81,9 @@ func (h *RTOHandler) HandleRTO(c *gin.Context) {
 
 	idempotencyKey := h.buildIdempotencyKey(req.Context.TransactionID, req.Context.MessageID)
 	if existingResponseBytes, exists, err := h.idempotencyService.CheckIdempotency(ctx, idempotencyKey); err == nil && exists {
-		var existingResponse models.ONDCACKResponse
-		if err := json.Unmarshal(existingResponseBytes, &existingResponse); err == nil {
-			h.respondACK(c, existingResponse)
+		// Serve the stored bytes verbatim so the signature covers the original response
+		if json.Valid(existingResponseBytes) {
+			middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, existingResponseBytes)
 			return
 		}
 	}
@@ -113,7 +117,10 @@ func (h *RTOHandler) HandleRTO(c *gin.Context) {
 		return
 	}
 
-	if err := h.orderServiceClient.InitiateRTO(ctx, dispatchOrderID); err != nil {
+	// The request key makes the command safe to retry within the request TTL
+	orderCtx, cancelOrderCtx := utils.WithONDCDeadline(utils.WithRequestKey(ctx, idempotencyKey), req.Context.Timestamp, req.Context.TTL)
+	defer cancelOrderCtx()
+	if err := h.orderServiceClient.InitiateRTO(orderCtx, dispatchOrderID); err != nil {
 		h.logger.Error("failed to initiate RTO", zap.Error(err), zap.String("trace_id", traceID), zap.String("dispatch_order_id", dispatchOrderID))
 		domainErr, ok := err.(*errors.DomainError)
 		if ok {
@@ -132,9 +139,9 @@ func (h *RTOHandler) HandleRTO(c *gin.Context) {
 	// Log request/response audit
 	h.logRequestResponse(ctx, &req, response, nil, orderRecord, clientID, traceID)
 
-	go h.sendRTOCallback(ctx, &req, orderRecord, traceID)
+	go h.sendRTOCallback(context.WithoutCancel(ctx), &req, orderRecord, traceID)
 
-	h.respondACK(c, response)
+	middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, responseBytes)
 }
 
 func (h *RTOHandler) extractOrderID(req *models.ONDCRequest) (string, error) {
@@ -164,10 +171,12 @@ func (h *RTOHandler) sendRTOCallback(ctx context.Context, req *models.ONDCReques
 	callbackURL := req.Context.BapURI + "/on_update"
 	callbackPayload := h.buildOnUpdateCallback(req, orderRecord)
 
-	if err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload); err != nil {
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
 		h.logger.Error("failed to send /on_update callback for RTO", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "failed", err.Error())
 	} else {
+		h.logger.Info("/on_update callback for RTO accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "success", "")
 	}
 }
@@ -229,10 +238,6 @@ func (h *RTOHandler) buildIdempotencyKey(transactionID, messageID string) string
 	return "rto:" + transactionID + ":" + messageID
 }
 
-func (h *RTOHandler) respondACK(c *gin.Context, response interface{}) {
-	c.JSON(http.StatusOK, response)
-}
-
 func (h *RTOHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 	ctx := c.Request.Context()
 	traceID := utils.ExtractTraceID(utils.EnsureTraceparent(c.GetHeader("traceparent")))
@@ -265,7 +270,7 @@ func (h *RTOHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 		h.logRequestResponse(ctx, req, response, nil, nil, clientID, traceID)
 	}
 
-	c.JSON(errors.GetHTTPStatus(err), response)
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, errors.GetHTTPStatus(err), response)
 }
 
 func (h *RTOHandler) logRequestResponse(ctx context.Context, req *models.ONDCRequest, ackResponse interface{}, callbackPayload interface{}, orderRecord *OrderRecord, clientID, traceID string) {
@@ -28,7 +28,7 @@ func TestRTOHandler_Success(t *testing.T) {
 	orderRecordService := new(mockOrderRecordService)
 	auditService := new(mockAuditService)
 
-	handler := NewRTOHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, auditService, "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewRTOHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, auditService, "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	clientOrderID := uuid.New().String()
 	dispatchOrderID := uuid.New().String()
@@ -51,7 +51,7 @@ func TestRTOHandler_Success(t *testing.T) {
 
 	callbackService.On("SendCallback", mock.Anything, mock.MatchedBy(func(url string) bool {
 		return strings.HasSuffix(url, "/on_update")
-	}), mock.Anything).Return(nil).Maybe()
+	}), mock.Anything).Return("", nil).Maybe()
 
 	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil)
 	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil).Maybe()
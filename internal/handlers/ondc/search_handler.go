@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/middleware"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/services/audit"
 	"uois-gateway/internal/utils"
@@ -22,15 +24,18 @@ import (
 type SearchHandler struct {
 	eventPublisher     EventPublisher
 	eventConsumer      EventConsumer
+	streams            config.StreamsConfig // Consumed stream names and consumer group (STREAM_*, CONSUMER_GROUP_NAME)
 	callbackService    CallbackService
 	idempotencyService IdempotencyService
 	orderRecordService OrderRecordService
 	auditService       AuditService
+	deliveryPolicy     DeliveryPolicy
 	providerID         string // Stable provider identifier (e.g., "P1")
 	bppID              string // BPP ID (ONDC-registered Seller NP identity)
 	bppURI             string // BPP URI
 	bppName            string // BPP display name
 	bppTermsURL        string // Static terms URL
+	responseSigner     ResponseSigner
 	logger             *zap.Logger
 }
 
@@ -38,29 +43,35 @@ type SearchHandler struct {
 func NewSearchHandler(
 	eventPublisher EventPublisher,
 	eventConsumer EventConsumer,
+	streams config.StreamsConfig,
 	callbackService CallbackService,
 	idempotencyService IdempotencyService,
 	orderRecordService OrderRecordService,
 	auditService AuditService,
+	deliveryPolicy DeliveryPolicy,
 	providerID string,
 	bppID string,
 	bppURI string,
 	bppName string,
 	bppTermsURL string,
+	responseSigner ResponseSigner,
 	logger *zap.Logger,
 ) *SearchHandler {
 	return &SearchHandler{
 		eventPublisher:     eventPublisher,
 		eventConsumer:      eventConsumer,
+		streams:            streams,
 		callbackService:    callbackService,
 		idempotencyService: idempotencyService,
 		orderRecordService: orderRecordService,
 		auditService:       auditService,
+		deliveryPolicy:     deliveryPolicy,
 		providerID:         providerID,
 		bppID:              bppID,
 		bppURI:             bppURI,
 		bppName:            bppName,
 		bppTermsURL:        bppTermsURL,
+		responseSigner:     responseSigner,
 		logger:             logger,
 	}
 }
@@ -91,20 +102,30 @@ func (h *SearchHandler) HandleSearch(c *gin.Context) {
 		return
 	}
 
-	// Validate payment type (Dispatch does not support COD)
+	// Get client ID from context
+	client, _ := c.Get("client")
+	cl, _ := client.(*models.Client)
+	var clientID string
+	if cl != nil {
+		clientID = cl.ID
+	}
+
+	// Validate delivery category against the delivery policy (category, city, client, TAT, time of day)
 	intent, _ := req.Message["intent"].(map[string]interface{})
+	categoryID := utils.ExtractCategoryID(intent)
+	timeDuration := utils.ExtractTimeDuration(intent)
+	policyReq := models.DeliveryPolicyRequest{CategoryID: categoryID, TAT: timeDuration, CityCode: req.Context.City, ClientID: clientID}
+
+	// Validate payment type (COD only for clients and categories that opted in)
 	paymentInfo, _ := intent["payment"].(map[string]interface{})
-	if err := utils.ValidatePaymentType(paymentInfo); err != nil {
+	if err := utils.ValidatePaymentType(paymentInfo, codAllowed(cl, h.deliveryPolicy, policyReq)); err != nil {
 		h.logger.Warn("payment type validation failed", zap.Error(err), zap.String("trace_id", traceID))
 		h.respondNACK(c, err)
 		return
 	}
 
-	// Validate delivery category (Dispatch only supports Immediate Delivery or Standard Delivery with immediate subcategory)
-	categoryID := utils.ExtractCategoryID(intent)
-	timeDuration := utils.ExtractTimeDuration(intent)
-	if err := utils.ValidateDeliveryCategory(categoryID, timeDuration); err != nil {
-		h.logger.Warn("delivery category validation failed", zap.Error(err), zap.String("trace_id", traceID), zap.String("category_id", categoryID), zap.String("time_duration", timeDuration))
+	if err := h.deliveryPolicy.Validate(policyReq); err != nil {
+		h.logger.Warn("delivery category validation failed", zap.Error(err), zap.String("trace_id", traceID), zap.String("category_id", categoryID), zap.String("time_duration", timeDuration), zap.String("city", req.Context.City))
 		h.respondNACK(c, err)
 		return
 	}
@@ -112,9 +133,9 @@ func (h *SearchHandler) HandleSearch(c *gin.Context) {
 	// Check idempotency
 	idempotencyKey := h.buildIdempotencyKey(req.Context.TransactionID, req.Context.MessageID)
 	if existingResponseBytes, exists, err := h.idempotencyService.CheckIdempotency(ctx, idempotencyKey); err == nil && exists {
-		var existingResponse models.ONDCACKResponse
-		if err := json.Unmarshal(existingResponseBytes, &existingResponse); err == nil {
-			h.respondACK(c, existingResponse)
+		// Serve the stored bytes verbatim so the signature covers the original response
+		if json.Valid(existingResponseBytes) {
+			middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, existingResponseBytes)
 			return
 		}
 	}
@@ -122,13 +143,6 @@ func (h *SearchHandler) HandleSearch(c *gin.Context) {
 	// Generate search_id
 	searchID := uuid.New().String()
 
-	// Get client ID from context
-	client, _ := c.Get("client")
-	var clientID string
-	if cl, ok := client.(*models.Client); ok {
-		clientID = cl.ID
-	}
-
 	// Store order record with search_id, transaction_id, and message_id
 	// IMPORTANT: transaction_id is the primary flow key for /init correlation lookup.
 	// message_id is used ONLY for idempotency (deduplication of protocol messages).
@@ -170,11 +184,12 @@ func (h *SearchHandler) HandleSearch(c *gin.Context) {
 	// Log request/response audit
 	h.logRequestResponse(ctx, &req, response, nil, searchID, clientID, traceID)
 
-	// Send callback asynchronously (consumes QUOTE_COMPUTED event inside)
-	go h.sendSearchCallback(ctx, &req, searchID, traceID)
+	// Send callback asynchronously (consumes QUOTE_COMPUTED event inside); detached from the request
+	// context, which is cancelled as soon as the ACK is written
+	go h.sendSearchCallback(context.WithoutCancel(ctx), &req, searchID, clientID, traceID)
 
 	// Return ACK immediately
-	h.respondACK(c, response)
+	middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, responseBytes)
 }
 
 func (h *SearchHandler) extractCoordinates(req *models.ONDCRequest) (float64, float64, float64, float64, error) {
@@ -341,7 +356,7 @@ func (h *SearchHandler) composeSearchResponse() models.ONDCACKResponse {
 	}
 }
 
-func (h *SearchHandler) sendSearchCallback(ctx context.Context, req *models.ONDCRequest, searchID string, traceID string) {
+func (h *SearchHandler) sendSearchCallback(ctx context.Context, req *models.ONDCRequest, searchID, clientID, traceID string) {
 	// Parse TTL for event consumption timeout
 	ttlDuration, err := h.parseTTL(req.Context.TTL)
 	if err != nil {
@@ -355,27 +370,50 @@ func (h *SearchHandler) sendSearchCallback(ctx context.Context, req *models.ONDC
 		return
 	}
 
-	// Consume QUOTE_COMPUTED event (async, non-blocking)
-	// Consumer group name: "uois-gateway-consumers" (shared across all UOIS Gateway handlers for consistency)
-	quoteEvent, err := h.eventConsumer.ConsumeEvent(ctx, "quote:computed", "uois-gateway-consumers", searchID, ttlDuration)
+	// Consume QUOTE_COMPUTED or QUOTE_ERROR event (async, non-blocking), whichever arrives first
+	quoteEvent, err := consumeFirstEvent(ctx, h.eventConsumer, []string{h.streams.QuoteComputed, h.streams.QuoteError}, h.streams.ConsumerGroupName, searchID, ttlDuration)
 	if err != nil {
-		h.logger.Error("failed to consume QUOTE_COMPUTED event for callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("search_id", searchID))
+		h.logger.Error("failed to consume quote event for callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("search_id", searchID))
 		return
 	}
 
+	if quoteError, ok := quoteEvent.(*models.QuoteErrorEvent); ok {
+		h.logger.Warn("quote computation failed",
+			zap.String("trace_id", traceID),
+			zap.String("search_id", searchID),
+			zap.String("stage", quoteError.Stage),
+			zap.String("code", quoteError.Code),
+			zap.String("message", quoteError.Message),
+		)
+	}
+
 	// Build and send callback
 	callbackURL := req.Context.BapURI + "/on_search"
-	callbackPayload := h.buildOnSearchCallback(req, quoteEvent)
+	callbackPayload := h.buildOnSearchCallback(req, clientID, quoteEvent)
 
-	if err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload); err != nil {
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
 		h.logger.Error("failed to send /on_search callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("search_id", searchID))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "failed", err.Error())
 	} else {
+		h.logger.Info("/on_search callback accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "success", "")
 	}
 }
 
-func (h *SearchHandler) buildOnSearchCallback(req *models.ONDCRequest, quoteEvent interface{}) models.ONDCResponse {
+func (h *SearchHandler) buildOnSearchCallback(req *models.ONDCRequest, clientID string, quoteEvent interface{}) models.ONDCResponse {
+	if quoteError, ok := quoteEvent.(*models.QuoteErrorEvent); ok {
+		callbackCtx := req.Context
+		callbackCtx.MessageID = uuid.New().String()
+		callbackCtx.Timestamp = time.Now().UTC()
+		callbackCtx.BppID = h.bppID
+		callbackCtx.BppURI = h.bppURI
+		return models.ONDCResponse{
+			Context: callbackCtx,
+			Error:   quoteErrorToONDCError(quoteError),
+		}
+	}
+
 	quoteComputed, ok := quoteEvent.(*models.QuoteComputedEvent)
 	if !ok {
 		// Regenerate context for error callback
@@ -400,29 +438,27 @@ func (h *SearchHandler) buildOnSearchCallback(req *models.ONDCRequest, quoteEven
 	callbackCtx.BppURI = h.bppURI               // Set BPP URI
 	// transaction_id is preserved (required for correlation)
 
-	// Extract category from intent (if provided)
-	categoryID := h.extractCategoryID(req)
-	if categoryID == "" {
-		categoryID = "Immediate Delivery" // Default category for P2P
-	}
-
 	// If not serviceable, return empty catalog (ONDC-compliant)
 	if !quoteComputed.Serviceable {
-		return models.ONDCResponse{
-			Context: callbackCtx,
-			Message: map[string]interface{}{
-				"catalog": map[string]interface{}{
-					"bpp/descriptor": h.buildBPPDescriptor(),
-					"bpp/providers":  []map[string]interface{}{},
-				},
-			},
-		}
+		return h.buildEmptyCatalog(callbackCtx)
 	}
 
 	// Calculate absolute durations (not relative deltas)
 	toPickupDuration := h.calculateAbsoluteDuration(quoteComputed.ETAOrigin, quoteComputed.Timestamp)
 	toDropDuration := h.calculateAbsoluteDuration(quoteComputed.ETADestination, quoteComputed.Timestamp)
 
+	// The quoted TAT must still satisfy the delivery policy; nothing is offered otherwise
+	categoryID, ok := h.offeredCategory(req, clientID, toDropDuration)
+	if !ok {
+		h.logger.Info("no delivery category offered for quote",
+			zap.String("transaction_id", req.Context.TransactionID),
+			zap.String("category_id", h.extractCategoryID(req)),
+			zap.String("tat", toDropDuration),
+			zap.String("city", req.Context.City),
+		)
+		return h.buildEmptyCatalog(callbackCtx)
+	}
+
 	// Build ONDC-compliant catalog structure
 	message := map[string]interface{}{
 		"catalog": map[string]interface{}{
@@ -503,6 +539,49 @@ func (h *SearchHandler) buildOnSearchCallback(req *models.ONDCRequest, quoteEven
 	}
 }
 
+// offeredCategory picks the catalog category for a quote with the given TAT
+// A requested category is offered only if the policy allows it for the quoted TAT; without one,
+// the first category the policy allows is offered. When the quote has no drop ETA (PT0S), the
+// TAT requested in the intent is used instead. A COD search is only offered categories that
+// allow COD.
+func (h *SearchHandler) offeredCategory(req *models.ONDCRequest, clientID, tat string) (string, bool) {
+	intent, _ := req.Message["intent"].(map[string]interface{})
+	if tat == "PT0S" {
+		tat = utils.ExtractTimeDuration(intent)
+	}
+	paymentInfo, _ := intent["payment"].(map[string]interface{})
+
+	policyReq := models.DeliveryPolicyRequest{
+		CategoryID: h.extractCategoryID(req),
+		TAT:        tat,
+		CityCode:   req.Context.City,
+		ClientID:   clientID,
+		COD:        utils.IsCODPayment(paymentInfo),
+	}
+	if policyReq.CategoryID != "" {
+		return policyReq.CategoryID, h.deliveryPolicy.Validate(policyReq) == nil
+	}
+
+	categories := h.deliveryPolicy.Categories(policyReq)
+	if len(categories) == 0 {
+		return "", false
+	}
+	return categories[0], true
+}
+
+// buildEmptyCatalog builds an on_search with no providers (ONDC-compliant "nothing to offer")
+func (h *SearchHandler) buildEmptyCatalog(callbackCtx models.ONDCContext) models.ONDCResponse {
+	return models.ONDCResponse{
+		Context: callbackCtx,
+		Message: map[string]interface{}{
+			"catalog": map[string]interface{}{
+				"bpp/descriptor": h.buildBPPDescriptor(),
+				"bpp/providers":  []map[string]interface{}{},
+			},
+		},
+	}
+}
+
 func (h *SearchHandler) buildBPPDescriptor() map[string]interface{} {
 	descriptor := map[string]interface{}{
 		"name": h.bppName,
@@ -570,10 +649,6 @@ func (h *SearchHandler) buildIdempotencyKey(transactionID, messageID string) str
 	return "search:" + transactionID + ":" + messageID
 }
 
-func (h *SearchHandler) respondACK(c *gin.Context, response interface{}) {
-	c.JSON(http.StatusOK, response)
-}
-
 func (h *SearchHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 	ctx := c.Request.Context()
 	traceID := utils.ExtractTraceID(utils.EnsureTraceparent(c.GetHeader("traceparent")))
@@ -607,7 +682,7 @@ func (h *SearchHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 		h.logRequestResponse(ctx, req, response, nil, "", clientID, traceID)
 	}
 
-	c.JSON(errors.GetHTTPStatus(err), response)
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, errors.GetHTTPStatus(err), response)
 }
 
 func (h *SearchHandler) logRequestResponse(ctx context.Context, req *models.ONDCRequest, ackResponse interface{}, callbackPayload interface{}, searchID, clientID, traceID string) {
@@ -10,17 +10,43 @@ import (
 	"testing"
 	"time"
 
+	"uois-gateway/internal/config"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/services/audit"
+	"uois-gateway/internal/services/deliverypolicy"
 	"uois-gateway/pkg/errors"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
+// testDeliveryPolicy returns a delivery policy engine with the built-in rules, or with rules when given
+func testDeliveryPolicy(t *testing.T, rules ...models.DeliveryCategoryRule) DeliveryPolicy {
+	if len(rules) == 0 {
+		rules = deliverypolicy.DefaultRules()
+	}
+	engine, err := deliverypolicy.NewEngine(context.Background(), deliverypolicy.NewStaticSource(rules), config.DeliveryPolicyConfig{}, zap.NewNop())
+	require.NoError(t, err)
+	return engine
+}
+
+// testStreamsConfig returns the consumed stream names of .env.example
+func testStreamsConfig() config.StreamsConfig {
+	return config.StreamsConfig{
+		QuoteComputed:      "quote:computed",
+		QuoteCreated:       "stream.uois.quote_created",
+		QuoteInvalidated:   "stream.uois.quote_invalidated",
+		QuoteError:         "quote:error",
+		OrderConfirmed:     "stream.uois.order_confirmed",
+		OrderConfirmFailed: "stream.uois.order_confirm_failed",
+		ConsumerGroupName:  "uois-gateway-consumers",
+	}
+}
+
 // Mock dependencies
 type mockEventPublisher struct {
 	mock.Mock
@@ -44,9 +70,9 @@ type mockCallbackService struct {
 	mock.Mock
 }
 
-func (m *mockCallbackService) SendCallback(ctx context.Context, callbackURL string, payload interface{}) error {
+func (m *mockCallbackService) SendCallback(ctx context.Context, callbackURL string, payload interface{}) (string, error) {
 	args := m.Called(ctx, callbackURL, payload)
-	return args.Error(0)
+	return args.String(0), args.Error(1)
 }
 
 type mockIdempotencyService struct {
@@ -99,6 +125,15 @@ func (m *mockCacheService) Delete(ctx context.Context, key string) error {
 	return args.Error(0)
 }
 
+type mockResponseSigner struct {
+	mock.Mock
+}
+
+func (m *mockResponseSigner) SignResponse(payload []byte) (string, error) {
+	args := m.Called(payload)
+	return args.String(0), args.Error(1)
+}
+
 func TestSearchHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	logger := zap.NewNop()
@@ -110,7 +145,7 @@ func TestSearchHandler_Success(t *testing.T) {
 
 	orderRecordService := new(mockOrderRecordService)
 	auditService := new(mockAuditService)
-	handler := NewSearchHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderRecordService, auditService, "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", logger)
+	handler := NewSearchHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderRecordService, auditService, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", nil, logger)
 
 	transactionID := uuid.New().String()
 	messageID := uuid.New().String()
@@ -135,11 +170,12 @@ func TestSearchHandler_Success(t *testing.T) {
 		ETADestination:              timePtr(time.Now().Add(30 * time.Minute)),
 	}
 	eventConsumer.On("ConsumeEvent", mock.Anything, "quote:computed", "uois-gateway-consumers", mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(quoteComputedEvent, nil)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "quote:error", "uois-gateway-consumers", mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(nil, nil)
 
 	// Mock successful callback (called asynchronously, so we'll wait for it)
 	callbackService.On("SendCallback", mock.Anything, mock.MatchedBy(func(url string) bool {
 		return strings.HasSuffix(url, "/on_search")
-	}), mock.Anything).Return(nil).Maybe() // Maybe() allows the call to happen asynchronously
+	}), mock.Anything).Return("", nil).Maybe() // Maybe() allows the call to happen asynchronously
 
 	// Mock idempotency check (no existing request)
 	idempotencyService.On("CheckIdempotency", mock.Anything, mock.AnythingOfType("string")).Return(nil, false, nil)
@@ -221,7 +257,7 @@ func TestSearchHandler_InvalidRequest(t *testing.T) {
 
 	orderRecordService := new(mockOrderRecordService)
 	auditService := new(mockAuditService)
-	handler := NewSearchHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderRecordService, auditService, "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", logger)
+	handler := NewSearchHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderRecordService, auditService, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", nil, logger)
 
 	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil)
 
@@ -262,7 +298,7 @@ func TestSearchHandler_EventPublishFailure(t *testing.T) {
 
 	orderRecordService := new(mockOrderRecordService)
 	auditService := new(mockAuditService)
-	handler := NewSearchHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderRecordService, auditService, "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", logger)
+	handler := NewSearchHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderRecordService, auditService, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", nil, logger)
 
 	transactionID := uuid.New().String()
 	messageID := uuid.New().String()
@@ -336,7 +372,7 @@ func TestSearchHandler_CallbackContextRegeneration(t *testing.T) {
 
 	orderRecordService := new(mockOrderRecordService)
 	auditService := new(mockAuditService)
-	handler := NewSearchHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderRecordService, auditService, "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", logger)
+	handler := NewSearchHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderRecordService, auditService, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", nil, logger)
 
 	transactionID := uuid.New().String()
 	originalMessageID := uuid.New().String()
@@ -362,6 +398,7 @@ func TestSearchHandler_CallbackContextRegeneration(t *testing.T) {
 		ETADestination:              timePtr(time.Now().Add(30 * time.Minute)),
 	}
 	eventConsumer.On("ConsumeEvent", mock.Anything, "quote:computed", "uois-gateway-consumers", mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(quoteComputedEvent, nil)
+	eventConsumer.On("ConsumeEvent", mock.Anything, "quote:error", "uois-gateway-consumers", mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(nil, nil)
 
 	// Mock successful order record storage
 	orderRecordService.On("StoreOrderRecord", mock.Anything, mock.AnythingOfType("*ondc.OrderRecord")).Return(nil)
@@ -377,7 +414,7 @@ func TestSearchHandler_CallbackContextRegeneration(t *testing.T) {
 			return true
 		}
 		return false
-	})).Return(nil).Maybe()
+	})).Return("", nil).Maybe()
 
 	idempotencyService.On("CheckIdempotency", mock.Anything, mock.AnythingOfType("string")).Return(nil, false, nil)
 	idempotencyService.On("StoreIdempotency", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("time.Duration")).Return(nil)
@@ -455,7 +492,7 @@ func TestSearchHandler_TTLParsing(t *testing.T) {
 
 	orderRecordService := new(mockOrderRecordService)
 	auditService := new(mockAuditService)
-	handler := NewSearchHandler(nil, nil, nil, nil, orderRecordService, auditService, "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", logger)
+	handler := NewSearchHandler(nil, nil, testStreamsConfig(), nil, nil, orderRecordService, auditService, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", nil, logger)
 
 	tests := []struct {
 		name        string
@@ -498,7 +535,7 @@ func TestSearchHandler_StoreOrderRecordFailure(t *testing.T) {
 	orderRecordService := new(mockOrderRecordService)
 
 	auditService := new(mockAuditService)
-	handler := NewSearchHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderRecordService, auditService, "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", logger)
+	handler := NewSearchHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderRecordService, auditService, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", nil, logger)
 
 	transactionID := uuid.New().String()
 	messageID := uuid.New().String()
@@ -574,7 +611,7 @@ func TestSearchHandler_InvalidTTL(t *testing.T) {
 	orderRecordService := new(mockOrderRecordService)
 
 	auditService := new(mockAuditService)
-	handler := NewSearchHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderRecordService, auditService, "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", logger)
+	handler := NewSearchHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderRecordService, auditService, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", nil, logger)
 
 	transactionID := uuid.New().String()
 	messageID := uuid.New().String()
@@ -652,7 +689,7 @@ func TestSearchHandler_EmptyTTL(t *testing.T) {
 	orderRecordService := new(mockOrderRecordService)
 
 	auditService := new(mockAuditService)
-	handler := NewSearchHandler(eventPublisher, eventConsumer, callbackService, idempotencyService, orderRecordService, auditService, "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", logger)
+	handler := NewSearchHandler(eventPublisher, eventConsumer, testStreamsConfig(), callbackService, idempotencyService, orderRecordService, auditService, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "https://bpp.example.com/terms", nil, logger)
 
 	transactionID := uuid.New().String()
 	messageID := uuid.New().String()
@@ -728,3 +765,107 @@ func TestSearchHandler_EmptyTTL(t *testing.T) {
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+func TestSearchHandler_DeliveryCategoryRejectedByPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	eventPublisher := new(mockEventPublisher)
+	auditService := new(mockAuditService)
+	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil).Maybe()
+	handler := NewSearchHandler(eventPublisher, nil, testStreamsConfig(), nil, nil, nil, auditService, testDeliveryPolicy(t), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "", nil, zap.NewNop())
+
+	requestBody := map[string]interface{}{
+		"context": map[string]interface{}{
+			"domain":         "nic2004:60232",
+			"city":           "std:080",
+			"action":         "search",
+			"transaction_id": uuid.New().String(),
+			"message_id":     uuid.New().String(),
+			"timestamp":      time.Now().UTC().Format(time.RFC3339),
+			"ttl":            "PT30S",
+			"bap_uri":        "https://buyer.example.com",
+		},
+		"message": map[string]interface{}{
+			"intent": map[string]interface{}{
+				"category": map[string]interface{}{"id": "Same Day Delivery"},
+			},
+		},
+	}
+
+	body, _ := json.Marshal(requestBody)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/search", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("client", &models.Client{ID: "test-client", ClientCode: "test-client"})
+
+	handler.HandleSearch(c)
+
+	var response models.ONDCResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "66002", response.Error.Code)
+	eventPublisher.AssertNotCalled(t, "PublishEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchHandler_BuildOnSearchCallback_DeliveryPolicy(t *testing.T) {
+	sameDayInBengaluru := append(deliverypolicy.DefaultRules(), models.DeliveryCategoryRule{
+		CategoryID: "Same Day Delivery",
+		CityCodes:  []string{"std:080"},
+		MaxTAT:     "PT8H",
+	})
+	handler := NewSearchHandler(nil, nil, testStreamsConfig(), nil, nil, nil, nil, testDeliveryPolicy(t, sameDayInBengaluru...), "P1", "bpp.example.com", "https://bpp.example.com", "Test BPP", "", nil, zap.NewNop())
+
+	quoteWithTAT := func(tat time.Duration) *models.QuoteComputedEvent {
+		now := time.Now()
+		return &models.QuoteComputedEvent{
+			BaseEvent:      models.BaseEvent{Timestamp: now},
+			Serviceable:    true,
+			Price:          models.Price{Value: 60.0, Currency: "INR"},
+			ETAOrigin:      timePtr(now.Add(10 * time.Minute)),
+			ETADestination: timePtr(now.Add(tat)),
+		}
+	}
+	searchRequest := func(city, category string) *models.ONDCRequest {
+		intent := map[string]interface{}{}
+		if category != "" {
+			intent["category"] = map[string]interface{}{"id": category}
+		}
+		return &models.ONDCRequest{
+			Context: models.ONDCContext{City: city, TransactionID: "txn-1"},
+			Message: map[string]interface{}{"intent": intent},
+		}
+	}
+	offered := func(resp models.ONDCResponse) []string {
+		catalog := resp.Message["catalog"].(map[string]interface{})
+		var categories []string
+		for _, provider := range catalog["bpp/providers"].([]map[string]interface{}) {
+			for _, category := range provider["categories"].([]map[string]interface{}) {
+				categories = append(categories, category["id"].(string))
+			}
+		}
+		return categories
+	}
+
+	tests := []struct {
+		name     string
+		city     string
+		category string
+		tat      time.Duration
+		want     []string
+	}{
+		{name: "no category, fast quote", city: "std:080", tat: 30 * time.Minute, want: []string{"Immediate Delivery"}},
+		{name: "no category, same day quote in enabled city", city: "std:080", tat: 4 * time.Hour, want: []string{"Same Day Delivery"}},
+		{name: "no category, same day quote elsewhere", city: "std:011", tat: 4 * time.Hour, want: nil},
+		{name: "requested same day", city: "std:080", category: "Same Day Delivery", tat: 4 * time.Hour, want: []string{"Same Day Delivery"}},
+		{name: "requested immediate, quote too slow", city: "std:080", category: "Immediate Delivery", tat: 2 * time.Hour, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := handler.buildOnSearchCallback(searchRequest(tt.city, tt.category), "test-client", quoteWithTAT(tt.tat))
+			assert.Nil(t, resp.Error)
+			assert.Equal(t, tt.want, offered(resp))
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"uois-gateway/internal/middleware"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/services/audit"
 	"uois-gateway/internal/utils"
@@ -30,6 +31,7 @@ type StatusHandler struct {
 	cacheService                      CacheService
 	bppID                             string // BPP ID (ONDC-registered Seller NP identity)
 	bppURI                            string // BPP URI
+	responseSigner                    ResponseSigner
 	logger                            *zap.Logger
 }
 
@@ -45,6 +47,7 @@ func NewStatusHandler(
 	cacheService CacheService,
 	bppID string,
 	bppURI string,
+	responseSigner ResponseSigner,
 	logger *zap.Logger,
 ) *StatusHandler {
 	return &StatusHandler{
@@ -58,6 +61,7 @@ func NewStatusHandler(
 		cacheService:                      cacheService,
 		bppID:                             bppID,
 		bppURI:                            bppURI,
+		responseSigner:                    responseSigner,
 		logger:                            logger,
 	}
 }
@@ -91,9 +95,9 @@ func (h *StatusHandler) HandleStatus(c *gin.Context) {
 	// Check idempotency
 	idempotencyKey := h.buildIdempotencyKey(req.Context.TransactionID, req.Context.MessageID)
 	if existingResponseBytes, exists, err := h.idempotencyService.CheckIdempotency(ctx, idempotencyKey); err == nil && exists {
-		var existingResponse models.ONDCACKResponse
-		if err := json.Unmarshal(existingResponseBytes, &existingResponse); err == nil {
-			h.respondACK(c, existingResponse)
+		// Serve the stored bytes verbatim so the signature covers the original response
+		if json.Valid(existingResponseBytes) {
+			middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, existingResponseBytes)
 			return
 		}
 	}
@@ -146,7 +150,10 @@ func (h *StatusHandler) HandleStatus(c *gin.Context) {
 	// If not cached, call Order Service
 	if !cached {
 		var err error
-		orderStatus, err = h.orderServiceClient.GetOrder(ctx, dispatchOrderID)
+		// Retries of the Order Service call stop once the request TTL runs out
+		orderCtx, cancelOrderCtx := utils.WithONDCDeadline(utils.WithRequestKey(ctx, idempotencyKey), req.Context.Timestamp, req.Context.TTL)
+		defer cancelOrderCtx()
+		orderStatus, err = h.orderServiceClient.GetOrder(orderCtx, dispatchOrderID)
 		if err != nil {
 			h.logger.Error("failed to get order status", zap.Error(err), zap.String("trace_id", traceID), zap.String("dispatch_order_id", dispatchOrderID))
 			domainErr, ok := err.(*errors.DomainError)
@@ -175,10 +182,10 @@ func (h *StatusHandler) HandleStatus(c *gin.Context) {
 	h.logRequestResponse(ctx, &req, response, nil, orderRecord, clientID, traceID)
 
 	// Send callback asynchronously (pass orderRecord for stable fulfillment.id)
-	go h.sendStatusCallback(ctx, &req, orderStatus, orderRecord, traceID)
+	go h.sendStatusCallback(context.WithoutCancel(ctx), &req, orderStatus, orderRecord, traceID)
 
 	// Return ACK
-	h.respondACK(c, response)
+	middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, responseBytes)
 }
 
 func (h *StatusHandler) extractOrderID(req *models.ONDCRequest) (string, error) {
@@ -208,14 +215,45 @@ func (h *StatusHandler) sendStatusCallback(ctx context.Context, req *models.ONDC
 	callbackURL := req.Context.BapURI + "/on_status"
 	callbackPayload := h.buildOnStatusCallback(ctx, req, orderStatus, orderRecord)
 
-	if err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload); err != nil {
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
 		h.logger.Error("failed to send /on_status callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "failed", err.Error())
 	} else {
+		h.logger.Info("/on_status callback accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "success", "")
 	}
 }
 
+// SendUnsolicitedStatus pushes an /on_status the BAP did not poll for, after an order state transition
+// The callback context is rebuilt from the identifiers captured on the order record at /confirm;
+// billing and contacts come from storage by transaction_id, exactly as for a polled /status.
+func (h *StatusHandler) SendUnsolicitedStatus(ctx context.Context, orderRecord *OrderRecord, orderStatus *OrderStatus, traceID string) error {
+	req, err := unsolicitedRequest(orderRecord, "on_status")
+	if err != nil {
+		return err
+	}
+
+	// Cached /status responses predate the transition
+	if h.cacheService != nil {
+		_ = h.cacheService.Delete(ctx, fmt.Sprintf("status:%s:%s", orderRecord.ClientID, orderRecord.OrderID))
+	}
+
+	callbackURL := orderRecord.BapURI + "/on_status"
+	callbackPayload := h.buildOnStatusCallback(ctx, req, orderStatus, orderRecord)
+
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
+		h.logger.Error("failed to send unsolicited /on_status callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL))
+		h.logCallbackDelivery(ctx, orderRecord.TransactionID, callbackURL, 1, "failed", err.Error())
+		return err
+	}
+
+	h.logger.Info("unsolicited /on_status callback accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
+	h.logCallbackDelivery(ctx, orderRecord.TransactionID, callbackURL, 1, "success", "")
+	return nil
+}
+
 func (h *StatusHandler) buildOnStatusCallback(ctx context.Context, req *models.ONDCRequest, orderStatus *OrderStatus, orderRecord *OrderRecord) models.ONDCResponse {
 	// Regenerate callback context (ONDC protocol requirement)
 	callbackCtx := req.Context
@@ -265,8 +303,26 @@ func (h *StatusHandler) buildOnStatusCallback(ctx context.Context, req *models.O
 		fulfillmentStateCode = "IN_TRANSIT"
 	}
 
+	// Agent details: Order Service first, then what was persisted on RIDER_ASSIGNED
+	riderID := orderStatus.RiderID
+	if riderID == "" {
+		riderID = orderRecord.RiderID
+	}
+	agent := orderStatus.Agent
+	if agent == nil {
+		agent = orderRecord.Agent
+	}
+
 	// Build ONDC-compliant structure: order.fulfillments[] array with contacts
-	fulfillment := h.buildFulfillmentWithContacts(ctx, req, fulfillmentID, orderStatus.RiderID, fulfillmentStateCode)
+	fulfillment := h.buildFulfillmentWithContacts(ctx, req, fulfillmentID, riderID, fulfillmentStateCode)
+	if agentInfo := fulfillmentAgent(riderID, agent); agentInfo != nil {
+		fulfillment["agent"] = agentInfo
+	}
+	if vehicle := fulfillmentVehicle(agent); vehicle != nil {
+		fulfillment["vehicle"] = vehicle
+	}
+	// PCC/DCC from /update, VERIFIED once the order has passed pickup / delivery
+	applyFulfillmentAuthorizations(fulfillment, orderRecord, orderStatus.State, time.Now())
 
 	// Retrieve billing: first from request, then from Redis (stored during /init)
 	billing := h.getBilling(ctx, req)
@@ -409,10 +465,6 @@ func (h *StatusHandler) buildIdempotencyKey(transactionID, messageID string) str
 	return "status:" + transactionID + ":" + messageID
 }
 
-func (h *StatusHandler) respondACK(c *gin.Context, response interface{}) {
-	c.JSON(http.StatusOK, response)
-}
-
 func (h *StatusHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 	ctx := c.Request.Context()
 	traceID := utils.ExtractTraceID(utils.EnsureTraceparent(c.GetHeader("traceparent")))
@@ -445,7 +497,7 @@ func (h *StatusHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 		h.logRequestResponse(ctx, req, response, nil, nil, clientID, traceID)
 	}
 
-	c.JSON(errors.GetHTTPStatus(err), response)
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, errors.GetHTTPStatus(err), response)
 }
 
 func (h *StatusHandler) logRequestResponse(ctx context.Context, req *models.ONDCRequest, ackResponse interface{}, callbackPayload interface{}, orderRecord *OrderRecord, clientID, traceID string) {
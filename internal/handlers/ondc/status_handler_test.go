@@ -2,6 +2,7 @@ package ondc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -32,7 +33,7 @@ func TestStatusHandler_Success(t *testing.T) {
 	auditService := new(mockAuditService)
 	cacheService := new(mockCacheService)
 
-	handler := NewStatusHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, cacheService, "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewStatusHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, cacheService, "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	clientOrderID := uuid.New().String()
 	dispatchOrderID := uuid.New().String()
@@ -83,7 +84,7 @@ func TestStatusHandler_Success(t *testing.T) {
 
 	callbackService.On("SendCallback", mock.Anything, mock.MatchedBy(func(url string) bool {
 		return strings.HasSuffix(url, "/on_status")
-	}), mock.Anything).Return(nil).Maybe()
+	}), mock.Anything).Return("", nil).Maybe()
 
 	requestBody := map[string]interface{}{
 		"context": map[string]interface{}{
@@ -141,7 +142,7 @@ func TestStatusHandler_OrderNotFound(t *testing.T) {
 	auditService := new(mockAuditService)
 	cacheService := new(mockCacheService)
 
-	handler := NewStatusHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, cacheService, "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewStatusHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, billingStorageService, fulfillmentContactsStorageService, auditService, cacheService, "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	clientOrderID := uuid.New().String()
 	transactionID := uuid.New().String()
@@ -187,3 +188,89 @@ func TestStatusHandler_OrderNotFound(t *testing.T) {
 
 	orderRecordService.AssertExpectations(t)
 }
+
+func TestStatusHandler_SendUnsolicitedStatus(t *testing.T) {
+	logger := zap.NewNop()
+
+	callbackService := new(mockCallbackService)
+	billingStorageService := new(mockBillingStorageService)
+	fulfillmentContactsStorageService := new(mockFulfillmentContactsStorageService)
+	auditService := new(mockAuditService)
+	cacheService := new(mockCacheService)
+
+	handler := NewStatusHandler(callbackService, nil, nil, nil, billingStorageService, fulfillmentContactsStorageService, auditService, cacheService, "test-bpp-id", "https://bpp.example.com", nil, logger)
+
+	orderRecord := &OrderRecord{
+		DispatchOrderID: "dispatch-123",
+		OrderID:         "order-123",
+		ClientID:        "test-client",
+		TransactionID:   "txn-123",
+		FulfillmentID:   "F1",
+		BapID:           "buyer.example.com",
+		BapURI:          "https://buyer.example.com",
+		Domain:          "nic2004:60232",
+	}
+	orderStatus := &OrderStatus{
+		DispatchOrderID: "dispatch-123",
+		State:           "PICKED_UP",
+		RiderID:         "rider-1",
+		Fulfillment:     FulfillmentStatus{State: "Order-picked-up"},
+	}
+
+	cacheService.On("Delete", mock.Anything, "status:test-client:order-123").Return(nil)
+	billingStorageService.On("GetBilling", mock.Anything, "txn-123").Return(nil, nil)
+	fulfillmentContactsStorageService.On("GetFulfillmentContacts", mock.Anything, "txn-123").Return(nil, nil)
+	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	var payload models.ONDCResponse
+	callbackService.On("SendCallback", mock.Anything, "https://buyer.example.com/on_status", mock.Anything).Run(func(args mock.Arguments) {
+		payload = args.Get(2).(models.ONDCResponse)
+	}).Return("", nil)
+
+	err := handler.SendUnsolicitedStatus(context.Background(), orderRecord, orderStatus, "trace-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "on_status", payload.Context.Action)
+	assert.Equal(t, "txn-123", payload.Context.TransactionID)
+	assert.Equal(t, "buyer.example.com", payload.Context.BapID)
+	assert.Equal(t, "test-bpp-id", payload.Context.BppID)
+	assert.NotEmpty(t, payload.Context.MessageID)
+
+	order := payload.Message["order"].(map[string]interface{})
+	assert.Equal(t, "order-123", order["id"])
+	assert.Equal(t, "IN_PROGRESS", order["state"])
+	fulfillment := order["fulfillments"].([]map[string]interface{})[0]
+	assert.Equal(t, "Order-picked-up", fulfillment["state"].(map[string]interface{})["descriptor"].(map[string]interface{})["code"])
+	assert.Equal(t, map[string]interface{}{"id": "rider-1"}, fulfillment["agent"])
+	callbackService.AssertExpectations(t)
+	cacheService.AssertExpectations(t)
+}
+
+func TestStatusHandler_SendUnsolicitedStatus_MissingCallbackTarget(t *testing.T) {
+	callbackService := new(mockCallbackService)
+	handler := NewStatusHandler(callbackService, nil, nil, nil, nil, nil, nil, nil, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	err := handler.SendUnsolicitedStatus(context.Background(), &OrderRecord{DispatchOrderID: "dispatch-123", OrderID: "order-123"}, &OrderStatus{}, "trace-1")
+
+	assert.Error(t, err)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65006, domainErr.Code)
+	callbackService.AssertNotCalled(t, "SendCallback", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStatusHandler_SendUnsolicitedStatus_CallbackFailure(t *testing.T) {
+	callbackService := new(mockCallbackService)
+	handler := NewStatusHandler(callbackService, nil, nil, nil, nil, nil, nil, nil, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	callbackService.On("SendCallback", mock.Anything, "https://buyer.example.com/on_status", mock.Anything).
+		Return("", errors.NewDomainError(65020, "callback delivery failed", "unexpected status: 503"))
+
+	err := handler.SendUnsolicitedStatus(context.Background(), &OrderRecord{
+		DispatchOrderID: "dispatch-123",
+		OrderID:         "order-123",
+		BapURI:          "https://buyer.example.com",
+	}, &OrderStatus{State: "DELIVERED"}, "trace-1")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package ondc
+
+import (
+	"fmt"
+	"net/http"
+
+	"uois-gateway/internal/models"
+	ondcService "uois-gateway/internal/services/ondc"
+	"uois-gateway/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// OnSubscribeRequest is the registry /on_subscribe challenge
+type OnSubscribeRequest struct {
+	SubscriberID string `json:"subscriber_id" binding:"required"`
+	Challenge    string `json:"challenge" binding:"required"`
+}
+
+// OnSubscribeResponse carries the decrypted challenge back to the registry
+type OnSubscribeResponse struct {
+	Answer string `json:"answer"`
+}
+
+// SigningKeysResponse lists the gateway's signing public keys for registry key updates
+type SigningKeysResponse struct {
+	SubscriberID string                      `json:"subscriber_id"`
+	Keys         []ondcService.PublicKeyInfo `json:"keys"`
+}
+
+// SubscriptionHandler serves the ONDC registry onboarding endpoints
+// These are called by the registry itself, not by clients, so they sit outside the /ondc auth group
+type SubscriptionHandler struct {
+	subscriptionService SubscriptionService
+	subscriberID        string
+	logger              *zap.Logger
+}
+
+// NewSubscriptionHandler creates a new subscription handler
+func NewSubscriptionHandler(subscriptionService SubscriptionService, subscriberID string, logger *zap.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionService: subscriptionService,
+		subscriberID:        subscriberID,
+		logger:              logger,
+	}
+}
+
+// RegisterRoutes registers the onboarding endpoints on the router
+// /on_subscribe is served under routePrefix, the prefix /subscribe registers as callback_url; the
+// site verification page stays at the root where the registry fetches it
+func (h *SubscriptionHandler) RegisterRoutes(router gin.IRoutes, routePrefix string) {
+	router.POST(ondcService.OnSubscribePath(routePrefix), h.HandleOnSubscribe)
+	router.GET("/ondc-site-verification.html", h.HandleSiteVerification)
+	router.GET("/ondc-signing-keys", h.HandleSigningKeys)
+}
+
+// HandleOnSubscribe handles POST /on_subscribe from the ONDC registry
+func (h *SubscriptionHandler) HandleOnSubscribe(c *gin.Context) {
+	var req OnSubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.NewDomainError(65001, "invalid request", err.Error()))
+		return
+	}
+
+	if req.SubscriberID != h.subscriberID {
+		h.logger.Warn("on_subscribe for unexpected subscriber", zap.String("subscriber_id", req.SubscriberID))
+		h.respondError(c, errors.NewDomainError(65001, "invalid request", "subscriber_id mismatch"))
+		return
+	}
+
+	answer, err := h.subscriptionService.DecryptChallenge(req.Challenge)
+	if err != nil {
+		h.logger.Error("failed to decrypt on_subscribe challenge", zap.Error(err))
+		h.respondError(c, toDomainError(err))
+		return
+	}
+
+	h.logger.Info("answered registry on_subscribe challenge", zap.String("subscriber_id", req.SubscriberID))
+	c.JSON(http.StatusOK, OnSubscribeResponse{Answer: answer})
+}
+
+// HandleSiteVerification handles GET /ondc-site-verification.html
+func (h *SubscriptionHandler) HandleSiteVerification(c *gin.Context) {
+	html, err := h.subscriptionService.SiteVerificationHTML()
+	if err != nil {
+		h.logger.Error("failed to render site verification page", zap.Error(err))
+		domainErr := toDomainError(err)
+		c.String(errors.GetHTTPStatus(domainErr), domainErr.Message)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
+}
+
+// HandleSigningKeys handles GET /ondc-signing-keys
+// Lists every keyring key (public half only) with its validity window so ops can stage a rotation
+// with the registry before the new key becomes the signing key
+func (h *SubscriptionHandler) HandleSigningKeys(c *gin.Context) {
+	keys := h.subscriptionService.SigningKeys()
+	if keys == nil {
+		keys = []ondcService.PublicKeyInfo{}
+	}
+
+	c.JSON(http.StatusOK, SigningKeysResponse{SubscriberID: h.subscriberID, Keys: keys})
+}
+
+func (h *SubscriptionHandler) respondError(c *gin.Context, err *errors.DomainError) {
+	c.JSON(errors.GetHTTPStatus(err), models.ONDCResponse{
+		Error: &models.ONDCError{
+			Type:    "CONTEXT_ERROR",
+			Code:    fmt.Sprintf("%d", err.Code),
+			Message: map[string]string{"en": err.Message},
+		},
+	})
+}
+
+// toDomainError normalizes service errors for HTTP status mapping
+func toDomainError(err error) *errors.DomainError {
+	if domainErr, ok := err.(*errors.DomainError); ok {
+		return domainErr
+	}
+	return errors.WrapDomainError(err, 65020, "internal error", "subscription error")
+}
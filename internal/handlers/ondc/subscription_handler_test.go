@@ -0,0 +1,163 @@
+package ondc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"testing"
+
+	ondcService "uois-gateway/internal/services/ondc"
+	"uois-gateway/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockSubscriptionService struct {
+	mock.Mock
+}
+
+func (m *mockSubscriptionService) DecryptChallenge(challenge string) (string, error) {
+	args := m.Called(challenge)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockSubscriptionService) SiteVerificationHTML() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockSubscriptionService) SigningKeys() []ondcService.PublicKeyInfo {
+	args := m.Called()
+	return args.Get(0).([]ondcService.PublicKeyInfo)
+}
+
+func newSubscriptionTestRouter(service *mockSubscriptionService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewSubscriptionHandler(service, "lsp.example.com", zap.NewNop())
+	router := gin.New()
+	handler.RegisterRoutes(router, "")
+	return router
+}
+
+func TestSubscriptionHandler_HandleOnSubscribe_Success(t *testing.T) {
+	service := new(mockSubscriptionService)
+	router := newSubscriptionTestRouter(service)
+	service.On("DecryptChallenge", "ZW5jcnlwdGVk").Return("plain-answer", nil)
+
+	body := []byte(`{"subscriber_id":"lsp.example.com","challenge":"ZW5jcnlwdGVk"}`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/on_subscribe", bytes.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response OnSubscribeResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "plain-answer", response.Answer)
+}
+
+func TestSubscriptionHandler_RegisterRoutes_CallbackURLResolvesToOnSubscribe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := new(mockSubscriptionService)
+	service.On("DecryptChallenge", "ZW5jcnlwdGVk").Return("plain-answer", nil)
+
+	for _, prefix := range []string{"", "/registry", "registry/"} {
+		router := gin.New()
+		NewSubscriptionHandler(service, "lsp.example.com", zap.NewNop()).RegisterRoutes(router, prefix)
+
+		// The registry calls subscriber_url + callback_url + "/on_subscribe", joined as URL paths
+		subscriberURL, err := url.Parse("https://lsp.example.com")
+		require.NoError(t, err)
+		registryPath := path.Join("/", subscriberURL.Path, ondcService.SubscribeCallbackURL(prefix), "on_subscribe")
+
+		body := []byte(`{"subscriber_id":"lsp.example.com","challenge":"ZW5jcnlwdGVk"}`)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, registryPath, bytes.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, w.Code, "prefix %q path %s", prefix, registryPath)
+		assert.Contains(t, w.Body.String(), "plain-answer")
+	}
+}
+
+func TestSubscriptionHandler_HandleOnSubscribe_SubscriberMismatch(t *testing.T) {
+	service := new(mockSubscriptionService)
+	router := newSubscriptionTestRouter(service)
+
+	body := []byte(`{"subscriber_id":"other.example.com","challenge":"ZW5jcnlwdGVk"}`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/on_subscribe", bytes.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	service.AssertNotCalled(t, "DecryptChallenge", mock.Anything)
+}
+
+func TestSubscriptionHandler_HandleOnSubscribe_MissingChallenge(t *testing.T) {
+	service := new(mockSubscriptionService)
+	router := newSubscriptionTestRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/on_subscribe", bytes.NewReader([]byte(`{"subscriber_id":"lsp.example.com"}`))))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSubscriptionHandler_HandleOnSubscribe_DecryptFailure(t *testing.T) {
+	service := new(mockSubscriptionService)
+	router := newSubscriptionTestRouter(service)
+	service.On("DecryptChallenge", mock.Anything).Return("", errors.NewDomainError(65020, "internal error", "subscription encryption keys not configured"))
+
+	body := []byte(`{"subscriber_id":"lsp.example.com","challenge":"ZW5jcnlwdGVk"}`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/on_subscribe", bytes.NewReader(body)))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestSubscriptionHandler_HandleSiteVerification(t *testing.T) {
+	service := new(mockSubscriptionService)
+	router := newSubscriptionTestRouter(service)
+	service.On("SiteVerificationHTML").Return("<html>signed</html>", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ondc-site-verification.html", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "<html>signed</html>", w.Body.String())
+}
+
+func TestSubscriptionHandler_HandleSiteVerification_NotConfigured(t *testing.T) {
+	service := new(mockSubscriptionService)
+	router := newSubscriptionTestRouter(service)
+	service.On("SiteVerificationHTML").Return("", errors.NewDomainError(65006, "not found", "subscription request_id not configured"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ondc-site-verification.html", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSubscriptionHandler_HandleSigningKeys(t *testing.T) {
+	service := new(mockSubscriptionService)
+	router := newSubscriptionTestRouter(service)
+	service.On("SigningKeys").Return([]ondcService.PublicKeyInfo{
+		{UkID: "UK1", SigningPublicKey: "a2V5MQ==", Active: true},
+		{UkID: "UK2", SigningPublicKey: "a2V5Mg==", Active: true, Signing: true},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ondc-signing-keys", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response SigningKeysResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "lsp.example.com", response.SubscriberID)
+	assert.Len(t, response.Keys, 2)
+	assert.True(t, response.Keys[1].Signing)
+	assert.NotContains(t, w.Body.String(), "private")
+}
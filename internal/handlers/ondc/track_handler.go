@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"uois-gateway/internal/middleware"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/services/audit"
 	"uois-gateway/internal/utils"
@@ -26,6 +27,7 @@ type TrackHandler struct {
 	cacheService       CacheService
 	bppID              string // BPP ID (ONDC-registered Seller NP identity)
 	bppURI             string // BPP URI
+	responseSigner     ResponseSigner
 	logger             *zap.Logger
 }
 
@@ -39,6 +41,7 @@ func NewTrackHandler(
 	cacheService CacheService,
 	bppID string,
 	bppURI string,
+	responseSigner ResponseSigner,
 	logger *zap.Logger,
 ) *TrackHandler {
 	return &TrackHandler{
@@ -50,6 +53,7 @@ func NewTrackHandler(
 		cacheService:       cacheService,
 		bppID:              bppID,
 		bppURI:             bppURI,
+		responseSigner:     responseSigner,
 		logger:             logger,
 	}
 }
@@ -79,9 +83,9 @@ func (h *TrackHandler) HandleTrack(c *gin.Context) {
 
 	idempotencyKey := h.buildIdempotencyKey(req.Context.TransactionID, req.Context.MessageID)
 	if existingResponseBytes, exists, err := h.idempotencyService.CheckIdempotency(ctx, idempotencyKey); err == nil && exists {
-		var existingResponse models.ONDCResponse
-		if err := json.Unmarshal(existingResponseBytes, &existingResponse); err == nil {
-			h.respondACK(c, existingResponse)
+		// Serve the stored bytes verbatim so the signature covers the original response
+		if json.Valid(existingResponseBytes) {
+			middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, existingResponseBytes)
 			return
 		}
 	}
@@ -132,7 +136,10 @@ func (h *TrackHandler) HandleTrack(c *gin.Context) {
 	// If not cached, call Order Service
 	if !cached {
 		var err error
-		orderTracking, err = h.orderServiceClient.GetOrderTracking(ctx, dispatchOrderID)
+		// Retries of the Order Service call stop once the request TTL runs out
+		orderCtx, cancelOrderCtx := utils.WithONDCDeadline(utils.WithRequestKey(ctx, idempotencyKey), req.Context.Timestamp, req.Context.TTL)
+		defer cancelOrderCtx()
+		orderTracking, err = h.orderServiceClient.GetOrderTracking(orderCtx, dispatchOrderID)
 		if err != nil {
 			h.logger.Error("failed to get order tracking", zap.Error(err), zap.String("trace_id", traceID), zap.String("dispatch_order_id", dispatchOrderID))
 			domainErr, ok := err.(*errors.DomainError)
@@ -161,7 +168,7 @@ func (h *TrackHandler) HandleTrack(c *gin.Context) {
 	// Log request/response audit (no callback for /track)
 	h.logRequestResponse(ctx, &req, response, nil, orderRecord, clientID, traceID)
 
-	h.respondACK(c, response)
+	middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, responseBytes)
 }
 
 func (h *TrackHandler) extractOrderID(req *models.ONDCRequest) (string, error) {
@@ -259,11 +266,6 @@ func (h *TrackHandler) buildIdempotencyKey(transactionID, messageID string) stri
 	return "track:" + transactionID + ":" + messageID
 }
 
-func (h *TrackHandler) respondACK(c *gin.Context, response interface{}) {
-	// ONDC v1.2.0: /track returns SYNC response with tracking data
-	c.JSON(http.StatusOK, response)
-}
-
 func (h *TrackHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 	ctx := c.Request.Context()
 	traceID := utils.ExtractTraceID(utils.EnsureTraceparent(c.GetHeader("traceparent")))
@@ -296,7 +298,7 @@ func (h *TrackHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 		h.logRequestResponse(ctx, req, response, nil, nil, clientID, traceID)
 	}
 
-	c.JSON(errors.GetHTTPStatus(err), response)
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, errors.GetHTTPStatus(err), response)
 }
 
 func (h *TrackHandler) logRequestResponse(ctx context.Context, req *models.ONDCRequest, ackResponse interface{}, callbackPayload interface{}, orderRecord *OrderRecord, clientID, traceID string) {
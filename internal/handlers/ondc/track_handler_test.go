@@ -28,7 +28,7 @@ func TestTrackHandler_Success(t *testing.T) {
 	auditService := new(mockAuditService)
 	cacheService := new(mockCacheService)
 
-	handler := NewTrackHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, auditService, cacheService, "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewTrackHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, auditService, cacheService, "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	clientOrderID := uuid.New().String()
 	dispatchOrderID := uuid.New().String()
@@ -122,3 +122,83 @@ func TestTrackHandler_Success(t *testing.T) {
 	orderServiceClient.AssertExpectations(t)
 	orderRecordService.AssertExpectations(t)
 }
+
+func TestTrackHandler_SignsResponseAndStoresSameBytes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	idempotencyService := new(mockIdempotencyService)
+	orderServiceClient := new(mockOrderServiceClient)
+	orderRecordService := new(mockOrderRecordService)
+	auditService := new(mockAuditService)
+	signer := new(mockResponseSigner)
+
+	handler := NewTrackHandler(new(mockCallbackService), idempotencyService, orderServiceClient, orderRecordService, auditService, nil, "test-bpp-id", "https://bpp.example.com", signer, zap.NewNop())
+
+	var storedBytes []byte
+	idempotencyService.On("CheckIdempotency", mock.Anything, mock.AnythingOfType("string")).Return(nil, false, nil)
+	idempotencyService.On("StoreIdempotency", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("time.Duration")).
+		Run(func(args mock.Arguments) { storedBytes = args.Get(2).([]byte) }).Return(nil)
+	auditService.On("LogRequestResponse", mock.Anything, mock.Anything).Return(nil).Maybe()
+	orderRecordService.On("GetOrderRecordByOrderID", mock.Anything, "test-client", "O1").
+		Return(&OrderRecord{DispatchOrderID: "D1", OrderID: "O1", FulfillmentID: "F1"}, nil)
+	orderServiceClient.On("GetOrderTracking", mock.Anything, "D1").
+		Return(&OrderTracking{DispatchOrderID: "D1", TrackingURL: "https://track.example.com/O1"}, nil)
+	signer.On("SignResponse", mock.Anything).Return("track-sig", nil)
+
+	w := serveTrackRequest(handler, "O1")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "track-sig", w.Header().Get("Authorization"))
+	assert.Equal(t, storedBytes, w.Body.Bytes(), "stored idempotency bytes must match the served bytes")
+	assert.Equal(t, w.Body.Bytes(), signer.Calls[0].Arguments.Get(0).([]byte), "signature must cover the exact response bytes")
+}
+
+func TestTrackHandler_IdempotentReplayServedVerbatim(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	idempotencyService := new(mockIdempotencyService)
+	orderRecordService := new(mockOrderRecordService)
+	signer := new(mockResponseSigner)
+
+	handler := NewTrackHandler(new(mockCallbackService), idempotencyService, new(mockOrderServiceClient), orderRecordService, new(mockAuditService), nil, "test-bpp-id", "https://bpp.example.com", signer, zap.NewNop())
+
+	// Key order and whitespace differ from what json.Marshal would produce
+	stored := []byte(`{"message": {"order": {"id": "O1"}}, "context": {"action": "on_track"}}`)
+	idempotencyService.On("CheckIdempotency", mock.Anything, mock.AnythingOfType("string")).Return(stored, true, nil)
+	signer.On("SignResponse", stored).Return("replay-sig", nil)
+
+	w := serveTrackRequest(handler, "O1")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, stored, w.Body.Bytes())
+	assert.Equal(t, "replay-sig", w.Header().Get("Authorization"))
+	orderRecordService.AssertNotCalled(t, "GetOrderRecordByOrderID", mock.Anything, mock.Anything, mock.Anything)
+	signer.AssertExpectations(t)
+}
+
+func serveTrackRequest(handler *TrackHandler, orderID string) *httptest.ResponseRecorder {
+	requestBody := map[string]interface{}{
+		"context": map[string]interface{}{
+			"domain":         "nic2004:52110",
+			"action":         "track",
+			"transaction_id": uuid.New().String(),
+			"message_id":     uuid.New().String(),
+			"timestamp":      time.Now().Format(time.RFC3339),
+			"ttl":            "PT30S",
+			"bap_uri":        "https://buyer.example.com",
+		},
+		"message": map[string]interface{}{
+			"order": map[string]interface{}{"id": orderID},
+		},
+	}
+
+	body, _ := json.Marshal(requestBody)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/track", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("client", &models.Client{ID: "test-client", ClientCode: "test-client"})
+
+	handler.HandleTrack(c)
+	return w
+}
@@ -0,0 +1,62 @@
+package ondc
+
+import (
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+)
+
+// unsolicitedRequest rebuilds the ONDC request context for a callback the BAP did not ask for
+// Callback builders copy req.Context; identifiers come from the order record captured at /confirm
+func unsolicitedRequest(orderRecord *OrderRecord, action string) (*models.ONDCRequest, error) {
+	if orderRecord.BapURI == "" || orderRecord.OrderID == "" {
+		return nil, errors.NewDomainError(65006, "order not found", "order record has no callback target")
+	}
+
+	return &models.ONDCRequest{
+		Context: models.ONDCContext{
+			Domain:        orderRecord.Domain,
+			Action:        action,
+			BapID:         orderRecord.BapID,
+			BapURI:        orderRecord.BapURI,
+			TransactionID: orderRecord.TransactionID,
+		},
+		Message: map[string]interface{}{},
+	}, nil
+}
+
+// fulfillmentAgent builds fulfillment.agent from the rider ID and any persisted agent details
+func fulfillmentAgent(riderID string, agent *AgentDetails) map[string]interface{} {
+	if riderID == "" && agent == nil {
+		return nil
+	}
+
+	result := map[string]interface{}{}
+	if riderID != "" {
+		result["id"] = riderID
+	}
+	if agent != nil {
+		if agent.Name != "" {
+			result["name"] = agent.Name
+		}
+		if agent.Phone != "" {
+			result["phone"] = agent.Phone
+		}
+	}
+	return result
+}
+
+// fulfillmentVehicle builds fulfillment.vehicle, or nil when no vehicle is known
+func fulfillmentVehicle(agent *AgentDetails) map[string]interface{} {
+	if agent == nil || (agent.VehicleCategory == "" && agent.VehicleRegistration == "") {
+		return nil
+	}
+
+	vehicle := map[string]interface{}{}
+	if agent.VehicleCategory != "" {
+		vehicle["category"] = agent.VehicleCategory
+	}
+	if agent.VehicleRegistration != "" {
+		vehicle["registration"] = agent.VehicleRegistration
+	}
+	return vehicle
+}
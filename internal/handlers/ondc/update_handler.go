@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"uois-gateway/internal/middleware"
 	"uois-gateway/internal/models"
 	"uois-gateway/internal/services/audit"
 	"uois-gateway/internal/utils"
@@ -29,6 +30,7 @@ type UpdateHandler struct {
 	auditService       AuditService
 	bppID              string // BPP ID (ONDC-registered Seller NP identity)
 	bppURI             string // BPP URI
+	responseSigner     ResponseSigner
 	logger             *zap.Logger
 }
 
@@ -41,6 +43,7 @@ func NewUpdateHandler(
 	auditService AuditService,
 	bppID string,
 	bppURI string,
+	responseSigner ResponseSigner,
 	logger *zap.Logger,
 ) *UpdateHandler {
 	return &UpdateHandler{
@@ -51,6 +54,7 @@ func NewUpdateHandler(
 		auditService:       auditService,
 		bppID:              bppID,
 		bppURI:             bppURI,
+		responseSigner:     responseSigner,
 		logger:             logger,
 	}
 }
@@ -80,9 +84,9 @@ func (h *UpdateHandler) HandleUpdate(c *gin.Context) {
 
 	idempotencyKey := h.buildIdempotencyKey(req.Context.TransactionID, req.Context.MessageID)
 	if existingResponseBytes, exists, err := h.idempotencyService.CheckIdempotency(ctx, idempotencyKey); err == nil && exists {
-		var existingResponse models.ONDCACKResponse
-		if err := json.Unmarshal(existingResponseBytes, &existingResponse); err == nil {
-			h.respondACK(c, existingResponse)
+		// Serve the stored bytes verbatim so the signature covers the original response
+		if json.Valid(existingResponseBytes) {
+			middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, existingResponseBytes)
 			return
 		}
 	}
@@ -94,6 +98,15 @@ func (h *UpdateHandler) HandleUpdate(c *gin.Context) {
 		return
 	}
 
+	// PCC/DCC (start/end.authorization) are validated here and forwarded to Order Service as typed fields
+	fulfillments, _ := updates["fulfillments"].([]interface{})
+	authorizations, authErr := utils.ExtractFulfillmentAuthorizations(fulfillments, time.Now())
+	if authErr != nil {
+		h.logger.Warn("invalid fulfillment authorization", zap.Error(authErr), zap.String("trace_id", traceID))
+		h.respondNACK(c, authErr)
+		return
+	}
+
 	client, _ := c.Get("client")
 	var clientID string
 	if cl, ok := client.(*models.Client); ok {
@@ -116,7 +129,10 @@ func (h *UpdateHandler) HandleUpdate(c *gin.Context) {
 		return
 	}
 
-	if err := h.orderServiceClient.UpdateOrder(ctx, dispatchOrderID, updates); err != nil {
+	// The request key makes the command safe to retry within the request TTL
+	orderCtx, cancelOrderCtx := utils.WithONDCDeadline(utils.WithRequestKey(ctx, idempotencyKey), req.Context.Timestamp, req.Context.TTL)
+	defer cancelOrderCtx()
+	if err := h.orderServiceClient.UpdateOrder(orderCtx, dispatchOrderID, updates, authorizations); err != nil {
 		h.logger.Error("failed to update order", zap.Error(err), zap.String("trace_id", traceID), zap.String("dispatch_order_id", dispatchOrderID))
 		domainErr, ok := err.(*errors.DomainError)
 		if ok {
@@ -127,6 +143,14 @@ func (h *UpdateHandler) HandleUpdate(c *gin.Context) {
 		return
 	}
 
+	// Order Service accepted the codes; keep them on the order record for /on_update and /on_status
+	if len(authorizations) > 0 {
+		recordAuthorizations(orderRecord, authorizations)
+		if err := h.orderRecordService.UpdateOrderRecord(ctx, orderRecord); err != nil {
+			h.logger.Error("failed to store fulfillment authorizations", zap.Error(err), zap.String("trace_id", traceID), zap.String("order.id", orderID))
+		}
+	}
+
 	response := h.composeUpdateResponse(&req)
 
 	responseBytes, _ := json.Marshal(response)
@@ -135,9 +159,9 @@ func (h *UpdateHandler) HandleUpdate(c *gin.Context) {
 	// Log request/response audit
 	h.logRequestResponse(ctx, &req, response, nil, orderRecord, clientID, traceID)
 
-	go h.sendUpdateCallback(ctx, &req, updates, orderRecord, traceID)
+	go h.sendUpdateCallback(context.WithoutCancel(ctx), &req, updates, orderRecord, traceID)
 
-	h.respondACK(c, response)
+	middleware.WriteSignedBytes(c, h.responseSigner, h.logger, http.StatusOK, responseBytes)
 }
 
 func (h *UpdateHandler) extractUpdateData(req *models.ONDCRequest) (string, map[string]interface{}, error) {
@@ -157,7 +181,7 @@ func (h *UpdateHandler) extractUpdateData(req *models.ONDCRequest) (string, map[
 	// ONDC /update is strictly for: PCC/DCC, Authorization updates, Reverse QC, Fulfillment updates
 	// Validate only allowed update fields
 
-	// Extract fulfillments array (allowed: Fulfillment updates)
+	// Extract fulfillments array (allowed: Fulfillment updates, PCC/DCC in start/end.authorization)
 	if fulfillments, ok := order["fulfillments"].([]interface{}); ok && len(fulfillments) > 0 {
 		updates["fulfillments"] = fulfillments
 		hasAllowedUpdate = true
@@ -213,14 +237,85 @@ func (h *UpdateHandler) sendUpdateCallback(ctx context.Context, req *models.ONDC
 	callbackURL := req.Context.BapURI + "/on_update"
 	callbackPayload := h.buildOnUpdateCallback(req, updates, orderRecord)
 
-	if err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload); err != nil {
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
 		h.logger.Error("failed to send /on_update callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "failed", err.Error())
 	} else {
+		h.logger.Info("/on_update callback accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
 		h.logCallbackDelivery(ctx, req.Context.TransactionID, callbackURL, 1, "success", "")
 	}
 }
 
+// SendAgentAssignedUpdate pushes an unsolicited /on_update once a rider is assigned
+// fulfillment carries the Agent-assigned state, the agent (id, name, phone) and vehicle persisted on the order record
+func (h *UpdateHandler) SendAgentAssignedUpdate(ctx context.Context, orderRecord *OrderRecord, traceID string) error {
+	req, err := unsolicitedRequest(orderRecord, "on_update")
+	if err != nil {
+		return err
+	}
+
+	fulfillment := map[string]interface{}{
+		"state": map[string]interface{}{
+			"descriptor": map[string]interface{}{
+				"code": "Agent-assigned",
+			},
+		},
+	}
+	if agent := fulfillmentAgent(orderRecord.RiderID, orderRecord.Agent); agent != nil {
+		fulfillment["agent"] = agent
+	}
+	if vehicle := fulfillmentVehicle(orderRecord.Agent); vehicle != nil {
+		fulfillment["vehicle"] = vehicle
+	}
+	updates := map[string]interface{}{
+		"fulfillments": []interface{}{fulfillment},
+	}
+
+	callbackURL := orderRecord.BapURI + "/on_update"
+	callbackPayload := h.buildOnUpdateCallback(req, updates, orderRecord)
+
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
+		h.logger.Error("failed to send unsolicited /on_update callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL))
+		h.logCallbackDelivery(ctx, orderRecord.TransactionID, callbackURL, 1, "failed", err.Error())
+		return err
+	}
+
+	h.logger.Info("unsolicited /on_update callback accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
+	h.logCallbackDelivery(ctx, orderRecord.TransactionID, callbackURL, 1, "success", "")
+	return nil
+}
+
+// SendQuoteDifferentialUpdate pushes an unsolicited /on_update for a weight/dimension differential
+// The quote is the one persisted on the order record after ApplyQuoteDifferential (agreed lines plus
+// diff/tax_diff); order.tags carry the measured package (linked_order_diff) and its proof
+func (h *UpdateHandler) SendQuoteDifferentialUpdate(ctx context.Context, orderRecord *OrderRecord, differential *models.QuoteDifferentialEvent, traceID string) error {
+	req, err := unsolicitedRequest(orderRecord, "on_update")
+	if err != nil {
+		return err
+	}
+
+	callbackURL := orderRecord.BapURI + "/on_update"
+	callbackPayload := h.buildOnUpdateCallback(req, map[string]interface{}{}, orderRecord)
+	if order, ok := callbackPayload.Message["order"].(map[string]interface{}); ok {
+		order["quote"] = quoteToMap(orderRecord.QuotePrice, orderRecord.QuoteBreakup)
+		order["tags"] = differentialTags(differential)
+		order["updated_at"] = differential.Timestamp.UTC().Format(time.RFC3339)
+	}
+
+	deliveryID, err := h.callbackService.SendCallback(ctx, callbackURL, callbackPayload)
+	if err != nil {
+		h.logger.Error("failed to send differential /on_update callback", zap.Error(err), zap.String("trace_id", traceID), zap.String("callback_url", callbackURL))
+		h.logCallbackDelivery(ctx, orderRecord.TransactionID, callbackURL, 1, "failed", err.Error())
+		return err
+	}
+
+	h.logger.Info("differential /on_update callback accepted for delivery", zap.String("trace_id", traceID), zap.String("callback_url", callbackURL), zap.String("delivery_id", deliveryID))
+	h.logCallbackDelivery(ctx, orderRecord.TransactionID, callbackURL, 1, "success", "")
+	return nil
+}
+
 func (h *UpdateHandler) buildOnUpdateCallback(req *models.ONDCRequest, updates map[string]interface{}, orderRecord *OrderRecord) models.ONDCResponse {
 	callbackCtx := req.Context
 	callbackCtx.MessageID = uuid.New().String()
@@ -263,15 +358,17 @@ func (h *UpdateHandler) buildOnUpdateCallback(req *models.ONDCRequest, updates m
 		if ok {
 			// Override fulfillment.id with stable ID from orderRecord
 			fulfillment["id"] = fulfillmentID
+			// Echo stored PCC/DCC with their status (Order Service state is not known here)
+			applyFulfillmentAuthorizations(fulfillment, orderRecord, "", time.Now())
 			message["order"].(map[string]interface{})["fulfillments"] = []map[string]interface{}{fulfillment}
 		}
 	} else {
 		// If no fulfillments in update, create minimal fulfillment with stable ID
-		message["order"].(map[string]interface{})["fulfillments"] = []map[string]interface{}{
-			{
-				"id": fulfillmentID,
-			},
+		fulfillment := map[string]interface{}{
+			"id": fulfillmentID,
 		}
+		applyFulfillmentAuthorizations(fulfillment, orderRecord, "", time.Now())
+		message["order"].(map[string]interface{})["fulfillments"] = []map[string]interface{}{fulfillment}
 	}
 
 	// Add payment info if provided
@@ -289,10 +386,6 @@ func (h *UpdateHandler) buildIdempotencyKey(transactionID, messageID string) str
 	return "update:" + transactionID + ":" + messageID
 }
 
-func (h *UpdateHandler) respondACK(c *gin.Context, response interface{}) {
-	c.JSON(http.StatusOK, response)
-}
-
 func (h *UpdateHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 	ctx := c.Request.Context()
 	traceID := utils.ExtractTraceID(utils.EnsureTraceparent(c.GetHeader("traceparent")))
@@ -325,7 +418,7 @@ func (h *UpdateHandler) respondNACK(c *gin.Context, err *errors.DomainError) {
 		h.logRequestResponse(ctx, req, response, nil, nil, clientID, traceID)
 	}
 
-	c.JSON(errors.GetHTTPStatus(err), response)
+	middleware.WriteSignedJSON(c, h.responseSigner, h.logger, errors.GetHTTPStatus(err), response)
 }
 
 func (h *UpdateHandler) logRequestResponse(ctx context.Context, req *models.ONDCRequest, ackResponse interface{}, callbackPayload interface{}, orderRecord *OrderRecord, clientID, traceID string) {
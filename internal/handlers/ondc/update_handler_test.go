@@ -2,6 +2,7 @@ package ondc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -28,7 +29,7 @@ func TestUpdateHandler_Success(t *testing.T) {
 	orderRecordService := new(mockOrderRecordService)
 	auditService := new(mockAuditService)
 
-	handler := NewUpdateHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, auditService, "test-bpp-id", "https://bpp.example.com", logger)
+	handler := NewUpdateHandler(callbackService, idempotencyService, orderServiceClient, orderRecordService, auditService, "test-bpp-id", "https://bpp.example.com", nil, logger)
 
 	clientOrderID := uuid.New().String()
 	dispatchOrderID := uuid.New().String()
@@ -51,11 +52,11 @@ func TestUpdateHandler_Success(t *testing.T) {
 	}
 	orderRecordService.On("GetOrderRecordByOrderID", mock.Anything, "test-client", clientOrderID).Return(orderRecord, nil)
 
-	orderServiceClient.On("UpdateOrder", mock.Anything, dispatchOrderID, mock.AnythingOfType("map[string]interface {}")).Return(nil)
+	orderServiceClient.On("UpdateOrder", mock.Anything, dispatchOrderID, mock.AnythingOfType("map[string]interface {}"), []models.FulfillmentAuthorization(nil)).Return(nil)
 
 	callbackService.On("SendCallback", mock.Anything, mock.MatchedBy(func(url string) bool {
 		return strings.HasSuffix(url, "/on_update")
-	}), mock.Anything).Return(nil).Maybe()
+	}), mock.Anything).Return("", nil).Maybe()
 
 	requestBody := map[string]interface{}{
 		"context": map[string]interface{}{
@@ -106,3 +107,69 @@ func TestUpdateHandler_Success(t *testing.T) {
 	orderServiceClient.AssertExpectations(t)
 	orderRecordService.AssertExpectations(t)
 }
+
+func TestUpdateHandler_SendAgentAssignedUpdate(t *testing.T) {
+	callbackService := new(mockCallbackService)
+	auditService := new(mockAuditService)
+	handler := NewUpdateHandler(callbackService, nil, nil, nil, auditService, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	orderRecord := &OrderRecord{
+		DispatchOrderID: "dispatch-123",
+		OrderID:         "order-123",
+		TransactionID:   "txn-123",
+		FulfillmentID:   "F1",
+		BapID:           "buyer.example.com",
+		BapURI:          "https://buyer.example.com",
+		RiderID:         "rider-1",
+		Agent: &AgentDetails{
+			Name:                "Ravi",
+			Phone:               "9886098860",
+			VehicleCategory:     "Bike",
+			VehicleRegistration: "KA01AB1234",
+		},
+	}
+
+	auditService.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	var payload models.ONDCResponse
+	callbackService.On("SendCallback", mock.Anything, "https://buyer.example.com/on_update", mock.Anything).Run(func(args mock.Arguments) {
+		payload = args.Get(2).(models.ONDCResponse)
+	}).Return("", nil)
+
+	err := handler.SendAgentAssignedUpdate(context.Background(), orderRecord, "trace-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "on_update", payload.Context.Action)
+	assert.Equal(t, "txn-123", payload.Context.TransactionID)
+	assert.Equal(t, "buyer.example.com", payload.Context.BapID)
+
+	order := payload.Message["order"].(map[string]interface{})
+	assert.Equal(t, "order-123", order["id"])
+	fulfillment := order["fulfillments"].([]map[string]interface{})[0]
+	assert.Equal(t, "F1", fulfillment["id"])
+	assert.Equal(t, "Agent-assigned", fulfillment["state"].(map[string]interface{})["descriptor"].(map[string]interface{})["code"])
+	assert.Equal(t, map[string]interface{}{"id": "rider-1", "name": "Ravi", "phone": "9886098860"}, fulfillment["agent"])
+	assert.Equal(t, map[string]interface{}{"category": "Bike", "registration": "KA01AB1234"}, fulfillment["vehicle"])
+}
+
+func TestUpdateHandler_SendAgentAssignedUpdate_WithoutAgentDetails(t *testing.T) {
+	callbackService := new(mockCallbackService)
+	handler := NewUpdateHandler(callbackService, nil, nil, nil, nil, "test-bpp-id", "https://bpp.example.com", nil, zap.NewNop())
+
+	var payload models.ONDCResponse
+	callbackService.On("SendCallback", mock.Anything, "https://buyer.example.com/on_update", mock.Anything).Run(func(args mock.Arguments) {
+		payload = args.Get(2).(models.ONDCResponse)
+	}).Return("", nil)
+
+	err := handler.SendAgentAssignedUpdate(context.Background(), &OrderRecord{
+		OrderID:       "order-123",
+		TransactionID: "txn-123",
+		BapURI:        "https://buyer.example.com",
+		RiderID:       "rider-1",
+	}, "trace-1")
+
+	assert.NoError(t, err)
+	fulfillment := payload.Message["order"].(map[string]interface{})["fulfillments"].([]map[string]interface{})[0]
+	assert.Equal(t, map[string]interface{}{"id": "rider-1"}, fulfillment["agent"])
+	assert.NotContains(t, fulfillment, "vehicle")
+}
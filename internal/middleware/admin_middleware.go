@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"uois-gateway/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AdminTokenMiddleware protects operator endpoints with a static bearer token (ADMIN_API_TOKEN)
+// An empty token rejects every request, so admin endpoints are off unless explicitly configured
+func AdminTokenMiddleware(token string, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			respondError(c, logger, http.StatusNotFound, errors.NewDomainError(65006, "admin endpoints disabled", "ADMIN_API_TOKEN not configured"))
+			c.Abort()
+			return
+		}
+
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			respondError(c, logger, http.StatusUnauthorized, errors.NewDomainError(65002, "unauthorized", "invalid admin token"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
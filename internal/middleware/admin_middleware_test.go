@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestAdminTokenMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		token          string
+		authorization  string
+		expectedStatus int
+	}{
+		{
+			name:           "valid token",
+			token:          "secret",
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "wrong token",
+			token:          "secret",
+			authorization:  "Bearer other",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing header",
+			token:          "secret",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "admin endpoints disabled",
+			token:          "",
+			authorization:  "Bearer ",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AdminTokenMiddleware(tt.token, zap.NewNop()))
+			router.GET("/admin/callbacks/dlq", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/callbacks/dlq", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
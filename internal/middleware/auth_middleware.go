@@ -41,18 +41,8 @@ func AuthMiddleware(authService AuthService, rateLimitService RateLimitService,
 
 func AuthMiddlewareWithConfig(authService AuthService, rateLimitService RateLimitService, logger *zap.Logger, config AuthMiddlewareConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientID, clientSecret, ok := extractCredentials(c)
+		client, clientID, ok := authenticateCredentials(c, authService, logger, config)
 		if !ok {
-			respondError(c, logger, http.StatusUnauthorized, errors.NewDomainError(65002, "authentication failed", "missing credentials"))
-			c.Abort()
-			return
-		}
-
-		clientIP := extractClientIP(c, config.TrustedProxyChecker)
-		client, err := authService.AuthenticateClient(c.Request.Context(), clientID, clientSecret, clientIP)
-		if err != nil {
-			httpStatus := errors.GetHTTPStatus(err)
-			respondError(c, logger, httpStatus, err)
 			c.Abort()
 			return
 		}
@@ -79,6 +69,31 @@ func AuthMiddlewareWithConfig(authService AuthService, rateLimitService RateLimi
 	}
 }
 
+// authenticateCredentials authenticates Basic/Bearer credentials
+// Requests already authenticated by ONDCSignatureMiddleware reuse the signed client
+func authenticateCredentials(c *gin.Context, authService AuthService, logger *zap.Logger, config AuthMiddlewareConfig) (*models.Client, string, bool) {
+	if signed, _ := c.Get(ONDCSignedContextKey); signed == true {
+		if client := GetClientFromContext(c); client != nil {
+			return client, client.ID, true
+		}
+	}
+
+	clientID, clientSecret, ok := extractCredentials(c)
+	if !ok {
+		respondError(c, logger, http.StatusUnauthorized, errors.NewDomainError(65002, "authentication failed", "missing credentials"))
+		return nil, "", false
+	}
+
+	clientIP := extractClientIP(c, config.TrustedProxyChecker)
+	client, err := authService.AuthenticateClient(c.Request.Context(), clientID, clientSecret, clientIP)
+	if err != nil {
+		respondError(c, logger, errors.GetHTTPStatus(err), err)
+		return nil, "", false
+	}
+
+	return client, clientID, true
+}
+
 func extractCredentials(c *gin.Context) (clientID, clientSecret string, ok bool) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CallbackBindingMiddleware binds context.bap_id/bap_uri to the authenticated client
+// Handlers POST signed callbacks to context.bap_uri, so an unchecked bap_uri would let any client
+// point the gateway at arbitrary (including internal) hosts:
+//   - A registered bap_id must equal context.bap_id
+//   - A registered bap_uri must equal context.bap_uri (ignoring a trailing slash)
+//   - Without a registered bap_uri, context.bap_uri must start with an allowlisted prefix
+//
+// Must run after the auth middlewares so the client is in context.
+func CallbackBindingMiddleware(allowedURIPrefixes []string, signer ResponseSigner, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := GetClientFromContext(c)
+		if client == nil {
+			respondSignedNACK(c, signer, logger, nil, errors.NewDomainError(65002, "authentication failed", "no authenticated client"))
+			return
+		}
+
+		rawBody := GetRawBodyFromContext(c)
+		if rawBody == nil {
+			body, err := captureRawBody(c)
+			if err != nil {
+				respondSignedNACK(c, signer, logger, nil, errors.WrapDomainError(err, 65001, "invalid request", "failed to read request body"))
+				return
+			}
+			rawBody = body
+		}
+
+		var req models.ONDCRequest
+		if err := json.Unmarshal(rawBody, &req); err != nil {
+			// Malformed bodies are rejected by the handlers with their own validation errors
+			c.Next()
+			return
+		}
+
+		if err := checkCallbackBinding(client, req.Context, allowedURIPrefixes); err != nil {
+			logger.Warn("callback target does not match client registration",
+				zap.String("client_id", client.ID),
+				zap.String("bap_id", req.Context.BapID),
+				zap.String("bap_uri", req.Context.BapURI),
+			)
+			respondSignedNACK(c, signer, logger, rawBody, err)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// checkCallbackBinding validates the request's callback target against the client registration
+func checkCallbackBinding(client *models.Client, ondcCtx models.ONDCContext, allowedURIPrefixes []string) error {
+	if registered := client.BapID(); registered != "" && ondcCtx.BapID != registered {
+		return errors.NewDomainError(65002, "bap_id does not match authenticated client", ondcCtx.BapID)
+	}
+
+	parsed, err := url.Parse(ondcCtx.BapURI)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" || parsed.User != nil {
+		return errors.NewDomainError(65001, "invalid context", "bap_uri must be an absolute http(s) URL")
+	}
+
+	if registered := client.BapURI(); registered != "" {
+		if strings.TrimSuffix(ondcCtx.BapURI, "/") != strings.TrimSuffix(registered, "/") {
+			return errors.NewDomainError(65002, "bap_uri does not match authenticated client", ondcCtx.BapURI)
+		}
+		return nil
+	}
+
+	for _, prefix := range allowedURIPrefixes {
+		if hasURIPrefix(ondcCtx.BapURI, prefix) {
+			return nil
+		}
+	}
+	return errors.NewDomainError(65002, "bap_uri not allowed", "client has no registered bap_uri and bap_uri matches no allowed prefix")
+}
+
+// hasURIPrefix matches whole path segments, so "https://bap.example.com" does not allow
+// "https://bap.example.com.attacker.net"
+func hasURIPrefix(uri, prefix string) bool {
+	if prefix == "" || !strings.HasPrefix(uri, prefix) {
+		return false
+	}
+	if strings.HasSuffix(prefix, "/") || len(uri) == len(prefix) {
+		return true
+	}
+	return uri[len(prefix)] == '/'
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"uois-gateway/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func newCallbackBindingTestRouter(client *models.Client, allowedPrefixes []string, signer *MockResponseSigner) (*gin.Engine, *[]byte) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if client != nil {
+			c.Set(ClientContextKey, client)
+		}
+		c.Next()
+	})
+	router.Use(CallbackBindingMiddleware(allowedPrefixes, signer, zap.NewNop()))
+
+	var boundBody []byte
+	router.POST("/ondc/search", func(c *gin.Context) {
+		boundBody, _ = io.ReadAll(c.Request.Body)
+		c.Status(http.StatusOK)
+	})
+	return router, &boundBody
+}
+
+func callbackBindingBody(bapID, bapURI string) string {
+	return fmt.Sprintf(`{"context":{"domain":"nic2004:60232","action":"search","bap_id":%q,"bap_uri":%q,"transaction_id":"txn-1","message_id":"msg-1"},"message":{}}`, bapID, bapURI)
+}
+
+func serveCallbackBinding(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/ondc/search", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func assertNACKCode(t *testing.T, w *httptest.ResponseRecorder, code int) {
+	var resp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, fmt.Sprint(code), resp.Error.Code)
+}
+
+func registeredClient() *models.Client {
+	return &models.Client{
+		ID:     "client-1",
+		Status: models.ClientStatusActive,
+		Metadata: map[string]interface{}{
+			"bap_id":  "buyer.com",
+			"bap_uri": "https://buyer.com/ondc/",
+		},
+	}
+}
+
+func TestCallbackBindingMiddleware_RegisteredClientMatches(t *testing.T) {
+	router, boundBody := newCallbackBindingTestRouter(registeredClient(), nil, new(MockResponseSigner))
+	body := callbackBindingBody("buyer.com", "https://buyer.com/ondc")
+
+	w := serveCallbackBinding(router, body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, string(*boundBody), "handler must still be able to read the body")
+}
+
+func TestCallbackBindingMiddleware_BapIDMismatch(t *testing.T) {
+	signer := new(MockResponseSigner)
+	signer.On("SignResponse", mock.Anything).Return("Signature keyId=\"seller\"", nil)
+	router, _ := newCallbackBindingTestRouter(registeredClient(), nil, signer)
+
+	w := serveCallbackBinding(router, callbackBindingBody("other.com", "https://buyer.com/ondc"))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assertNACKCode(t, w, 65002)
+	assert.NotEmpty(t, w.Header().Get("Authorization"))
+}
+
+func TestCallbackBindingMiddleware_BapURIMismatch(t *testing.T) {
+	signer := new(MockResponseSigner)
+	signer.On("SignResponse", mock.Anything).Return("sig", nil)
+	router, _ := newCallbackBindingTestRouter(registeredClient(), []string{"http://10.0.0.5"}, signer)
+
+	w := serveCallbackBinding(router, callbackBindingBody("buyer.com", "http://10.0.0.5/internal"))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assertNACKCode(t, w, 65002)
+}
+
+func TestCallbackBindingMiddleware_AllowlistPrefix(t *testing.T) {
+	signer := new(MockResponseSigner)
+	signer.On("SignResponse", mock.Anything).Return("sig", nil)
+	client := &models.Client{ID: "client-2", Status: models.ClientStatusActive}
+	router, _ := newCallbackBindingTestRouter(client, []string{"https://bap.example.com"}, signer)
+
+	tests := []struct {
+		name   string
+		bapURI string
+		status int
+	}{
+		{"exact prefix", "https://bap.example.com", http.StatusOK},
+		{"path under prefix", "https://bap.example.com/ondc", http.StatusOK},
+		{"host suffix", "https://bap.example.com.attacker.net/ondc", http.StatusUnauthorized},
+		{"unlisted host", "https://attacker.net/ondc", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveCallbackBinding(router, callbackBindingBody("any.bap", tt.bapURI))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestCallbackBindingMiddleware_InvalidScheme(t *testing.T) {
+	signer := new(MockResponseSigner)
+	signer.On("SignResponse", mock.Anything).Return("sig", nil)
+	client := &models.Client{ID: "client-2", Status: models.ClientStatusActive}
+	router, _ := newCallbackBindingTestRouter(client, []string{"file://"}, signer)
+
+	w := serveCallbackBinding(router, callbackBindingBody("any.bap", "file:///etc/passwd"))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertNACKCode(t, w, 65001)
+}
+
+func TestCallbackBindingMiddleware_NoClient(t *testing.T) {
+	signer := new(MockResponseSigner)
+	signer.On("SignResponse", mock.Anything).Return("sig", nil)
+	router, _ := newCallbackBindingTestRouter(nil, nil, signer)
+
+	w := serveCallbackBinding(router, callbackBindingBody("buyer.com", "https://buyer.com/ondc"))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
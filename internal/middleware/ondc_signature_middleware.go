@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// RawBodyContextKey holds the exact request bytes as received (used for signature verification)
+	RawBodyContextKey = "raw_body"
+	// ONDCSignedContextKey is set to true when the request was authenticated by ONDC signature
+	ONDCSignedContextKey = "ondc_signed"
+	// GatewayAuthorizationHeader carries the ONDC gateway signature for requests relayed by the gateway
+	GatewayAuthorizationHeader = "X-Gateway-Authorization"
+
+	signatureAuthScheme = "Signature "
+	// maxRequestBodyBytes caps the request body buffered for signature verification
+	maxRequestBodyBytes = 1 << 20
+)
+
+// ONDCSignatureVerifier verifies ONDC HTTP signature headers
+type ONDCSignatureVerifier interface {
+	VerifyRequestSignature(ctx context.Context, authHeader string, payload []byte) error
+	VerifySignatureTimestamps(authHeader string) error
+	ExtractSubscriberID(authHeader string) (string, error)
+}
+
+// ResponseSigner signs outgoing response bytes
+type ResponseSigner interface {
+	SignResponse(payload []byte) (string, error)
+}
+
+// SubscriberAuthService resolves a verified ONDC subscriber to a registered client
+type SubscriberAuthService interface {
+	AuthenticateSubscriber(ctx context.Context, subscriberID, clientIP string) (*models.Client, error)
+}
+
+// ONDCSignatureMiddleware authenticates ONDC-signed requests
+// Requests with a "Signature" Authorization header are verified against the registry
+// (plus X-Gateway-Authorization when relayed by the ONDC gateway) and resolved to a client
+// enrolled for ONDC signature auth. Other requests pass through to AuthMiddleware.
+// Must run before AuthMiddleware so the signed client is already set in context.
+func ONDCSignatureMiddleware(verifier ONDCSignatureVerifier, signer ResponseSigner, subscriberAuth SubscriberAuthService, logger *zap.Logger, config AuthMiddlewareConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawBody, err := captureRawBody(c)
+		if err != nil {
+			detail := "failed to read request body"
+			var tooLarge *http.MaxBytesError
+			if stderrors.As(err, &tooLarge) {
+				detail = fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)
+			}
+			respondSignedNACK(c, signer, logger, nil, errors.WrapDomainError(err, 65001, "invalid request", detail))
+			return
+		}
+
+		authHeader := c.GetHeader("Authorization")
+		if !strings.HasPrefix(authHeader, signatureAuthScheme) {
+			c.Next()
+			return
+		}
+
+		client, err := authenticateSignedRequest(c, verifier, subscriberAuth, config, authHeader, rawBody)
+		if err != nil {
+			respondSignedNACK(c, signer, logger, rawBody, err)
+			return
+		}
+
+		c.Set(ClientContextKey, client)
+		c.Set(ONDCSignedContextKey, true)
+		c.Next()
+	}
+}
+
+// authenticateSignedRequest verifies request (and gateway) signatures and resolves the client
+func authenticateSignedRequest(c *gin.Context, verifier ONDCSignatureVerifier, subscriberAuth SubscriberAuthService, config AuthMiddlewareConfig, authHeader string, rawBody []byte) (*models.Client, error) {
+	ctx := c.Request.Context()
+
+	if err := verifySignatureHeader(ctx, verifier, authHeader, rawBody); err != nil {
+		return nil, err
+	}
+
+	if gatewayHeader := c.GetHeader(GatewayAuthorizationHeader); gatewayHeader != "" {
+		if err := verifySignatureHeader(ctx, verifier, gatewayHeader, rawBody); err != nil {
+			return nil, err
+		}
+	}
+
+	subscriberID, err := verifier.ExtractSubscriberID(authHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriberAuth.AuthenticateSubscriber(ctx, subscriberID, extractClientIP(c, config.TrustedProxyChecker))
+}
+
+func verifySignatureHeader(ctx context.Context, verifier ONDCSignatureVerifier, header string, rawBody []byte) error {
+	if err := verifier.VerifySignatureTimestamps(header); err != nil {
+		return err
+	}
+	return verifier.VerifyRequestSignature(ctx, header, rawBody)
+}
+
+// captureRawBody reads the request body (up to maxRequestBodyBytes), stores the exact bytes in context
+// and restores the body so handlers can still bind JSON
+func captureRawBody(c *gin.Context) ([]byte, error) {
+	if c.Request.Body == nil {
+		return nil, nil
+	}
+
+	rawBody, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxRequestBodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(rawBody))
+	c.Set(RawBodyContextKey, rawBody)
+
+	return rawBody, nil
+}
+
+// respondSignedNACK writes a signed ONDC NACK and aborts the request
+func respondSignedNACK(c *gin.Context, signer ResponseSigner, logger *zap.Logger, rawBody []byte, err error) {
+	domainErr, ok := err.(*errors.DomainError)
+	if !ok {
+		domainErr = errors.WrapDomainError(err, 65002, "authentication failed", "signature verification failed")
+	}
+
+	logger.Warn("request rejected by ONDC signature middleware",
+		zap.Int("error_code", domainErr.Code),
+		zap.Error(domainErr),
+	)
+
+	body, marshalErr := json.Marshal(buildNACKResponse(rawBody, domainErr))
+	if marshalErr != nil {
+		c.AbortWithStatus(errors.GetHTTPStatus(domainErr))
+		return
+	}
+
+	WriteSignedBytes(c, signer, logger, errors.GetHTTPStatus(domainErr), body)
+	c.Abort()
+}
+
+// buildNACKResponse builds an ONDC NACK echoing the request context when it can be parsed
+func buildNACKResponse(rawBody []byte, err *errors.DomainError) models.ONDCResponse {
+	var req models.ONDCRequest
+	_ = json.Unmarshal(rawBody, &req)
+
+	return models.ONDCResponse{
+		Context: req.Context,
+		Message: map[string]interface{}{
+			"ack": map[string]interface{}{"status": "NACK"},
+		},
+		Error: &models.ONDCError{
+			Type:    "CONTEXT_ERROR",
+			Code:    fmt.Sprintf("%d", err.Code),
+			Message: map[string]string{"en": err.Message},
+		},
+	}
+}
+
+// GetRawBodyFromContext returns the exact request bytes captured by ONDCSignatureMiddleware
+func GetRawBodyFromContext(c *gin.Context) []byte {
+	rawBody, exists := c.Get(RawBodyContextKey)
+	if !exists {
+		return nil
+	}
+
+	body, ok := rawBody.([]byte)
+	if !ok {
+		return nil
+	}
+
+	return body
+}
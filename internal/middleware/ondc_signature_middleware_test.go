@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/models"
+	domainerrors "uois-gateway/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockSignatureVerifier struct {
+	mock.Mock
+}
+
+func (m *MockSignatureVerifier) VerifyRequestSignature(ctx context.Context, authHeader string, payload []byte) error {
+	args := m.Called(ctx, authHeader, payload)
+	return args.Error(0)
+}
+
+func (m *MockSignatureVerifier) VerifySignatureTimestamps(authHeader string) error {
+	args := m.Called(authHeader)
+	return args.Error(0)
+}
+
+func (m *MockSignatureVerifier) ExtractSubscriberID(authHeader string) (string, error) {
+	args := m.Called(authHeader)
+	return args.String(0), args.Error(1)
+}
+
+type MockResponseSigner struct {
+	mock.Mock
+}
+
+func (m *MockResponseSigner) SignResponse(payload []byte) (string, error) {
+	args := m.Called(payload)
+	return args.String(0), args.Error(1)
+}
+
+type MockSubscriberAuthService struct {
+	mock.Mock
+}
+
+func (m *MockSubscriberAuthService) AuthenticateSubscriber(ctx context.Context, subscriberID, clientIP string) (*models.Client, error) {
+	args := m.Called(ctx, subscriberID, clientIP)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Client), args.Error(1)
+}
+
+const testSignedBody = `{"context":{"domain":"nic2004:60232","action":"search","transaction_id":"txn-1","message_id":"msg-1"},"message":{}}`
+
+const testSignatureHeader = `Signature keyId="buyer.com|UK1|ed25519",algorithm="ed25519",created="1",expires="2",headers="(created) (expires) digest",signature="sig"`
+
+func newSignedTestRouter(verifier *MockSignatureVerifier, signer *MockResponseSigner, subscriberAuth *MockSubscriberAuthService) (*gin.Engine, *[]byte) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ONDCSignatureMiddleware(verifier, signer, subscriberAuth, zap.NewNop(), AuthMiddlewareConfig{}))
+
+	var boundBody []byte
+	router.POST("/ondc/search", func(c *gin.Context) {
+		boundBody, _ = io.ReadAll(c.Request.Body)
+		client := GetClientFromContext(c)
+		if client != nil {
+			c.Header("X-Client-ID", client.ID)
+		}
+		c.Status(http.StatusOK)
+	})
+	return router, &boundBody
+}
+
+func TestONDCSignatureMiddleware_ValidSignature(t *testing.T) {
+	verifier := new(MockSignatureVerifier)
+	signer := new(MockResponseSigner)
+	subscriberAuth := new(MockSubscriberAuthService)
+	router, boundBody := newSignedTestRouter(verifier, signer, subscriberAuth)
+
+	client := &models.Client{ID: "client-1", Status: models.ClientStatusActive}
+	verifier.On("VerifySignatureTimestamps", testSignatureHeader).Return(nil)
+	verifier.On("VerifyRequestSignature", mock.Anything, testSignatureHeader, []byte(testSignedBody)).Return(nil)
+	verifier.On("ExtractSubscriberID", testSignatureHeader).Return("buyer.com", nil)
+	subscriberAuth.On("AuthenticateSubscriber", mock.Anything, "buyer.com", "192.168.1.1").Return(client, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/ondc/search", bytes.NewBufferString(testSignedBody))
+	req.Header.Set("Authorization", testSignatureHeader)
+	req.RemoteAddr = "192.168.1.1:1234"
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "client-1", w.Header().Get("X-Client-ID"))
+	assert.Equal(t, testSignedBody, string(*boundBody), "handler must still be able to read the body")
+	verifier.AssertExpectations(t)
+	subscriberAuth.AssertExpectations(t)
+}
+
+func TestONDCSignatureMiddleware_CredentialRequestPassesThrough(t *testing.T) {
+	verifier := new(MockSignatureVerifier)
+	signer := new(MockResponseSigner)
+	subscriberAuth := new(MockSubscriberAuthService)
+	router, boundBody := newSignedTestRouter(verifier, signer, subscriberAuth)
+
+	req := httptest.NewRequest(http.MethodPost, "/ondc/search", bytes.NewBufferString(testSignedBody))
+	req.SetBasicAuth("client-1", "secret")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-Client-ID"))
+	assert.Equal(t, testSignedBody, string(*boundBody))
+	verifier.AssertNotCalled(t, "VerifyRequestSignature", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestONDCSignatureMiddleware_InvalidSignature_SignedNACK(t *testing.T) {
+	verifier := new(MockSignatureVerifier)
+	signer := new(MockResponseSigner)
+	subscriberAuth := new(MockSubscriberAuthService)
+	router, _ := newSignedTestRouter(verifier, signer, subscriberAuth)
+
+	verifier.On("VerifySignatureTimestamps", testSignatureHeader).Return(nil)
+	verifier.On("VerifyRequestSignature", mock.Anything, testSignatureHeader, mock.Anything).
+		Return(domainerrors.NewDomainError(65002, "authentication failed", "signature verification failed"))
+	signer.On("SignResponse", mock.Anything).Return(`Signature keyId="seller.com|SELLER_UK|ed25519",signature="nack-sig"`, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/ondc/search", bytes.NewBufferString(testSignedBody))
+	req.Header.Set("Authorization", testSignatureHeader)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("Authorization"), "nack-sig")
+
+	var response models.ONDCResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "65002", response.Error.Code)
+	assert.Equal(t, "txn-1", response.Context.TransactionID)
+	assert.Equal(t, map[string]interface{}{"status": "NACK"}, response.Message["ack"])
+
+	signedBytes := signer.Calls[0].Arguments.Get(0).([]byte)
+	assert.Equal(t, w.Body.Bytes(), signedBytes, "signature must cover the exact response bytes")
+	subscriberAuth.AssertNotCalled(t, "AuthenticateSubscriber", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestONDCSignatureMiddleware_OversizedBodyRejected(t *testing.T) {
+	verifier := new(MockSignatureVerifier)
+	signer := new(MockResponseSigner)
+	subscriberAuth := new(MockSubscriberAuthService)
+	router, _ := newSignedTestRouter(verifier, signer, subscriberAuth)
+
+	signer.On("SignResponse", mock.Anything).Return(`Signature keyId="seller.com|SELLER_UK|ed25519",signature="nack-sig"`, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/ondc/search", bytes.NewReader(bytes.Repeat([]byte("a"), maxRequestBodyBytes+1)))
+	req.Header.Set("Authorization", testSignatureHeader)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response models.ONDCResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "65001", response.Error.Code)
+	verifier.AssertNotCalled(t, "VerifyRequestSignature", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestONDCSignatureMiddleware_StaleSignature(t *testing.T) {
+	verifier := new(MockSignatureVerifier)
+	signer := new(MockResponseSigner)
+	subscriberAuth := new(MockSubscriberAuthService)
+	router, _ := newSignedTestRouter(verifier, signer, subscriberAuth)
+
+	verifier.On("VerifySignatureTimestamps", testSignatureHeader).
+		Return(domainerrors.NewDomainError(65002, "authentication failed", "signature expired"))
+	signer.On("SignResponse", mock.Anything).Return("sig", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/ondc/search", bytes.NewBufferString(testSignedBody))
+	req.Header.Set("Authorization", testSignatureHeader)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	verifier.AssertNotCalled(t, "VerifyRequestSignature", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestONDCSignatureMiddleware_GatewaySignatureVerified(t *testing.T) {
+	verifier := new(MockSignatureVerifier)
+	signer := new(MockResponseSigner)
+	subscriberAuth := new(MockSubscriberAuthService)
+	router, _ := newSignedTestRouter(verifier, signer, subscriberAuth)
+
+	gatewayHeader := `Signature keyId="gateway.ondc.org|GW1|ed25519",created="1",expires="2",signature="gw-sig"`
+	verifier.On("VerifySignatureTimestamps", testSignatureHeader).Return(nil)
+	verifier.On("VerifyRequestSignature", mock.Anything, testSignatureHeader, mock.Anything).Return(nil)
+	verifier.On("VerifySignatureTimestamps", gatewayHeader).Return(nil)
+	verifier.On("VerifyRequestSignature", mock.Anything, gatewayHeader, mock.Anything).
+		Return(domainerrors.NewDomainError(65002, "authentication failed", "signature verification failed"))
+	signer.On("SignResponse", mock.Anything).Return("sig", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/ondc/search", bytes.NewBufferString(testSignedBody))
+	req.Header.Set("Authorization", testSignatureHeader)
+	req.Header.Set(GatewayAuthorizationHeader, gatewayHeader)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	verifier.AssertExpectations(t)
+}
+
+func TestONDCSignatureMiddleware_SubscriberNotEnrolled(t *testing.T) {
+	verifier := new(MockSignatureVerifier)
+	signer := new(MockResponseSigner)
+	subscriberAuth := new(MockSubscriberAuthService)
+	router, _ := newSignedTestRouter(verifier, signer, subscriberAuth)
+
+	verifier.On("VerifySignatureTimestamps", testSignatureHeader).Return(nil)
+	verifier.On("VerifyRequestSignature", mock.Anything, testSignatureHeader, mock.Anything).Return(nil)
+	verifier.On("ExtractSubscriberID", testSignatureHeader).Return("buyer.com", nil)
+	subscriberAuth.On("AuthenticateSubscriber", mock.Anything, "buyer.com", mock.Anything).
+		Return(nil, domainerrors.NewDomainError(65002, "authentication failed", "subscriber not allowed"))
+	signer.On("SignResponse", mock.Anything).Return("sig", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/ondc/search", bytes.NewBufferString(testSignedBody))
+	req.Header.Set("Authorization", testSignatureHeader)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthMiddleware_SkipsCredentialsForSignedClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockAuth := new(MockAuthService)
+	mockRateLimit := new(MockRateLimitService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/test", nil)
+	c.Request.Header.Set("Authorization", testSignatureHeader)
+	c.Set(ClientContextKey, &models.Client{ID: "client-1", Status: models.ClientStatusActive})
+	c.Set(ONDCSignedContextKey, true)
+
+	mockRateLimit.On("CheckRateLimit", mock.Anything, "client-1").Return(true, int64(59), time.Now().Add(time.Minute), nil)
+
+	AuthMiddleware(mockAuth, mockRateLimit, zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, c.IsAborted())
+	mockAuth.AssertNotCalled(t, "AuthenticateClient", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRateLimit.AssertExpectations(t)
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WriteSignedJSON marshals response once and writes it with an ONDC signature over those bytes
+func WriteSignedJSON(c *gin.Context, signer ResponseSigner, logger *zap.Logger, status int, response interface{}) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		logger.Error("failed to marshal response", zap.Error(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	WriteSignedBytes(c, signer, logger, status, body)
+}
+
+// WriteSignedBytes writes body verbatim with an Authorization header signing the exact bytes
+// Used for idempotent replays so the buyer can verify the stored response as originally sent
+func WriteSignedBytes(c *gin.Context, signer ResponseSigner, logger *zap.Logger, status int, body []byte) {
+	if signer != nil {
+		authHeader, err := signer.SignResponse(body)
+		if err != nil {
+			logger.Error("failed to sign response", zap.Error(err))
+		} else {
+			c.Header("Authorization", authHeader)
+		}
+	}
+
+	c.Data(status, "application/json; charset=utf-8", body)
+}
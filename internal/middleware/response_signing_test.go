@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestWriteSignedBytes_SignsExactBytes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	body := []byte(`{"message":{"ack":{"status":"ACK"}}}`)
+	signer := new(MockResponseSigner)
+	signer.On("SignResponse", body).Return(`Signature keyId="bpp|UK1|ed25519",signature="sig"`, nil)
+
+	WriteSignedBytes(c, signer, zap.NewNop(), http.StatusOK, body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.Bytes())
+	assert.Equal(t, `Signature keyId="bpp|UK1|ed25519",signature="sig"`, w.Header().Get("Authorization"))
+	signer.AssertExpectations(t)
+}
+
+func TestWriteSignedBytes_NilSigner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	WriteSignedBytes(c, nil, zap.NewNop(), http.StatusOK, []byte(`{}`))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Authorization"))
+}
+
+func TestWriteSignedBytes_SignerErrorStillResponds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	signer := new(MockResponseSigner)
+	signer.On("SignResponse", mock.Anything).Return("", errors.New("key not loaded"))
+
+	WriteSignedBytes(c, signer, zap.NewNop(), http.StatusBadRequest, []byte(`{"error":{}}`))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `{"error":{}}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("Authorization"))
+}
+
+func TestWriteSignedJSON_SignsMarshaledBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	signer := new(MockResponseSigner)
+	signer.On("SignResponse", []byte(`{"status":"NACK"}`)).Return("sig", nil)
+
+	WriteSignedJSON(c, signer, zap.NewNop(), http.StatusUnauthorized, map[string]string{"status": "NACK"})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `{"status":"NACK"}`, w.Body.String())
+	assert.Equal(t, "sig", w.Header().Get("Authorization"))
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// ONDC fulfillment authorization types (fulfillments[].start/end.authorization.type)
+const (
+	AuthorizationTypeOTP = "OTP"
+)
+
+// Fulfillment authorization stages
+const (
+	AuthorizationStagePickup   = "pickup"   // start.authorization, pickup confirmation code (PCC)
+	AuthorizationStageDelivery = "delivery" // end.authorization, delivery confirmation code (DCC)
+)
+
+// Fulfillment authorization statuses (authorization.status in /on_update and /on_status)
+const (
+	AuthorizationStatusPending  = "PENDING"  // Code is valid and not yet used by the rider
+	AuthorizationStatusVerified = "VERIFIED" // Rider collected the code (order picked up / delivered)
+	AuthorizationStatusExpired  = "EXPIRED"  // valid_to passed before the code was used
+)
+
+// FulfillmentAuthorization is a PCC or DCC sent by the buyer app in /update
+// The rider must collect the token from the pickup or drop contact within [ValidFrom, ValidTo]
+type FulfillmentAuthorization struct {
+	Stage     string // pickup or delivery
+	Type      string // OTP
+	Token     string
+	ValidFrom time.Time
+	ValidTo   time.Time
+}
+
+// StatusAt returns the authorization status at now
+// verified is true once the order has passed the authorization's stage
+func (a *FulfillmentAuthorization) StatusAt(now time.Time, verified bool) string {
+	if verified {
+		return AuthorizationStatusVerified
+	}
+	if !a.ValidTo.IsZero() && now.After(a.ValidTo) {
+		return AuthorizationStatusExpired
+	}
+	return AuthorizationStatusPending
+}
@@ -0,0 +1,26 @@
+package models
+
+// Cancellation initiators (who may use a cancellation reason code)
+const (
+	CancellationInitiatorBuyer  = "buyer"  // Logistics buyer NP, through /cancel
+	CancellationInitiatorSeller = "seller" // LSP (this BPP), through unsolicited /on_cancel
+)
+
+// CancellationReason is an entry of the ONDC logistics cancellation reason code catalog
+type CancellationReason struct {
+	Code        string `json:"code"` // e.g. "011"
+	Description string `json:"description"`
+	InitiatedBy string `json:"initiated_by"` // buyer or seller
+	// TriggersRTO: once the order is picked up, cancelling with this reason returns the package
+	// to origin (RTO) instead of cancelling outright
+	TriggersRTO bool `json:"triggers_rto,omitempty"`
+}
+
+// CancellationTerm prices a cancellation by pre-cancel fulfillment state and reason code (ONDC cancellation_terms)
+// When both FeePercentage and FeeAmount are set, the lower fee applies
+type CancellationTerm struct {
+	FulfillmentState string   `json:"fulfillment_state"`        // ONDC fulfillment state code, "*" for all states
+	ReasonCodes      []string `json:"reason_codes"`             // Reason codes, "*" for all codes
+	FeePercentage    string   `json:"fee_percentage,omitempty"` // Of order value minus taxes, "0.00" to "100.00"
+	FeeAmount        string   `json:"fee_amount,omitempty"`     // Flat fee in the quote currency
+}
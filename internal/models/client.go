@@ -11,6 +11,12 @@ const (
 	ClientStatusRevoked   = "REVOKED"
 )
 
+// Client authentication modes (stored in Metadata["auth_mode"])
+const (
+	ClientAuthModeCredentials   = "CREDENTIALS"    // Internal clients using Basic/Bearer credentials
+	ClientAuthModeONDCSignature = "ONDC_SIGNATURE" // ONDC Buyer NPs signing requests with registry keys
+)
+
 // CIDRLogger is an optional interface for logging invalid CIDRs during client sync/load.
 // If provided, invalid CIDRs will be logged during normalization instead of at runtime.
 type CIDRLogger interface {
@@ -31,6 +37,33 @@ func (c *Client) IsActive() bool {
 	return c.Status == ClientStatusActive
 }
 
+// AuthMode returns the client's authentication mode
+// Defaults to credentials when not configured
+func (c *Client) AuthMode() string {
+	if mode, ok := c.Metadata["auth_mode"].(string); ok && mode == ClientAuthModeONDCSignature {
+		return ClientAuthModeONDCSignature
+	}
+	return ClientAuthModeCredentials
+}
+
+// BapID returns the registered ONDC bap_id of the client
+func (c *Client) BapID() string {
+	bapID, _ := c.Metadata["bap_id"].(string)
+	return bapID
+}
+
+// BapURI returns the registered ONDC bap_uri (callback base URL) of the client
+func (c *Client) BapURI() string {
+	bapURI, _ := c.Metadata["bap_uri"].(string)
+	return bapURI
+}
+
+// CODEnabled reports whether the client has opted in to cash on delivery (ON-FULFILLMENT) orders
+func (c *Client) CODEnabled() bool {
+	enabled, _ := c.Metadata["cod_enabled"].(bool)
+	return enabled
+}
+
 // NormalizeIPs parses and validates AllowedIPs at load time, populating NormalizedIPs.
 // Invalid CIDRs are skipped and optionally logged via the provided logger.
 // This should be called when loading clients from DB/admin API to avoid repeated parsing on hot path.
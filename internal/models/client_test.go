@@ -29,6 +29,38 @@ func TestClient_IsActive(t *testing.T) {
 	}
 }
 
+func TestClient_AuthMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		expected string
+	}{
+		{"Default", nil, ClientAuthModeCredentials},
+		{"Credentials", map[string]interface{}{"auth_mode": ClientAuthModeCredentials}, ClientAuthModeCredentials},
+		{"ONDCSignature", map[string]interface{}{"auth_mode": ClientAuthModeONDCSignature}, ClientAuthModeONDCSignature},
+		{"Unknown", map[string]interface{}{"auth_mode": "OTHER"}, ClientAuthModeCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{Metadata: tt.metadata}
+			assert.Equal(t, tt.expected, client.AuthMode())
+		})
+	}
+}
+
+func TestClient_BapURI(t *testing.T) {
+	assert.Equal(t, "", (&Client{}).BapURI())
+	client := &Client{Metadata: map[string]interface{}{"bap_id": "buyer.example.com", "bap_uri": "https://buyer.example.com/ondc"}}
+	assert.Equal(t, "https://buyer.example.com/ondc", client.BapURI())
+}
+
+func TestClient_CODEnabled(t *testing.T) {
+	assert.False(t, (&Client{}).CODEnabled())
+	assert.False(t, (&Client{Metadata: map[string]interface{}{"cod_enabled": "true"}}).CODEnabled())
+	assert.True(t, (&Client{Metadata: map[string]interface{}{"cod_enabled": true}}).CODEnabled())
+}
+
 func TestClient_ValidateIP(t *testing.T) {
 	client := &Client{
 		AllowedIPs: []string{"192.168.1.0/24", "10.0.0.1/32"},
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// ONDC payment types (message.order.payment.type)
+const (
+	PaymentTypeOnOrder         = "ON-ORDER"
+	PaymentTypeOnFulfillment   = "ON-FULFILLMENT" // Cash on delivery, collected by the LSP
+	PaymentTypePostFulfillment = "POST-FULFILLMENT"
+)
+
+// COD reconciliation statuses
+const (
+	CODStatusPending   = "PENDING"   // Order confirmed, cash not yet collected
+	CODStatusCollected = "COLLECTED" // Rider collected cash at delivery
+	CODStatusRemitted  = "REMITTED"  // Collected cash settled to the buyer app
+)
+
+// CODReconciliation tracks the cash the LSP collects for a COD order until it is remitted
+// One record per order, created when the order is confirmed
+type CODReconciliation struct {
+	ID               int64
+	OrderID          string // ONDC order.id
+	ClientID         string
+	DispatchOrderID  string
+	TransactionID    string
+	CollectionAmount string // @ondc/org/collection_amount agreed in /init
+	Currency         string
+	Status           string
+	SettlementRef    string // settlement_reference once remitted
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
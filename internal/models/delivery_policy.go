@@ -0,0 +1,30 @@
+package models
+
+// DeliveryCategoryRule allows an ONDC delivery category under the given conditions
+// Empty CityCodes, ClientIDs or Windows match any city, client or time of day.
+// A request is accepted when at least one enabled rule for its category matches.
+type DeliveryCategoryRule struct {
+	CategoryID string           `json:"category_id"`          // ONDC category id (e.g., "Same Day Delivery")
+	CityCodes  []string         `json:"city_codes,omitempty"` // ONDC city codes (e.g., "std:080")
+	ClientIDs  []string         `json:"client_ids,omitempty"`
+	MaxTAT     string           `json:"max_tat,omitempty"` // ISO 8601 duration upper bound for time.duration (e.g., "PT60M")
+	RequireTAT bool             `json:"require_tat,omitempty"`
+	Windows    []DeliveryWindow `json:"windows,omitempty"`
+	COD        bool             `json:"cod,omitempty"` // Cash on delivery (ON-FULFILLMENT) allowed for this category
+}
+
+// DeliveryWindow is a local time-of-day window ("HH:MM", end exclusive)
+// A window whose end is before its start runs past midnight (e.g., 22:00-02:00)
+type DeliveryWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// DeliveryPolicyRequest is what the delivery category policy is evaluated against
+type DeliveryPolicyRequest struct {
+	CategoryID string
+	TAT        string // ISO 8601 time.duration requested or quoted; empty when not known
+	CityCode   string
+	ClientID   string
+	COD        bool // Only categories that allow cash on delivery qualify (Categories)
+}
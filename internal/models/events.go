@@ -1,10 +1,95 @@
 package models
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
 
+// Event types carried in the event_type field
+const (
+	EventTypeSearchRequested    = "SEARCH_REQUESTED"
+	EventTypeInitRequested      = "INIT_REQUESTED"
+	EventTypeConfirmRequested   = "CONFIRM_REQUESTED"
+	EventTypeQuoteComputed      = "QUOTE_COMPUTED"
+	EventTypeQuoteCreated       = "QUOTE_CREATED"
+	EventTypeQuoteInvalidated   = "QUOTE_INVALIDATED"
+	EventTypeQuoteError         = "QUOTE_ERROR"
+	EventTypeOrderConfirmed     = "ORDER_CONFIRMED"
+	EventTypeOrderConfirmFailed = "ORDER_CONFIRM_FAILED"
+	EventTypeOrderLifecycle     = "order.lifecycle"
+	EventTypeRiderAssigned      = "RIDER_ASSIGNED"
+	EventTypeRiderAssignFailed  = "RIDER_ASSIGNMENT_FAILED"
+	EventTypeQuoteDifferential  = "QUOTE_DIFFERENTIAL"
+)
+
+// Canonical order states carried in order.lifecycle events (Order Service is the source of truth)
+const (
+	OrderStateCreated                  = "CREATED"
+	OrderStateQuoteValidated           = "QUOTE_VALIDATED"
+	OrderStateQuoteInvalid             = "QUOTE_INVALID"
+	OrderStateConfirmationPending      = "CONFIRMATION_PENDING"
+	OrderStateConfirmed                = "CONFIRMED"
+	OrderStateAssigned                 = "ASSIGNED"
+	OrderStateSoftArrivedAtOrigin      = "SOFT_ARRIVED_AT_ORIGIN"
+	OrderStatePickedUp                 = "PICKED_UP"
+	OrderStateSoftArrivedAtDestination = "SOFT_ARRIVED_AT_DESTINATION"
+	OrderStateDelivered                = "DELIVERED"
+	OrderStateCancelled                = "CANCELLED"
+	OrderStateRTOInitiated             = "RTO_INITIATED"
+	OrderStateRTODelivered             = "RTO_DELIVERED"
+	OrderStateCompleted                = "COMPLETED"
+	OrderStateExpired                  = "EXPIRED"
+)
+
+// Event is implemented by every typed stream event
+type Event interface {
+	Validate() error
+}
+
+// ErrUnknownEventType is returned by DecodeEvent for an event_type with no registered struct
+var ErrUnknownEventType = errors.New("unknown event_type")
+
+// consumedEventTypes maps the event_type of each consumed event to the struct it decodes into
+var consumedEventTypes = map[string]func() Event{
+	EventTypeQuoteComputed:      func() Event { return &QuoteComputedEvent{} },
+	EventTypeQuoteCreated:       func() Event { return &QuoteCreatedEvent{} },
+	EventTypeQuoteInvalidated:   func() Event { return &QuoteInvalidatedEvent{} },
+	EventTypeQuoteError:         func() Event { return &QuoteErrorEvent{} },
+	EventTypeOrderConfirmed:     func() Event { return &OrderConfirmedEvent{} },
+	EventTypeOrderConfirmFailed: func() Event { return &OrderConfirmFailedEvent{} },
+	EventTypeOrderLifecycle:     func() Event { return &OrderLifecycleEvent{} },
+	EventTypeRiderAssigned:      func() Event { return &RiderAssignedEvent{} },
+	EventTypeRiderAssignFailed:  func() Event { return &RiderAssignmentFailedEvent{} },
+	EventTypeQuoteDifferential:  func() Event { return &QuoteDifferentialEvent{} },
+}
+
+// DecodeEvent decodes a consumed stream event into its typed struct (e.g. *QuoteComputedEvent)
+// and validates it. Unknown event types return an error wrapping ErrUnknownEventType.
+func DecodeEvent(data []byte) (Event, error) {
+	var base BaseEvent
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	newEvent, ok := consumedEventTypes[base.EventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEventType, base.EventType)
+	}
+
+	event := newEvent()
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s event: %w", base.EventType, err)
+	}
+
+	if err := event.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s event: %w", base.EventType, err)
+	}
+
+	return event, nil
+}
+
 // BaseEvent contains common fields for all events
 // ID Stack Compliance:
 // - event_id: Used only for event-level deduplication (NOT business logic)
@@ -91,6 +176,8 @@ type InitRequestedEvent struct {
 	DestinationLng     float64                `json:"destination_lng"` // Internal format
 	DestinationAddress map[string]interface{} `json:"destination_address,omitempty"`
 	PackageInfo        map[string]interface{} `json:"package_info,omitempty"`
+	PaymentType        string                 `json:"payment_type,omitempty"`      // ONDC payment.type (ON-FULFILLMENT for COD)
+	CollectionAmount   string                 `json:"collection_amount,omitempty"` // COD amount the rider collects at delivery
 }
 
 // Validate validates InitRequestedEvent
@@ -114,10 +201,12 @@ func (e *InitRequestedEvent) Validate() error {
 // client_id is extracted from auth context (tenant boundary, NOT business correlation)
 type ConfirmRequestedEvent struct {
 	BaseEvent
-	QuoteID       string                 `json:"quote_id"`        // Business correlation ID (NOT WebSocket correlation_id)
-	ClientID      string                 `json:"client_id"`       // Extracted from auth (tenant boundary)
-	ClientOrderID string                 `json:"client_order_id"` // ONDC order ID from message.order.id
-	PaymentInfo   map[string]interface{} `json:"payment_info,omitempty"`
+	QuoteID          string                 `json:"quote_id"`        // Business correlation ID (NOT WebSocket correlation_id)
+	ClientID         string                 `json:"client_id"`       // Extracted from auth (tenant boundary)
+	ClientOrderID    string                 `json:"client_order_id"` // ONDC order ID from message.order.id
+	PaymentInfo      map[string]interface{} `json:"payment_info,omitempty"`
+	PaymentType      string                 `json:"payment_type,omitempty"`      // ONDC payment.type (ON-FULFILLMENT for COD)
+	CollectionAmount string                 `json:"collection_amount,omitempty"` // COD amount the rider collects at delivery
 }
 
 // Validate validates ConfirmRequestedEvent
@@ -230,6 +319,49 @@ func (e *QuoteInvalidatedEvent) Validate() error {
 	return nil
 }
 
+// Quote Service processing stages reported in QUOTE_ERROR events
+const (
+	QuoteErrorStageValidation         = "VALIDATION"
+	QuoteErrorStageAdminConfigFetch   = "ADMIN_CONFIG_FETCH"
+	QuoteErrorStagePricingComputation = "PRICING_COMPUTATION"
+	QuoteErrorStageCacheWrite         = "CACHE_WRITE"
+	QuoteErrorStageEventPublish       = "EVENT_PUBLISH"
+)
+
+// QuoteErrorEvent is consumed from quote:error (published by Quote Service when /search pricing fails)
+// ID Stack Compliance: Uses search_id (business correlation ID) for event correlation
+// NOTE: The processing_error contract carries no event_id; BaseEvent validation is relaxed accordingly
+type QuoteErrorEvent struct {
+	BaseEvent
+	SearchID          string                 `json:"search_id"` // Business correlation ID (NOT WebSocket correlation_id)
+	Stage             string                 `json:"stage"`     // VALIDATION, ADMIN_CONFIG_FETCH, PRICING_COMPUTATION, CACHE_WRITE, EVENT_PUBLISH
+	Code              string                 `json:"code"`      // Quote Service error code
+	Message           string                 `json:"message"`   // Internal message (logged, not sent to the buyer)
+	Details           map[string]interface{} `json:"details,omitempty"`
+	RetryAfterSeconds int                    `json:"retry_after_seconds,omitempty"` // Set for transient failures
+	OriginTimestamp   *time.Time             `json:"origin_timestamp,omitempty"`
+}
+
+// Validate validates QuoteErrorEvent
+func (e *QuoteErrorEvent) Validate() error {
+	if e.EventType == "" {
+		return fmt.Errorf("event_type is required")
+	}
+	if e.Timestamp.IsZero() {
+		return fmt.Errorf("timestamp is required")
+	}
+	if e.SearchID == "" {
+		return fmt.Errorf("search_id is required")
+	}
+	if e.Stage == "" {
+		return fmt.Errorf("stage is required")
+	}
+	if e.Code == "" {
+		return fmt.Errorf("code is required")
+	}
+	return nil
+}
+
 // OrderConfirmedEvent is consumed from stream.uois.order_confirmed
 // ID Stack Compliance: Uses quote_id (business correlation ID) for event correlation, NOT WebSocket correlation_id
 type OrderConfirmedEvent struct {
@@ -282,3 +414,163 @@ func (e *OrderConfirmFailedEvent) Validate() error {
 	}
 	return nil
 }
+
+// OrderLifecycleEvent is consumed from stream.order.lifecycle
+// ID Stack Compliance: Uses dispatch_order_id (business lifecycle ID) for correlation, NOT WebSocket correlation_id
+type OrderLifecycleEvent struct {
+	BaseEvent
+	EventVersion    int                    `json:"event_version"`
+	DispatchOrderID string                 `json:"dispatch_order_id"`         // Business lifecycle ID
+	ClientOrderID   string                 `json:"client_order_id,omitempty"` // ONDC order.id when Order Service has it
+	QuoteID         string                 `json:"quote_id,omitempty"`
+	RiderID         string                 `json:"rider_id,omitempty"`
+	State           string                 `json:"state"`
+	PreviousState   string                 `json:"previous_state"`
+	Reason          string                 `json:"reason,omitempty"`
+	Source          string                 `json:"source,omitempty"`
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Validate validates OrderLifecycleEvent
+func (e *OrderLifecycleEvent) Validate() error {
+	if err := e.ValidateBaseEvent(); err != nil {
+		return err
+	}
+	if e.DispatchOrderID == "" {
+		return fmt.Errorf("dispatch_order_id is required")
+	}
+	if e.State == "" {
+		return fmt.Errorf("state is required")
+	}
+	return nil
+}
+
+// RiderLocation is the rider's position at assignment time
+type RiderLocation struct {
+	Lat      float64 `json:"lat"`
+	Lng      float64 `json:"lng"`
+	Accuracy float64 `json:"accuracy,omitempty"`
+}
+
+// RiderAssignedEvent is consumed from stream.droneai.order.assigned (owned by DroneAI)
+// ID Stack Compliance: Uses dispatch_order_id (business lifecycle ID) for correlation, NOT WebSocket correlation_id
+// NOTE: DroneAI does not publish traceparent for this event; BaseEvent validation is relaxed accordingly
+type RiderAssignedEvent struct {
+	BaseEvent
+	DispatchOrderID  string                 `json:"dispatch_order_id"` // Business lifecycle ID
+	RiderID          string                 `json:"rider_id"`
+	AssignmentID     string                 `json:"assignment_id"`
+	RiderLocation    *RiderLocation         `json:"rider_location,omitempty"`
+	ETAToPickup      int                    `json:"eta_to_pickup,omitempty"`      // Seconds
+	DistanceToPickup float64                `json:"distance_to_pickup,omitempty"` // Meters
+	ScoringMetadata  map[string]interface{} `json:"scoring_metadata,omitempty"`
+}
+
+// Validate validates RiderAssignedEvent
+func (e *RiderAssignedEvent) Validate() error {
+	if err := e.validateDroneAIEvent(); err != nil {
+		return err
+	}
+	if e.DispatchOrderID == "" {
+		return fmt.Errorf("dispatch_order_id is required")
+	}
+	if e.RiderID == "" {
+		return fmt.Errorf("rider_id is required")
+	}
+	if e.AssignmentID == "" {
+		return fmt.Errorf("assignment_id is required")
+	}
+	return nil
+}
+
+// RiderAssignmentFailedEvent is consumed from stream.droneai.order.assign_failed (owned by DroneAI)
+// ID Stack Compliance: Uses dispatch_order_id (business lifecycle ID) for correlation, NOT WebSocket correlation_id
+type RiderAssignmentFailedEvent struct {
+	BaseEvent
+	DispatchOrderID      string     `json:"dispatch_order_id"` // Business lifecycle ID
+	Reason               string     `json:"reason"`            // NO_RIDERS_AVAILABLE, ALL_RIDERS_DECLINED, RIDER_TIMEOUT, SYSTEM_ERROR
+	AttemptCount         int        `json:"attempt_count"`
+	MaxAttempts          int        `json:"max_attempts,omitempty"`
+	LastAttemptTimestamp *time.Time `json:"last_attempt_timestamp,omitempty"`
+	CandidateRidersCount int        `json:"candidate_riders_count,omitempty"`
+	DeclinedRidersCount  int        `json:"declined_riders_count,omitempty"`
+	TimeoutRidersCount   int        `json:"timeout_riders_count,omitempty"`
+	RetryRecommended     bool       `json:"retry_recommended,omitempty"`
+}
+
+// Validate validates RiderAssignmentFailedEvent
+func (e *RiderAssignmentFailedEvent) Validate() error {
+	if err := e.validateDroneAIEvent(); err != nil {
+		return err
+	}
+	if e.DispatchOrderID == "" {
+		return fmt.Errorf("dispatch_order_id is required")
+	}
+	if e.Reason == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if e.AttemptCount < 1 {
+		return fmt.Errorf("attempt_count must be at least 1")
+	}
+	return nil
+}
+
+// PackageWeight is a measured package weight
+type PackageWeight struct {
+	Unit  string  `json:"unit"` // e.g. "kilogram"
+	Value float64 `json:"value"`
+}
+
+// PackageDimensions are measured package dimensions
+type PackageDimensions struct {
+	Unit    string  `json:"unit"` // e.g. "centimeter"
+	Length  float64 `json:"length"`
+	Breadth float64 `json:"breadth"`
+	Height  float64 `json:"height"`
+}
+
+// QuoteDifferentialEvent is consumed from stream.quote.differential (owned by Quote Service)
+// Published when the rider finds the package heavier or bigger than declared: Quote Service re-prices
+// the order for the measured package; the gateway charges the difference to the agreed quote as diff/tax_diff
+// ID Stack Compliance: Uses dispatch_order_id (business lifecycle ID) for correlation
+type QuoteDifferentialEvent struct {
+	BaseEvent
+	DispatchOrderID string             `json:"dispatch_order_id"`         // Business lifecycle ID
+	QuoteID         string             `json:"quote_id,omitempty"`        // Quote agreed in /init
+	LinkedOrderID   string             `json:"linked_order_id,omitempty"` // Retail order id (@ondc/org/linked_order.order.id), when known
+	Weight          *PackageWeight     `json:"weight,omitempty"`          // Measured weight, only if it differs from the declared one
+	Dimensions      *PackageDimensions `json:"dimensions,omitempty"`      // Measured dimensions, only if they differ from the declared ones
+	ProofURLs       []string           `json:"proof_urls,omitempty"`      // Images of the measurement (e.g. from the sorter)
+	RevisedBreakup  []BreakupItem      `json:"revised_breakup"`           // Delivery and tax lines re-priced for the measured package
+}
+
+// Validate validates QuoteDifferentialEvent
+func (e *QuoteDifferentialEvent) Validate() error {
+	if err := e.ValidateBaseEvent(); err != nil {
+		return err
+	}
+	if e.DispatchOrderID == "" {
+		return fmt.Errorf("dispatch_order_id is required")
+	}
+	if e.Weight == nil && e.Dimensions == nil {
+		return fmt.Errorf("weight or dimensions is required")
+	}
+	if len(e.RevisedBreakup) == 0 {
+		return fmt.Errorf("revised_breakup is required")
+	}
+	return nil
+}
+
+// validateDroneAIEvent validates the common fields DroneAI events carry (no traceparent)
+func (e *BaseEvent) validateDroneAIEvent() error {
+	if e.EventType == "" {
+		return fmt.Errorf("event_type is required")
+	}
+	if e.EventID == "" {
+		return fmt.Errorf("event_id is required")
+	}
+	if e.Timestamp.IsZero() {
+		return fmt.Errorf("timestamp is required")
+	}
+	return nil
+}
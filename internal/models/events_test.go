@@ -218,3 +218,64 @@ func TestOrderConfirmedEvent_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeEvent(t *testing.T) {
+	base := `"event_id":"2b1f5f0e-5e0c-4a43-9d4b-0a1f7c1c2d3e","traceparent":"00-4bf92f3577b34da6a3ce929d0e0e4736-8f2a1b2c3d4e5f6a-01","timestamp":"2025-01-01T00:00:00Z"`
+
+	tests := []struct {
+		name     string
+		data     string
+		wantType interface{}
+		wantErr  bool
+	}{
+		{"QuoteComputed", `{"event_type":"QUOTE_COMPUTED",` + base + `,"search_id":"s1","serviceable":true}`, &QuoteComputedEvent{}, false},
+		{"QuoteCreated", `{"event_type":"QUOTE_CREATED",` + base + `,"search_id":"s1","quote_id":"q1"}`, &QuoteCreatedEvent{}, false},
+		{"QuoteInvalidated", `{"event_type":"QUOTE_INVALIDATED",` + base + `,"search_id":"s1","error":"EXPIRED"}`, &QuoteInvalidatedEvent{}, false},
+		{"OrderConfirmed", `{"event_type":"ORDER_CONFIRMED",` + base + `,"quote_id":"q1","dispatch_order_id":"d1"}`, &OrderConfirmedEvent{}, false},
+		{"OrderConfirmFailed", `{"event_type":"ORDER_CONFIRM_FAILED",` + base + `,"quote_id":"q1","reason":"no rider"}`, &OrderConfirmFailedEvent{}, false},
+		{"OrderLifecycle", `{"event_type":"order.lifecycle",` + base + `,"event_version":1,"dispatch_order_id":"d1","state":"PICKED_UP","previous_state":"ASSIGNED"}`, &OrderLifecycleEvent{}, false},
+		{"OrderLifecycle missing state", `{"event_type":"order.lifecycle",` + base + `,"dispatch_order_id":"d1"}`, nil, true},
+		{"RiderAssigned without traceparent", `{"event_type":"RIDER_ASSIGNED","event_id":"e1","timestamp":"2025-01-01T00:00:00Z","dispatch_order_id":"d1","rider_id":"r1","assignment_id":"a1"}`, &RiderAssignedEvent{}, false},
+		{"RiderAssigned missing rider_id", `{"event_type":"RIDER_ASSIGNED",` + base + `,"dispatch_order_id":"d1","assignment_id":"a1"}`, nil, true},
+		{"RiderAssignmentFailed", `{"event_type":"RIDER_ASSIGNMENT_FAILED",` + base + `,"dispatch_order_id":"d1","reason":"NO_RIDERS_AVAILABLE","attempt_count":3}`, &RiderAssignmentFailedEvent{}, false},
+		{"RiderAssignmentFailed missing reason", `{"event_type":"RIDER_ASSIGNMENT_FAILED",` + base + `,"dispatch_order_id":"d1","attempt_count":1}`, nil, true},
+		{"QuoteDifferential", `{"event_type":"QUOTE_DIFFERENTIAL",` + base + `,"dispatch_order_id":"d1","weight":{"unit":"kilogram","value":1.5},"revised_breakup":[{"@ondc/org/item_id":"I1","@ondc/org/title_type":"delivery","price":{"currency":"INR","value":75}}]}`, &QuoteDifferentialEvent{}, false},
+		{"QuoteDifferential without measurement", `{"event_type":"QUOTE_DIFFERENTIAL",` + base + `,"dispatch_order_id":"d1","revised_breakup":[{"@ondc/org/item_id":"I1","@ondc/org/title_type":"delivery","price":{"currency":"INR","value":75}}]}`, nil, true},
+		{"QuoteDifferential missing revised_breakup", `{"event_type":"QUOTE_DIFFERENTIAL",` + base + `,"dispatch_order_id":"d1","weight":{"unit":"kilogram","value":1.5}}`, nil, true},
+		{"QuoteError without event_id", `{"event_type":"QUOTE_ERROR","search_id":"s1","stage":"PRICING_COMPUTATION","code":"65002","message":"pricing failed","timestamp":"2025-01-01T00:00:00Z"}`, &QuoteErrorEvent{}, false},
+		{"QuoteError missing stage", `{"event_type":"QUOTE_ERROR",` + base + `,"search_id":"s1","code":"65002","message":"pricing failed"}`, nil, true},
+		{"Fails validation", `{"event_type":"ORDER_CONFIRMED",` + base + `,"quote_id":"q1"}`, nil, true},
+		{"Invalid JSON", `{`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := DecodeEvent([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, event)
+				return
+			}
+			assert.NoError(t, err)
+			assert.IsType(t, tt.wantType, event)
+		})
+	}
+}
+
+func TestDecodeEvent_UnknownType(t *testing.T) {
+	event, err := DecodeEvent([]byte(`{"event_type":"SEARCH_REQUESTED","search_id":"s1"}`))
+
+	assert.Nil(t, event)
+	assert.ErrorIs(t, err, ErrUnknownEventType)
+}
+
+func TestDecodeEvent_PopulatesFields(t *testing.T) {
+	event, err := DecodeEvent([]byte(`{"event_type":"QUOTE_COMPUTED","event_id":"e1","traceparent":"00-4bf92f3577b34da6a3ce929d0e0e4736-8f2a1b2c3d4e5f6a-01","timestamp":"2025-01-01T00:00:00Z","search_id":"s1","serviceable":true,"price":{"value":60,"currency":"INR"},"ttl":"PT10M"}`))
+
+	assert.NoError(t, err)
+	quoteComputed, ok := event.(*QuoteComputedEvent)
+	assert.True(t, ok)
+	assert.Equal(t, "s1", quoteComputed.SearchID)
+	assert.Equal(t, 60.0, quoteComputed.Price.Value)
+	assert.Equal(t, "PT10M", quoteComputed.TTL)
+}
@@ -13,6 +13,8 @@ import (
 // - UOIS Gateway NEVER generates or uses correlation_id (WebSocket Gateway responsibility only)
 type ONDCContext struct {
 	Domain        string    `json:"domain"`
+	Country       string    `json:"country,omitempty"`
+	City          string    `json:"city,omitempty"` // ONDC city code (e.g., "std:080")
 	Action        string    `json:"action"`
 	BapID         string    `json:"bap_id,omitempty"`
 	BapURI        string    `json:"bap_uri,omitempty"`
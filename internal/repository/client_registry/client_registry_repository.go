@@ -18,6 +18,7 @@ import (
 // DBClient interface for database operations
 type DBClient interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
@@ -115,16 +116,84 @@ func (r *Repository) UpsertClient(ctx context.Context, client *models.Client) er
 
 // GetByClientID retrieves a client by ID
 func (r *Repository) GetByClientID(ctx context.Context, clientID string) (*models.Client, error) {
-	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
-	defer cancel()
+	return r.getClient(ctx, "client_id", clientID)
+}
 
-	query := `SELECT 
+// GetByBapID retrieves a client by its registered ONDC bap_id
+// Used to resolve ONDC-signed requests (keyId subscriber_id) to a client
+func (r *Repository) GetByBapID(ctx context.Context, bapID string) (*models.Client, error) {
+	return r.getClient(ctx, "bap_id", bapID)
+}
+
+// selectClientsQuery selects every registry column scanClient reads
+const selectClientsQuery = `SELECT 
 		client_id, client_code, client_secret_hash,
 		api_key_hash, bap_id, bap_uri, allowed_ips,
 		rate_limit, status, metadata
-	FROM client_registry.clients
-	WHERE client_id = $1`
+	FROM client_registry.clients`
 
+// getClient retrieves a single client filtered by the given column
+// column must be a trusted constant (never user input)
+func (r *Repository) getClient(ctx context.Context, column, value string) (*models.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := selectClientsQuery + `
+	WHERE ` + column + ` = $1`
+
+	client, err := scanClient(r.db.QueryRowContext(ctx, query, value))
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NewDomainError(65006, "client not found", fmt.Sprintf("%s %s not found", column, value))
+	}
+
+	if err != nil {
+		r.logger.Error("failed to get client", zap.Error(err), zap.String(column, value))
+		return nil, errors.WrapDomainError(err, 65011, "client registry unavailable", "database error")
+	}
+
+	return client, nil
+}
+
+// ListClients retrieves every client in the registry, whatever its status
+// Used by the Admin Service reconciler to diff the local projection against upstream
+func (r *Repository) ListClients(ctx context.Context) ([]*models.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, selectClientsQuery+`
+	ORDER BY client_id`)
+	if err != nil {
+		r.logger.Error("failed to list clients", zap.Error(err))
+		return nil, errors.WrapDomainError(err, 65011, "client registry unavailable", "database error")
+	}
+	defer rows.Close()
+
+	var clients []*models.Client
+	for rows.Next() {
+		client, err := scanClient(rows)
+		if err != nil {
+			return nil, errors.WrapDomainError(err, 65011, "client registry unavailable", "failed to scan client")
+		}
+		clients = append(clients, client)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to list clients", zap.Error(err))
+		return nil, errors.WrapDomainError(err, 65011, "client registry unavailable", "database error")
+	}
+
+	return clients, nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanClient reads one selectClientsQuery row into a client
+// bap_id, bap_uri and rate_limit columns are exposed through Metadata
+func scanClient(row rowScanner) (*models.Client, error) {
 	var (
 		dbClientID       string
 		clientCode       string
@@ -138,7 +207,7 @@ func (r *Repository) GetByClientID(ctx context.Context, clientID string) (*model
 		metadataJSON     sql.NullString
 	)
 
-	err := r.db.QueryRowContext(ctx, query, clientID).Scan(
+	err := row.Scan(
 		&dbClientID,
 		&clientCode,
 		&clientSecretHash,
@@ -150,14 +219,8 @@ func (r *Repository) GetByClientID(ctx context.Context, clientID string) (*model
 		&status,
 		&metadataJSON,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, errors.NewDomainError(65006, "client not found", fmt.Sprintf("client_id %s not found", clientID))
-	}
-
 	if err != nil {
-		r.logger.Error("failed to get client", zap.Error(err), zap.String("client_id", clientID))
-		return nil, errors.WrapDomainError(err, 65011, "client registry unavailable", "database error")
+		return nil, err
 	}
 
 	client := &models.Client{
@@ -104,6 +104,64 @@ func TestClientRegistryRepository_GetByClientID_Success(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestClientRegistryRepository_GetByBapID_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRepository(db, config.Config{}, zap.NewNop())
+
+	clientID := "550e8400-e29b-41d4-a716-446655440000"
+	rows := sqlmock.NewRows([]string{
+		"client_id", "client_code", "client_secret_hash",
+		"api_key_hash", "bap_id", "bap_uri", "allowed_ips",
+		"rate_limit", "status", "metadata",
+	}).
+		AddRow(
+			clientID,
+			"ABC",
+			"hashed_secret",
+			"hashed_secret",
+			"buyer.example.com",
+			"https://buyer.example.com",
+			"{}",
+			100,
+			models.ClientStatusActive,
+			`{"auth_mode":"ONDC_SIGNATURE"}`,
+		)
+
+	mock.ExpectQuery(`SELECT client_id, client_code, client_secret_hash(.+)WHERE bap_id = \$1`).
+		WithArgs("buyer.example.com").
+		WillReturnRows(rows)
+
+	client, err := repo.GetByBapID(context.Background(), "buyer.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, clientID, client.ID)
+	assert.Equal(t, "buyer.example.com", client.BapID())
+	assert.Equal(t, models.ClientAuthModeONDCSignature, client.AuthMode())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientRegistryRepository_GetByBapID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRepository(db, config.Config{}, zap.NewNop())
+
+	mock.ExpectQuery(`SELECT client_id, client_code, client_secret_hash`).
+		WithArgs("unknown.example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	client, err := repo.GetByBapID(context.Background(), "unknown.example.com")
+	assert.Error(t, err)
+	assert.Nil(t, client)
+	domainErr, ok := err.(*domainErrors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65006, domainErr.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestClientRegistryRepository_GetByClientID_NotFound(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -183,3 +241,31 @@ func TestClientRegistryRepository_UpdateStatus_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestClientRegistryRepository_ListClients_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRepository(db, config.Config{}, zap.NewNop())
+
+	rows := sqlmock.NewRows([]string{
+		"client_id", "client_code", "client_secret_hash",
+		"api_key_hash", "bap_id", "bap_uri", "allowed_ips",
+		"rate_limit", "status", "metadata",
+	}).
+		AddRow("client-1", "ABC", "hash-1", "hash-1", "buyer.example.com", "https://buyer.example.com", "{192.168.1.0/24}", 100, models.ClientStatusActive, `{"key":"value"}`).
+		AddRow("client-2", "XYZ", "hash-2", nil, nil, nil, "{}", nil, models.ClientStatusSuspended, nil)
+
+	mock.ExpectQuery(`SELECT client_id, client_code, client_secret_hash(.+)ORDER BY client_id`).
+		WillReturnRows(rows)
+
+	clients, err := repo.ListClients(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, clients, 2)
+	assert.Equal(t, "buyer.example.com", clients[0].BapID())
+	assert.Equal(t, []string{"192.168.1.0/24"}, clients[0].AllowedIPs)
+	assert.Equal(t, models.ClientStatusSuspended, clients[1].Status)
+	assert.Empty(t, clients[1].BapID())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,151 @@
+package cod_reconciliation
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// DBClient interface for database operations
+type DBClient interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Repository stores COD reconciliation records (one per cash on delivery order)
+type Repository struct {
+	db     DBClient
+	config config.Config
+	logger *zap.Logger
+}
+
+// NewRepository creates a new COD reconciliation repository
+func NewRepository(db DBClient, cfg config.Config, logger *zap.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// CreateReconciliation stores a PENDING reconciliation record for a confirmed COD order
+// Idempotent per client_id + order.id, so a replayed /confirm does not reset a collected record
+func (r *Repository) CreateReconciliation(ctx context.Context, record *models.CODReconciliation) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if record.Status == "" {
+		record.Status = models.CODStatusPending
+	}
+	if record.Currency == "" {
+		record.Currency = "INR"
+	}
+
+	query := `INSERT INTO operations.cod_reconciliation (
+		order_id, client_id, dispatch_order_id, transaction_id, collection_amount, currency, status
+	) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (client_id, order_id) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query,
+		record.OrderID,
+		record.ClientID,
+		sqlNullString(record.DispatchOrderID),
+		sqlNullString(record.TransactionID),
+		record.CollectionAmount,
+		record.Currency,
+		record.Status,
+	)
+	if err != nil {
+		r.logger.Error("failed to store cod reconciliation", zap.Error(err), zap.String("order_id", record.OrderID))
+		return errors.WrapDomainError(err, 65011, "cod reconciliation storage failed", "database error")
+	}
+
+	r.logger.Debug("cod reconciliation stored",
+		zap.String("order_id", record.OrderID),
+		zap.String("client_id", record.ClientID),
+		zap.String("collection_amount", record.CollectionAmount),
+	)
+
+	return nil
+}
+
+// GetReconciliation retrieves the reconciliation record of an order by client_id + order.id
+func (r *Repository) GetReconciliation(ctx context.Context, clientID, orderID string) (*models.CODReconciliation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := `SELECT id, order_id, client_id, dispatch_order_id, transaction_id, collection_amount::TEXT, currency, status, settlement_reference, created_at, updated_at
+	FROM operations.cod_reconciliation
+	WHERE client_id = $1 AND order_id = $2`
+
+	var (
+		record          models.CODReconciliation
+		dispatchOrderID sql.NullString
+		transactionID   sql.NullString
+		settlementRef   sql.NullString
+	)
+	err := r.db.QueryRowContext(ctx, query, clientID, orderID).Scan(
+		&record.ID,
+		&record.OrderID,
+		&record.ClientID,
+		&dispatchOrderID,
+		&transactionID,
+		&record.CollectionAmount,
+		&record.Currency,
+		&record.Status,
+		&settlementRef,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewDomainError(65006, "cod reconciliation not found", "order_id not found")
+	}
+	if err != nil {
+		r.logger.Error("failed to get cod reconciliation", zap.Error(err), zap.String("order_id", orderID))
+		return nil, errors.WrapDomainError(err, 65011, "cod reconciliation retrieval failed", "database error")
+	}
+
+	record.DispatchOrderID = dispatchOrderID.String
+	record.TransactionID = transactionID.String
+	record.SettlementRef = settlementRef.String
+	return &record, nil
+}
+
+// UpdateStatus moves a reconciliation record to COLLECTED or REMITTED
+// settlementRef is the remittance reference and is kept when empty
+func (r *Repository) UpdateStatus(ctx context.Context, clientID, orderID, status, settlementRef string) error {
+	if status != models.CODStatusPending && status != models.CODStatusCollected && status != models.CODStatusRemitted {
+		return errors.NewDomainError(65001, "invalid cod reconciliation status", status)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := `UPDATE operations.cod_reconciliation
+	SET status = $3, settlement_reference = COALESCE($4, settlement_reference), updated_at = CURRENT_TIMESTAMP
+	WHERE client_id = $1 AND order_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, clientID, orderID, status, sqlNullString(settlementRef))
+	if err != nil {
+		r.logger.Error("failed to update cod reconciliation", zap.Error(err), zap.String("order_id", orderID))
+		return errors.WrapDomainError(err, 65011, "cod reconciliation update failed", "database error")
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errors.NewDomainError(65006, "cod reconciliation not found", "order_id not found")
+	}
+
+	return nil
+}
+
+func sqlNullString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{Valid: false}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
@@ -0,0 +1,83 @@
+package cod_reconciliation
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCODReconciliationRepository_CreateReconciliation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRepository(db, config.Config{}, zap.NewNop())
+
+	mock.ExpectExec(`INSERT INTO operations\.cod_reconciliation(.+)ON CONFLICT \(client_id, order_id\) DO NOTHING`).
+		WithArgs("order-1", "client-1", "dispatch-1", sql.NullString{}, "300.00", "INR", models.CODStatusPending).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	record := &models.CODReconciliation{OrderID: "order-1", ClientID: "client-1", DispatchOrderID: "dispatch-1", CollectionAmount: "300.00"}
+	require.NoError(t, repo.CreateReconciliation(context.Background(), record))
+
+	assert.Equal(t, models.CODStatusPending, record.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCODReconciliationRepository_GetReconciliation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRepository(db, config.Config{}, zap.NewNop())
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT (.+) FROM operations\.cod_reconciliation WHERE client_id = \$1 AND order_id = \$2`).
+		WithArgs("client-1", "order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "client_id", "dispatch_order_id", "transaction_id", "collection_amount", "currency", "status", "settlement_reference", "created_at", "updated_at"}).
+			AddRow(1, "order-1", "client-1", "dispatch-1", nil, "300.00", "INR", models.CODStatusCollected, nil, now, now))
+
+	record, err := repo.GetReconciliation(context.Background(), "client-1", "order-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "dispatch-1", record.DispatchOrderID)
+	assert.Equal(t, "300.00", record.CollectionAmount)
+	assert.Equal(t, models.CODStatusCollected, record.Status)
+	assert.Empty(t, record.SettlementRef)
+
+	mock.ExpectQuery(`SELECT (.+) FROM operations\.cod_reconciliation`).WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetReconciliation(context.Background(), "client-1", "order-2")
+	var domainErr *errors.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, 65006, domainErr.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCODReconciliationRepository_UpdateStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRepository(db, config.Config{}, zap.NewNop())
+
+	mock.ExpectExec(`UPDATE operations\.cod_reconciliation`).
+		WithArgs("client-1", "order-1", models.CODStatusRemitted, sql.NullString{String: "UTR123", Valid: true}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.UpdateStatus(context.Background(), "client-1", "order-1", models.CODStatusRemitted, "UTR123"))
+
+	mock.ExpectExec(`UPDATE operations\.cod_reconciliation`).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Error(t, repo.UpdateStatus(context.Background(), "client-1", "order-2", models.CODStatusCollected, ""))
+
+	assert.Error(t, repo.UpdateStatus(context.Background(), "client-1", "order-1", "LOST", ""))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
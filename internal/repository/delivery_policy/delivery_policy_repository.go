@@ -0,0 +1,84 @@
+package delivery_policy
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// DBClient interface for database operations
+type DBClient interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Repository reads delivery category rules (DELIVERY_POLICY_SOURCE=db)
+type Repository struct {
+	db     DBClient
+	config config.Config
+	logger *zap.Logger
+}
+
+// NewRepository creates a new delivery policy repository
+func NewRepository(db DBClient, cfg config.Config, logger *zap.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// ListRules retrieves the enabled delivery category rules in priority order
+func (r *Repository) ListRules(ctx context.Context) ([]models.DeliveryCategoryRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `SELECT category_id, city_codes, client_ids, max_tat, require_tat, windows, cod_enabled
+	FROM ondc_reference.delivery_category_rules
+	WHERE enabled
+	ORDER BY priority, id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to list delivery category rules", zap.Error(err))
+		return nil, errors.WrapDomainError(err, 65011, "delivery policy unavailable", "database error")
+	}
+	defer rows.Close()
+
+	var rules []models.DeliveryCategoryRule
+	for rows.Next() {
+		var (
+			rule        models.DeliveryCategoryRule
+			cityCodes   pq.StringArray
+			clientIDs   pq.StringArray
+			maxTAT      sql.NullString
+			windowsJSON []byte
+		)
+		if err := rows.Scan(&rule.CategoryID, &cityCodes, &clientIDs, &maxTAT, &rule.RequireTAT, &windowsJSON, &rule.COD); err != nil {
+			return nil, errors.WrapDomainError(err, 65011, "delivery policy unavailable", "failed to scan delivery category rule")
+		}
+		if len(windowsJSON) > 0 {
+			if err := json.Unmarshal(windowsJSON, &rule.Windows); err != nil {
+				return nil, errors.WrapDomainError(err, 65020, "invalid delivery category rule", "failed to unmarshal windows")
+			}
+		}
+		rule.CityCodes = []string(cityCodes)
+		rule.ClientIDs = []string(clientIDs)
+		rule.MaxTAT = maxTAT.String
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to list delivery category rules", zap.Error(err))
+		return nil, errors.WrapDomainError(err, 65011, "delivery policy unavailable", "database error")
+	}
+
+	return rules, nil
+}
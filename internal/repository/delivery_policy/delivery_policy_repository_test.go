@@ -0,0 +1,56 @@
+package delivery_policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDeliveryPolicyRepository_ListRules_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRepository(db, config.Config{}, zap.NewNop())
+
+	rows := sqlmock.NewRows([]string{"category_id", "city_codes", "client_ids", "max_tat", "require_tat", "windows", "cod_enabled"}).
+		AddRow("Immediate Delivery", "{}", "{}", "PT60M", false, []byte(`[]`), false).
+		AddRow("Same Day Delivery", "{std:080,std:011}", "{client-1}", nil, false, []byte(`[{"start":"08:00","end":"16:00"}]`), true)
+
+	mock.ExpectQuery(`SELECT category_id, city_codes, client_ids, max_tat, require_tat, windows, cod_enabled(.+)WHERE enabled(.+)ORDER BY priority, id`).
+		WillReturnRows(rows)
+
+	rules, err := repo.ListRules(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, models.DeliveryCategoryRule{CategoryID: "Immediate Delivery", CityCodes: []string{}, ClientIDs: []string{}, MaxTAT: "PT60M", Windows: []models.DeliveryWindow{}}, rules[0])
+	assert.Equal(t, []string{"std:080", "std:011"}, rules[1].CityCodes)
+	assert.Equal(t, []string{"client-1"}, rules[1].ClientIDs)
+	assert.Empty(t, rules[1].MaxTAT)
+	assert.Equal(t, []models.DeliveryWindow{{Start: "08:00", End: "16:00"}}, rules[1].Windows)
+	assert.True(t, rules[1].COD)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeliveryPolicyRepository_ListRules_DatabaseError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRepository(db, config.Config{}, zap.NewNop())
+	mock.ExpectQuery(`SELECT category_id`).WillReturnError(errors.New("connection refused"))
+
+	_, err = repo.ListRules(context.Background())
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
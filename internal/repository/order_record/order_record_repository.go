@@ -37,7 +37,7 @@ func NewRepository(rdb RedisClient, cfg config.Config, logger *zap.Logger) *Repo
 }
 
 // StoreOrderRecord stores an order record
-// CRITICAL: Must store all keys (search_id, quote_id, order_id, transaction_id)
+// CRITICAL: Must store all keys (search_id, quote_id, order_id, transaction_id, dispatch_order_id)
 // to ensure lookups work immediately after storage.
 // Delegates to UpdateOrderRecord to avoid duplication.
 func (r *Repository) StoreOrderRecord(ctx context.Context, record *ondc.OrderRecord) error {
@@ -68,6 +68,12 @@ func (r *Repository) GetOrderRecordByTransactionID(ctx context.Context, transact
 	return r.getByKey(ctx, key)
 }
 
+// GetOrderRecordByDispatchOrderID retrieves order record by dispatch_order_id
+func (r *Repository) GetOrderRecordByDispatchOrderID(ctx context.Context, dispatchOrderID string) (*ondc.OrderRecord, error) {
+	key := r.buildKey("dispatch_order_id", dispatchOrderID)
+	return r.getByKey(ctx, key)
+}
+
 // UpdateOrderRecord updates an existing order record
 // NOTE: TTL extension on update is acceptable - status/track calls can keep extending lifetime
 // NOTE: Atomicity - if Redis crashes mid-loop, partial state may occur
@@ -80,6 +86,7 @@ func (r *Repository) UpdateOrderRecord(ctx context.Context, record *ondc.OrderRe
 		r.buildKey("quote_id", record.QuoteID),
 		r.buildKey("order_id", fmt.Sprintf("%s:%s", record.ClientID, record.OrderID)),
 		r.buildKey("transaction_id", record.TransactionID),
+		r.buildKey("dispatch_order_id", record.DispatchOrderID),
 	}
 
 	val, err := json.Marshal(record)
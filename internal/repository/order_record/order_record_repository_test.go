@@ -55,11 +55,11 @@ func TestOrderRecordRepository_StoreOrderRecord_Success(t *testing.T) {
 		FulfillmentID:   "fulfill-1",
 	}
 
-	// StoreOrderRecord should store all keys (search_id, quote_id, order_id, transaction_id)
+	// StoreOrderRecord should store all keys (search_id, quote_id, order_id, transaction_id, dispatch_order_id)
 	// This is critical for lookups to work after StoreOrderRecord
 	statusCmd := redis.NewStatusCmd(context.Background())
 	statusCmd.SetVal("OK")
-	mockRedis.On("Set", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("time.Duration")).Return(statusCmd).Times(4)
+	mockRedis.On("Set", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("time.Duration")).Return(statusCmd).Times(5)
 
 	err := repo.StoreOrderRecord(context.Background(), record)
 
@@ -151,6 +151,43 @@ func TestOrderRecordRepository_GetOrderRecordBySearchID_Success(t *testing.T) {
 	mockRedis.AssertExpectations(t)
 }
 
+func TestOrderRecordRepository_GetOrderRecordByDispatchOrderID_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockRedis := new(MockRedisClient)
+
+	cfg := config.Config{
+		Redis: config.RedisConfig{
+			KeyPrefix: "test-prefix",
+		},
+		TTL: config.TTLConfig{
+			OrderMapping: 2592000,
+		},
+	}
+
+	repo := NewRepository(mockRedis, cfg, logger)
+
+	expectedRecord := &ondc.OrderRecord{
+		QuoteID:         "quote-456",
+		DispatchOrderID: "dispatch-789",
+		OrderID:         "order-abc",
+		ClientID:        "client-1",
+		TransactionID:   "txn-xyz",
+		BapURI:          "https://bap.example.com",
+	}
+
+	recordJSON, _ := json.Marshal(expectedRecord)
+	stringCmd := redis.NewStringCmd(context.Background())
+	stringCmd.SetVal(string(recordJSON))
+	mockRedis.On("Get", mock.Anything, "test-prefix:order_record:dispatch_order_id:dispatch-789").Return(stringCmd)
+
+	record, err := repo.GetOrderRecordByDispatchOrderID(context.Background(), "dispatch-789")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "order-abc", record.OrderID)
+	assert.Equal(t, "https://bap.example.com", record.BapURI)
+	mockRedis.AssertExpectations(t)
+}
+
 func TestOrderRecordRepository_GetOrderRecordBySearchID_NotFound(t *testing.T) {
 	logger := zap.NewNop()
 	mockRedis := new(MockRedisClient)
@@ -322,7 +359,7 @@ func TestOrderRecordRepository_UpdateOrderRecord_Success(t *testing.T) {
 
 	statusCmd := redis.NewStatusCmd(context.Background())
 	statusCmd.SetVal("OK")
-	mockRedis.On("Set", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("time.Duration")).Return(statusCmd).Times(4)
+	mockRedis.On("Set", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("time.Duration")).Return(statusCmd).Times(5)
 
 	err := repo.UpdateOrderRecord(context.Background(), record)
 
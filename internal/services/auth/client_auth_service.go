@@ -21,6 +21,7 @@ const (
 
 type ClientRegistry interface {
 	GetByClientID(ctx context.Context, clientID string) (*models.Client, error)
+	GetByBapID(ctx context.Context, bapID string) (*models.Client, error)
 }
 
 type ClientAuthService struct {
@@ -59,6 +60,14 @@ func (s *ClientAuthService) AuthenticateClient(ctx context.Context, clientID, cl
 		return nil, perrors.NewDomainError(ErrCodeAuthFailed, ErrMsgAuthFailed, "invalid credentials")
 	}
 
+	if client.AuthMode() == models.ClientAuthModeONDCSignature {
+		s.logger.Warn("authentication failed: client requires ONDC signature",
+			zap.String("client_id", clientID),
+			zap.String("client_ip", clientIP),
+		)
+		return nil, perrors.NewDomainError(ErrCodeAuthFailed, ErrMsgAuthFailed, "invalid credentials")
+	}
+
 	if err := s.validateSecret(clientSecret, client.ClientSecretHash); err != nil {
 		s.logger.Warn("authentication failed: invalid credentials",
 			zap.String("client_id", clientID),
@@ -70,6 +79,29 @@ func (s *ClientAuthService) AuthenticateClient(ctx context.Context, clientID, cl
 	return client, nil
 }
 
+// AuthenticateSubscriber resolves an ONDC-signed request to a client by subscriber_id (bap_id)
+// The signature itself must already be verified; this enforces client status, IP allowlist
+// and that the client is enrolled for ONDC signature authentication.
+func (s *ClientAuthService) AuthenticateSubscriber(ctx context.Context, subscriberID, clientIP string) (*models.Client, error) {
+	client, err := s.registry.GetByBapID(ctx, subscriberID)
+	if err != nil {
+		return s.handleRegistryError(err, subscriberID)
+	}
+
+	if !client.IsActive() || !client.ValidateIP(clientIP) || client.AuthMode() != models.ClientAuthModeONDCSignature {
+		s.logger.Warn("authentication failed: subscriber not allowed",
+			zap.String("subscriber_id", subscriberID),
+			zap.String("client_id", client.ID),
+			zap.String("status", client.Status),
+			zap.String("auth_mode", client.AuthMode()),
+			zap.String("client_ip", clientIP),
+		)
+		return nil, perrors.NewDomainError(ErrCodeAuthFailed, ErrMsgAuthFailed, "subscriber not allowed")
+	}
+
+	return client, nil
+}
+
 func (s *ClientAuthService) handleRegistryError(err error, clientID string) (*models.Client, error) {
 	domainErr, isDomainErr := err.(*perrors.DomainError)
 	if isDomainErr && domainErr.Code == 65006 {
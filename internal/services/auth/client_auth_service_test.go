@@ -26,6 +26,14 @@ func (m *MockClientRegistry) GetByClientID(ctx context.Context, clientID string)
 	return args.Get(0).(*models.Client), args.Error(1)
 }
 
+func (m *MockClientRegistry) GetByBapID(ctx context.Context, bapID string) (*models.Client, error) {
+	args := m.Called(ctx, bapID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Client), args.Error(1)
+}
+
 func TestAuthenticateClient_Success(t *testing.T) {
 	mockRepo := new(MockClientRegistry)
 	logger := zap.NewNop()
@@ -225,3 +233,77 @@ func TestAuthenticateClient_RegistryInfrastructureError(t *testing.T) {
 	assert.Equal(t, ErrCodeDependencyUnavailable, domainErr.Code)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestAuthenticateClient_ONDCSignatureClientRejected(t *testing.T) {
+	mockRepo := new(MockClientRegistry)
+	service := NewClientAuthService(mockRepo, zap.NewNop())
+
+	hashedSecret, _ := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.DefaultCost)
+	client := &models.Client{
+		ID:               "uuid-123",
+		ClientSecretHash: string(hashedSecret),
+		Status:           models.ClientStatusActive,
+		Metadata:         map[string]interface{}{"auth_mode": models.ClientAuthModeONDCSignature},
+	}
+	mockRepo.On("GetByClientID", mock.Anything, "uuid-123").Return(client, nil)
+
+	authenticatedClient, err := service.AuthenticateClient(context.Background(), "uuid-123", "secret123", "10.0.0.1")
+
+	assert.Error(t, err)
+	assert.Nil(t, authenticatedClient)
+	domainErr, ok := err.(*perrors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, ErrCodeAuthFailed, domainErr.Code)
+}
+
+func TestAuthenticateSubscriber_Success(t *testing.T) {
+	mockRepo := new(MockClientRegistry)
+	service := NewClientAuthService(mockRepo, zap.NewNop())
+
+	client := &models.Client{
+		ID:     "uuid-123",
+		Status: models.ClientStatusActive,
+		Metadata: map[string]interface{}{
+			"bap_id":    "buyer.com",
+			"auth_mode": models.ClientAuthModeONDCSignature,
+		},
+	}
+	mockRepo.On("GetByBapID", mock.Anything, "buyer.com").Return(client, nil)
+
+	authenticatedClient, err := service.AuthenticateSubscriber(context.Background(), "buyer.com", "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "uuid-123", authenticatedClient.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthenticateSubscriber_CredentialsClientRejected(t *testing.T) {
+	mockRepo := new(MockClientRegistry)
+	service := NewClientAuthService(mockRepo, zap.NewNop())
+
+	client := &models.Client{
+		ID:       "uuid-123",
+		Status:   models.ClientStatusActive,
+		Metadata: map[string]interface{}{"bap_id": "buyer.com"},
+	}
+	mockRepo.On("GetByBapID", mock.Anything, "buyer.com").Return(client, nil)
+
+	authenticatedClient, err := service.AuthenticateSubscriber(context.Background(), "buyer.com", "10.0.0.1")
+
+	assert.Error(t, err)
+	assert.Nil(t, authenticatedClient)
+}
+
+func TestAuthenticateSubscriber_NotFound(t *testing.T) {
+	mockRepo := new(MockClientRegistry)
+	service := NewClientAuthService(mockRepo, zap.NewNop())
+
+	mockRepo.On("GetByBapID", mock.Anything, "unknown.com").
+		Return(nil, perrors.NewDomainError(65006, "client not found", "bap_id not found"))
+
+	_, err := service.AuthenticateSubscriber(context.Background(), "unknown.com", "10.0.0.1")
+
+	domainErr, ok := err.(*perrors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, ErrCodeAuthFailed, domainErr.Code)
+}
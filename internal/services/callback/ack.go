@@ -0,0 +1,98 @@
+package callback
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+
+	"uois-gateway/pkg/errors"
+)
+
+// maxAckBodyBytes bounds how much of a Buyer NP response is read to find the ACK/NACK
+const maxAckBodyBytes = 64 << 10
+
+// retryableNACKCodes are Buyer NP error codes for transient conditions
+// Any other NACK is final: re-sending the same payload would be rejected the same way
+var retryableNACKCodes = map[string]bool{
+	"20001": true, // Invalid signature: the BAP may hold a stale registry key, and each attempt is re-signed
+	"23001": true, // Internal error
+	"65010": true, // Dependency timeout (UOIS taxonomy)
+	"65011": true, // Dependency unavailable (UOIS taxonomy)
+	"65012": true, // Rate limit exceeded (UOIS taxonomy)
+}
+
+// NACKError is a Buyer NP response whose message.ack.status is NACK
+type NACKError struct {
+	Code      string
+	Message   string
+	Retryable bool
+}
+
+func (e *NACKError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("NACK: %s", e.Message)
+	}
+	return fmt.Sprintf("NACK %s: %s", e.Code, e.Message)
+}
+
+// AsNACK returns the NACK behind a callback delivery error, if the BAP NACKed the callback
+func AsNACK(err error) (*NACKError, bool) {
+	var nack *NACKError
+	if stderrors.As(err, &nack) {
+		return nack, true
+	}
+	return nil, false
+}
+
+// parseNACK reads an ONDC ACK response and returns the NACK it carries, or nil
+// An empty or non-ONDC body is not a NACK: delivery is judged by the HTTP status alone
+func parseNACK(body []byte) *NACKError {
+	var response struct {
+		Message struct {
+			Ack struct {
+				Status string `json:"status"`
+			} `json:"ack"`
+		} `json:"message"`
+		Error *struct {
+			Code    string          `json:"code"`
+			Message json.RawMessage `json:"message"`
+		} `json:"error"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &response) != nil || response.Message.Ack.Status != "NACK" {
+		return nil
+	}
+
+	nack := &NACKError{Message: "no error given"}
+	if response.Error != nil {
+		nack.Code = response.Error.Code
+		if message := nackMessage(response.Error.Message); message != "" {
+			nack.Message = message
+		}
+	}
+	nack.Retryable = retryableNACKCodes[nack.Code]
+	return nack
+}
+
+// nackMessage accepts error.message as a plain string or as a localized {"en": "..."} object
+func nackMessage(raw json.RawMessage) string {
+	var message string
+	if json.Unmarshal(raw, &message) == nil {
+		return message
+	}
+
+	var localized map[string]string
+	if json.Unmarshal(raw, &localized) == nil {
+		if message, ok := localized["en"]; ok {
+			return message
+		}
+		for _, message := range localized {
+			return message
+		}
+	}
+	return ""
+}
+
+// nackDeliveryError wraps a NACK as a callback delivery failure (65021)
+func nackDeliveryError(nack *NACKError) error {
+	return errors.WrapDomainError(nack, 65021, "callback rejected by BAP", nack.Error()).WithRetryable(nack.Retryable)
+}
@@ -0,0 +1,140 @@
+package callback
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/services/audit"
+	"uois-gateway/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestParseNACK(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected *NACKError
+	}{
+		{
+			name:     "ACK",
+			body:     `{"message":{"ack":{"status":"ACK"}}}`,
+			expected: nil,
+		},
+		{
+			name:     "empty body",
+			body:     ``,
+			expected: nil,
+		},
+		{
+			name:     "not JSON",
+			body:     `OK`,
+			expected: nil,
+		},
+		{
+			name:     "NACK with string message",
+			body:     `{"message":{"ack":{"status":"NACK"}},"error":{"type":"DOMAIN-ERROR","code":"30004","message":"Item not found"}}`,
+			expected: &NACKError{Code: "30004", Message: "Item not found", Retryable: false},
+		},
+		{
+			name:     "NACK with localized message",
+			body:     `{"message":{"ack":{"status":"NACK"}},"error":{"type":"CONTEXT_ERROR","code":"23001","message":{"en":"Internal Error"}}}`,
+			expected: &NACKError{Code: "23001", Message: "Internal Error", Retryable: true},
+		},
+		{
+			name:     "NACK without error",
+			body:     `{"message":{"ack":{"status":"NACK"}}}`,
+			expected: &NACKError{Message: "no error given", Retryable: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseNACK([]byte(tt.body)))
+		})
+	}
+}
+
+func TestCallbackService_SendCallback_NACKOnSuccessStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"message":{"ack":{"status":"NACK"}},"error":{"type":"CONTEXT_ERROR","code":"20006","message":"Invalid response"}}`))
+	}))
+	defer server.Close()
+
+	service := NewService(config.CallbackConfig{HTTPTimeoutSeconds: 5}, nil, zap.NewNop())
+	_, err := service.SendCallback(context.Background(), server.URL, map[string]interface{}{"test": "data"})
+
+	assert.Error(t, err)
+	nack, ok := AsNACK(err)
+	assert.True(t, ok)
+	assert.Equal(t, "20006", nack.Code)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65021, domainErr.Code)
+}
+
+func TestCallbackService_SendCallback_ACKBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"message":{"ack":{"status":"ACK"}}}`))
+	}))
+	defer server.Close()
+
+	service := NewService(config.CallbackConfig{HTTPTimeoutSeconds: 5}, nil, zap.NewNop())
+	_, err := service.SendCallback(context.Background(), server.URL, map[string]interface{}{"test": "data"})
+
+	assert.NoError(t, err)
+}
+
+func TestRetryService_SendCallbackWithRetry_FinalNACKIsNotRetried(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":{"ack":{"status":"NACK"}},"error":{"type":"DOMAIN-ERROR","code":"30004","message":"Item not found"}}`))
+	}))
+	defer server.Close()
+
+	cfg := config.CallbackConfig{HTTPTimeoutSeconds: 5}
+	mockAudit := new(MockAuditService)
+	mockAudit.On("LogCallbackDelivery", mock.Anything, mock.MatchedBy(func(p *audit.CallbackDeliveryLogParams) bool {
+		return p.Status == "nack" && p.Error != "" && p.AttemptNo == 1
+	})).Return(nil).Once()
+
+	service := NewService(cfg, nil, zap.NewNop())
+	retryService := NewRetryService(service, config.RetryConfig{CallbackMaxRetries: 3, CallbackBackoff: []int{1, 1}}, cfg, nil, mockAudit, zap.NewNop())
+
+	err := retryService.SendCallbackWithRetry(context.Background(), server.URL, map[string]interface{}{"test": "data"}, "req-123", 30)
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestRetryService_SendCallbackWithDeadline_StopsAtDeadline(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := config.CallbackConfig{HTTPTimeoutSeconds: 5}
+	mockAudit := new(MockAuditService)
+	mockAudit.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	service := NewService(cfg, nil, zap.NewNop())
+	retryService := NewRetryService(service, config.RetryConfig{CallbackMaxRetries: 5, CallbackBackoff: []int{1, 1, 1, 1}}, cfg, nil, mockAudit, zap.NewNop())
+
+	err := retryService.SendCallbackWithDeadline(context.Background(), server.URL, map[string]interface{}{"test": "data"}, "req-123", time.Now().Add(-time.Second))
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
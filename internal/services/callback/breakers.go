@@ -0,0 +1,165 @@
+package callback
+
+import (
+	"context"
+	stderrors "errors"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/services/circuitbreaker"
+	"uois-gateway/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// bapDependencyPrefix prefixes the callback host in the dependency health metric
+const bapDependencyPrefix = "bap:"
+
+// HealthRecorder interface for publishing BAP reachability as dependency health
+type HealthRecorder interface {
+	SetDependencyHealth(dependency string, healthy bool)
+}
+
+// BAPHealth describes the circuit of one callback host
+type BAPHealth struct {
+	Host          string    `json:"host"`
+	State         string    `json:"state"`
+	Failures      int       `json:"failures"`
+	LastError     string    `json:"last_error,omitempty"`
+	LastFailureAt time.Time `json:"last_failure_at"`
+}
+
+// BAPBreakers keeps one circuit breaker per callback host
+// A BAP that stops answering opens its own circuit, so its callbacks stop consuming retries and
+// worker time while callbacks to every other buyer app are unaffected.
+type BAPBreakers struct {
+	config   circuitbreaker.Config
+	health   HealthRecorder
+	logger   *zap.Logger
+	mu       sync.Mutex
+	breakers map[string]*circuitbreaker.CircuitBreaker
+	lastErrs map[string]string
+}
+
+// NewBAPBreakers creates per-host breakers from the CALLBACK_BREAKER_* settings
+// health can be nil when metrics are not wired (tests, CLI)
+func NewBAPBreakers(cfg config.CallbackConfig, health HealthRecorder, logger *zap.Logger) *BAPBreakers {
+	cbConfig := circuitbreaker.DefaultConfig()
+	if cfg.BreakerThreshold > 0 {
+		cbConfig.FailureThreshold = cfg.BreakerThreshold
+	}
+	if cfg.BreakerOpenSeconds > 0 {
+		cbConfig.Timeout = time.Duration(cfg.BreakerOpenSeconds) * time.Second
+	}
+	// One probe at a time: a recovering BAP should not receive a burst of queued callbacks
+	cbConfig.MaxRequestsHalfOpen = 1
+	cbConfig.SuccessThreshold = 1
+
+	return &BAPBreakers{
+		config:   cbConfig,
+		health:   health,
+		logger:   logger,
+		breakers: make(map[string]*circuitbreaker.CircuitBreaker),
+		lastErrs: make(map[string]string),
+	}
+}
+
+// Execute runs fn through the breaker of callbackURL's host
+// fn should only return errors that mean the BAP is unreachable (transport errors, 5xx)
+// An open circuit returns a 65011 error for which IsBAPUnavailable is true
+func (b *BAPBreakers) Execute(ctx context.Context, callbackURL string, fn func() error) error {
+	host := callbackHost(callbackURL)
+	breaker := b.breaker(host)
+	wasOpen := breaker.GetState() == circuitbreaker.StateOpen
+
+	err := breaker.Execute(ctx, fn)
+	if stderrors.Is(err, circuitbreaker.ErrCircuitBreakerOpen) || stderrors.Is(err, circuitbreaker.ErrCircuitBreakerHalfOpen) {
+		return errors.WrapDomainError(err, 65011, "callback target unavailable", host).WithRetryable(true)
+	}
+
+	b.mu.Lock()
+	if err != nil {
+		b.lastErrs[host] = err.Error()
+	} else {
+		delete(b.lastErrs, host)
+	}
+	b.mu.Unlock()
+
+	isOpen := breaker.GetState() == circuitbreaker.StateOpen
+	if isOpen != wasOpen {
+		if isOpen {
+			b.logger.Warn("BAP callback circuit opened", zap.String("host", host), zap.Error(err))
+		} else {
+			b.logger.Info("BAP callback circuit closed", zap.String("host", host))
+		}
+	}
+	if b.health != nil {
+		b.health.SetDependencyHealth(bapDependencyPrefix+host, !isOpen)
+	}
+
+	return err
+}
+
+// IsOpen reports whether callbacks to callbackURL's host are currently rejected
+func (b *BAPBreakers) IsOpen(callbackURL string) bool {
+	b.mu.Lock()
+	breaker, ok := b.breakers[callbackHost(callbackURL)]
+	b.mu.Unlock()
+	return ok && breaker.IsOpen()
+}
+
+// Unreachable lists the hosts whose circuit is not closed, most recent failure first
+func (b *BAPBreakers) Unreachable() []BAPHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	unreachable := make([]BAPHealth, 0)
+	for host, breaker := range b.breakers {
+		state := breaker.GetState()
+		if state == circuitbreaker.StateClosed {
+			continue
+		}
+		unreachable = append(unreachable, BAPHealth{
+			Host:          host,
+			State:         state.String(),
+			Failures:      breaker.GetFailureCount(),
+			LastError:     b.lastErrs[host],
+			LastFailureAt: breaker.GetLastFailure(),
+		})
+	}
+
+	sort.Slice(unreachable, func(i, j int) bool {
+		return unreachable[i].LastFailureAt.After(unreachable[j].LastFailureAt)
+	})
+	return unreachable
+}
+
+// breaker returns the breaker for host, creating it on first use
+func (b *BAPBreakers) breaker(host string) *circuitbreaker.CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker, ok := b.breakers[host]
+	if !ok {
+		breaker = circuitbreaker.NewCircuitBreaker(b.config)
+		b.breakers[host] = breaker
+	}
+	return breaker
+}
+
+// IsBAPUnavailable reports whether err was returned because the BAP's circuit is open
+func IsBAPUnavailable(err error) bool {
+	return stderrors.Is(err, circuitbreaker.ErrCircuitBreakerOpen) || stderrors.Is(err, circuitbreaker.ErrCircuitBreakerHalfOpen)
+}
+
+// callbackHost keys breakers by host so every callback path of one BAP shares a circuit
+func callbackHost(callbackURL string) string {
+	parsed, err := url.Parse(callbackURL)
+	if err != nil || parsed.Host == "" {
+		return callbackURL
+	}
+	return parsed.Host
+}
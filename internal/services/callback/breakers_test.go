@@ -0,0 +1,269 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockHealthRecorder is a mock implementation of HealthRecorder
+type MockHealthRecorder struct {
+	mock.Mock
+}
+
+func (m *MockHealthRecorder) SetDependencyHealth(dependency string, healthy bool) {
+	m.Called(dependency, healthy)
+}
+
+func testBreakerConfig() config.CallbackConfig {
+	return config.CallbackConfig{
+		HTTPTimeoutSeconds: 5,
+		BreakerThreshold:   2,
+		BreakerOpenSeconds: 60,
+		BreakerMaxDefer:    900,
+	}
+}
+
+func TestBAPBreakers_OpensPerHost(t *testing.T) {
+	health := new(MockHealthRecorder)
+	health.On("SetDependencyHealth", "bap:down.example.com", true).Once()
+	health.On("SetDependencyHealth", "bap:down.example.com", false).Once()
+	health.On("SetDependencyHealth", "bap:up.example.com", true).Once()
+	breakers := NewBAPBreakers(testBreakerConfig(), health, zap.NewNop())
+
+	failure := errors.NewDomainError(65020, "callback delivery failed", "unexpected status: 503")
+	for i := 0; i < 2; i++ {
+		err := breakers.Execute(context.Background(), "https://down.example.com/on_search", func() error { return failure })
+		assert.Equal(t, failure, err)
+	}
+
+	called := false
+	err := breakers.Execute(context.Background(), "https://down.example.com/on_status", func() error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+	assert.True(t, IsBAPUnavailable(err))
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65011, domainErr.Code)
+	assert.True(t, breakers.IsOpen("https://down.example.com/on_cancel"))
+
+	// Other BAPs are unaffected
+	assert.NoError(t, breakers.Execute(context.Background(), "https://up.example.com/on_search", func() error { return nil }))
+	assert.False(t, breakers.IsOpen("https://up.example.com/on_search"))
+
+	unreachable := breakers.Unreachable()
+	assert.Len(t, unreachable, 1)
+	assert.Equal(t, "down.example.com", unreachable[0].Host)
+	assert.Equal(t, "open", unreachable[0].State)
+	assert.Contains(t, unreachable[0].LastError, "unexpected status: 503")
+	health.AssertExpectations(t)
+}
+
+func TestCallbackService_ClientErrorsDoNotOpenCircuit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	service := NewService(testBreakerConfig(), nil, zap.NewNop())
+	service.breakers = NewBAPBreakers(testBreakerConfig(), nil, zap.NewNop())
+
+	for i := 0; i < 3; i++ {
+		_, err := service.SendCallback(context.Background(), server.URL, map[string]interface{}{"test": "data"})
+		assert.Error(t, err)
+		assert.False(t, IsBAPUnavailable(err))
+	}
+	assert.False(t, service.breakers.IsOpen(server.URL))
+}
+
+func TestRetryService_SendCallbackWithRetry_OpenCircuitGoesToDLQ(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := testBreakerConfig()
+	cfg.DLQEnabled = true
+	cfg.DLQStream = "stream.uois.callback_dlq"
+	retryConfig := config.RetryConfig{CallbackMaxRetries: 5, CallbackBackoff: []int{1, 1, 1, 1}}
+
+	mockRedis := new(MockRedisClient)
+	mockRedis.On("XAdd", mock.Anything, mock.MatchedBy(func(a *redis.XAddArgs) bool {
+		return a.Stream == "stream.uois.callback_dlq"
+	})).Return(redis.NewStringCmd(context.Background())).Once()
+
+	mockAudit := new(MockAuditService)
+	mockAudit.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	service := NewService(cfg, nil, zap.NewNop())
+	service.breakers = NewBAPBreakers(cfg, nil, zap.NewNop())
+	retryService := NewRetryService(service, retryConfig, cfg, mockRedis, mockAudit, zap.NewNop())
+
+	// Open the circuit with two direct failures
+	for i := 0; i < 2; i++ {
+		_ = service.SendCallbackDirect(context.Background(), server.URL, map[string]interface{}{"test": "data"})
+	}
+	assert.Equal(t, 2, attempts)
+
+	start := time.Now()
+	err := retryService.SendCallbackWithRetry(context.Background(), server.URL, map[string]interface{}{"test": "data"}, "req-123", 30)
+
+	assert.Error(t, err)
+	assert.True(t, IsBAPUnavailable(err))
+	assert.Equal(t, 2, attempts)
+	assert.Less(t, time.Since(start), time.Second)
+	mockRedis.AssertExpectations(t)
+	mockAudit.AssertNumberOfCalls(t, "LogCallbackDelivery", 1)
+}
+
+func TestRetryService_SendCallbackOrDefer_OpenCircuitIsDeferred(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := testBreakerConfig()
+	cfg.DLQEnabled = true
+	cfg.DLQStream = "stream.uois.callback_dlq"
+	retryConfig := config.RetryConfig{CallbackMaxRetries: 5, CallbackBackoff: []int{1, 1, 1, 1}}
+
+	mockRedis := new(MockRedisClient)
+	mockAudit := new(MockAuditService)
+	mockAudit.On("LogCallbackDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	service := NewService(cfg, nil, zap.NewNop())
+	service.breakers = NewBAPBreakers(cfg, nil, zap.NewNop())
+	retryService := NewRetryService(service, retryConfig, cfg, mockRedis, mockAudit, zap.NewNop())
+
+	for i := 0; i < 2; i++ {
+		_ = service.SendCallbackDirect(context.Background(), server.URL, map[string]interface{}{"test": "data"})
+	}
+
+	err := retryService.SendCallbackOrDefer(context.Background(), server.URL, map[string]interface{}{"test": "data"}, "req-123", time.Time{})
+
+	assert.ErrorIs(t, err, ErrCallbackDeferred)
+	assert.Equal(t, 2, attempts)
+	mockRedis.AssertNotCalled(t, "XAdd", mock.Anything, mock.Anything)
+}
+
+func TestQueue_Process_LeavesCallbackRejectedMidDeliveryPending(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	var deferrable []bool
+	deliver := func(ctx context.Context, callbackURL string, payload interface{}, requestID string, canDefer bool) error {
+		deferrable = append(deferrable, canDefer)
+		return ErrCallbackDeferred
+	}
+
+	cfg := testQueueConfig()
+	cfg.BreakerMaxDefer = 900
+	queue := NewQueue(mockRedis, cfg, "uois-gateway-consumers", "consumer-1", deliver, zap.NewNop())
+	queue.breakers = NewBAPBreakers(cfg, nil, zap.NewNop())
+
+	job, _ := parseQueuedCallback(queuedMessage("1-0"))
+	job.enqueuedAt = time.Now()
+	queue.process(job)
+
+	assert.Equal(t, []bool{true}, deferrable)
+	mockRedis.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestQueue_Process_DefersCallbackWhileCircuitOpen(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	delivered := false
+	deliver := func(ctx context.Context, callbackURL string, payload interface{}, requestID string, deferrable bool) error {
+		delivered = true
+		assert.False(t, deferrable)
+		return nil
+	}
+
+	cfg := testQueueConfig()
+	cfg.BreakerThreshold = 1
+	cfg.BreakerOpenSeconds = 60
+	cfg.BreakerMaxDefer = 900
+	queue := NewQueue(mockRedis, cfg, "uois-gateway-consumers", "consumer-1", deliver, zap.NewNop())
+	queue.breakers = NewBAPBreakers(cfg, nil, zap.NewNop())
+	_ = queue.breakers.Execute(context.Background(), "https://bap.example.com/on_search", func() error {
+		return errors.NewDomainError(65020, "callback delivery failed", "http request failed")
+	})
+
+	job, _ := parseQueuedCallback(queuedMessage("1-0"))
+	job.enqueuedAt = time.Now()
+	queue.process(job)
+
+	assert.False(t, delivered)
+	mockRedis.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// Past the max deferral the callback is delivered (and dead-lettered by RetryService if still down)
+	expectAckAndDelete(mockRedis, "1-0")
+	job.enqueuedAt = time.Now().Add(-time.Hour)
+	job.body = fmt.Sprintf(`{"context":{"timestamp":%q,"ttl":"PT2H"}}`, job.enqueuedAt.UTC().Format(time.RFC3339))
+	queue.process(job)
+
+	assert.True(t, delivered)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestQueue_Process_DeadLettersCallbackPastTTL(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	deliver := func(ctx context.Context, callbackURL string, payload interface{}, requestID string, deferrable bool) error {
+		t.Fatal("expired callback must not be sent")
+		return nil
+	}
+
+	cfg := testQueueConfig()
+	cfg.BreakerThreshold = 1
+	cfg.BreakerMaxDefer = 900
+	queue := NewQueue(mockRedis, cfg, "uois-gateway-consumers", "consumer-1", deliver, zap.NewNop())
+	queue.breakers = NewBAPBreakers(cfg, nil, zap.NewNop())
+	var expired []time.Time
+	queue.expire = func(ctx context.Context, callbackURL string, payload interface{}, requestID string, deadline time.Time) error {
+		expired = append(expired, deadline)
+		return nil
+	}
+	_ = queue.breakers.Execute(context.Background(), "https://bap.example.com/on_search", func() error {
+		return errors.NewDomainError(65020, "callback delivery failed", "http request failed")
+	})
+
+	// Deferred for 5 minutes, well within the max deferral, but its PT30S TTL has elapsed
+	job, _ := parseQueuedCallback(queuedMessage("1-0"))
+	job.enqueuedAt = time.Now().Add(-5 * time.Minute)
+	timestamp := job.enqueuedAt.UTC().Truncate(time.Second)
+	job.body = fmt.Sprintf(`{"context":{"timestamp":%q,"ttl":"PT30S"}}`, timestamp.Format(time.RFC3339))
+	expectAckAndDelete(mockRedis, "1-0")
+
+	queue.process(job)
+
+	assert.Equal(t, []time.Time{timestamp.Add(30 * time.Second)}, expired)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestQueue_Process_FailedExpiryStaysPending(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	queue := NewQueue(mockRedis, testQueueConfig(), "uois-gateway-consumers", "consumer-1", nil, zap.NewNop())
+	queue.expire = func(ctx context.Context, callbackURL string, payload interface{}, requestID string, deadline time.Time) error {
+		return assert.AnError
+	}
+
+	job, _ := parseQueuedCallback(queuedMessage("1-0"))
+	job.enqueuedAt = time.Now().Add(-time.Minute)
+	queue.process(job)
+
+	mockRedis.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -3,14 +3,14 @@ package callback
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
 	"uois-gateway/internal/config"
+	"uois-gateway/internal/services/ondc"
 	"uois-gateway/pkg/errors"
 
 	"go.uber.org/zap"
@@ -24,6 +24,8 @@ type Service struct {
 	retryConfig  config.RetryConfig
 	signer       Signer // ONDC-required: HTTP signature generator
 	retryService *RetryService
+	queue        *Queue       // Durable delivery queue; nil delivers inline
+	breakers     *BAPBreakers // Per-BAP circuit breakers; nil disables them
 	logger       *zap.Logger
 	useRetry     bool
 }
@@ -32,13 +34,11 @@ type Service struct {
 // signer can be nil for testing, but production must provide a valid signer
 func NewService(cfg config.CallbackConfig, signer Signer, logger *zap.Logger) *Service {
 	return &Service{
-		httpClient: &http.Client{
-			Timeout: time.Duration(cfg.HTTPTimeoutSeconds) * time.Second,
-		},
-		config:   cfg,
-		signer:   signer,
-		logger:   logger,
-		useRetry: false,
+		httpClient: newHTTPClient(cfg),
+		config:     cfg,
+		signer:     signer,
+		logger:     logger,
+		useRetry:   false,
 	}
 }
 
@@ -52,9 +52,7 @@ func NewServiceWithRetry(
 	logger *zap.Logger,
 ) *Service {
 	baseService := &Service{
-		httpClient: &http.Client{
-			Timeout: time.Duration(cfg.HTTPTimeoutSeconds) * time.Second,
-		},
+		httpClient:  newHTTPClient(cfg),
 		config:      cfg,
 		retryConfig: retryCfg,
 		signer:      signer,
@@ -69,26 +67,97 @@ func NewServiceWithRetry(
 	return baseService
 }
 
+// NewQueuedService creates a callback service that enqueues callbacks on the durable callback queue
+// Delivery (retry, audit, DLQ) runs in the queue's worker pool; start it with Queue().Run
+// Deliveries go through per-BAP circuit breakers whose state is published to health
+func NewQueuedService(
+	cfg config.CallbackConfig,
+	retryCfg config.RetryConfig,
+	signer Signer,
+	redis QueueRedisClient,
+	auditService AuditService,
+	health HealthRecorder,
+	group string,
+	consumer string,
+	logger *zap.Logger,
+) *Service {
+	s := NewServiceWithRetry(cfg, retryCfg, signer, redis, auditService, logger)
+	s.breakers = NewBAPBreakers(cfg, health, logger)
+	if cfg.QueueStream != "" {
+		s.queue = NewQueue(redis, cfg, group, consumer, s.deliver, logger)
+		s.queue.breakers = s.breakers
+		s.queue.expire = s.expire
+	}
+	return s
+}
+
+// Queue returns the durable callback queue, or nil when callbacks are delivered inline
+func (s *Service) Queue() *Queue {
+	return s.queue
+}
+
+// Breakers returns the per-BAP circuit breakers, or nil when they are disabled
+func (s *Service) Breakers() *BAPBreakers {
+	return s.breakers
+}
+
 // SendCallbackDirect implements CallbackSender interface for retry service
 func (s *Service) SendCallbackDirect(ctx context.Context, callbackURL string, payload interface{}) error {
 	return s.sendCallbackDirect(ctx, callbackURL, payload)
 }
 
 // SendCallback sends a callback to the specified URL
-// ONDC requires: Digest header (SHA-256) and Authorization header (HTTP signature)
-// If retry is enabled, this will automatically retry with exponential backoff
-func (s *Service) SendCallback(ctx context.Context, callbackURL string, payload interface{}) error {
+// ONDC requires: Digest header (BLAKE-512) and Authorization header (HTTP signature)
+// With a queue the callback is enqueued and delivered by the worker pool (see EnqueueCallback);
+// otherwise it is delivered inline, retrying with exponential backoff if retry is enabled
+// It returns the queue's delivery id, or "" for an inline delivery
+func (s *Service) SendCallback(ctx context.Context, callbackURL string, payload interface{}) (string, error) {
+	if s.queue != nil {
+		return s.EnqueueCallback(ctx, callbackURL, payload)
+	}
+
 	// Extract request ID from context if available, otherwise generate one
-	requestID := s.extractRequestID(ctx)
-	ttlSeconds := 30 // Default TTL for callbacks
+	return "", s.deliver(ctx, callbackURL, payload, s.extractRequestID(ctx), false)
+}
+
+// EnqueueCallback persists the callback on the durable queue and returns its delivery id
+func (s *Service) EnqueueCallback(ctx context.Context, callbackURL string, payload interface{}) (string, error) {
+	if s.queue == nil {
+		return "", errors.NewDomainError(65020, "callback enqueue failed", "callback queue not configured")
+	}
+	return s.queue.Enqueue(ctx, callbackURL, payload, s.extractRequestID(ctx))
+}
 
+// deliver sends one callback, with retry and DLQ when retry is enabled
+// A deferrable callback rejected by the BAP's circuit returns ErrCallbackDeferred instead of being dead-lettered
+func (s *Service) deliver(ctx context.Context, callbackURL string, payload interface{}, requestID string, deferrable bool) error {
 	// Use retry service if enabled
 	if s.useRetry && s.retryService != nil {
-		return s.retryService.SendCallbackWithRetry(ctx, callbackURL, payload, requestID, ttlSeconds)
+		deadline := callbackDeadline(payload, time.Now())
+		if deferrable {
+			return s.retryService.SendCallbackOrDefer(ctx, callbackURL, payload, requestID, deadline)
+		}
+		return s.retryService.SendCallbackWithDeadline(ctx, callbackURL, payload, requestID, deadline)
 	}
 
 	// Fallback to direct call without retry
-	return s.sendCallbackDirect(ctx, callbackURL, payload)
+	err := s.sendCallbackDirect(ctx, callbackURL, payload)
+	if deferrable && IsBAPUnavailable(err) {
+		return fmt.Errorf("%w: %v", ErrCallbackDeferred, err)
+	}
+	return err
+}
+
+// expire dead-letters a queued callback whose TTL elapsed before it was delivered
+func (s *Service) expire(ctx context.Context, callbackURL string, payload interface{}, requestID string, deadline time.Time) error {
+	if s.retryService == nil {
+		s.logger.Warn("callback TTL elapsed before delivery, dropping",
+			zap.String("request_id", requestID),
+			zap.Time("deadline", deadline),
+		)
+		return nil
+	}
+	return s.retryService.DeadLetterExpired(ctx, callbackURL, payload, requestID, deadline)
 }
 
 // sendCallbackDirect sends a callback without retry logic
@@ -106,7 +175,7 @@ func (s *Service) sendCallbackDirect(ctx context.Context, callbackURL string, pa
 	// Set Content-Type header (required for ONDC signature)
 	req.Header.Set("Content-Type", "application/json")
 
-	// Calculate and set Digest header (ONDC requirement: SHA-256 hash of body)
+	// Calculate and set Digest header (matches the digest covered by the ONDC signature)
 	digest := s.calculateDigest(body)
 	req.Header.Set("Digest", digest)
 
@@ -128,19 +197,47 @@ func (s *Service) sendCallbackDirect(ctx context.Context, callbackURL string, pa
 		s.logger.Warn("callback sent without HTTP signature (signer not provided)", zap.String("url", callbackURL))
 	}
 
-	resp, err := s.httpClient.Do(req)
+	// Only transport errors and 5xx count against the BAP's circuit; a 4xx or NACK means the BAP is up
+	var statusCode int
+	var respBody []byte
+	err = s.guard(ctx, callbackURL, func() error {
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return errors.WrapDomainError(err, 65020, "callback delivery failed", "http request failed")
+		}
+		defer resp.Body.Close()
+
+		statusCode = resp.StatusCode
+		if statusCode >= 500 {
+			return errors.NewDomainError(65020, "callback delivery failed", fmt.Sprintf("unexpected status: %d", statusCode))
+		}
+		respBody, _ = io.ReadAll(io.LimitReader(resp.Body, maxAckBodyBytes))
+		return nil
+	})
 	if err != nil {
-		return errors.WrapDomainError(err, 65020, "callback delivery failed", "http request failed")
+		return err
+	}
+
+	// A 2xx can still carry a NACK; BAPs also NACK with 4xx
+	if nack := parseNACK(respBody); nack != nil {
+		return nackDeliveryError(nack)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return errors.NewDomainError(65020, "callback delivery failed", fmt.Sprintf("unexpected status: %d", resp.StatusCode))
+	if statusCode < 200 || statusCode >= 300 {
+		return errors.NewDomainError(65020, "callback delivery failed", fmt.Sprintf("unexpected status: %d", statusCode))
 	}
 
 	return nil
 }
 
+// guard runs a delivery through the callback host's circuit breaker, if breakers are configured
+func (s *Service) guard(ctx context.Context, callbackURL string, fn func() error) error {
+	if s.breakers == nil {
+		return fn()
+	}
+	return s.breakers.Execute(ctx, callbackURL, fn)
+}
+
 // extractRequestID extracts request ID from context or generates one
 func (s *Service) extractRequestID(ctx context.Context) string {
 	if requestID, ok := ctx.Value("request_id").(string); ok && requestID != "" {
@@ -152,10 +249,8 @@ func (s *Service) extractRequestID(ctx context.Context) string {
 	return fmt.Sprintf("req-%d", time.Now().UnixNano())
 }
 
-// calculateDigest computes SHA-256 digest of the body in ONDC format
-// Format: "SHA-256=<base64(sha256(body))>"
+// calculateDigest computes the BLAKE-512 digest of the body in ONDC format
+// Format: "BLAKE-512=<base64(blake2b-512(body))>"
 func (s *Service) calculateDigest(body []byte) string {
-	hash := sha256.Sum256(body)
-	digest := base64.StdEncoding.EncodeToString(hash[:])
-	return fmt.Sprintf("SHA-256=%s", digest)
+	return ondc.CalculateDigest(body)
 }
@@ -2,7 +2,6 @@ package callback
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"net/http"
@@ -16,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/blake2b"
 )
 
 // MockSigner is a mock implementation of Signer interface
@@ -46,7 +46,7 @@ func TestCallbackService_SendCallback_Success(t *testing.T) {
 		// Verify ONDC-required headers
 		digest := r.Header.Get("Digest")
 		assert.NotEmpty(t, digest, "Digest header must be present")
-		assert.True(t, strings.HasPrefix(digest, "SHA-256="), "Digest must use SHA-256")
+		assert.True(t, strings.HasPrefix(digest, "BLAKE-512="), "Digest must use BLAKE-512")
 
 		auth := r.Header.Get("Authorization")
 		assert.NotEmpty(t, auth, "Authorization header must be present")
@@ -65,7 +65,7 @@ func TestCallbackService_SendCallback_Success(t *testing.T) {
 		"message": "test",
 	}
 
-	err := service.SendCallback(context.Background(), server.URL, payload)
+	_, err := service.SendCallback(context.Background(), server.URL, payload)
 	assert.NoError(t, err)
 	mockSigner.AssertExpectations(t)
 }
@@ -97,14 +97,14 @@ func TestCallbackService_SendCallback_WithDigest(t *testing.T) {
 		},
 	}
 
-	err := service.SendCallback(context.Background(), server.URL, payload)
+	_, err := service.SendCallback(context.Background(), server.URL, payload)
 	assert.NoError(t, err)
 
 	// Verify digest matches body
 	expectedBody, _ := json.Marshal(payload)
-	hash := sha256.Sum256(expectedBody)
-	expectedDigest := "SHA-256=" + base64.StdEncoding.EncodeToString(hash[:])
-	assert.Equal(t, expectedDigest, receivedDigest, "Digest must match SHA-256 hash of body")
+	hash := blake2b.Sum512(expectedBody)
+	expectedDigest := "BLAKE-512=" + base64.StdEncoding.EncodeToString(hash[:])
+	assert.Equal(t, expectedDigest, receivedDigest, "Digest must match BLAKE-512 hash of body")
 }
 
 func TestCallbackService_SendCallback_ServerError(t *testing.T) {
@@ -129,7 +129,7 @@ func TestCallbackService_SendCallback_ServerError(t *testing.T) {
 		"message": "test",
 	}
 
-	err := service.SendCallback(context.Background(), server.URL, payload)
+	_, err := service.SendCallback(context.Background(), server.URL, payload)
 	assert.Error(t, err)
 	mockSigner.AssertExpectations(t)
 }
@@ -150,7 +150,7 @@ func TestCallbackService_SendCallback_SignerError(t *testing.T) {
 		"message": "test",
 	}
 
-	err := service.SendCallback(context.Background(), "https://example.com/callback", payload)
+	_, err := service.SendCallback(context.Background(), "https://example.com/callback", payload)
 	assert.Error(t, err)
 	mockSigner.AssertExpectations(t)
 }
@@ -178,7 +178,7 @@ func TestCallbackService_SendCallback_Timeout(t *testing.T) {
 		"message": "test",
 	}
 
-	err := service.SendCallback(context.Background(), server.URL, payload)
+	_, err := service.SendCallback(context.Background(), server.URL, payload)
 	assert.Error(t, err)
 	mockSigner.AssertExpectations(t)
 }
@@ -199,7 +199,7 @@ func TestCallbackService_SendCallback_InvalidURL(t *testing.T) {
 		"message": "test",
 	}
 
-	err := service.SendCallback(context.Background(), "invalid-url", payload)
+	_, err := service.SendCallback(context.Background(), "invalid-url", payload)
 	assert.Error(t, err)
 	mockSigner.AssertExpectations(t)
 }
@@ -220,7 +220,7 @@ func TestCallbackService_SendCallback_SerializationFailure(t *testing.T) {
 	circular := &Circular{}
 	circular.Self = circular
 
-	err := service.SendCallback(context.Background(), "https://example.com/callback", circular)
+	_, err := service.SendCallback(context.Background(), "https://example.com/callback", circular)
 	assert.Error(t, err)
 }
 
@@ -253,7 +253,7 @@ func TestCallbackService_SendCallback_ValidJSON(t *testing.T) {
 		},
 	}
 
-	err := service.SendCallback(context.Background(), server.URL, payload)
+	_, err := service.SendCallback(context.Background(), server.URL, payload)
 	assert.NoError(t, err)
 	assert.Equal(t, "ORDER123", receivedPayload["order"].(map[string]interface{})["id"])
 	mockSigner.AssertExpectations(t)
@@ -0,0 +1,370 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/services/audit"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// dlqScanPage is the number of DLQ entries read per XRANGE while filtering
+	dlqScanPage = 500
+	// defaultDLQListLimit caps a DLQ listing when the caller sets no limit
+	defaultDLQListLimit = 100
+
+	// Audit statuses recorded in audit.callback_delivery_logs for DLQ redrives
+	DLQRedriveStatusSuccess = "redrive_success"
+	DLQRedriveStatusFailed  = "redrive_failed"
+
+	// Redrive result statuses for entries that were never sent
+	DLQRedriveStatusNotFound  = "not_found"
+	DLQRedriveStatusInvalidID = "invalid_id"
+	DLQRedriveStatusMalformed = "malformed"
+
+	// Reasons a callback was dead-lettered
+	DLQReasonRetriesExhausted = "retries_exhausted"
+	DLQReasonTTLExpired       = "ttl_expired"
+)
+
+// DLQClient interface for the Redis stream operations behind the callback DLQ
+type DLQClient interface {
+	XRangeN(ctx context.Context, stream, start, stop string, count int64) *redis.XMessageSliceCmd
+	XDel(ctx context.Context, stream string, ids ...string) *redis.IntCmd
+}
+
+// DLQEntry is one exhausted callback written by RetryService.sendToDLQ
+type DLQEntry struct {
+	ID          string          `json:"id"`
+	RequestID   string          `json:"request_id"`
+	CallbackURL string          `json:"callback_url"`
+	BapID       string          `json:"bap_id,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	Error       string          `json:"error"`
+	Reason      string          `json:"reason,omitempty"`
+	Retries     int             `json:"retries"`
+	FailedAt    time.Time       `json:"failed_at"`
+}
+
+// DLQFilter selects DLQ entries; empty fields match everything
+type DLQFilter struct {
+	RequestID   string
+	CallbackURL string
+	BapID       string
+	From        time.Time
+	To          time.Time
+	Limit       int
+}
+
+// DLQRedriveResult reports the outcome of redriving one DLQ entry
+type DLQRedriveResult struct {
+	ID        string `json:"id"`
+	RequestID string `json:"request_id,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DLQService reads, redrives and purges the callback DLQ (CALLBACK_DLQ_STREAM)
+// Redrives send the stored payload once, with a fresh context.timestamp and a new ONDC signature;
+// a redriven entry is deleted, a failed one stays in the DLQ for another attempt.
+type DLQService struct {
+	rdb          DLQClient
+	stream       string
+	sender       CallbackSender
+	auditService AuditService
+	logger       *zap.Logger
+}
+
+// NewDLQService creates a DLQ service over cfg.DLQStream
+func NewDLQService(rdb DLQClient, cfg config.CallbackConfig, sender CallbackSender, auditService AuditService, logger *zap.Logger) *DLQService {
+	return &DLQService{
+		rdb:          rdb,
+		stream:       cfg.DLQStream,
+		sender:       sender,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// List returns DLQ entries matching filter, oldest first
+func (s *DLQService) List(ctx context.Context, filter DLQFilter) ([]DLQEntry, error) {
+	if s.stream == "" {
+		return nil, errors.NewDomainError(65020, "dlq not configured", "CALLBACK_DLQ_STREAM is empty")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultDLQListLimit
+	}
+
+	// Stream IDs are millisecond timestamps, so the time range is applied by XRANGE itself
+	start, stop := "-", "+"
+	if !filter.From.IsZero() {
+		start = strconv.FormatInt(filter.From.UnixMilli(), 10)
+	}
+	if !filter.To.IsZero() {
+		stop = strconv.FormatInt(filter.To.UnixMilli(), 10)
+	}
+
+	entries := make([]DLQEntry, 0)
+	for {
+		messages, err := s.rdb.XRangeN(ctx, s.stream, start, stop, dlqScanPage).Result()
+		if err != nil {
+			return nil, errors.WrapDomainError(err, 65011, "dlq read failed", "redis error")
+		}
+
+		for _, msg := range messages {
+			entry, err := parseDLQEntry(msg)
+			if err != nil {
+				s.logger.Warn("skipping malformed DLQ entry", zap.String("id", msg.ID), zap.Error(err))
+				continue
+			}
+			if !filter.matches(entry) {
+				continue
+			}
+			entries = append(entries, entry)
+			if len(entries) >= limit {
+				return entries, nil
+			}
+		}
+
+		if len(messages) < dlqScanPage {
+			return entries, nil
+		}
+		start = "(" + messages[len(messages)-1].ID
+	}
+}
+
+// Get returns one DLQ entry by stream ID
+func (s *DLQService) Get(ctx context.Context, id string) (*DLQEntry, error) {
+	if s.stream == "" {
+		return nil, errors.NewDomainError(65020, "dlq not configured", "CALLBACK_DLQ_STREAM is empty")
+	}
+
+	messages, err := s.rdb.XRangeN(ctx, s.stream, id, id, 1).Result()
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65011, "dlq read failed", "redis error")
+	}
+	if len(messages) == 0 {
+		return nil, errors.NewDomainError(65006, "dlq entry not found", id)
+	}
+
+	entry, err := parseDLQEntry(messages[0])
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "dlq entry malformed", id)
+	}
+	return &entry, nil
+}
+
+// Redrive re-sends the given entries and records each attempt in the callback delivery audit log
+// Unknown, malformed or invalid IDs are reported per entry; only Redis failures fail the batch
+func (s *DLQService) Redrive(ctx context.Context, ids []string) ([]DLQRedriveResult, error) {
+	if s.stream == "" {
+		return nil, errors.NewDomainError(65020, "dlq not configured", "CALLBACK_DLQ_STREAM is empty")
+	}
+
+	results := make([]DLQRedriveResult, 0, len(ids))
+	for _, id := range ids {
+		if !isStreamID(id) {
+			results = append(results, DLQRedriveResult{ID: id, Status: DLQRedriveStatusInvalidID, Error: "not a stream entry id"})
+			continue
+		}
+
+		entry, err := s.Get(ctx, id)
+		if err != nil {
+			domainErr, ok := err.(*errors.DomainError)
+			switch {
+			case ok && domainErr.Code == 65006:
+				results = append(results, DLQRedriveResult{ID: id, Status: DLQRedriveStatusNotFound})
+				continue
+			case ok && domainErr.Code == 65020:
+				results = append(results, DLQRedriveResult{ID: id, Status: DLQRedriveStatusMalformed, Error: err.Error()})
+				continue
+			}
+			return results, err
+		}
+		results = append(results, s.redrive(ctx, entry))
+	}
+	return results, nil
+}
+
+// Purge deletes the given entries and returns how many were removed
+func (s *DLQService) Purge(ctx context.Context, ids []string) (int64, error) {
+	if s.stream == "" {
+		return 0, errors.NewDomainError(65020, "dlq not configured", "CALLBACK_DLQ_STREAM is empty")
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	deleted, err := s.rdb.XDel(ctx, s.stream, ids...).Result()
+	if err != nil {
+		return 0, errors.WrapDomainError(err, 65011, "dlq purge failed", "redis error")
+	}
+
+	s.logger.Info("purged callback DLQ entries", zap.Strings("ids", ids), zap.Int64("deleted", deleted))
+	return deleted, nil
+}
+
+// redrive sends one entry; the sender signs the refreshed body, so the signature is always new
+func (s *DLQService) redrive(ctx context.Context, entry *DLQEntry) DLQRedriveResult {
+	result := DLQRedriveResult{ID: entry.ID, RequestID: entry.RequestID, Status: DLQRedriveStatusSuccess}
+
+	payload, err := refreshContextTimestamp(entry.Payload, time.Now().UTC())
+	if err == nil {
+		err = s.sender.SendCallbackDirect(ctx, entry.CallbackURL, payload)
+	}
+	if err != nil {
+		result.Status = DLQRedriveStatusFailed
+		result.Error = err.Error()
+	}
+
+	if s.auditService != nil {
+		logCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		_ = s.auditService.LogCallbackDelivery(logCtx, &audit.CallbackDeliveryLogParams{
+			RequestID:   entry.RequestID,
+			CallbackURL: entry.CallbackURL,
+			AttemptNo:   entry.Retries + 1,
+			Status:      result.Status,
+			Error:       result.Error,
+		})
+		cancel()
+	}
+
+	if err != nil {
+		s.logger.Warn("callback DLQ redrive failed",
+			zap.String("id", entry.ID),
+			zap.String("request_id", entry.RequestID),
+			zap.Error(err),
+		)
+		return result
+	}
+
+	if err := s.rdb.XDel(ctx, s.stream, entry.ID).Err(); err != nil {
+		s.logger.Warn("failed to delete redriven DLQ entry", zap.String("id", entry.ID), zap.Error(err))
+	}
+
+	s.logger.Info("callback DLQ entry redriven",
+		zap.String("id", entry.ID),
+		zap.String("request_id", entry.RequestID),
+		zap.String("callback_url", entry.CallbackURL),
+	)
+	return result
+}
+
+// isStreamID reports whether id is a complete stream entry ID ("<ms>-<seq>") or a bare millisecond timestamp
+func isStreamID(id string) bool {
+	ms, seq, hasSeq := strings.Cut(id, "-")
+	if _, err := strconv.ParseUint(ms, 10, 64); err != nil {
+		return false
+	}
+	if hasSeq {
+		if _, err := strconv.ParseUint(seq, 10, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// matches applies the non-time filters (the time range is applied by XRANGE)
+func (f DLQFilter) matches(entry DLQEntry) bool {
+	if f.RequestID != "" && entry.RequestID != f.RequestID {
+		return false
+	}
+	if f.CallbackURL != "" && entry.CallbackURL != f.CallbackURL {
+		return false
+	}
+	if f.BapID != "" && entry.BapID != f.BapID {
+		return false
+	}
+	return true
+}
+
+// parseDLQEntry decodes the "data" field written by RetryService.sendToDLQ
+func parseDLQEntry(msg redis.XMessage) (DLQEntry, error) {
+	data, _ := msg.Values["data"].(string)
+	if data == "" {
+		return DLQEntry{}, fmt.Errorf("missing data field")
+	}
+
+	var stored struct {
+		RequestID   string          `json:"request_id"`
+		CallbackURL string          `json:"callback_url"`
+		Payload     json.RawMessage `json:"payload"`
+		Error       string          `json:"error"`
+		Reason      string          `json:"reason"`
+		Timestamp   int64           `json:"timestamp"`
+		Retries     int             `json:"retries"`
+	}
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return DLQEntry{}, err
+	}
+	if stored.CallbackURL == "" || len(stored.Payload) == 0 {
+		return DLQEntry{}, fmt.Errorf("missing callback_url or payload")
+	}
+
+	// The BAP is not stored separately; every ONDC callback carries it in context.bap_id
+	var envelope struct {
+		Context struct {
+			BapID string `json:"bap_id"`
+		} `json:"context"`
+	}
+	_ = json.Unmarshal(stored.Payload, &envelope)
+
+	return DLQEntry{
+		ID:          msg.ID,
+		RequestID:   stored.RequestID,
+		CallbackURL: stored.CallbackURL,
+		BapID:       envelope.Context.BapID,
+		Payload:     stored.Payload,
+		Error:       stored.Error,
+		Reason:      stored.Reason,
+		Retries:     stored.Retries,
+		FailedAt:    time.Unix(stored.Timestamp, 0).UTC(),
+	}, nil
+}
+
+// refreshContextTimestamp sets context.timestamp to now and keeps every other value
+// The payload is re-serialized (object keys sorted, whitespace dropped); that is safe because the redrive is signed afresh
+// BAPs reject callbacks whose context timestamp is older than the request TTL
+func refreshContextTimestamp(payload json.RawMessage, now time.Time) (json.RawMessage, error) {
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "dlq payload malformed", "payload is not a JSON object")
+	}
+
+	rawContext, ok := body["context"]
+	if !ok {
+		return payload, nil
+	}
+
+	var callbackCtx map[string]json.RawMessage
+	if err := json.Unmarshal(rawContext, &callbackCtx); err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "dlq payload malformed", "context is not a JSON object")
+	}
+
+	timestamp, err := json.Marshal(now)
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "dlq payload malformed", "failed to marshal timestamp")
+	}
+	callbackCtx["timestamp"] = timestamp
+
+	if body["context"], err = json.Marshal(callbackCtx); err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "dlq payload malformed", "failed to marshal context")
+	}
+
+	refreshed, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "dlq payload malformed", "failed to marshal payload")
+	}
+	return refreshed, nil
+}
@@ -0,0 +1,235 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/services/audit"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockDLQClient is a mock implementation of DLQClient
+type MockDLQClient struct {
+	mock.Mock
+}
+
+func (m *MockDLQClient) XRangeN(ctx context.Context, stream, start, stop string, count int64) *redis.XMessageSliceCmd {
+	args := m.Called(ctx, stream, start, stop, count)
+	return args.Get(0).(*redis.XMessageSliceCmd)
+}
+
+func (m *MockDLQClient) XDel(ctx context.Context, stream string, ids ...string) *redis.IntCmd {
+	args := m.Called(ctx, stream, ids)
+	return args.Get(0).(*redis.IntCmd)
+}
+
+// MockCallbackSender is a mock implementation of CallbackSender
+type MockCallbackSender struct {
+	mock.Mock
+}
+
+func (m *MockCallbackSender) SendCallbackDirect(ctx context.Context, callbackURL string, payload interface{}) error {
+	args := m.Called(ctx, callbackURL, payload)
+	return args.Error(0)
+}
+
+func dlqMessage(id, requestID, bapID string) redis.XMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"request_id":   requestID,
+		"callback_url": "https://bap.example.com/on_search",
+		"payload": map[string]interface{}{
+			"context": map[string]interface{}{
+				"action":    "on_search",
+				"bap_id":    bapID,
+				"timestamp": "2026-01-01T00:00:00Z",
+			},
+			"message": map[string]interface{}{"catalog": map[string]interface{}{}},
+		},
+		"error":     "[65020] callback delivery failed: unexpected status: 500",
+		"timestamp": 1767225600,
+		"retries":   3,
+	})
+	return redis.XMessage{ID: id, Values: map[string]interface{}{"data": string(data), "request_id": requestID}}
+}
+
+func xmessages(messages ...redis.XMessage) *redis.XMessageSliceCmd {
+	cmd := redis.NewXMessageSliceCmd(context.Background())
+	cmd.SetVal(messages)
+	return cmd
+}
+
+func newTestDLQService(rdb *MockDLQClient, sender CallbackSender, auditService AuditService) *DLQService {
+	cfg := config.CallbackConfig{DLQStream: "stream.uois.callback_dlq", DLQEnabled: true}
+	return NewDLQService(rdb, cfg, sender, auditService, zap.NewNop())
+}
+
+func TestDLQService_List_FiltersEntries(t *testing.T) {
+	mockRedis := new(MockDLQClient)
+	service := newTestDLQService(mockRedis, nil, nil)
+
+	mockRedis.On("XRangeN", mock.Anything, "stream.uois.callback_dlq", "-", "+", int64(dlqScanPage)).Return(xmessages(
+		dlqMessage("1-0", "req-1", "bap.one"),
+		dlqMessage("2-0", "req-2", "bap.two"),
+		redis.XMessage{ID: "3-0", Values: map[string]interface{}{"data": "not json"}},
+	))
+
+	entries, err := service.List(context.Background(), DLQFilter{BapID: "bap.two"})
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "2-0", entries[0].ID)
+	assert.Equal(t, "req-2", entries[0].RequestID)
+	assert.Equal(t, "https://bap.example.com/on_search", entries[0].CallbackURL)
+	assert.Equal(t, 3, entries[0].Retries)
+	assert.Contains(t, entries[0].Error, "unexpected status: 500")
+}
+
+func TestDLQService_List_TimeRangeUsesStreamIDs(t *testing.T) {
+	mockRedis := new(MockDLQClient)
+	service := newTestDLQService(mockRedis, nil, nil)
+
+	from := time.UnixMilli(1767225600000)
+	to := time.UnixMilli(1767312000000)
+	mockRedis.On("XRangeN", mock.Anything, "stream.uois.callback_dlq", "1767225600000", "1767312000000", int64(dlqScanPage)).Return(xmessages())
+
+	entries, err := service.List(context.Background(), DLQFilter{From: from, To: to})
+
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestDLQService_Get_NotFound(t *testing.T) {
+	mockRedis := new(MockDLQClient)
+	service := newTestDLQService(mockRedis, nil, nil)
+	mockRedis.On("XRangeN", mock.Anything, "stream.uois.callback_dlq", "9-0", "9-0", int64(1)).Return(xmessages())
+
+	entry, err := service.Get(context.Background(), "9-0")
+
+	assert.Nil(t, entry)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65006, domainErr.Code)
+}
+
+func TestDLQService_Redrive_Success(t *testing.T) {
+	mockRedis := new(MockDLQClient)
+	mockSender := new(MockCallbackSender)
+	mockAudit := new(MockAuditService)
+	service := newTestDLQService(mockRedis, mockSender, mockAudit)
+
+	mockRedis.On("XRangeN", mock.Anything, "stream.uois.callback_dlq", "1-0", "1-0", int64(1)).Return(xmessages(dlqMessage("1-0", "req-1", "bap.one")))
+	mockRedis.On("XDel", mock.Anything, "stream.uois.callback_dlq", []string{"1-0"}).Return(redis.NewIntCmd(context.Background()))
+
+	var sent json.RawMessage
+	mockSender.On("SendCallbackDirect", mock.Anything, "https://bap.example.com/on_search", mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(2).(json.RawMessage)
+	}).Return(nil)
+	mockAudit.On("LogCallbackDelivery", mock.Anything, &audit.CallbackDeliveryLogParams{
+		RequestID:   "req-1",
+		CallbackURL: "https://bap.example.com/on_search",
+		AttemptNo:   4,
+		Status:      DLQRedriveStatusSuccess,
+	}).Return(nil)
+
+	results, err := service.Redrive(context.Background(), []string{"1-0"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []DLQRedriveResult{{ID: "1-0", RequestID: "req-1", Status: DLQRedriveStatusSuccess}}, results)
+
+	var body struct {
+		Context struct {
+			Timestamp time.Time `json:"timestamp"`
+			BapID     string    `json:"bap_id"`
+		} `json:"context"`
+	}
+	assert.NoError(t, json.Unmarshal(sent, &body))
+	assert.Equal(t, "bap.one", body.Context.BapID)
+	assert.WithinDuration(t, time.Now(), body.Context.Timestamp, 5*time.Second)
+
+	mockRedis.AssertExpectations(t)
+	mockSender.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestDLQService_Redrive_FailureKeepsEntry(t *testing.T) {
+	mockRedis := new(MockDLQClient)
+	mockSender := new(MockCallbackSender)
+	mockAudit := new(MockAuditService)
+	service := newTestDLQService(mockRedis, mockSender, mockAudit)
+
+	mockRedis.On("XRangeN", mock.Anything, "stream.uois.callback_dlq", "1-0", "1-0", int64(1)).Return(xmessages(dlqMessage("1-0", "req-1", "bap.one")))
+	mockRedis.On("XRangeN", mock.Anything, "stream.uois.callback_dlq", "2-0", "2-0", int64(1)).Return(xmessages())
+	mockSender.On("SendCallbackDirect", mock.Anything, mock.Anything, mock.Anything).Return(errors.NewDomainError(65020, "callback delivery failed", "unexpected status: 503"))
+	mockAudit.On("LogCallbackDelivery", mock.Anything, mock.MatchedBy(func(p *audit.CallbackDeliveryLogParams) bool {
+		return p.Status == DLQRedriveStatusFailed && p.Error != ""
+	})).Return(nil)
+
+	results, err := service.Redrive(context.Background(), []string{"1-0", "2-0"})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, DLQRedriveStatusFailed, results[0].Status)
+	assert.Equal(t, DLQRedriveStatusNotFound, results[1].Status)
+	mockRedis.AssertNotCalled(t, "XDel", mock.Anything, mock.Anything, mock.Anything)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestDLQService_Purge(t *testing.T) {
+	mockRedis := new(MockDLQClient)
+	service := newTestDLQService(mockRedis, nil, nil)
+
+	cmd := redis.NewIntCmd(context.Background())
+	cmd.SetVal(2)
+	mockRedis.On("XDel", mock.Anything, "stream.uois.callback_dlq", []string{"1-0", "2-0"}).Return(cmd)
+
+	deleted, err := service.Purge(context.Background(), []string{"1-0", "2-0"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
+
+func TestRefreshContextTimestamp(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	payload := json.RawMessage(`{"context":{"action":"on_status","timestamp":"2026-01-01T00:00:00Z","ttl":"PT30S"},"message":{"order":{"id":"O1"}}}`)
+
+	refreshed, err := refreshContextTimestamp(payload, now)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"context":{"action":"on_status","timestamp":"2026-10-16T09:30:00Z","ttl":"PT30S"},"message":{"order":{"id":"O1"}}}`, string(refreshed))
+
+	_, err = refreshContextTimestamp(json.RawMessage(`[]`), now)
+	assert.Error(t, err)
+}
+
+func TestDLQService_Redrive_ReportsInvalidAndMalformedEntries(t *testing.T) {
+	mockRedis := new(MockDLQClient)
+	mockSender := new(MockCallbackSender)
+	service := newTestDLQService(mockRedis, mockSender, nil)
+
+	malformed := redis.XMessage{ID: "1-0", Values: map[string]interface{}{"data": "{not json"}}
+	mockRedis.On("XRangeN", mock.Anything, "stream.uois.callback_dlq", "1-0", "1-0", int64(1)).Return(xmessages(malformed))
+	mockRedis.On("XRangeN", mock.Anything, "stream.uois.callback_dlq", "2-0", "2-0", int64(1)).Return(xmessages(dlqMessage("2-0", "req-2", "bap.two")))
+	mockSender.On("SendCallbackDirect", mock.Anything, "https://bap.example.com/on_search", mock.Anything).Return(nil)
+	delCmd := redis.NewIntCmd(context.Background())
+	delCmd.SetVal(1)
+	mockRedis.On("XDel", mock.Anything, "stream.uois.callback_dlq", []string{"2-0"}).Return(delCmd)
+
+	results, err := service.Redrive(context.Background(), []string{"not-an-id", "1-0", "2-0"})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, DLQRedriveStatusInvalidID, results[0].Status)
+	assert.Equal(t, DLQRedriveStatusMalformed, results[1].Status)
+	assert.NotEmpty(t, results[1].Error)
+	assert.Equal(t, DLQRedriveStatusSuccess, results[2].Status)
+	mockRedis.AssertNotCalled(t, "XRangeN", mock.Anything, mock.Anything, "not-an-id", mock.Anything, mock.Anything)
+}
@@ -2,8 +2,6 @@ package callback
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
 	"fmt"
 
 	"uois-gateway/internal/config"
@@ -25,43 +23,20 @@ func NewONDCSigner(ondcAuth *ondc.ONDCAuthService) *ONDCSigner {
 }
 
 // SignRequest generates an HTTP signature for the given request
-// ONDC requires signing the digest and headers for callback requests
+// ONDC signs the body only: (created), (expires) and the BLAKE-512 digest of the exact bytes sent
 func (s *ONDCSigner) SignRequest(ctx context.Context, method, url string, body []byte, headers map[string]string) (string, error) {
-	hash := sha256.Sum256(body)
-	digest := base64.StdEncoding.EncodeToString(hash[:])
-	
-	signaturePayload := fmt.Sprintf("%s %s\nContent-Type: %s\nDigest: SHA-256=%s",
-		method,
-		url,
-		headers["Content-Type"],
-		digest,
-	)
-	
-	signatureBytes := []byte(signaturePayload)
-	authHeader, err := s.ondcAuth.SignResponse(signatureBytes)
-	if err != nil {
-		return "", err
-	}
-	
-	return authHeader, nil
+	return s.ondcAuth.SignResponse(body)
 }
 
 // NewONDCSignerFromConfig creates a new ONDC signer from config
 // This is a helper function for main.go initialization
 func NewONDCSignerFromConfig(cfg config.ONDCConfig, logger *zap.Logger) (*ONDCSigner, error) {
-	registry := &mockRegistryClient{}
+	registry := ondc.NewHTTPRegistryClient(cfg, logger)
 	ondcAuth, err := ondc.NewONDCAuthService(registry, cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ONDC auth service: %w", err)
 	}
-	return NewONDCSigner(ondcAuth), nil
-}
-
-// mockRegistryClient is a placeholder for registry client
-// TODO: Implement actual registry client
-type mockRegistryClient struct{}
-
-func (m *mockRegistryClient) LookupPublicKey(ctx context.Context, subscriberID, ukID string) (string, error) {
-	return "", fmt.Errorf("registry client not implemented")
+	signer := NewONDCSigner(ondcAuth)
+	registry.SetSigner(signer)
+	return signer, nil
 }
-
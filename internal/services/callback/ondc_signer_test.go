@@ -0,0 +1,69 @@
+package callback
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/services/ondc"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// staticRegistry returns a fixed public key for every lookup
+type staticRegistry struct {
+	publicKey string
+}
+
+func (r *staticRegistry) LookupPublicKey(ctx context.Context, subscriberID, ukID string) (string, error) {
+	return r.publicKey, nil
+}
+
+func newTestONDCAuthService(t *testing.T) *ondc.ONDCAuthService {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privateKeyPath := filepath.Join(dir, "private.key")
+	publicKeyPath := filepath.Join(dir, "public.key")
+	require.NoError(t, os.WriteFile(privateKeyPath, []byte(base64.StdEncoding.EncodeToString(privateKey)), 0600))
+	require.NoError(t, os.WriteFile(publicKeyPath, []byte(base64.StdEncoding.EncodeToString(publicKey)), 0600))
+
+	cfg := config.ONDCConfig{
+		PrivateKeyPath:  privateKeyPath,
+		PublicKeyPath:   publicKeyPath,
+		SubscriberID:    "lsp.com",
+		UkID:            "UK1",
+		TimestampWindow: 300,
+	}
+	registry := &staticRegistry{publicKey: base64.StdEncoding.EncodeToString(publicKey)}
+
+	service, err := ondc.NewONDCAuthService(registry, cfg, zap.NewNop())
+	require.NoError(t, err)
+	return service
+}
+
+func TestONDCSigner_SignRequest_VerifiesWithONDCVerifier(t *testing.T) {
+	authService := newTestONDCAuthService(t)
+	signer := NewONDCSigner(authService)
+	body := []byte(`{"context":{"action":"on_confirm"},"message":{"order":{"id":"O1"}}}`)
+
+	authHeader, err := signer.SignRequest(context.Background(), http.MethodPost, "https://buyer.com/on_confirm", body, nil)
+	require.NoError(t, err)
+
+	params, err := ondc.ParseSignatureHeader(authHeader)
+	require.NoError(t, err)
+	assert.Equal(t, "lsp.com|UK1|ed25519", params.KeyID)
+	assert.Equal(t, []string{"(created)", "(expires)", "digest"}, params.Headers)
+
+	assert.NoError(t, authService.VerifySignatureTimestamps(authHeader))
+	assert.NoError(t, authService.VerifyRequestSignature(context.Background(), authHeader, body))
+	assert.Error(t, authService.VerifyRequestSignature(context.Background(), authHeader, append(body, ' ')))
+}
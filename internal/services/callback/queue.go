@@ -0,0 +1,450 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultQueueWorkers   = 100
+	defaultQueueClaimIdle = 120 * time.Second
+	defaultQueueBlock     = 5 * time.Second
+	// queueErrorBackoff is the pause after a failed queue read before reading again
+	queueErrorBackoff = time.Second
+)
+
+// QueueClient interface for the Redis stream operations behind the callback queue
+type QueueClient interface {
+	XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd
+	XGroupCreateMkStream(ctx context.Context, stream, group, start string) *redis.StatusCmd
+	XReadGroup(ctx context.Context, a *redis.XReadGroupArgs) *redis.XStreamSliceCmd
+	XAutoClaim(ctx context.Context, a *redis.XAutoClaimArgs) *redis.XAutoClaimCmd
+	XAck(ctx context.Context, stream, group string, ids ...string) *redis.IntCmd
+	XDel(ctx context.Context, stream string, ids ...string) *redis.IntCmd
+}
+
+// QueueRedisClient is the Redis client of a queued callback service (retry DLQ and callback queue)
+type QueueRedisClient interface {
+	RedisClient
+	QueueClient
+}
+
+// DeliverFunc delivers one queued callback; payload is the serialized callback body
+// When deferrable, a callback rejected by the BAP's circuit returns ErrCallbackDeferred instead of being dead-lettered
+type DeliverFunc func(ctx context.Context, callbackURL string, payload interface{}, requestID string, deferrable bool) error
+
+// ExpireFunc dead-letters one queued callback whose TTL (deadline) elapsed before it was delivered
+type ExpireFunc func(ctx context.Context, callbackURL string, payload interface{}, requestID string, deadline time.Time) error
+
+// Queue is a durable callback queue backed by a Redis stream
+//
+// Handlers enqueue callbacks (XADD) and return; a bounded pool of CALLBACK_MAX_CONCURRENT workers
+// reads the stream as a consumer group member and delivers each callback with RetryService semantics
+// (backoff, audit per attempt, DLQ when retries are exhausted). Entries are ACKed and deleted once
+// delivered or dead-lettered, so callbacks survive restarts:
+//   - On start, the instance re-delivers entries it had read but not finished (its own PEL)
+//   - Entries held by a dead instance are claimed after CALLBACK_QUEUE_CLAIM_IDLE_SECONDS
+//   - On shutdown no new entries are read; in-flight deliveries finish or stay pending
+//
+// The queue stores the serialized body, not the signature: ONDC signatures carry created/expires
+// and are generated on every delivery attempt.
+type Queue struct {
+	rdb       QueueClient
+	stream    string
+	group     string
+	consumer  string
+	workers   int
+	claimIdle time.Duration
+	block     time.Duration
+	deliver   DeliverFunc
+	logger    *zap.Logger
+
+	// breakers defers callbacks to BAPs whose circuit is open, for up to maxDefer after enqueue
+	// and never past the callback's TTL
+	breakers *BAPBreakers
+	maxDefer time.Duration
+	// expire dead-letters callbacks whose TTL elapsed while queued; nil drops them
+	expire ExpireFunc
+
+	// deliveryCtx outlives Run's context so in-flight deliveries can finish while draining
+	deliveryCtx   context.Context
+	abortDelivery context.CancelFunc
+	started       atomic.Bool
+	done          chan struct{}
+}
+
+// queuedCallback is one entry of the callback queue stream
+type queuedCallback struct {
+	messageID   string
+	deliveryID  string
+	callbackURL string
+	requestID   string
+	body        string
+	enqueuedAt  time.Time
+}
+
+// NewQueue creates a callback queue on cfg.QueueStream read by group/consumer
+func NewQueue(rdb QueueClient, cfg config.CallbackConfig, group, consumer string, deliver DeliverFunc, logger *zap.Logger) *Queue {
+	deliveryCtx, abortDelivery := context.WithCancel(context.Background())
+
+	q := &Queue{
+		rdb:           rdb,
+		stream:        cfg.QueueStream,
+		group:         group,
+		consumer:      consumer,
+		workers:       cfg.MaxConcurrent,
+		claimIdle:     time.Duration(cfg.QueueClaimIdle) * time.Second,
+		maxDefer:      time.Duration(cfg.BreakerMaxDefer) * time.Second,
+		block:         defaultQueueBlock,
+		deliver:       deliver,
+		logger:        logger,
+		deliveryCtx:   deliveryCtx,
+		abortDelivery: abortDelivery,
+		done:          make(chan struct{}),
+	}
+
+	if q.workers <= 0 {
+		q.workers = defaultQueueWorkers
+	}
+	if q.claimIdle <= 0 {
+		q.claimIdle = defaultQueueClaimIdle
+	}
+
+	return q
+}
+
+// Enqueue persists a callback and returns its delivery id
+func (q *Queue) Enqueue(ctx context.Context, callbackURL string, payload interface{}, requestID string) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", errors.WrapDomainError(err, 65020, "callback serialization failed", "failed to marshal payload")
+	}
+
+	deliveryID := uuid.New().String()
+	args := &redis.XAddArgs{
+		Stream: q.stream,
+		Values: map[string]interface{}{
+			"delivery_id":  deliveryID,
+			"callback_url": callbackURL,
+			"request_id":   requestID,
+			"body":         string(body),
+			"enqueued_at":  time.Now().Unix(),
+		},
+	}
+	if err := q.rdb.XAdd(ctx, args).Err(); err != nil {
+		return "", errors.WrapDomainError(err, 65011, "callback enqueue failed", "redis error")
+	}
+
+	q.logger.Debug("callback enqueued",
+		zap.String("delivery_id", deliveryID),
+		zap.String("request_id", requestID),
+		zap.String("callback_url", callbackURL),
+	)
+	return deliveryID, nil
+}
+
+// Run delivers queued callbacks until ctx is cancelled, then waits for in-flight deliveries
+func (q *Queue) Run(ctx context.Context) {
+	q.started.Store(true)
+	defer close(q.done)
+
+	if err := q.rdb.XGroupCreateMkStream(ctx, q.stream, q.group, "0").Err(); err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		q.logger.Error("failed to create callback queue consumer group", zap.Error(err), zap.String("stream", q.stream))
+	}
+
+	jobs := make(chan queuedCallback)
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				q.process(job)
+			}
+		}()
+	}
+
+	q.logger.Info("callback queue started", zap.String("stream", q.stream), zap.Int("workers", q.workers))
+
+	q.recoverOwnPending(ctx, jobs)
+	q.readLoop(ctx, jobs)
+
+	close(jobs)
+	wg.Wait()
+	q.logger.Info("callback queue drained")
+}
+
+// Shutdown waits for Run to drain after its context is cancelled
+// Deliveries still running when ctx expires are aborted and stay pending for the next start
+func (q *Queue) Shutdown(ctx context.Context) error {
+	if !q.started.Load() {
+		return nil
+	}
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		q.abortDelivery()
+		<-q.done
+		return ctx.Err()
+	}
+}
+
+// recoverOwnPending re-delivers entries this consumer read before a restart but never ACKed
+func (q *Queue) recoverOwnPending(ctx context.Context, jobs chan<- queuedCallback) {
+	start := "0"
+	for ctx.Err() == nil {
+		streams, err := q.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    q.group,
+			Consumer: q.consumer,
+			Streams:  []string{q.stream, start},
+			Count:    int64(q.workers),
+		}).Result()
+		if err != nil {
+			if err != redis.Nil && ctx.Err() == nil {
+				q.logger.Error("failed to read pending callbacks", zap.Error(err), zap.String("stream", q.stream))
+			}
+			return
+		}
+
+		var messages []redis.XMessage
+		for _, result := range streams {
+			messages = append(messages, result.Messages...)
+		}
+		if len(messages) == 0 {
+			return
+		}
+
+		q.logger.Info("re-delivering pending callbacks", zap.Int("count", len(messages)))
+		if !q.submit(ctx, jobs, messages) {
+			return
+		}
+		start = messages[len(messages)-1].ID
+	}
+}
+
+// readLoop reads new entries and periodically claims entries abandoned by dead instances
+func (q *Queue) readLoop(ctx context.Context, jobs chan<- queuedCallback) {
+	args := &redis.XReadGroupArgs{
+		Group:    q.group,
+		Consumer: q.consumer,
+		Streams:  []string{q.stream, ">"},
+		Count:    int64(q.workers),
+		Block:    q.block,
+	}
+	nextClaim := time.Now().Add(q.claimIdle)
+
+	for ctx.Err() == nil {
+		if time.Now().After(nextClaim) {
+			q.claimAbandoned(ctx, jobs)
+			nextClaim = time.Now().Add(q.claimIdle)
+		}
+
+		streams, err := q.rdb.XReadGroup(ctx, args).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			q.logger.Error("failed to read callback queue", zap.Error(err), zap.String("stream", q.stream))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(queueErrorBackoff):
+			}
+			continue
+		}
+
+		for _, result := range streams {
+			if !q.submit(ctx, jobs, result.Messages) {
+				return
+			}
+		}
+	}
+}
+
+// claimAbandoned takes over entries idle longer than claimIdle (their consumer stopped)
+func (q *Queue) claimAbandoned(ctx context.Context, jobs chan<- queuedCallback) {
+	start := "0-0"
+	for ctx.Err() == nil {
+		messages, next, err := q.rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   q.stream,
+			Group:    q.group,
+			Consumer: q.consumer,
+			MinIdle:  q.claimIdle,
+			Start:    start,
+			Count:    int64(q.workers),
+		}).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				q.logger.Warn("failed to claim abandoned callbacks", zap.Error(err), zap.String("stream", q.stream))
+			}
+			return
+		}
+
+		if len(messages) > 0 {
+			q.logger.Info("claimed abandoned callbacks", zap.Int("count", len(messages)))
+			if !q.submit(ctx, jobs, messages) {
+				return
+			}
+		}
+		if next == "0-0" || next == "" {
+			return
+		}
+		start = next
+	}
+}
+
+// submit hands messages to the worker pool, blocking while every worker is busy
+// Returns false if ctx is cancelled first; unsubmitted entries stay pending for the next start
+func (q *Queue) submit(ctx context.Context, jobs chan<- queuedCallback, messages []redis.XMessage) bool {
+	for _, msg := range messages {
+		job, ok := parseQueuedCallback(msg)
+		if !ok {
+			q.logger.Error("dropping malformed queued callback", zap.String("stream", q.stream), zap.String("id", msg.ID))
+			q.ack(msg.ID)
+			continue
+		}
+
+		select {
+		case jobs <- job:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// process delivers one callback and removes it from the queue unless delivery was aborted or deferred
+// A callback to a BAP whose circuit is open is left pending: claimAbandoned picks it up again after
+// claimIdle (delayed retry). That includes callbacks the circuit rejects mid-delivery (it opened during
+// the retries, or another callback holds the half-open probe). Past maxDefer it is delivered anyway,
+// and RetryService dead-letters it. Past the callback's TTL (context.timestamp + context.ttl) the BAP
+// would reject it, so it is dead-lettered without being sent.
+func (q *Queue) process(job queuedCallback) {
+	if deadline := callbackDeadline(json.RawMessage(job.body), job.enqueuedAt); !time.Now().Before(deadline) {
+		q.expireJob(job, deadline)
+		return
+	}
+
+	deferrable := q.breakers != nil && time.Since(job.enqueuedAt) < q.maxDefer
+	if deferrable && q.breakers.IsOpen(job.callbackURL) {
+		q.logDeferred(job)
+		return
+	}
+
+	err := q.deliver(q.deliveryCtx, job.callbackURL, json.RawMessage(job.body), job.requestID, deferrable)
+	if err != nil && q.deliveryCtx.Err() != nil {
+		q.logger.Warn("callback delivery aborted by shutdown, left queued",
+			zap.String("delivery_id", job.deliveryID),
+			zap.String("request_id", job.requestID),
+		)
+		return
+	}
+
+	if stderrors.Is(err, ErrCallbackDeferred) {
+		q.logDeferred(job)
+		return
+	}
+
+	if err != nil {
+		// RetryService has exhausted retries and written the DLQ entry
+		q.logger.Error("callback delivery failed",
+			zap.Error(err),
+			zap.String("delivery_id", job.deliveryID),
+			zap.String("request_id", job.requestID),
+			zap.String("callback_url", job.callbackURL),
+		)
+	}
+
+	q.ack(job.messageID)
+}
+
+// expireJob dead-letters a callback whose TTL elapsed while it was queued
+// The entry stays pending (and is retried after claimIdle) if the dead-letter write fails
+func (q *Queue) expireJob(job queuedCallback, deadline time.Time) {
+	if q.expire != nil {
+		if err := q.expire(q.deliveryCtx, job.callbackURL, json.RawMessage(job.body), job.requestID, deadline); err != nil {
+			q.logger.Error("failed to dead-letter expired callback, left queued",
+				zap.Error(err),
+				zap.String("delivery_id", job.deliveryID),
+				zap.String("request_id", job.requestID),
+			)
+			return
+		}
+	}
+
+	q.logger.Warn("callback TTL elapsed before delivery, dead-lettered",
+		zap.String("delivery_id", job.deliveryID),
+		zap.String("request_id", job.requestID),
+		zap.String("callback_url", job.callbackURL),
+		zap.Time("deadline", deadline),
+	)
+	q.ack(job.messageID)
+}
+
+// logDeferred records a callback left pending until the BAP's circuit recovers
+func (q *Queue) logDeferred(job queuedCallback) {
+	q.logger.Debug("callback deferred, BAP circuit open",
+		zap.String("delivery_id", job.deliveryID),
+		zap.String("request_id", job.requestID),
+		zap.String("callback_url", job.callbackURL),
+	)
+}
+
+// ack removes a finished entry from the consumer group and the stream
+func (q *Queue) ack(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := q.rdb.XAck(ctx, q.stream, q.group, id).Err(); err != nil {
+		q.logger.Warn("failed to ack queued callback", zap.Error(err), zap.String("id", id))
+		return
+	}
+	if err := q.rdb.XDel(ctx, q.stream, id).Err(); err != nil {
+		q.logger.Warn("failed to delete queued callback", zap.Error(err), zap.String("id", id))
+	}
+}
+
+// parseQueuedCallback reads the fields written by Enqueue
+func parseQueuedCallback(msg redis.XMessage) (queuedCallback, bool) {
+	callbackURL, _ := msg.Values["callback_url"].(string)
+	body, _ := msg.Values["body"].(string)
+	if callbackURL == "" || body == "" {
+		return queuedCallback{}, false
+	}
+
+	deliveryID, _ := msg.Values["delivery_id"].(string)
+	requestID, _ := msg.Values["request_id"].(string)
+	enqueuedAt := time.Now()
+	if raw, ok := msg.Values["enqueued_at"].(string); ok {
+		if unix, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			enqueuedAt = time.Unix(unix, 0)
+		}
+	}
+
+	return queuedCallback{
+		messageID:   msg.ID,
+		deliveryID:  deliveryID,
+		callbackURL: callbackURL,
+		requestID:   requestID,
+		body:        body,
+		enqueuedAt:  enqueuedAt,
+	}, true
+}
@@ -0,0 +1,267 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockQueueClient is a mock implementation of QueueRedisClient
+type MockQueueClient struct {
+	mock.Mock
+}
+
+func (m *MockQueueClient) XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd {
+	args := m.Called(ctx, a)
+	return args.Get(0).(*redis.StringCmd)
+}
+
+func (m *MockQueueClient) XInfoStream(ctx context.Context, stream string) *redis.XInfoStreamCmd {
+	args := m.Called(ctx, stream)
+	return args.Get(0).(*redis.XInfoStreamCmd)
+}
+
+func (m *MockQueueClient) XGroupCreateMkStream(ctx context.Context, stream, group, start string) *redis.StatusCmd {
+	args := m.Called(ctx, stream, group, start)
+	return args.Get(0).(*redis.StatusCmd)
+}
+
+func (m *MockQueueClient) XReadGroup(ctx context.Context, a *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+	args := m.Called(ctx, a)
+	return args.Get(0).(*redis.XStreamSliceCmd)
+}
+
+func (m *MockQueueClient) XAutoClaim(ctx context.Context, a *redis.XAutoClaimArgs) *redis.XAutoClaimCmd {
+	args := m.Called(ctx, a)
+	return args.Get(0).(*redis.XAutoClaimCmd)
+}
+
+func (m *MockQueueClient) XAck(ctx context.Context, stream, group string, ids ...string) *redis.IntCmd {
+	args := m.Called(ctx, stream, group, ids)
+	return args.Get(0).(*redis.IntCmd)
+}
+
+func (m *MockQueueClient) XDel(ctx context.Context, stream string, ids ...string) *redis.IntCmd {
+	args := m.Called(ctx, stream, ids)
+	return args.Get(0).(*redis.IntCmd)
+}
+
+func testQueueConfig() config.CallbackConfig {
+	return config.CallbackConfig{
+		HTTPTimeoutSeconds: 5,
+		MaxConcurrent:      2,
+		QueueStream:        "stream.uois.callbacks",
+		QueueClaimIdle:     120,
+	}
+}
+
+func queuedMessage(id string) redis.XMessage {
+	return redis.XMessage{
+		ID: id,
+		Values: map[string]interface{}{
+			"delivery_id":  "delivery-1",
+			"callback_url": "https://bap.example.com/on_search",
+			"request_id":   "req-123",
+			"body":         `{"test":"data"}`,
+		},
+	}
+}
+
+func expectAckAndDelete(mockRedis *MockQueueClient, id string) {
+	mockRedis.On("XAck", mock.Anything, "stream.uois.callbacks", "uois-gateway-consumers", []string{id}).Return(redis.NewIntCmd(context.Background())).Once()
+	mockRedis.On("XDel", mock.Anything, "stream.uois.callbacks", []string{id}).Return(redis.NewIntCmd(context.Background())).Once()
+}
+
+func TestQueue_Enqueue(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	queue := NewQueue(mockRedis, testQueueConfig(), "uois-gateway-consumers", "consumer-1", nil, zap.NewNop())
+
+	var values map[string]interface{}
+	mockRedis.On("XAdd", mock.Anything, mock.MatchedBy(func(a *redis.XAddArgs) bool {
+		values, _ = a.Values.(map[string]interface{})
+		return a.Stream == "stream.uois.callbacks"
+	})).Return(redis.NewStringCmd(context.Background()))
+
+	deliveryID, err := queue.Enqueue(context.Background(), "https://bap.example.com/on_search", map[string]interface{}{"test": "data"}, "req-123")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, deliveryID)
+	assert.Equal(t, deliveryID, values["delivery_id"])
+	assert.Equal(t, "https://bap.example.com/on_search", values["callback_url"])
+	assert.Equal(t, "req-123", values["request_id"])
+	assert.JSONEq(t, `{"test":"data"}`, values["body"].(string))
+	mockRedis.AssertExpectations(t)
+}
+
+func TestQueue_Enqueue_RedisError(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	queue := NewQueue(mockRedis, testQueueConfig(), "uois-gateway-consumers", "consumer-1", nil, zap.NewNop())
+
+	cmd := redis.NewStringCmd(context.Background())
+	cmd.SetErr(assert.AnError)
+	mockRedis.On("XAdd", mock.Anything, mock.Anything).Return(cmd)
+
+	deliveryID, err := queue.Enqueue(context.Background(), "https://bap.example.com/on_search", map[string]interface{}{"test": "data"}, "req-123")
+
+	assert.Error(t, err)
+	assert.Empty(t, deliveryID)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65011, domainErr.Code)
+}
+
+func TestQueue_Process_AcksDeliveredCallback(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	var delivered json.RawMessage
+	deliver := func(ctx context.Context, callbackURL string, payload interface{}, requestID string, deferrable bool) error {
+		delivered = payload.(json.RawMessage)
+		assert.Equal(t, "https://bap.example.com/on_search", callbackURL)
+		assert.Equal(t, "req-123", requestID)
+		return nil
+	}
+	queue := NewQueue(mockRedis, testQueueConfig(), "uois-gateway-consumers", "consumer-1", deliver, zap.NewNop())
+	expectAckAndDelete(mockRedis, "1-0")
+
+	job, ok := parseQueuedCallback(queuedMessage("1-0"))
+	assert.True(t, ok)
+	queue.process(job)
+
+	assert.JSONEq(t, `{"test":"data"}`, string(delivered))
+	mockRedis.AssertExpectations(t)
+}
+
+func TestQueue_Process_AcksFailedCallback(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	deliver := func(ctx context.Context, callbackURL string, payload interface{}, requestID string, deferrable bool) error {
+		return errors.NewDomainError(65020, "callback delivery failed", "unexpected status: 500")
+	}
+	queue := NewQueue(mockRedis, testQueueConfig(), "uois-gateway-consumers", "consumer-1", deliver, zap.NewNop())
+	expectAckAndDelete(mockRedis, "1-0")
+
+	job, _ := parseQueuedCallback(queuedMessage("1-0"))
+	queue.process(job)
+
+	mockRedis.AssertExpectations(t)
+}
+
+func TestQueue_Process_LeavesAbortedCallbackPending(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	deliver := func(ctx context.Context, callbackURL string, payload interface{}, requestID string, deferrable bool) error {
+		return ctx.Err()
+	}
+	queue := NewQueue(mockRedis, testQueueConfig(), "uois-gateway-consumers", "consumer-1", deliver, zap.NewNop())
+	queue.abortDelivery()
+
+	job, _ := parseQueuedCallback(queuedMessage("1-0"))
+	queue.process(job)
+
+	mockRedis.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestQueue_Submit_DropsMalformedEntry(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	queue := NewQueue(mockRedis, testQueueConfig(), "uois-gateway-consumers", "consumer-1", nil, zap.NewNop())
+	expectAckAndDelete(mockRedis, "1-0")
+
+	jobs := make(chan queuedCallback, 1)
+	ok := queue.submit(context.Background(), jobs, []redis.XMessage{{ID: "1-0", Values: map[string]interface{}{"request_id": "req-123"}}})
+
+	assert.True(t, ok)
+	assert.Empty(t, jobs)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestQueue_Run_DeliversAndDrainsOnShutdown(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var deliveries []string
+	deliver := func(dctx context.Context, callbackURL string, payload interface{}, requestID string, deferrable bool) error {
+		mu.Lock()
+		deliveries = append(deliveries, requestID)
+		mu.Unlock()
+		cancel()
+		return nil
+	}
+	queue := NewQueue(mockRedis, testQueueConfig(), "uois-gateway-consumers", "consumer-1", deliver, zap.NewNop())
+
+	mockRedis.On("XGroupCreateMkStream", mock.Anything, "stream.uois.callbacks", "uois-gateway-consumers", "0").Return(redis.NewStatusCmd(context.Background()))
+
+	pending := redis.NewXStreamSliceCmd(context.Background())
+	pending.SetVal([]redis.XStream{{Stream: "stream.uois.callbacks"}})
+	mockRedis.On("XReadGroup", mock.Anything, mock.MatchedBy(func(a *redis.XReadGroupArgs) bool {
+		return a.Streams[1] == "0"
+	})).Return(pending).Once()
+
+	fresh := redis.NewXStreamSliceCmd(context.Background())
+	fresh.SetVal([]redis.XStream{{Stream: "stream.uois.callbacks", Messages: []redis.XMessage{queuedMessage("1-0")}}})
+	mockRedis.On("XReadGroup", mock.Anything, mock.MatchedBy(func(a *redis.XReadGroupArgs) bool {
+		return a.Streams[1] == ">"
+	})).Return(fresh).Once()
+
+	empty := redis.NewXStreamSliceCmd(context.Background())
+	empty.SetErr(redis.Nil)
+	mockRedis.On("XReadGroup", mock.Anything, mock.Anything).Return(empty).After(10 * time.Millisecond).Maybe()
+
+	expectAckAndDelete(mockRedis, "1-0")
+
+	go queue.Run(ctx)
+	<-ctx.Done()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer shutdownCancel()
+	assert.NoError(t, queue.Shutdown(shutdownCtx))
+
+	assert.Equal(t, []string{"req-123"}, deliveries)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestQueue_Shutdown_NotStarted(t *testing.T) {
+	queue := NewQueue(new(MockQueueClient), testQueueConfig(), "uois-gateway-consumers", "consumer-1", nil, zap.NewNop())
+
+	assert.NoError(t, queue.Shutdown(context.Background()))
+}
+
+func TestCallbackService_SendCallback_EnqueuesWhenQueueConfigured(t *testing.T) {
+	mockRedis := new(MockQueueClient)
+	mockSigner := new(MockSigner)
+	mockAudit := new(MockAuditService)
+	retryConfig := config.RetryConfig{CallbackMaxRetries: 3, CallbackBackoff: []int{1, 2, 4}}
+
+	service := NewQueuedService(testQueueConfig(), retryConfig, mockSigner, mockRedis, mockAudit, nil, "uois-gateway-consumers", "consumer-1", zap.NewNop())
+	assert.NotNil(t, service.Queue())
+
+	mockRedis.On("XAdd", mock.Anything, mock.MatchedBy(func(a *redis.XAddArgs) bool {
+		return a.Stream == "stream.uois.callbacks"
+	})).Return(redis.NewStringCmd(context.Background())).Once()
+
+	deliveryID, err := service.SendCallback(context.Background(), "https://bap.example.com/on_search", map[string]interface{}{"test": "data"})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, deliveryID)
+	mockRedis.AssertExpectations(t)
+	mockSigner.AssertNotCalled(t, "SignRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNewQueuedService_WithoutQueueStream(t *testing.T) {
+	cfg := testQueueConfig()
+	cfg.QueueStream = ""
+
+	service := NewQueuedService(cfg, config.RetryConfig{}, nil, new(MockQueueClient), new(MockAuditService), nil, "uois-gateway-consumers", "consumer-1", zap.NewNop())
+
+	assert.Nil(t, service.Queue())
+	_, err := service.EnqueueCallback(context.Background(), "https://bap.example.com/on_search", map[string]interface{}{})
+	assert.Error(t, err)
+}
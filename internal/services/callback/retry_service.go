@@ -3,7 +3,9 @@ package callback
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"math"
 	"time"
 
 	"uois-gateway/internal/config"
@@ -14,6 +16,13 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrCallbackDeferred is returned instead of dead-lettering when the BAP's circuit rejects a deferrable
+// callback; the callback queue leaves the entry pending and delivers it once the circuit recovers
+var ErrCallbackDeferred = stderrors.New("callback deferred, BAP circuit open")
+
+// ErrCallbackExpired is the DLQ error of a queued callback whose TTL elapsed before it could be delivered
+var ErrCallbackExpired = stderrors.New("callback TTL elapsed before delivery")
+
 // CallbackSender interface for sending callbacks (to avoid circular dependency)
 type CallbackSender interface {
 	SendCallbackDirect(ctx context.Context, callbackURL string, payload interface{}) error
@@ -60,12 +69,53 @@ func NewRetryService(
 }
 
 // SendCallbackWithRetry sends a callback with exponential backoff retry and DLQ support
+// Retries stop once ttlSeconds have elapsed (0 means no deadline)
 func (r *RetryService) SendCallbackWithRetry(
 	ctx context.Context,
 	callbackURL string,
 	payload interface{},
 	requestID string,
 	ttlSeconds int,
+) error {
+	var deadline time.Time
+	if ttlSeconds > 0 {
+		deadline = time.Now().Add(time.Duration(ttlSeconds) * time.Second)
+	}
+	return r.SendCallbackWithDeadline(ctx, callbackURL, payload, requestID, deadline)
+}
+
+// SendCallbackWithDeadline sends a callback with exponential backoff retry and DLQ support
+// No retry is scheduled past deadline (derived from the originating request's context.ttl); zero means none
+func (r *RetryService) SendCallbackWithDeadline(
+	ctx context.Context,
+	callbackURL string,
+	payload interface{},
+	requestID string,
+	deadline time.Time,
+) error {
+	return r.sendCallback(ctx, callbackURL, payload, requestID, deadline, false)
+}
+
+// SendCallbackOrDefer is SendCallbackWithDeadline for queued callbacks that can wait for the BAP:
+// when the BAP's circuit is open or its half-open probe is taken, it returns ErrCallbackDeferred
+// without dead-lettering
+func (r *RetryService) SendCallbackOrDefer(
+	ctx context.Context,
+	callbackURL string,
+	payload interface{},
+	requestID string,
+	deadline time.Time,
+) error {
+	return r.sendCallback(ctx, callbackURL, payload, requestID, deadline, true)
+}
+
+func (r *RetryService) sendCallback(
+	ctx context.Context,
+	callbackURL string,
+	payload interface{},
+	requestID string,
+	deadline time.Time,
+	deferrable bool,
 ) error {
 	var lastErr error
 	attempt := 0
@@ -79,6 +129,11 @@ func (r *RetryService) SendCallbackWithRetry(
 		errorMsg := ""
 		if err != nil {
 			status = "failed"
+			if IsBAPUnavailable(err) {
+				status = "circuit_open"
+			} else if _, ok := AsNACK(err); ok {
+				status = "nack"
+			}
 			errorMsg = err.Error()
 			lastErr = err
 		}
@@ -100,13 +155,38 @@ func (r *RetryService) SendCallbackWithRetry(
 			return nil
 		}
 
+		// The BAP's circuit is open: further attempts would be rejected too, defer or dead-letter now
+		if IsBAPUnavailable(err) {
+			if deferrable {
+				return fmt.Errorf("%w: %v", ErrCallbackDeferred, err)
+			}
+			break
+		}
+
+		// The BAP will NACK the same payload again (e.g. a validation error), dead-letter now
+		if nack, ok := AsNACK(err); ok && !nack.Retryable {
+			break
+		}
+
 		// Check if we should retry
 		if attempt >= r.config.CallbackMaxRetries {
 			break
 		}
 
-		// Calculate backoff duration
-		backoffDuration := r.calculateBackoff(attempt, ttlSeconds)
+		// Calculate backoff duration, bounded by the time left before the deadline
+		remainingSeconds := 0
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				r.logger.Warn("callback TTL elapsed, stopping retries",
+					zap.Int("attempt", attempt),
+					zap.String("request_id", requestID),
+				)
+				break
+			}
+			remainingSeconds = int(math.Ceil(remaining.Seconds()))
+		}
+		backoffDuration := r.calculateBackoff(attempt, remainingSeconds)
 		if backoffDuration <= 0 {
 			r.logger.Warn("backoff duration is zero or negative, stopping retries",
 				zap.Int("attempt", attempt),
@@ -133,7 +213,7 @@ func (r *RetryService) SendCallbackWithRetry(
 
 	// All retries exhausted, send to DLQ if enabled
 	if r.callbackConfig.DLQEnabled && r.callbackConfig.DLQStream != "" && r.redis != nil {
-		if err := r.sendToDLQ(ctx, callbackURL, payload, requestID, lastErr); err != nil {
+		if err := r.sendToDLQ(ctx, callbackURL, payload, requestID, lastErr, DLQReasonRetriesExhausted, r.config.CallbackMaxRetries); err != nil {
 			r.logger.Error("failed to send callback to DLQ",
 				zap.String("request_id", requestID),
 				zap.Error(err),
@@ -191,21 +271,46 @@ func (r *RetryService) calculateBackoff(attempt int, ttlSeconds int) time.Durati
 	return backoffDuration
 }
 
+// DeadLetterExpired writes a queued callback whose TTL elapsed before delivery to the DLQ without sending it
+// Without a DLQ the callback is dropped and only logged
+func (r *RetryService) DeadLetterExpired(
+	ctx context.Context,
+	callbackURL string,
+	payload interface{},
+	requestID string,
+	deadline time.Time,
+) error {
+	if !r.callbackConfig.DLQEnabled || r.callbackConfig.DLQStream == "" || r.redis == nil {
+		r.logger.Warn("callback TTL elapsed before delivery, no DLQ configured, dropping",
+			zap.String("request_id", requestID),
+			zap.Time("deadline", deadline),
+		)
+		return nil
+	}
+
+	lastErr := fmt.Errorf("%w (deadline %s)", ErrCallbackExpired, deadline.UTC().Format(time.RFC3339))
+	return r.sendToDLQ(ctx, callbackURL, payload, requestID, lastErr, DLQReasonTTLExpired, 0)
+}
+
 // sendToDLQ sends failed callback to Dead Letter Queue
+// reason is one of the DLQReason values; retries is the number of delivery retries made
 func (r *RetryService) sendToDLQ(
 	ctx context.Context,
 	callbackURL string,
 	payload interface{},
 	requestID string,
 	lastErr error,
+	reason string,
+	retries int,
 ) error {
 	dlqPayload := map[string]interface{}{
 		"request_id":   requestID,
 		"callback_url": callbackURL,
 		"payload":       payload,
 		"error":         lastErr.Error(),
+		"reason":        reason,
 		"timestamp":     time.Now().Unix(),
-		"retries":       r.config.CallbackMaxRetries,
+		"retries":       retries,
 	}
 
 	payloadJSON, err := json.Marshal(dlqPayload)
@@ -219,6 +324,7 @@ func (r *RetryService) sendToDLQ(
 			"data":      string(payloadJSON),
 			"request_id": requestID,
 			"error":     lastErr.Error(),
+			"reason":    reason,
 		},
 	}
 
@@ -228,6 +334,7 @@ func (r *RetryService) sendToDLQ(
 
 	r.logger.Info("callback sent to DLQ",
 		zap.String("request_id", requestID),
+		zap.String("reason", reason),
 		zap.String("dlq_stream", r.callbackConfig.DLQStream),
 	)
 
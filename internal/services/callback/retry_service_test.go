@@ -211,12 +211,40 @@ func TestRetryService_SendToDLQ(t *testing.T) {
 	retryService := NewRetryService(nil, retryConfig, cfg, mockRedis, nil, logger)
 
 	payload := map[string]interface{}{"test": "data"}
-	err := retryService.sendToDLQ(ctx, "http://example.com/callback", payload, "req-123", assert.AnError)
+	err := retryService.sendToDLQ(ctx, "http://example.com/callback", payload, "req-123", assert.AnError, DLQReasonRetriesExhausted, 3)
 
 	assert.NoError(t, err)
 	mockRedis.AssertExpectations(t)
 }
 
+func TestRetryService_DeadLetterExpired(t *testing.T) {
+	ctx := context.Background()
+	mockRedis := new(MockRedisClient)
+	cfg := config.CallbackConfig{DLQEnabled: true, DLQStream: "stream.uois.callback_dlq"}
+
+	var values map[string]interface{}
+	stringCmd := redis.NewStringCmd(ctx)
+	stringCmd.SetVal("1234567890-0")
+	mockRedis.On("XAdd", ctx, mock.MatchedBy(func(a *redis.XAddArgs) bool {
+		values, _ = a.Values.(map[string]interface{})
+		return a.Stream == "stream.uois.callback_dlq"
+	})).Return(stringCmd).Once()
+
+	retryService := NewRetryService(nil, config.RetryConfig{CallbackMaxRetries: 3}, cfg, mockRedis, nil, zap.NewNop())
+
+	err := retryService.DeadLetterExpired(ctx, "http://example.com/on_search", map[string]interface{}{"test": "data"}, "req-123", time.Now())
+
+	assert.NoError(t, err)
+	mockRedis.AssertExpectations(t)
+	assert.Equal(t, DLQReasonTTLExpired, values["reason"])
+
+	entry, err := parseDLQEntry(redis.XMessage{ID: "1234567890-0", Values: values})
+	assert.NoError(t, err)
+	assert.Equal(t, DLQReasonTTLExpired, entry.Reason)
+	assert.Equal(t, 0, entry.Retries)
+	assert.Contains(t, entry.Error, ErrCallbackExpired.Error())
+}
+
 func TestRetryService_SendCallbackWithRetry_WithDLQ(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := config.CallbackConfig{
@@ -12,8 +12,8 @@ func TestSignatureParams_Validation(t *testing.T) {
 		KeyID:     "bpp_id|key_id|ed25519",
 		Created:   time.Now(),
 		Expires:   time.Now().Add(time.Hour),
-		Digest:    "SHA-256=abc123",
-		Headers:   []string{"(created)", "(expires)", "digest", "content-type"},
+		Digest:    "BLAKE-512=abc123",
+		Headers:   []string{"(created)", "(expires)", "digest"},
 		Signature: "signature123",
 	}
 
@@ -0,0 +1,69 @@
+package callback
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"uois-gateway/internal/config"
+)
+
+// blockedPrefixes are non-public ranges not covered by the netip classification helpers
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+}
+
+// newHTTPClient builds the HTTP client used to deliver callbacks
+// With BlockPrivateNetworks set, connections are checked after DNS resolution, so a public
+// bap_uri hostname that resolves (or rebinds) to an internal address is refused as well.
+func newHTTPClient(cfg config.CallbackConfig) *http.Client {
+	client := &http.Client{
+		Timeout: time.Duration(cfg.HTTPTimeoutSeconds) * time.Second,
+	}
+	if !cfg.BlockPrivateNetworks {
+		return client
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   guardDial,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialled instead of the BAP, bypassing the address check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	client.Transport = transport
+	return client
+}
+
+// guardDial runs on the resolved address of every callback connection
+func guardDial(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("callback target %q: %w", address, err)
+	}
+	if isBlockedAddr(addrPort.Addr()) {
+		return fmt.Errorf("callback target %s is not a public address", addrPort.Addr())
+	}
+	return nil
+}
+
+// isBlockedAddr reports whether addr is loopback, private, link-local or otherwise not publicly routable
+func isBlockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package callback
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"uois-gateway/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBlockedAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.10", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"224.0.0.1", true},
+		{"::1", true},
+		{"fd00::1", true},
+		{"fe80::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"8.8.8.8", false},
+		{"2001:4860:4860::8888", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.blocked, isBlockedAddr(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestNewHTTPClient_BlocksPrivateTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	blocking := newHTTPClient(config.CallbackConfig{HTTPTimeoutSeconds: 5, BlockPrivateNetworks: true})
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, nil)
+	_, err := blocking.Do(req)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not a public address")
+
+	allowing := newHTTPClient(config.CallbackConfig{HTTPTimeoutSeconds: 5})
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, nil)
+	resp, err := allowing.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+}
@@ -0,0 +1,36 @@
+package callback
+
+import (
+	"encoding/json"
+	"time"
+
+	"uois-gateway/internal/utils"
+)
+
+// defaultCallbackTTL applies when the callback context has no usable ttl (ONDC default PT30S)
+const defaultCallbackTTL = utils.DefaultRequestTTL
+
+// callbackDeadline is when retrying a callback stops being useful to the Buyer NP
+// Callbacks carry the originating request's context.ttl; the window starts at the callback's
+// context.timestamp, so time spent queued or deferred counts against it.
+func callbackDeadline(payload interface{}, now time.Time) time.Time {
+	raw, ok := payload.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(payload); err != nil {
+			return now.Add(defaultCallbackTTL)
+		}
+	}
+
+	var envelope struct {
+		Context struct {
+			TTL       string    `json:"ttl"`
+			Timestamp time.Time `json:"timestamp"`
+		} `json:"context"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return now.Add(defaultCallbackTTL)
+	}
+
+	return utils.ONDCDeadline(envelope.Context.Timestamp, envelope.Context.TTL, now)
+}
@@ -0,0 +1,51 @@
+package callback
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallbackDeadline(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		payload  interface{}
+		expected time.Time
+	}{
+		{
+			name:     "context ttl from callback timestamp",
+			payload:  map[string]interface{}{"context": map[string]interface{}{"ttl": "PT15M", "timestamp": now.Add(-time.Minute)}},
+			expected: now.Add(14 * time.Minute),
+		},
+		{
+			name:     "queued raw payload",
+			payload:  json.RawMessage(`{"context":{"ttl":"PT1M","timestamp":"2026-10-16T09:29:30Z"}}`),
+			expected: now.Add(30 * time.Second),
+		},
+		{
+			name:     "missing ttl uses default",
+			payload:  map[string]interface{}{"context": map[string]interface{}{"timestamp": now}},
+			expected: now.Add(defaultCallbackTTL),
+		},
+		{
+			name:     "invalid ttl uses default",
+			payload:  map[string]interface{}{"context": map[string]interface{}{"ttl": "30 seconds"}},
+			expected: now.Add(defaultCallbackTTL),
+		},
+		{
+			name:     "future timestamp starts now",
+			payload:  map[string]interface{}{"context": map[string]interface{}{"ttl": "PT30S", "timestamp": now.Add(time.Hour)}},
+			expected: now.Add(30 * time.Second),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, callbackDeadline(tt.payload, now))
+		})
+	}
+}
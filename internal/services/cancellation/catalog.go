@@ -0,0 +1,222 @@
+// Package cancellation holds the ONDC logistics cancellation reason code catalog and the
+// cancellation terms that price a cancellation by fulfillment state and reason code.
+package cancellation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+)
+
+// Wildcard matches every fulfillment state or reason code in a cancellation term
+const Wildcard = "*"
+
+// pickedUpStates are the ONDC fulfillment states in which the package has left the pickup location
+var pickedUpStates = map[string]bool{
+	"Order-picked-up":    true,
+	"In-transit":         true,
+	"At-destination-hub": true,
+	"Out-for-delivery":   true,
+}
+
+// DefaultReasons returns the built-in reason catalog (ONDC logistics reason codes, hyperlocal)
+// Buyer-side codes carry the logistics buyer NP's own cancellation reasons; once the package is
+// picked up they turn into RTO. Seller-side codes 011-015 are the RTO codes for an unreachable or
+// refusing end buyer; the other seller-side codes are LSP failures.
+func DefaultReasons() []models.CancellationReason {
+	buyer := func(code, description string, rto bool) models.CancellationReason {
+		return models.CancellationReason{Code: code, Description: description, InitiatedBy: models.CancellationInitiatorBuyer, TriggersRTO: rto}
+	}
+	seller := func(code, description string, rto bool) models.CancellationReason {
+		return models.CancellationReason{Code: code, Description: description, InitiatedBy: models.CancellationInitiatorSeller, TriggersRTO: rto}
+	}
+
+	return []models.CancellationReason{
+		buyer("001", "Price of one or more items have changed due to which buyer was asked to make additional payment", true),
+		buyer("002", "One or more items in the order not available", true),
+		buyer("003", "Product available at lower than order price", true),
+		buyer("004", "Order in pending shipment / delivery state for too long", true),
+		buyer("005", "Merchant rejected the order", true),
+		buyer("006", "Order not shipped as per buyer app SLA", true),
+		buyer("007", "Fulfillment TAT breached", true),
+		buyer("008", "Order not picked up as per pickup SLA", true),
+		buyer("009", "Buyer wants to modify address / other order details", true),
+		buyer("010", "Buyer does not want the order any more", true),
+		seller("011", "Buyer not found or cannot be contacted", true),
+		seller("012", "Buyer does not want product any more", true),
+		seller("013", "Buyer refused to accept delivery", true),
+		seller("014", "Address not found", true),
+		seller("015", "Buyer not available at location", true),
+		seller("016", "Accident / rain / strike / vehicle issues", false),
+		seller("017", "Order delivery delayed or not possible", false),
+		seller("018", "Delivery pin code not serviceable", false),
+		seller("019", "Pickup pin code not serviceable", false),
+		seller("020", "Order lost in transit", false),
+		seller("021", "Packed order not complete", false),
+		buyer("996", "Order cancelled because of order confirmation failure", false),
+		seller("997", "Order cancelled because of order confirmation failure", false),
+	}
+}
+
+// DefaultTerms returns the built-in cancellation terms, evaluated in order (first match wins)
+// LSP failures and confirmation failures are free, as is cancelling before a rider is assigned;
+// an assigned rider costs a flat fee capped by the order value and a picked-up order (RTO) the full order value.
+func DefaultTerms() []models.CancellationTerm {
+	return []models.CancellationTerm{
+		{FulfillmentState: Wildcard, ReasonCodes: []string{"016", "017", "018", "019", "020", "021", "996", "997"}, FeePercentage: "0.00"},
+		{FulfillmentState: "Pending", ReasonCodes: []string{Wildcard}, FeePercentage: "0.00"},
+		{FulfillmentState: "Searching-for-Agent", ReasonCodes: []string{Wildcard}, FeePercentage: "0.00"},
+		{FulfillmentState: "Agent-assigned", ReasonCodes: []string{Wildcard}, FeePercentage: "100.00", FeeAmount: "50.00"},
+		{FulfillmentState: Wildcard, ReasonCodes: []string{Wildcard}, FeePercentage: "100.00"},
+	}
+}
+
+// catalogFile is the on-disk format of CANCELLATION_CATALOG_FILE; a missing section keeps the built-in one
+//
+//	{"reasons":[{"code":"011","description":"Buyer not found or cannot be contacted","initiated_by":"seller","triggers_rto":true}],
+//	 "terms":[{"fulfillment_state":"Agent-assigned","reason_codes":["*"],"fee_percentage":"100.00","fee_amount":"50.00"}]}
+type catalogFile struct {
+	Reasons []models.CancellationReason `json:"reasons"`
+	Terms   []models.CancellationTerm   `json:"terms"`
+}
+
+// Catalog validates cancellation reason codes and prices cancellations
+type Catalog struct {
+	reasons map[string]models.CancellationReason
+	terms   []models.CancellationTerm
+}
+
+// NewCatalog creates a catalog from reasons and terms
+// Fails on duplicate or unattributed reason codes and on terms with unparsable fees
+func NewCatalog(reasons []models.CancellationReason, terms []models.CancellationTerm) (*Catalog, error) {
+	if len(reasons) == 0 {
+		return nil, fmt.Errorf("cancellation catalog has no reasons")
+	}
+
+	byCode := make(map[string]models.CancellationReason, len(reasons))
+	for _, reason := range reasons {
+		if reason.Code == "" {
+			return nil, fmt.Errorf("cancellation reason without code")
+		}
+		if reason.InitiatedBy != models.CancellationInitiatorBuyer && reason.InitiatedBy != models.CancellationInitiatorSeller {
+			return nil, fmt.Errorf("cancellation reason %s: initiated_by must be buyer or seller", reason.Code)
+		}
+		if _, exists := byCode[reason.Code]; exists {
+			return nil, fmt.Errorf("duplicate cancellation reason %s", reason.Code)
+		}
+		byCode[reason.Code] = reason
+	}
+
+	for i, term := range terms {
+		if term.FulfillmentState == "" || len(term.ReasonCodes) == 0 {
+			return nil, fmt.Errorf("cancellation term %d: fulfillment_state and reason_codes are required", i)
+		}
+		if percentage, err := parseFee(term.FeePercentage); err != nil || (percentage != nil && *percentage > 100) {
+			return nil, fmt.Errorf("cancellation term %d: fee_percentage must be between 0.00 and 100.00", i)
+		}
+		if _, err := parseFee(term.FeeAmount); err != nil {
+			return nil, fmt.Errorf("cancellation term %d: invalid fee_amount: %w", i, err)
+		}
+	}
+
+	return &Catalog{reasons: byCode, terms: terms}, nil
+}
+
+// LoadCatalog builds the catalog from the JSON file at path, or the built-in catalog when path is empty
+func LoadCatalog(path string) (*Catalog, error) {
+	file := catalogFile{Reasons: DefaultReasons(), Terms: DefaultTerms()}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cancellation catalog file: %w", err)
+		}
+		var override catalogFile
+		if err := json.Unmarshal(data, &override); err != nil {
+			return nil, fmt.Errorf("failed to parse cancellation catalog file %s: %w", path, err)
+		}
+		if len(override.Reasons) > 0 {
+			file.Reasons = override.Reasons
+		}
+		if len(override.Terms) > 0 {
+			file.Terms = override.Terms
+		}
+	}
+
+	return NewCatalog(file.Reasons, file.Terms)
+}
+
+// Reason returns the catalog entry for code if initiatedBy may use it
+// Unknown codes and codes of the other side are rejected with 60009 (invalid cancellation reason)
+func (c *Catalog) Reason(code, initiatedBy string) (*models.CancellationReason, *errors.DomainError) {
+	reason, ok := c.reasons[code]
+	if !ok {
+		return nil, errors.NewDomainError(60009, "invalid cancellation reason", fmt.Sprintf("unknown cancellation_reason_id %q", code))
+	}
+	if reason.InitiatedBy != initiatedBy {
+		return nil, errors.NewDomainError(60009, "invalid cancellation reason", fmt.Sprintf("cancellation_reason_id %s is reserved for the %s", code, reason.InitiatedBy))
+	}
+	return &reason, nil
+}
+
+// TriggersRTO reports whether cancelling with code in fulfillmentState starts RTO instead of cancelling
+func (c *Catalog) TriggersRTO(code, fulfillmentState string) bool {
+	reason, ok := c.reasons[code]
+	return ok && reason.TriggersRTO && pickedUpStates[fulfillmentState]
+}
+
+// Fee returns the cancellation fee for code in fulfillmentState and the term that priced it
+// orderValue is the order value minus taxes; no matching term or an unknown (empty) fulfillment state means no fee
+func (c *Catalog) Fee(fulfillmentState, code string, orderValue float64) (float64, *models.CancellationTerm) {
+	if fulfillmentState == "" {
+		return 0, nil
+	}
+	for i := range c.terms {
+		term := &c.terms[i]
+		if !matches(term.FulfillmentState, fulfillmentState) || !matchesAny(term.ReasonCodes, code) {
+			continue
+		}
+
+		fee := -1.0
+		if percentage, _ := parseFee(term.FeePercentage); percentage != nil {
+			fee = orderValue * *percentage / 100
+		}
+		if amount, _ := parseFee(term.FeeAmount); amount != nil && (fee < 0 || *amount < fee) {
+			fee = *amount
+		}
+		if fee < 0 {
+			fee = 0
+		}
+		return math.Round(fee*100) / 100, term
+	}
+	return 0, nil
+}
+
+func matches(pattern, value string) bool {
+	return pattern == Wildcard || pattern == value
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matches(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFee parses an optional non-negative decimal; nil means not set
+func parseFee(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	fee, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(fee) || math.IsInf(fee, 0) || fee < 0 {
+		return nil, fmt.Errorf("invalid fee %q", value)
+	}
+	return &fee, nil
+}
@@ -0,0 +1,138 @@
+package cancellation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uois-gateway/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func defaultCatalog(t *testing.T) *Catalog {
+	catalog, err := LoadCatalog("")
+	require.NoError(t, err)
+	return catalog
+}
+
+func TestCatalog_Reason(t *testing.T) {
+	catalog := defaultCatalog(t)
+
+	reason, err := catalog.Reason("009", models.CancellationInitiatorBuyer)
+	require.Nil(t, err)
+	assert.Equal(t, "009", reason.Code)
+
+	reason, err = catalog.Reason("013", models.CancellationInitiatorSeller)
+	require.Nil(t, err)
+	assert.True(t, reason.TriggersRTO)
+
+	tests := []struct {
+		name        string
+		code        string
+		initiatedBy string
+	}{
+		{name: "unknown code", code: "999", initiatedBy: models.CancellationInitiatorBuyer},
+		{name: "free text", code: "customer changed mind", initiatedBy: models.CancellationInitiatorBuyer},
+		{name: "seller code from buyer", code: "013", initiatedBy: models.CancellationInitiatorBuyer},
+		{name: "buyer code from seller", code: "001", initiatedBy: models.CancellationInitiatorSeller},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := catalog.Reason(tt.code, tt.initiatedBy)
+			require.NotNil(t, err)
+			assert.Equal(t, 60009, err.Code)
+		})
+	}
+}
+
+func TestCatalog_TriggersRTO(t *testing.T) {
+	catalog := defaultCatalog(t)
+
+	assert.False(t, catalog.TriggersRTO("001", "Agent-assigned"))
+	assert.True(t, catalog.TriggersRTO("001", "Order-picked-up"))
+	assert.True(t, catalog.TriggersRTO("013", "Out-for-delivery"))
+	assert.False(t, catalog.TriggersRTO("017", "Out-for-delivery"))
+	assert.False(t, catalog.TriggersRTO("996", "In-transit"))
+	assert.False(t, catalog.TriggersRTO("999", "In-transit"))
+}
+
+func TestCatalog_Fee(t *testing.T) {
+	catalog := defaultCatalog(t)
+
+	tests := []struct {
+		name       string
+		state      string
+		code       string
+		orderValue float64
+		want       float64
+	}{
+		{name: "pending is free", state: "Pending", code: "001", orderValue: 120, want: 0},
+		{name: "agent assigned flat fee", state: "Agent-assigned", code: "001", orderValue: 120, want: 50},
+		{name: "agent assigned capped by order value", state: "Agent-assigned", code: "003", orderValue: 40, want: 40},
+		{name: "picked up pays full value", state: "Order-picked-up", code: "001", orderValue: 120.456, want: 120.46},
+		{name: "rto after buyer unreachable", state: "Out-for-delivery", code: "013", orderValue: 120, want: 120},
+		{name: "lsp failure is free", state: "Out-for-delivery", code: "017", orderValue: 120, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, term := catalog.Fee(tt.state, tt.code, tt.orderValue)
+			require.NotNil(t, term)
+			assert.Equal(t, tt.want, fee)
+		})
+	}
+
+	noTerms, err := NewCatalog(DefaultReasons(), nil)
+	require.NoError(t, err)
+	fee, term := noTerms.Fee("Agent-assigned", "001", 120)
+	assert.Zero(t, fee)
+	assert.Nil(t, term)
+
+	// An unknown pre-cancel state is not charged, even by a catch-all term
+	fee, term = catalog.Fee("", "013", 120)
+	assert.Zero(t, fee)
+	assert.Nil(t, term)
+}
+
+func TestNewCatalog_Invalid(t *testing.T) {
+	reasons := []models.CancellationReason{{Code: "001", InitiatedBy: models.CancellationInitiatorBuyer}}
+
+	_, err := NewCatalog(nil, nil)
+	assert.Error(t, err)
+	_, err = NewCatalog(append(reasons, reasons[0]), nil)
+	assert.Error(t, err)
+	_, err = NewCatalog([]models.CancellationReason{{Code: "001", InitiatedBy: "lsp"}}, nil)
+	assert.Error(t, err)
+	_, err = NewCatalog(reasons, []models.CancellationTerm{{FulfillmentState: "*", ReasonCodes: []string{"*"}, FeePercentage: "150"}})
+	assert.Error(t, err)
+	_, err = NewCatalog(reasons, []models.CancellationTerm{{FulfillmentState: "*", ReasonCodes: []string{"*"}, FeeAmount: "fifty"}})
+	assert.Error(t, err)
+	_, err = NewCatalog(reasons, []models.CancellationTerm{{ReasonCodes: []string{"*"}}})
+	assert.Error(t, err)
+}
+
+func TestLoadCatalog_File(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cancellation.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"terms":[
+		{"fulfillment_state":"*","reason_codes":["*"],"fee_amount":"25.00"}
+	]}`), 0o600))
+
+	catalog, err := LoadCatalog(path)
+	require.NoError(t, err)
+
+	// Reasons fall back to the built-in catalog
+	_, reasonErr := catalog.Reason("011", models.CancellationInitiatorSeller)
+	assert.Nil(t, reasonErr)
+	fee, _ := catalog.Fee("Pending", "001", 120)
+	assert.Equal(t, 25.0, fee)
+
+	_, err = LoadCatalog(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"reasons":`), 0o600))
+	_, err = LoadCatalog(invalid)
+	assert.Error(t, err)
+}
@@ -177,6 +177,29 @@ func (cb *CircuitBreaker) GetState() State {
 	return cb.state
 }
 
+// IsOpen reports whether calls are currently rejected, without using a half-open slot
+// Once the open timeout has elapsed the breaker lets a probe through, so IsOpen returns false
+// until the half-open probes are taken; it stays true while they are in flight
+func (cb *CircuitBreaker) IsOpen() bool {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	switch cb.state {
+	case StateOpen:
+		return time.Since(cb.lastFailure) < cb.config.Timeout
+	case StateHalfOpen:
+		return cb.halfOpenCount >= cb.config.MaxRequestsHalfOpen
+	default:
+		return false
+	}
+}
+
+// GetLastFailure returns the time of the most recent failed call
+func (cb *CircuitBreaker) GetLastFailure() time.Time {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	return cb.lastFailure
+}
+
 // GetFailureCount returns the current failure count
 func (cb *CircuitBreaker) GetFailureCount() int {
 	cb.mu.RLock()
@@ -189,3 +189,36 @@ func TestCircuitBreaker_MaxRequestsHalfOpen(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, ErrCircuitBreakerHalfOpen, err)
 }
+
+func TestCircuitBreaker_IsOpen(t *testing.T) {
+	config := Config{
+		FailureThreshold:    2,
+		SuccessThreshold:    1,
+		Timeout:             50 * time.Millisecond,
+		MaxRequestsHalfOpen: 1,
+	}
+	cb := NewCircuitBreaker(config)
+	assert.False(t, cb.IsOpen())
+
+	for i := 0; i < 2; i++ {
+		_ = cb.Execute(context.Background(), func() error {
+			return errors.New("failure")
+		})
+	}
+	assert.True(t, cb.IsOpen())
+	assert.WithinDuration(t, time.Now(), cb.GetLastFailure(), time.Second)
+
+	// After the timeout a probe is allowed, and checking does not consume the half-open slot
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, cb.IsOpen())
+	assert.False(t, cb.IsOpen())
+
+	// While the probe is in flight further calls are rejected
+	err := cb.Execute(context.Background(), func() error {
+		assert.True(t, cb.IsOpen())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, StateClosed, cb.GetState())
+	assert.False(t, cb.IsOpen())
+}
@@ -58,3 +58,16 @@ func (r *InMemoryClientRegistry) GetByClientID(ctx context.Context, clientID str
 	}
 	return client, nil
 }
+
+// GetByBapID retrieves a client by its registered ONDC bap_id
+func (r *InMemoryClientRegistry) GetByBapID(ctx context.Context, bapID string) (*models.Client, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, client := range r.clients {
+		if client.BapID() == bapID {
+			return client, nil
+		}
+	}
+	return nil, errors.NewDomainError(65006, "client not found", "bap_id not found")
+}
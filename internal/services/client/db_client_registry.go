@@ -24,6 +24,8 @@ type RedisClient interface {
 // ClientRegistryRepository interface for database operations
 type ClientRegistryRepository interface {
 	GetByClientID(ctx context.Context, clientID string) (*models.Client, error)
+	GetByBapID(ctx context.Context, bapID string) (*models.Client, error)
+	ListClients(ctx context.Context) ([]*models.Client, error)
 	UpsertClient(ctx context.Context, client *models.Client) error
 	UpdateStatus(ctx context.Context, clientID, status string) error
 }
@@ -92,6 +94,39 @@ func (r *DBClientRegistry) GetByClientID(ctx context.Context, clientID string) (
 	return client, nil
 }
 
+// GetByBapID retrieves a client by its registered ONDC bap_id
+// Caches only the bap_id -> client_id mapping so status changes invalidated
+// on the client_id key are picked up immediately.
+func (r *DBClientRegistry) GetByBapID(ctx context.Context, bapID string) (*models.Client, error) {
+	cacheKey := fmt.Sprintf("client:bap:%s", bapID)
+
+	if clientID, err := r.redis.Get(ctx, cacheKey).Result(); err == nil && clientID != "" {
+		client, err := r.GetByClientID(ctx, clientID)
+		if err == nil && client.BapID() == bapID {
+			return client, nil
+		}
+		r.logger.Debug("stale bap_id mapping, reloading", zap.String("bap_id", bapID))
+	}
+
+	client, err := r.repo.GetByBapID(ctx, bapID)
+	if err != nil {
+		return nil, err
+	}
+	client.NormalizeIPs(&zapCIDRLogger{logger: r.logger})
+
+	cacheTTL := time.Duration(r.config.TTL.ClientRegistryCache) * time.Second
+	if err := r.redis.Set(ctx, cacheKey, client.ID, cacheTTL).Err(); err != nil {
+		r.logger.Warn("failed to cache bap_id mapping", zap.Error(err), zap.String("bap_id", bapID))
+	}
+
+	return client, nil
+}
+
+// ListClients lists every client in the registry straight from the DB (used by reconciliation, not cached)
+func (r *DBClientRegistry) ListClients(ctx context.Context) ([]*models.Client, error) {
+	return r.repo.ListClients(ctx)
+}
+
 // UpsertClient upserts a client (updates DB and invalidates cache)
 func (r *DBClientRegistry) UpsertClient(ctx context.Context, client *models.Client) error {
 	if err := r.repo.UpsertClient(ctx, client); err != nil {
@@ -28,6 +28,22 @@ func (m *MockClientRegistryRepository) GetByClientID(ctx context.Context, client
 	return args.Get(0).(*models.Client), args.Error(1)
 }
 
+func (m *MockClientRegistryRepository) GetByBapID(ctx context.Context, bapID string) (*models.Client, error) {
+	args := m.Called(ctx, bapID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Client), args.Error(1)
+}
+
+func (m *MockClientRegistryRepository) ListClients(ctx context.Context) ([]*models.Client, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Client), args.Error(1)
+}
+
 func (m *MockClientRegistryRepository) UpsertClient(ctx context.Context, client *models.Client) error {
 	args := m.Called(ctx, client)
 	return args.Error(0)
@@ -205,3 +221,66 @@ func TestDBClientRegistry_UpdateStatus_Success(t *testing.T) {
 	err := registry.UpdateStatus(context.Background(), clientID, status)
 	assert.NoError(t, err)
 }
+
+func TestDBClientRegistry_GetByBapID_CacheMiss(t *testing.T) {
+	cfg := config.Config{TTL: config.TTLConfig{ClientRegistryCache: 300}}
+	mockRepo := new(MockClientRegistryRepository)
+	mockRedis := new(MockRedisClient)
+	registry := NewDBClientRegistry(mockRepo, mockRedis, cfg, zap.NewNop())
+
+	client := &models.Client{
+		ID:       "client-123",
+		Status:   models.ClientStatusActive,
+		Metadata: map[string]interface{}{"bap_id": "buyer.com"},
+	}
+
+	mockRedis.On("Get", mock.Anything, "client:bap:buyer.com").Return(nil, redis.Nil)
+	mockRepo.On("GetByBapID", mock.Anything, "buyer.com").Return(client, nil)
+	mockRedis.On("Set", mock.Anything, "client:bap:buyer.com", "client-123", 300*time.Second).Return(nil)
+
+	result, err := registry.GetByBapID(context.Background(), "buyer.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "client-123", result.ID)
+	mockRepo.AssertExpectations(t)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestDBClientRegistry_GetByBapID_CachedMapping(t *testing.T) {
+	cfg := config.Config{TTL: config.TTLConfig{ClientRegistryCache: 300}}
+	mockRepo := new(MockClientRegistryRepository)
+	mockRedis := new(MockRedisClient)
+	registry := NewDBClientRegistry(mockRepo, mockRedis, cfg, zap.NewNop())
+
+	client := &models.Client{
+		ID:       "client-123",
+		Status:   models.ClientStatusActive,
+		Metadata: map[string]interface{}{"bap_id": "buyer.com"},
+	}
+	clientJSON, _ := json.Marshal(client)
+
+	mockRedis.On("Get", mock.Anything, "client:bap:buyer.com").Return("client-123", nil)
+	mockRedis.On("Get", mock.Anything, "client:client-123").Return(string(clientJSON), nil)
+
+	result, err := registry.GetByBapID(context.Background(), "buyer.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "client-123", result.ID)
+	mockRepo.AssertNotCalled(t, "GetByBapID", mock.Anything, mock.Anything)
+}
+
+func TestDBClientRegistry_GetByBapID_NotFound(t *testing.T) {
+	cfg := config.Config{TTL: config.TTLConfig{ClientRegistryCache: 300}}
+	mockRepo := new(MockClientRegistryRepository)
+	mockRedis := new(MockRedisClient)
+	registry := NewDBClientRegistry(mockRepo, mockRedis, cfg, zap.NewNop())
+
+	mockRedis.On("Get", mock.Anything, "client:bap:unknown.com").Return(nil, redis.Nil)
+	mockRepo.On("GetByBapID", mock.Anything, "unknown.com").
+		Return(nil, domainErrors.NewDomainError(65006, "client not found", "bap_id unknown.com not found"))
+
+	result, err := registry.GetByBapID(context.Background(), "unknown.com")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
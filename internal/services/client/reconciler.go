@@ -0,0 +1,238 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"uois-gateway/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// defaultRateLimit is the client_registry.clients.rate_limit default applied when a client has none
+const defaultRateLimit = 100
+
+// UpstreamClients lists the clients registered in Admin Service (implemented by the admin client)
+type UpstreamClients interface {
+	ListClients(ctx context.Context) ([]*models.Client, error)
+}
+
+// RegistryStore is the local client registry the reconciler writes to (implemented by DBClientRegistry)
+// UpsertClient and UpdateStatus must evict the cached client so the change is seen on the next request
+type RegistryStore interface {
+	ListClients(ctx context.Context) ([]*models.Client, error)
+	UpsertClient(ctx context.Context, client *models.Client) error
+	UpdateStatus(ctx context.Context, clientID, status string) error
+}
+
+// ReconcileResult summarizes one reconciliation pass
+type ReconcileResult struct {
+	Upstream  int // Clients listed by Admin Service
+	Created   int // Missing locally, inserted
+	Updated   int // Drifted from upstream, overwritten
+	Revoked   int // Deleted upstream, revoked locally
+	Unchanged int
+	Failed    int // Writes that failed; retried on the next pass
+}
+
+// Reconciler keeps client_registry.clients in line with Admin Service
+// client.* events keep the registry current; a full listing at startup and every
+// ADMIN_CLIENT_RECONCILE_INTERVAL catches up on events that were missed, including clients
+// deleted upstream (which never get an event). Deleted clients are revoked rather than removed
+// so order records and audit rows keep resolving their client_id.
+type Reconciler struct {
+	upstream UpstreamClients
+	registry RegistryStore
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// NewReconciler creates a client registry reconciler
+// interval <= 0 reconciles only once at startup
+func NewReconciler(upstream UpstreamClients, registry RegistryStore, interval time.Duration, logger *zap.Logger) *Reconciler {
+	return &Reconciler{
+		upstream: upstream,
+		registry: registry,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run reconciles immediately, then every interval until ctx is cancelled
+func (r *Reconciler) Run(ctx context.Context) {
+	r.reconcileAndLog(ctx)
+	if r.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.reconcileAndLog(ctx)
+		}
+	}
+}
+
+func (r *Reconciler) reconcileAndLog(ctx context.Context) {
+	result, err := r.ReconcileOnce(ctx)
+	if err != nil {
+		r.logger.Error("client registry reconciliation failed", zap.Error(err))
+		return
+	}
+
+	r.logger.Info("client registry reconciled",
+		zap.Int("upstream", result.Upstream),
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+		zap.Int("revoked", result.Revoked),
+		zap.Int("unchanged", result.Unchanged),
+		zap.Int("failed", result.Failed),
+	)
+}
+
+// ReconcileOnce runs a single full sync
+// An error means nothing was compared (upstream or local listing failed); failed writes are
+// counted in the result and retried on the next pass.
+func (r *Reconciler) ReconcileOnce(ctx context.Context) (ReconcileResult, error) {
+	var result ReconcileResult
+
+	upstream, err := r.upstream.ListClients(ctx)
+	if err != nil {
+		return result, err
+	}
+	local, err := r.registry.ListClients(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.Upstream = len(upstream)
+
+	localByID := make(map[string]*models.Client, len(local))
+	for _, client := range local {
+		localByID[client.ID] = client
+	}
+
+	for _, client := range upstream {
+		existing, ok := localByID[client.ID]
+		delete(localByID, client.ID)
+
+		if ok {
+			drift := clientDrift(existing, client)
+			if len(drift) == 0 {
+				result.Unchanged++
+				continue
+			}
+			r.logger.Info("client drifted from Admin Service", zap.String("client_id", client.ID), zap.Strings("fields", drift))
+		}
+
+		if err := r.registry.UpsertClient(ctx, client); err != nil {
+			r.logger.Warn("failed to sync client", zap.Error(err), zap.String("client_id", client.ID))
+			result.Failed++
+			continue
+		}
+		if ok {
+			result.Updated++
+		} else {
+			result.Created++
+		}
+	}
+
+	// Whatever is left exists locally but not upstream
+	if len(upstream) == 0 && len(localByID) > 0 {
+		// An empty listing is far more likely an Admin Service fault than every client being deleted
+		r.logger.Warn("Admin Service listed no clients, not revoking local clients", zap.Int("local", len(localByID)))
+		return result, nil
+	}
+	for clientID, client := range localByID {
+		if client.Status == models.ClientStatusRevoked {
+			continue
+		}
+
+		r.logger.Warn("client deleted upstream, revoking", zap.String("client_id", clientID), zap.String("client_code", client.ClientCode))
+		if err := r.registry.UpdateStatus(ctx, clientID, models.ClientStatusRevoked); err != nil {
+			r.logger.Warn("failed to revoke client", zap.Error(err), zap.String("client_id", clientID))
+			result.Failed++
+			continue
+		}
+		result.Revoked++
+	}
+
+	return result, nil
+}
+
+// clientDrift returns the names of the fields where local differs from upstream
+func clientDrift(local, upstream *models.Client) []string {
+	var drift []string
+	if local.ClientCode != upstream.ClientCode {
+		drift = append(drift, "client_code")
+	}
+	if local.ClientSecretHash != upstream.ClientSecretHash {
+		drift = append(drift, "client_secret_hash")
+	}
+	if local.Status != upstream.Status {
+		drift = append(drift, "status")
+	}
+	if !sameIPs(local.AllowedIPs, upstream.AllowedIPs) {
+		drift = append(drift, "allowed_ips")
+	}
+	if local.BapID() != upstream.BapID() {
+		drift = append(drift, "bap_id")
+	}
+	if local.BapURI() != upstream.BapURI() {
+		drift = append(drift, "bap_uri")
+	}
+	if rateLimit(local) != rateLimit(upstream) {
+		drift = append(drift, "rate_limit")
+	}
+	if !sameMetadata(local.Metadata, upstream.Metadata) {
+		drift = append(drift, "metadata")
+	}
+	return drift
+}
+
+func sameIPs(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// rateLimit reads Metadata["rate_limit"], which is int64 from the DB or Admin Service and float64 once decoded from JSON
+func rateLimit(client *models.Client) int64 {
+	switch val := client.Metadata["rate_limit"].(type) {
+	case int64:
+		if val > 0 {
+			return val
+		}
+	case float64:
+		if val > 0 {
+			return int64(val)
+		}
+	}
+	return defaultRateLimit
+}
+
+// sameMetadata compares metadata apart from the keys that mirror dedicated columns
+// Both sides are compared as JSON so numeric types do not cause false drift
+func sameMetadata(a, b map[string]interface{}) bool {
+	return metadataJSON(a) == metadataJSON(b)
+}
+
+func metadataJSON(metadata map[string]interface{}) string {
+	rest := make(map[string]interface{}, len(metadata))
+	for key, val := range metadata {
+		switch key {
+		case "bap_id", "bap_uri", "rate_limit":
+			continue
+		}
+		rest[key] = val
+	}
+	raw, _ := json.Marshal(rest)
+	return string(raw)
+}
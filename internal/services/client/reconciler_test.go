@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"uois-gateway/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockUpstreamClients struct {
+	mock.Mock
+}
+
+func (m *MockUpstreamClients) ListClients(ctx context.Context) ([]*models.Client, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Client), args.Error(1)
+}
+
+type MockRegistryStore struct {
+	mock.Mock
+}
+
+func (m *MockRegistryStore) ListClients(ctx context.Context) ([]*models.Client, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Client), args.Error(1)
+}
+
+func (m *MockRegistryStore) UpsertClient(ctx context.Context, client *models.Client) error {
+	args := m.Called(ctx, client)
+	return args.Error(0)
+}
+
+func (m *MockRegistryStore) UpdateStatus(ctx context.Context, clientID, status string) error {
+	args := m.Called(ctx, clientID, status)
+	return args.Error(0)
+}
+
+func registryClient(id, secretHash string) *models.Client {
+	return &models.Client{
+		ID:               id,
+		ClientCode:       "CODE-" + id,
+		ClientSecretHash: secretHash,
+		AllowedIPs:       []string{"10.0.0.0/8", "192.168.1.0/24"},
+		Status:           models.ClientStatusActive,
+		Metadata:         map[string]interface{}{"bap_id": "buyer." + id, "rate_limit": int64(100)},
+	}
+}
+
+func TestReconciler_ReconcileOnce(t *testing.T) {
+	upstream := new(MockUpstreamClients)
+	registry := new(MockRegistryStore)
+
+	unchangedLocal := registryClient("unchanged", "hash")
+	// Same client as read back from the DB: metadata went through JSON, IPs in another order
+	unchangedLocal.AllowedIPs = []string{"192.168.1.0/24", "10.0.0.0/8"}
+	unchangedLocal.Metadata = map[string]interface{}{"bap_id": "buyer.unchanged", "rate_limit": float64(100)}
+
+	rotated := registryClient("rotated", "new-hash")
+	created := registryClient("created", "hash")
+
+	upstream.On("ListClients", mock.Anything).Return([]*models.Client{registryClient("unchanged", "hash"), rotated, created}, nil)
+	registry.On("ListClients", mock.Anything).Return([]*models.Client{
+		unchangedLocal,
+		registryClient("rotated", "old-hash"),
+		registryClient("deleted", "hash"),
+		{ID: "already-revoked", Status: models.ClientStatusRevoked},
+	}, nil)
+	registry.On("UpsertClient", mock.Anything, rotated).Return(nil)
+	registry.On("UpsertClient", mock.Anything, created).Return(nil)
+	registry.On("UpdateStatus", mock.Anything, "deleted", models.ClientStatusRevoked).Return(nil)
+
+	reconciler := NewReconciler(upstream, registry, 0, zap.NewNop())
+	result, err := reconciler.ReconcileOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, ReconcileResult{Upstream: 3, Created: 1, Updated: 1, Revoked: 1, Unchanged: 1}, result)
+	registry.AssertExpectations(t)
+	registry.AssertNotCalled(t, "UpdateStatus", mock.Anything, "already-revoked", mock.Anything)
+}
+
+func TestReconciler_EmptyUpstreamDoesNotRevoke(t *testing.T) {
+	upstream := new(MockUpstreamClients)
+	registry := new(MockRegistryStore)
+	upstream.On("ListClients", mock.Anything).Return([]*models.Client{}, nil)
+	registry.On("ListClients", mock.Anything).Return([]*models.Client{registryClient("client-1", "hash")}, nil)
+
+	reconciler := NewReconciler(upstream, registry, 0, zap.NewNop())
+	result, err := reconciler.ReconcileOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Zero(t, result.Revoked)
+	registry.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReconciler_UpstreamError(t *testing.T) {
+	upstream := new(MockUpstreamClients)
+	registry := new(MockRegistryStore)
+	upstream.On("ListClients", mock.Anything).Return(nil, errors.New("admin service unavailable"))
+
+	reconciler := NewReconciler(upstream, registry, 0, zap.NewNop())
+	_, err := reconciler.ReconcileOnce(context.Background())
+
+	assert.Error(t, err)
+	registry.AssertNotCalled(t, "ListClients", mock.Anything)
+}
+
+func TestReconciler_FailedWriteIsCounted(t *testing.T) {
+	upstream := new(MockUpstreamClients)
+	registry := new(MockRegistryStore)
+	upstream.On("ListClients", mock.Anything).Return([]*models.Client{registryClient("client-1", "hash")}, nil)
+	registry.On("ListClients", mock.Anything).Return([]*models.Client{}, nil)
+	registry.On("UpsertClient", mock.Anything, mock.Anything).Return(errors.New("database error"))
+
+	reconciler := NewReconciler(upstream, registry, 0, zap.NewNop())
+	result, err := reconciler.ReconcileOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, ReconcileResult{Upstream: 1, Failed: 1}, result)
+}
+
+func TestClientDrift(t *testing.T) {
+	local := registryClient("client-1", "hash")
+	upstream := registryClient("client-1", "hash")
+	assert.Empty(t, clientDrift(local, upstream))
+
+	upstream.Status = models.ClientStatusSuspended
+	upstream.Metadata = map[string]interface{}{"bap_id": "buyer.moved", "auth_mode": models.ClientAuthModeONDCSignature}
+	assert.Equal(t, []string{"status", "bap_id", "metadata"}, clientDrift(local, upstream))
+}
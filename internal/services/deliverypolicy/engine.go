@@ -0,0 +1,380 @@
+package deliverypolicy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // Rule windows use DELIVERY_POLICY_TIMEZONE; images may ship without a zoneinfo database
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+	"uois-gateway/internal/utils"
+	"uois-gateway/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// DefaultRules are the delivery categories Dispatch supports out of the box:
+// "Immediate Delivery" (time.duration <= PT60M when given) and "Standard Delivery"
+// with the immediate subcategory (time.duration required, <= PT60M)
+func DefaultRules() []models.DeliveryCategoryRule {
+	return []models.DeliveryCategoryRule{
+		{CategoryID: "Immediate Delivery", MaxTAT: "PT60M"},
+		{CategoryID: "Standard Delivery", MaxTAT: "PT60M", RequireTAT: true},
+	}
+}
+
+// rule is a DeliveryCategoryRule with its durations and windows parsed
+type rule struct {
+	models.DeliveryCategoryRule
+	maxTAT  time.Duration // 0 means no upper bound
+	windows []window
+}
+
+// window holds a DeliveryWindow as minutes since local midnight
+type window struct {
+	start, end int
+}
+
+func (w window) contains(minute int) bool {
+	if w.start <= w.end {
+		return minute >= w.start && minute < w.end
+	}
+	return minute >= w.start || minute < w.end
+}
+
+// Engine decides which delivery categories the gateway accepts and offers
+// /search, /init and on_search catalog generation all evaluate the same rules, which come
+// from a RuleSource and are refreshed every DELIVERY_POLICY_REFRESH_INTERVAL so a category
+// can be opened in a city without a redeploy.
+type Engine struct {
+	source   RuleSource
+	interval time.Duration
+	location *time.Location
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu    sync.RWMutex
+	rules []rule
+}
+
+// NewEngine creates a delivery policy engine and loads its rules
+// Fails if the source cannot be read or holds invalid rules, so a bad rollout is caught at startup
+func NewEngine(ctx context.Context, source RuleSource, cfg config.DeliveryPolicyConfig, logger *zap.Logger) (*Engine, error) {
+	location := time.UTC
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid delivery policy timezone %q: %w", cfg.Timezone, err)
+		}
+		location = loc
+	}
+
+	engine := &Engine{
+		source:   source,
+		interval: cfg.RefreshInterval,
+		location: location,
+		logger:   logger,
+		now:      time.Now,
+	}
+
+	if err := engine.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	return engine, nil
+}
+
+// Reload re-reads the rules from the source
+// The current rules are kept if the source fails, is empty or holds an invalid rule
+func (e *Engine) Reload(ctx context.Context) error {
+	sourceRules, err := e.source.ListRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load delivery category rules: %w", err)
+	}
+	if len(sourceRules) == 0 {
+		return fmt.Errorf("delivery policy source has no rules")
+	}
+
+	rules := make([]rule, 0, len(sourceRules))
+	for i, sourceRule := range sourceRules {
+		compiled, err := compileRule(sourceRule)
+		if err != nil {
+			return fmt.Errorf("delivery category rule %d (%s): %w", i, sourceRule.CategoryID, err)
+		}
+		rules = append(rules, compiled)
+	}
+
+	e.mu.Lock()
+	e.rules = rules
+	e.mu.Unlock()
+
+	e.logger.Info("delivery category policy loaded", zap.Int("rules", len(rules)), zap.Strings("categories", categoriesOf(rules)))
+	return nil
+}
+
+// Run reloads the rules every refresh interval until ctx is cancelled
+// No-op when the interval is 0
+func (e *Engine) Run(ctx context.Context) {
+	if e.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.Reload(ctx); err != nil {
+				e.logger.Error("delivery category policy reload failed, keeping current rules", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Validate checks a requested category against the policy
+// An empty category is accepted (category is optional in /search and /init).
+// Returns 66002 naming the condition that failed for the closest matching rules.
+func (e *Engine) Validate(req models.DeliveryPolicyRequest) *errors.DomainError {
+	if req.CategoryID == "" {
+		return nil
+	}
+
+	tat, err := parseTAT(req.TAT)
+	if err != nil {
+		return orderValidationFailure(fmt.Sprintf("invalid time.duration %q for delivery category '%s'", req.TAT, req.CategoryID))
+	}
+
+	candidates := e.rulesFor(req.CategoryID)
+	if len(candidates) == 0 {
+		return orderValidationFailure(fmt.Sprintf("unsupported delivery category '%s'", req.CategoryID))
+	}
+
+	candidates = filterRules(candidates, func(r rule) bool { return r.appliesTo(req) })
+	if len(candidates) == 0 {
+		return orderValidationFailure(fmt.Sprintf("delivery category '%s' is not available for this city or client", req.CategoryID))
+	}
+
+	minute := e.minuteOfDay()
+	candidates = filterRules(candidates, func(r rule) bool { return r.openAt(minute) })
+	if len(candidates) == 0 {
+		return orderValidationFailure(fmt.Sprintf("delivery category '%s' is not available at this time", req.CategoryID))
+	}
+
+	for _, candidate := range candidates {
+		if candidate.allowsTAT(tat) {
+			return nil
+		}
+	}
+	return orderValidationFailure(tatFailure(req, candidates))
+}
+
+// Categories returns the categories the policy offers for req, in rule order
+// req.CategoryID is ignored; a TAT that cannot be parsed is treated as unknown.
+// With req.COD only categories that allow cash on delivery are offered.
+func (e *Engine) Categories(req models.DeliveryPolicyRequest) []string {
+	tat, err := parseTAT(req.TAT)
+	if err != nil {
+		tat = 0
+	}
+	minute := e.minuteOfDay()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var categories []string
+	for _, r := range e.rules {
+		if slices.Contains(categories, r.CategoryID) {
+			continue
+		}
+		if req.COD && !r.COD {
+			continue
+		}
+		if r.appliesTo(req) && r.openAt(minute) && r.allowsTAT(tat) {
+			categories = append(categories, r.CategoryID)
+		}
+	}
+	return categories
+}
+
+// AllowsCOD reports whether cash on delivery (ON-FULFILLMENT) is enabled for the requested
+// category in req's city and for req's client. With no category, COD is allowed when any
+// category available to the city and client enables it.
+func (e *Engine) AllowsCOD(req models.DeliveryPolicyRequest) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, r := range e.rules {
+		if req.CategoryID != "" && r.CategoryID != req.CategoryID {
+			continue
+		}
+		if r.COD && r.appliesTo(req) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) rulesFor(categoryID string) []rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var matched []rule
+	for _, r := range e.rules {
+		if r.CategoryID == categoryID {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+func (e *Engine) minuteOfDay() int {
+	local := e.now().In(e.location)
+	return local.Hour()*60 + local.Minute()
+}
+
+func (r rule) appliesTo(req models.DeliveryPolicyRequest) bool {
+	if len(r.CityCodes) > 0 && !slices.Contains(r.CityCodes, req.CityCode) {
+		return false
+	}
+	if len(r.ClientIDs) > 0 && !slices.Contains(r.ClientIDs, req.ClientID) {
+		return false
+	}
+	return true
+}
+
+func (r rule) openAt(minute int) bool {
+	if len(r.windows) == 0 {
+		return true
+	}
+	for _, w := range r.windows {
+		if w.contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsTAT checks the requested TAT (0 when not given) against the rule's bounds
+func (r rule) allowsTAT(tat time.Duration) bool {
+	if tat == 0 {
+		return !r.RequireTAT
+	}
+	return r.maxTAT == 0 || tat <= r.maxTAT
+}
+
+// tatFailure describes why none of the remaining rules accepted the requested TAT
+func tatFailure(req models.DeliveryPolicyRequest, candidates []rule) string {
+	var limit time.Duration
+	var limitText string
+	for _, candidate := range candidates {
+		if candidate.maxTAT > limit {
+			limit = candidate.maxTAT
+			limitText = candidate.MaxTAT
+		}
+	}
+
+	if req.TAT == "" {
+		if limitText != "" {
+			return fmt.Sprintf("delivery category '%s' requires time.duration <= %s", req.CategoryID, limitText)
+		}
+		return fmt.Sprintf("delivery category '%s' requires time.duration", req.CategoryID)
+	}
+	return fmt.Sprintf("delivery category '%s' requires time.duration <= %s, got %s", req.CategoryID, limitText, req.TAT)
+}
+
+func orderValidationFailure(reason string) *errors.DomainError {
+	return errors.NewDomainError(66002, "order validation failure", "Order Validation Failed: "+reason)
+}
+
+// parseTAT parses an ONDC time.duration; empty and PT0S (unknown ETA) yield 0
+func parseTAT(value string) (time.Duration, error) {
+	if value == "" || value == "PT0S" {
+		return 0, nil
+	}
+	tat, ok := utils.ParseISO8601Duration(value)
+	if !ok {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", value)
+	}
+	return tat, nil
+}
+
+func compileRule(source models.DeliveryCategoryRule) (rule, error) {
+	compiled := rule{DeliveryCategoryRule: source}
+	if source.CategoryID == "" {
+		return compiled, fmt.Errorf("category_id is required")
+	}
+
+	if source.MaxTAT != "" {
+		maxTAT, ok := utils.ParseISO8601Duration(source.MaxTAT)
+		if !ok {
+			return compiled, fmt.Errorf("invalid max_tat %q", source.MaxTAT)
+		}
+		compiled.maxTAT = maxTAT
+	}
+
+	for _, sourceWindow := range source.Windows {
+		start, err := parseClock(sourceWindow.Start)
+		if err != nil {
+			return compiled, fmt.Errorf("invalid window start: %w", err)
+		}
+		end, err := parseClock(sourceWindow.End)
+		if err != nil {
+			return compiled, fmt.Errorf("invalid window end: %w", err)
+		}
+		if start == end {
+			return compiled, fmt.Errorf("empty window %s-%s", sourceWindow.Start, sourceWindow.End)
+		}
+		compiled.windows = append(compiled.windows, window{start: start, end: end})
+	}
+
+	return compiled, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight ("24:00" is allowed as a window end)
+func parseClock(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	if !ok {
+		return 0, fmt.Errorf("%q is not HH:MM", value)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", value)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", value)
+	}
+	clock := h*60 + m
+	if h < 0 || m < 0 || m > 59 || clock > 24*60 {
+		return 0, fmt.Errorf("%q is out of range", value)
+	}
+	return clock, nil
+}
+
+func filterRules(rules []rule, keep func(rule) bool) []rule {
+	var kept []rule
+	for _, r := range rules {
+		if keep(r) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+func categoriesOf(rules []rule) []string {
+	var categories []string
+	for _, r := range rules {
+		if !slices.Contains(categories, r.CategoryID) {
+			categories = append(categories, r.CategoryID)
+		}
+	}
+	return categories
+}
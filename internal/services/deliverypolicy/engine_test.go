@@ -0,0 +1,168 @@
+package deliverypolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// flakySource returns rules, or err when set
+type flakySource struct {
+	rules []models.DeliveryCategoryRule
+	err   error
+}
+
+func (s *flakySource) ListRules(ctx context.Context) ([]models.DeliveryCategoryRule, error) {
+	return s.rules, s.err
+}
+
+func newTestEngine(t *testing.T, rules []models.DeliveryCategoryRule, at string) *Engine {
+	engine, err := NewEngine(context.Background(), NewStaticSource(rules), config.DeliveryPolicyConfig{Timezone: "Asia/Kolkata"}, zap.NewNop())
+	require.NoError(t, err)
+	now, err := time.Parse(time.RFC3339, at)
+	require.NoError(t, err)
+	engine.now = func() time.Time { return now }
+	return engine
+}
+
+func TestEngine_DefaultRules(t *testing.T) {
+	engine := newTestEngine(t, DefaultRules(), "2026-10-16T06:30:00Z")
+
+	tests := []struct {
+		name     string
+		category string
+		tat      string
+		wantErr  string
+	}{
+		{name: "no category", category: ""},
+		{name: "immediate without TAT", category: "Immediate Delivery"},
+		{name: "immediate within limit", category: "Immediate Delivery", tat: "PT45M"},
+		{name: "immediate over limit", category: "Immediate Delivery", tat: "PT1H30M", wantErr: "'Immediate Delivery' requires time.duration <= PT60M, got PT1H30M"},
+		{name: "standard within limit", category: "Standard Delivery", tat: "PT60M"},
+		{name: "standard without TAT", category: "Standard Delivery", wantErr: "'Standard Delivery' requires time.duration <= PT60M"},
+		{name: "standard over limit", category: "Standard Delivery", tat: "PT60M30S", wantErr: "got PT60M30S"},
+		{name: "same day", category: "Same Day Delivery", wantErr: "unsupported delivery category 'Same Day Delivery'"},
+		{name: "invalid TAT", category: "Immediate Delivery", tat: "30 minutes", wantErr: "invalid time.duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := engine.Validate(models.DeliveryPolicyRequest{CategoryID: tt.category, TAT: tt.tat})
+			if tt.wantErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, 66002, err.Code)
+			assert.Contains(t, err.Details, tt.wantErr)
+		})
+	}
+}
+
+func TestEngine_SameDayByCityClientAndWindow(t *testing.T) {
+	rules := append(DefaultRules(), models.DeliveryCategoryRule{
+		CategoryID: "Same Day Delivery",
+		CityCodes:  []string{"std:080"},
+		ClientIDs:  []string{"client-1"},
+		MaxTAT:     "PT8H",
+		Windows:    []models.DeliveryWindow{{Start: "08:00", End: "16:00"}},
+	})
+	// 06:30Z is 12:00 in Asia/Kolkata
+	engine := newTestEngine(t, rules, "2026-10-16T06:30:00Z")
+	sameDay := models.DeliveryPolicyRequest{CategoryID: "Same Day Delivery", TAT: "PT4H", CityCode: "std:080", ClientID: "client-1"}
+
+	assert.Nil(t, engine.Validate(sameDay))
+
+	otherCity := sameDay
+	otherCity.CityCode = "std:011"
+	err := engine.Validate(otherCity)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Details, "not available for this city or client")
+
+	otherClient := sameDay
+	otherClient.ClientID = "client-2"
+	assert.NotNil(t, engine.Validate(otherClient))
+
+	tooSlow := sameDay
+	tooSlow.TAT = "PT9H"
+	assert.NotNil(t, engine.Validate(tooSlow))
+
+	// 11:00Z is 16:30 in Asia/Kolkata, after the window closes
+	closed := newTestEngine(t, rules, "2026-10-16T11:00:00Z")
+	err = closed.Validate(sameDay)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Details, "not available at this time")
+}
+
+func TestEngine_WindowAcrossMidnight(t *testing.T) {
+	rules := []models.DeliveryCategoryRule{{CategoryID: "Express Delivery", Windows: []models.DeliveryWindow{{Start: "22:00", End: "02:00"}}}}
+	req := models.DeliveryPolicyRequest{CategoryID: "Express Delivery"}
+
+	// 23:30 and 01:00 IST are inside, 12:00 IST is outside
+	assert.Nil(t, newTestEngine(t, rules, "2026-10-16T18:00:00Z").Validate(req))
+	assert.Nil(t, newTestEngine(t, rules, "2026-10-15T19:30:00Z").Validate(req))
+	assert.NotNil(t, newTestEngine(t, rules, "2026-10-16T06:30:00Z").Validate(req))
+}
+
+func TestEngine_Categories(t *testing.T) {
+	rules := []models.DeliveryCategoryRule{
+		{CategoryID: "Immediate Delivery", MaxTAT: "PT60M"},
+		{CategoryID: "Same Day Delivery", CityCodes: []string{"std:080"}, MaxTAT: "PT8H"},
+		{CategoryID: "Same Day Delivery", MaxTAT: "PT4H"},
+	}
+	engine := newTestEngine(t, rules, "2026-10-16T06:30:00Z")
+
+	assert.Equal(t, []string{"Immediate Delivery", "Same Day Delivery"}, engine.Categories(models.DeliveryPolicyRequest{TAT: "PT30M", CityCode: "std:080"}))
+	assert.Equal(t, []string{"Same Day Delivery"}, engine.Categories(models.DeliveryPolicyRequest{TAT: "PT6H", CityCode: "std:080"}))
+	assert.Empty(t, engine.Categories(models.DeliveryPolicyRequest{TAT: "PT6H", CityCode: "std:011"}))
+}
+
+func TestEngine_COD(t *testing.T) {
+	rules := []models.DeliveryCategoryRule{
+		{CategoryID: "Immediate Delivery", MaxTAT: "PT60M"},
+		{CategoryID: "Same Day Delivery", CityCodes: []string{"std:080"}, MaxTAT: "PT8H", COD: true},
+	}
+	engine := newTestEngine(t, rules, "2026-10-16T06:30:00Z")
+
+	assert.False(t, engine.AllowsCOD(models.DeliveryPolicyRequest{CategoryID: "Immediate Delivery", CityCode: "std:080"}))
+	assert.True(t, engine.AllowsCOD(models.DeliveryPolicyRequest{CategoryID: "Same Day Delivery", CityCode: "std:080"}))
+	assert.False(t, engine.AllowsCOD(models.DeliveryPolicyRequest{CategoryID: "Same Day Delivery", CityCode: "std:011"}))
+	assert.True(t, engine.AllowsCOD(models.DeliveryPolicyRequest{CityCode: "std:080"}))
+	assert.False(t, engine.AllowsCOD(models.DeliveryPolicyRequest{CityCode: "std:011"}))
+
+	assert.Equal(t, []string{"Same Day Delivery"}, engine.Categories(models.DeliveryPolicyRequest{TAT: "PT30M", CityCode: "std:080", COD: true}))
+}
+
+func TestEngine_ReloadKeepsRulesOnFailure(t *testing.T) {
+	source := &flakySource{rules: DefaultRules()}
+	engine, err := NewEngine(context.Background(), source, config.DeliveryPolicyConfig{}, zap.NewNop())
+	require.NoError(t, err)
+
+	source.rules, source.err = nil, errors.New("database error")
+	assert.Error(t, engine.Reload(context.Background()))
+
+	source.err = nil
+	assert.Error(t, engine.Reload(context.Background()), "empty rule set must not replace the current rules")
+
+	source.rules = []models.DeliveryCategoryRule{{CategoryID: "Same Day Delivery", Windows: []models.DeliveryWindow{{Start: "8am", End: "16:00"}}}}
+	assert.Error(t, engine.Reload(context.Background()))
+
+	assert.Nil(t, engine.Validate(models.DeliveryPolicyRequest{CategoryID: "Immediate Delivery"}))
+	assert.NotNil(t, engine.Validate(models.DeliveryPolicyRequest{CategoryID: "Same Day Delivery"}))
+}
+
+func TestNewEngine_InvalidRules(t *testing.T) {
+	_, err := NewEngine(context.Background(), NewStaticSource([]models.DeliveryCategoryRule{{CategoryID: "Immediate Delivery", MaxTAT: "60m"}}), config.DeliveryPolicyConfig{}, zap.NewNop())
+	assert.Error(t, err)
+
+	_, err = NewEngine(context.Background(), NewStaticSource(DefaultRules()), config.DeliveryPolicyConfig{Timezone: "Mars/Olympus"}, zap.NewNop())
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package deliverypolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"uois-gateway/internal/models"
+)
+
+// RuleSource supplies the delivery category rules
+// Implemented by StaticSource, FileSource and the delivery_policy repository (DB source)
+type RuleSource interface {
+	ListRules(ctx context.Context) ([]models.DeliveryCategoryRule, error)
+}
+
+// StaticSource serves a fixed rule set (DELIVERY_POLICY_SOURCE=default)
+type StaticSource struct {
+	rules []models.DeliveryCategoryRule
+}
+
+// NewStaticSource creates a source that always returns rules
+func NewStaticSource(rules []models.DeliveryCategoryRule) *StaticSource {
+	return &StaticSource{rules: rules}
+}
+
+// ListRules returns the fixed rules
+func (s *StaticSource) ListRules(ctx context.Context) ([]models.DeliveryCategoryRule, error) {
+	return s.rules, nil
+}
+
+// policyFile is the on-disk format of DELIVERY_POLICY_FILE
+//
+//	{"rules":[{"category_id":"Same Day Delivery","city_codes":["std:080"],"max_tat":"PT8H","windows":[{"start":"08:00","end":"16:00"}],"cod":true}]}
+type policyFile struct {
+	Rules []models.DeliveryCategoryRule `json:"rules"`
+}
+
+// FileSource reads rules from a JSON file (DELIVERY_POLICY_FILE)
+// The file is re-read on every ListRules, so edits apply on the next engine refresh
+type FileSource struct {
+	path string
+}
+
+// NewFileSource creates a source backed by the JSON rules file at path
+func NewFileSource(path string) *FileSource {
+	return &FileSource{path: path}
+}
+
+// ListRules parses the rules file
+func (s *FileSource) ListRules(ctx context.Context) ([]models.DeliveryCategoryRule, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read delivery policy file: %w", err)
+	}
+
+	var file policyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse delivery policy file %s: %w", s.path, err)
+	}
+
+	return file.Rules, nil
+}
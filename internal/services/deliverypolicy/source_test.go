@@ -0,0 +1,41 @@
+package deliverypolicy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uois-gateway/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSource_ListRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "delivery_policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"rules":[
+		{"category_id":"Immediate Delivery","max_tat":"PT60M"},
+		{"category_id":"Same Day Delivery","city_codes":["std:080"],"max_tat":"PT8H","windows":[{"start":"08:00","end":"16:00"}],"cod":true}
+	]}`), 0o600))
+
+	rules, err := NewFileSource(path).ListRules(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.DeliveryCategoryRule{
+		{CategoryID: "Immediate Delivery", MaxTAT: "PT60M"},
+		{CategoryID: "Same Day Delivery", CityCodes: []string{"std:080"}, MaxTAT: "PT8H", Windows: []models.DeliveryWindow{{Start: "08:00", End: "16:00"}}, COD: true},
+	}, rules)
+}
+
+func TestFileSource_ListRules_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewFileSource(filepath.Join(dir, "missing.json")).ListRules(context.Background())
+	assert.Error(t, err)
+
+	path := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"rules":`), 0o600))
+	_, err = NewFileSource(path).ListRules(context.Background())
+	assert.Error(t, err)
+}
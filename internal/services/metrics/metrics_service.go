@@ -33,6 +33,12 @@ type Service struct {
 	timeoutsTotal          *prometheus.CounterVec
 	rateLimitExceededTotal *prometheus.CounterVec
 	callbackRetriesTotal   prometheus.Counter
+	eventsRejectedTotal    *prometheus.CounterVec
+
+	// Stream Consumer Group Metrics
+	streamPendingEntries    *prometheus.GaugeVec
+	streamPendingOldestIdle *prometheus.GaugeVec
+	streamDeadLetteredTotal *prometheus.CounterVec
 
 	// Service Health Metrics
 	serviceAvailability       prometheus.Gauge
@@ -217,6 +223,36 @@ func NewService(serviceName, environment string) *Service {
 				Help: "Callback retry attempts",
 			},
 		),
+		eventsRejectedTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "uois_events_rejected_total",
+				Help: "Total number of consumed stream events dropped by stream, event_type, reason",
+			},
+			[]string{"stream", "event_type", "reason"},
+		),
+
+		// Stream Consumer Group Metrics
+		streamPendingEntries: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "uois_stream_pending_entries",
+				Help: "Consumer group pending entries (read but not ACKed) by stream",
+			},
+			[]string{"stream"},
+		),
+		streamPendingOldestIdle: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "uois_stream_pending_oldest_idle_seconds",
+				Help: "Idle time of the oldest pending entry by stream",
+			},
+			[]string{"stream"},
+		),
+		streamDeadLetteredTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "uois_stream_dead_lettered_total",
+				Help: "Total number of events moved to the dead-letter stream after max deliveries",
+			},
+			[]string{"stream"},
+		),
 
 		// Service Health Metrics
 		serviceAvailability: promauto.NewGauge(
@@ -482,6 +518,26 @@ func (s *Service) RecordEventProcessingDuration(eventType string, duration time.
 	s.eventProcessingDuration.WithLabelValues(eventType).Observe(duration.Seconds())
 }
 
+// RecordEventRejected records a consumed event that could not be decoded or validated
+func (s *Service) RecordEventRejected(stream, eventType, reason string) {
+	s.eventsRejectedTotal.WithLabelValues(stream, eventType, reason).Inc()
+}
+
+// SetStreamPendingEntries sets the consumer group PEL size for a stream
+func (s *Service) SetStreamPendingEntries(stream string, count float64) {
+	s.streamPendingEntries.WithLabelValues(stream).Set(count)
+}
+
+// SetStreamPendingOldestIdle sets the idle time of the oldest pending entry for a stream
+func (s *Service) SetStreamPendingOldestIdle(stream string, idle time.Duration) {
+	s.streamPendingOldestIdle.WithLabelValues(stream).Set(idle.Seconds())
+}
+
+// RecordStreamDeadLettered records an event moved to the dead-letter stream
+func (s *Service) RecordStreamDeadLettered(stream string) {
+	s.streamDeadLetteredTotal.WithLabelValues(stream).Inc()
+}
+
 // RecordDBQueryDuration records database query duration
 func (s *Service) RecordDBQueryDuration(queryType string, duration time.Duration) {
 	s.dbQueryDuration.WithLabelValues(queryType).Observe(duration.Seconds())
@@ -0,0 +1,344 @@
+package ondc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"uois-gateway/pkg/errors"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// keyringReloadDebounce coalesces the burst of events produced by a single key file update
+// (editors and Kubernetes secret mounts write, rename and chmod in quick succession)
+const keyringReloadDebounce = 500 * time.Millisecond
+
+// SigningKey is an ed25519 signing key labelled with its registry ukId
+// A zero ValidFrom/ValidUntil leaves that side of the validity window open
+type SigningKey struct {
+	UkID       string
+	PrivateKey ed25519.PrivateKey
+	PublicKey  ed25519.PublicKey
+	ValidFrom  time.Time
+	ValidUntil time.Time
+	Active     bool
+}
+
+// ValidAt reports whether t falls inside the key's validity window
+func (k *SigningKey) ValidAt(t time.Time) bool {
+	if !k.ValidFrom.IsZero() && t.Before(k.ValidFrom) {
+		return false
+	}
+	if !k.ValidUntil.IsZero() && !t.Before(k.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// PublicKeyInfo describes a keyring key for registry updates (never includes private material)
+type PublicKeyInfo struct {
+	UkID             string    `json:"ukId"`
+	SigningPublicKey string    `json:"signing_public_key"`
+	ValidFrom        time.Time `json:"valid_from,omitzero"`
+	ValidUntil       time.Time `json:"valid_until,omitzero"`
+	Active           bool      `json:"active"`
+	Signing          bool      `json:"signing"` // currently selected for outbound signatures
+}
+
+// keyFile is the on-disk format of one keyring entry (<name>.json in the keyring directory)
+//
+//	{"uk_id":"UK2","private_key":"<base64>","valid_from":"2025-01-01T00:00:00Z","valid_until":"2026-01-01T00:00:00Z","active":true}
+//
+// private_key is the base64 ed25519 private key (64 bytes) or seed (32 bytes); public_key is
+// optional and, when present, must match the private key
+type keyFile struct {
+	UkID       string `json:"uk_id"`
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key,omitempty"`
+	ValidFrom  string `json:"valid_from,omitempty"`
+	ValidUntil string `json:"valid_until,omitempty"`
+	Active     bool   `json:"active"`
+}
+
+// Keyring holds the gateway's signing keys
+// Outbound signatures use the active key that is currently valid; when several qualify the one
+// with the latest valid_from wins, so a rotation is staged by adding the new key as active with a
+// future valid_from while the old key stays registered until its valid_until
+type Keyring struct {
+	dir    string
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu   sync.RWMutex
+	keys []*SigningKey
+}
+
+// NewKeyring creates a static keyring (single key pair from ONDC_PRIVATE_KEY_PATH/ONDC_PUBLIC_KEY_PATH)
+func NewKeyring(keys []*SigningKey, logger *zap.Logger) *Keyring {
+	return &Keyring{
+		logger: logger,
+		now:    time.Now,
+		keys:   sortKeys(keys),
+	}
+}
+
+// LoadKeyring loads a keyring from a directory of key files
+// Fails if the directory holds no active key that is valid now, so a bad rollout is caught at startup
+func LoadKeyring(dir string, logger *zap.Logger) (*Keyring, error) {
+	keyring := &Keyring{
+		dir:    dir,
+		logger: logger,
+		now:    time.Now,
+	}
+
+	if err := keyring.Reload(); err != nil {
+		return nil, err
+	}
+
+	return keyring, nil
+}
+
+// Reload re-reads the keyring directory
+// The current keys are kept if the directory is invalid or has no usable active key
+func (k *Keyring) Reload() error {
+	if k.dir == "" {
+		return nil
+	}
+
+	keys, err := readKeyringDir(k.dir)
+	if err != nil {
+		return err
+	}
+
+	if selectSigningKey(keys, k.now()) == nil {
+		return fmt.Errorf("keyring %s has no active key valid at %s", k.dir, k.now().UTC().Format(time.RFC3339))
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+
+	ukIDs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ukIDs = append(ukIDs, key.UkID)
+	}
+	k.logger.Info("ONDC keyring loaded", zap.String("dir", k.dir), zap.Strings("uk_ids", ukIDs))
+
+	return nil
+}
+
+// SigningKey returns the key to sign outbound payloads with
+func (k *Keyring) SigningKey() (*SigningKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key := selectSigningKey(k.keys, k.now())
+	if key == nil {
+		return nil, errors.NewDomainError(65020, "internal error", "no active signing key")
+	}
+	return key, nil
+}
+
+// Key returns the keyring key with ukID, whether or not it is active or currently valid
+func (k *Keyring) Key(ukID string) (*SigningKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.keys {
+		if key.UkID == ukID {
+			return key, nil
+		}
+	}
+	return nil, errors.NewDomainError(65006, "not found", fmt.Sprintf("signing key %s not in keyring", ukID))
+}
+
+// PublicKeys returns the public half of every key in the keyring, ordered by ukId
+func (k *Keyring) PublicKeys() []PublicKeyInfo {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	signing := selectSigningKey(k.keys, k.now())
+	infos := make([]PublicKeyInfo, 0, len(k.keys))
+	for _, key := range k.keys {
+		infos = append(infos, PublicKeyInfo{
+			UkID:             key.UkID,
+			SigningPublicKey: base64.StdEncoding.EncodeToString(key.PublicKey),
+			ValidFrom:        key.ValidFrom,
+			ValidUntil:       key.ValidUntil,
+			Active:           key.Active,
+			Signing:          key == signing,
+		})
+	}
+	return infos
+}
+
+// Watch reloads the keyring when files in its directory change, until ctx is cancelled
+// No-op for static keyrings
+func (k *Keyring) Watch(ctx context.Context) error {
+	if k.dir == "" {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create keyring watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(k.dir); err != nil {
+		return fmt.Errorf("failed to watch keyring directory: %w", err)
+	}
+
+	debounce := time.NewTimer(keyringReloadDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			debounce.Reset(keyringReloadDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			k.logger.Warn("ONDC keyring watcher error", zap.Error(err))
+		case <-debounce.C:
+			if err := k.Reload(); err != nil {
+				k.logger.Error("ONDC keyring reload failed, keeping current keys", zap.String("dir", k.dir), zap.Error(err))
+			}
+		}
+	}
+}
+
+// selectSigningKey picks the active, currently valid key with the latest valid_from
+func selectSigningKey(keys []*SigningKey, now time.Time) *SigningKey {
+	var selected *SigningKey
+	for _, key := range keys {
+		if !key.Active || !key.ValidAt(now) {
+			continue
+		}
+		if selected == nil || key.ValidFrom.After(selected.ValidFrom) {
+			selected = key
+		}
+	}
+	return selected
+}
+
+// readKeyringDir parses every *.json key file in dir
+func readKeyringDir(dir string) ([]*SigningKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keyring directory: %w", err)
+	}
+
+	keys := make([]*SigningKey, 0, len(paths))
+	seen := make(map[string]string, len(paths))
+	for _, path := range paths {
+		key, err := readKeyFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		if other, ok := seen[key.UkID]; ok {
+			return nil, fmt.Errorf("duplicate ukId %q in %s and %s", key.UkID, other, filepath.Base(path))
+		}
+		seen[key.UkID] = filepath.Base(path)
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("keyring directory %s has no key files", dir)
+	}
+
+	return sortKeys(keys), nil
+}
+
+// readKeyFile parses and validates a single key file
+func readKeyFile(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %w", err)
+	}
+
+	if strings.TrimSpace(file.UkID) == "" {
+		return nil, fmt.Errorf("uk_id is required")
+	}
+
+	privateKeyDecoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(file.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode private key: %w", err)
+	}
+
+	var privateKey ed25519.PrivateKey
+	switch len(privateKeyDecoded) {
+	case ed25519.PrivateKeySize:
+		privateKey = ed25519.PrivateKey(privateKeyDecoded)
+	case ed25519.SeedSize:
+		privateKey = ed25519.NewKeyFromSeed(privateKeyDecoded)
+	default:
+		return nil, fmt.Errorf("invalid private key size: expected %d or %d, got %d", ed25519.PrivateKeySize, ed25519.SeedSize, len(privateKeyDecoded))
+	}
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+
+	if file.PublicKey != "" {
+		publicKeyDecoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(file.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode public key: %w", err)
+		}
+		if !publicKey.Equal(ed25519.PublicKey(publicKeyDecoded)) {
+			return nil, fmt.Errorf("public key does not match private key")
+		}
+	}
+
+	key := &SigningKey{
+		UkID:       file.UkID,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+		Active:     file.Active,
+	}
+
+	if key.ValidFrom, err = parseKeyTime(file.ValidFrom); err != nil {
+		return nil, fmt.Errorf("invalid valid_from: %w", err)
+	}
+	if key.ValidUntil, err = parseKeyTime(file.ValidUntil); err != nil {
+		return nil, fmt.Errorf("invalid valid_until: %w", err)
+	}
+	if !key.ValidFrom.IsZero() && !key.ValidUntil.IsZero() && !key.ValidUntil.After(key.ValidFrom) {
+		return nil, fmt.Errorf("valid_until must be after valid_from")
+	}
+
+	return key, nil
+}
+
+// parseKeyTime parses an optional RFC3339 timestamp
+func parseKeyTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// sortKeys orders keys by ukId so listings are stable across reloads
+func sortKeys(keys []*SigningKey) []*SigningKey {
+	sort.Slice(keys, func(i, j int) bool { return keys[i].UkID < keys[j].UkID })
+	return keys
+}
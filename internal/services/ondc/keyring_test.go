@@ -0,0 +1,184 @@
+package ondc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"uois-gateway/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// writeTestKeyFile writes a keyring key file for ukID and returns its public key
+func writeTestKeyFile(t *testing.T, dir, ukID string, validFrom, validUntil time.Time, active bool) ed25519.PublicKey {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	file := keyFile{
+		UkID:       ukID,
+		PrivateKey: base64.StdEncoding.EncodeToString(privateKey),
+		PublicKey:  base64.StdEncoding.EncodeToString(publicKey),
+		Active:     active,
+	}
+	if !validFrom.IsZero() {
+		file.ValidFrom = validFrom.Format(time.RFC3339)
+	}
+	if !validUntil.IsZero() {
+		file.ValidUntil = validUntil.Format(time.RFC3339)
+	}
+
+	data, err := json.Marshal(file)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ukID+".json"), data, 0600))
+
+	return publicKey
+}
+
+func TestKeyring_LoadKeyring_SelectsLatestActiveKey(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC()
+	writeTestKeyFile(t, dir, "UK1", now.Add(-48*time.Hour), now.Add(time.Hour), true)
+	current := writeTestKeyFile(t, dir, "UK2", now.Add(-time.Hour), time.Time{}, true)
+	writeTestKeyFile(t, dir, "UK3", now.Add(time.Hour), time.Time{}, true)
+	writeTestKeyFile(t, dir, "UK4", now.Add(-time.Minute), time.Time{}, false)
+
+	keyring, err := LoadKeyring(dir, zap.NewNop())
+	require.NoError(t, err)
+
+	key, err := keyring.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, "UK2", key.UkID)
+	assert.Equal(t, current, key.PublicKey)
+
+	keys := keyring.PublicKeys()
+	require.Len(t, keys, 4)
+	assert.Equal(t, []string{"UK1", "UK2", "UK3", "UK4"}, []string{keys[0].UkID, keys[1].UkID, keys[2].UkID, keys[3].UkID})
+	assert.True(t, keys[1].Signing)
+	assert.False(t, keys[2].Signing)
+}
+
+func TestKeyring_SigningKey_StagedKeyTakesOverAtValidFrom(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+	writeTestKeyFile(t, dir, "UK1", now.Add(-time.Hour), now.Add(2*time.Hour), true)
+	writeTestKeyFile(t, dir, "UK2", now.Add(time.Hour), time.Time{}, true)
+
+	keyring, err := LoadKeyring(dir, zap.NewNop())
+	require.NoError(t, err)
+
+	key, err := keyring.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, "UK1", key.UkID)
+
+	keyring.now = func() time.Time { return now.Add(time.Hour) }
+	key, err = keyring.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, "UK2", key.UkID)
+}
+
+func TestKeyring_SigningKey_AllExpired(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC()
+	writeTestKeyFile(t, dir, "UK1", time.Time{}, now.Add(time.Hour), true)
+
+	keyring, err := LoadKeyring(dir, zap.NewNop())
+	require.NoError(t, err)
+
+	keyring.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, err = keyring.SigningKey()
+
+	assert.Error(t, err)
+	domainErr, ok := err.(*errors.DomainError)
+	require.True(t, ok)
+	assert.Equal(t, 65020, domainErr.Code)
+}
+
+func TestKeyring_LoadKeyring_InvalidFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid json", `{`},
+		{"missing uk_id", `{"private_key":"` + base64.StdEncoding.EncodeToString(make([]byte, ed25519.SeedSize)) + `","active":true}`},
+		{"bad key size", `{"uk_id":"UK1","private_key":"` + base64.StdEncoding.EncodeToString([]byte("short")) + `","active":true}`},
+		{"mismatched public key", `{"uk_id":"UK1","private_key":"` + base64.StdEncoding.EncodeToString(make([]byte, ed25519.SeedSize)) + `","public_key":"` + base64.StdEncoding.EncodeToString(make([]byte, ed25519.PublicKeySize)) + `","active":true}`},
+		{"bad window", `{"uk_id":"UK1","private_key":"` + base64.StdEncoding.EncodeToString(make([]byte, ed25519.SeedSize)) + `","valid_from":"2025-02-01T00:00:00Z","valid_until":"2025-01-01T00:00:00Z","active":true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "key.json"), []byte(tt.content), 0600))
+
+			_, err := LoadKeyring(dir, zap.NewNop())
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeyring_LoadKeyring_DuplicateUkID(t *testing.T) {
+	dir := t.TempDir()
+	writeTestKeyFile(t, dir, "UK1", time.Time{}, time.Time{}, true)
+	data, err := os.ReadFile(filepath.Join(dir, "UK1.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "copy.json"), data, 0600))
+
+	_, err = LoadKeyring(dir, zap.NewNop())
+
+	assert.Error(t, err)
+}
+
+func TestKeyring_Reload_KeepsKeysOnInvalidUpdate(t *testing.T) {
+	dir := t.TempDir()
+	writeTestKeyFile(t, dir, "UK1", time.Time{}, time.Time{}, true)
+
+	keyring, err := LoadKeyring(dir, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "UK2.json"), []byte(`{`), 0600))
+	assert.Error(t, keyring.Reload())
+
+	key, err := keyring.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, "UK1", key.UkID)
+}
+
+func TestKeyring_Watch_ReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	writeTestKeyFile(t, dir, "UK1", time.Now().Add(-time.Hour), time.Time{}, true)
+
+	keyring, err := LoadKeyring(dir, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- keyring.Watch(ctx) }()
+	defer func() {
+		cancel()
+		assert.NoError(t, <-done)
+	}()
+
+	// Give the watcher time to register before writing
+	time.Sleep(100 * time.Millisecond)
+	rotated := writeTestKeyFile(t, dir, "UK2", time.Now().Add(-time.Minute), time.Time{}, true)
+
+	assert.Eventually(t, func() bool {
+		key, err := keyring.SigningKey()
+		return err == nil && key.UkID == "UK2" && key.PublicKey.Equal(rotated)
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestKeyring_Watch_StaticKeyringIsNoop(t *testing.T) {
+	keyring := NewKeyring(nil, zap.NewNop())
+
+	assert.NoError(t, keyring.Watch(context.Background()))
+}
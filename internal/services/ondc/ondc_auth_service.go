@@ -13,12 +13,13 @@ import (
 	"uois-gateway/pkg/errors"
 
 	"go.uber.org/zap"
-	"golang.org/x/crypto/blake2b"
 )
 
+// defaultSignatureTTL is used when ONDC_SIGNATURE_TTL_SECONDS is not configured
+const defaultSignatureTTL = time.Hour
+
 // RegistryClient interface for ONDC network registry lookup
-// NOTE: For production optimization, consider implementing an LRU cache with TTL
-// to avoid hot-path registry lookups. This is optional and does not affect correctness.
+// HTTPRegistryClient is the production implementation (cached /lookup)
 type RegistryClient interface {
 	LookupPublicKey(ctx context.Context, subscriberID, ukID string) (string, error)
 }
@@ -28,10 +29,8 @@ type ONDCAuthService struct {
 	registry     RegistryClient
 	config       config.ONDCConfig
 	logger       *zap.Logger
-	privateKey   ed25519.PrivateKey
-	publicKey    ed25519.PublicKey
+	keyring      *Keyring
 	subscriberID string
-	ukID         string
 }
 
 // NewONDCAuthService creates a new ONDC authentication service
@@ -41,7 +40,6 @@ func NewONDCAuthService(registry RegistryClient, cfg config.ONDCConfig, logger *
 		config:       cfg,
 		logger:       logger,
 		subscriberID: cfg.SubscriberID,
-		ukID:         cfg.UkID,
 	}
 
 	if err := service.loadKeys(); err != nil {
@@ -51,8 +49,19 @@ func NewONDCAuthService(registry RegistryClient, cfg config.ONDCConfig, logger *
 	return service, nil
 }
 
-// loadKeys loads ed25519 keys from file paths
+// loadKeys loads the signing keyring
+// ONDC_KEYRING_DIR (multiple ukId-labelled keys, hot-reloadable) takes precedence over the
+// single ONDC_PRIVATE_KEY_PATH/ONDC_PUBLIC_KEY_PATH pair, which is signed as ONDC_UK_ID
 func (s *ONDCAuthService) loadKeys() error {
+	if s.config.KeyringDir != "" {
+		keyring, err := LoadKeyring(s.config.KeyringDir, s.logger)
+		if err != nil {
+			return err
+		}
+		s.keyring = keyring
+		return nil
+	}
+
 	privateKeyBytes, err := os.ReadFile(s.config.PrivateKeyPath)
 	if err != nil {
 		return fmt.Errorf("failed to read private key file: %w", err)
@@ -81,8 +90,12 @@ func (s *ONDCAuthService) loadKeys() error {
 		return fmt.Errorf("invalid public key size: expected %d, got %d", ed25519.PublicKeySize, len(publicKeyDecoded))
 	}
 
-	s.privateKey = ed25519.PrivateKey(privateKeyDecoded)
-	s.publicKey = ed25519.PublicKey(publicKeyDecoded)
+	s.keyring = NewKeyring([]*SigningKey{{
+		UkID:       s.config.UkID,
+		PrivateKey: ed25519.PrivateKey(privateKeyDecoded),
+		PublicKey:  ed25519.PublicKey(publicKeyDecoded),
+		Active:     true,
+	}}, s.logger)
 
 	return nil
 }
@@ -95,25 +108,30 @@ func (s *ONDCAuthService) VerifyRequestSignature(ctx context.Context, authHeader
 		return errors.NewDomainError(65002, "authentication failed", "empty authorization header")
 	}
 
-	authParams, err := s.parseAuthHeader(authHeader)
+	params, err := ParseSignatureHeader(authHeader)
 	if err != nil {
-		return errors.NewDomainError(65002, "authentication failed", "invalid auth header")
+		return errors.NewDomainError(65002, "authentication failed", "invalid auth header: "+err.Error())
 	}
 
-	keyID := authParams["keyId"]
-	signature := authParams["signature"]
-
-	subscriberID, ukID, algorithm, err := s.parseKeyID(keyID)
+	subscriberID, ukID, algorithm, err := params.KeyParts()
 	if err != nil {
 		return errors.NewDomainError(65002, "authentication failed", "invalid keyId format")
 	}
 
-	if algorithm != "ed25519" {
+	if algorithm != SignatureAlgorithm {
+		return errors.NewDomainError(65002, "authentication failed", "unsupported algorithm")
+	}
+
+	if params.Algorithm != "" && params.Algorithm != SignatureAlgorithm {
 		return errors.NewDomainError(65002, "authentication failed", "unsupported algorithm")
 	}
 
 	publicKeyBase64, err := s.registry.LookupPublicKey(ctx, subscriberID, ukID)
 	if err != nil {
+		// Unknown or expired keys are authentication failures, not dependency failures
+		if domainErr, ok := err.(*errors.DomainError); ok && domainErr.Code == 65002 {
+			return domainErr
+		}
 		return errors.WrapDomainError(err, 65011, "registry unavailable", "dependency")
 	}
 
@@ -126,19 +144,14 @@ func (s *ONDCAuthService) VerifyRequestSignature(ctx context.Context, authHeader
 		return errors.NewDomainError(65002, "authentication failed", "invalid registry public key size")
 	}
 
-	publicKey := ed25519.PublicKey(publicKeyBytes)
-
-	// Generate Blake2b hash of payload
-	hash := blake2b.Sum256(payload)
-
-	// Decode signature
-	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	signatureBytes, err := base64.StdEncoding.DecodeString(params.Signature)
 	if err != nil {
 		return errors.NewDomainError(65002, "authentication failed", "invalid signature format")
 	}
 
-	// Verify signature (signature is over the Blake2b hash directly)
-	if !ed25519.Verify(publicKey, hash[:], signatureBytes) {
+	// Signature is over the ONDC signing string: (created), (expires) and BLAKE-512 digest of the body
+	signingString := BuildSigningString(params.Created, params.Expires, CalculateDigest(payload))
+	if !ed25519.Verify(ed25519.PublicKey(publicKeyBytes), []byte(signingString), signatureBytes) {
 		return errors.NewDomainError(65002, "authentication failed", "signature verification failed")
 	}
 
@@ -163,67 +176,127 @@ func (s *ONDCAuthService) VerifyTimestamp(timestampStr string) error {
 	return nil
 }
 
-// SignResponse signs an outgoing ONDC response
+// VerifySignatureTimestamps validates the (created)/(expires) parameters of a signature header
+// created must be within TimestampWindow of now (replay protection) and expires must be in the future
+func (s *ONDCAuthService) VerifySignatureTimestamps(authHeader string) error {
+	params, err := ParseSignatureHeader(authHeader)
+	if err != nil {
+		return errors.NewDomainError(65002, "authentication failed", "invalid auth header: "+err.Error())
+	}
+
+	now := time.Now().UTC()
+	window := time.Duration(s.config.TimestampWindow) * time.Second
+
+	if params.Created.After(now.Add(window)) || now.Sub(params.Created) > window {
+		return errors.NewDomainError(65002, "authentication failed", "signature created outside acceptable window")
+	}
+
+	if !now.Before(params.Expires) {
+		return errors.NewDomainError(65002, "authentication failed", "signature expired")
+	}
+
+	return nil
+}
+
+// ExtractSubscriberID returns the subscriber_id from the keyId of a signature header
+func (s *ONDCAuthService) ExtractSubscriberID(authHeader string) (string, error) {
+	params, err := ParseSignatureHeader(authHeader)
+	if err != nil {
+		return "", errors.NewDomainError(65002, "authentication failed", "invalid auth header: "+err.Error())
+	}
+
+	subscriberID, _, _, err := params.KeyParts()
+	if err != nil {
+		return "", errors.NewDomainError(65002, "authentication failed", "invalid keyId format")
+	}
+
+	return subscriberID, nil
+}
+
+// SignResponse signs an outgoing ONDC payload and returns the full Authorization header value
+// Signing string: "(created): ...\n(expires): ...\ndigest: BLAKE-512=..." (see BuildSigningString)
 // NOTE: payload must be the exact raw JSON bytes to be sent. Do not re-marshal or normalize
 // whitespace, as ONDC requires exact byte-for-byte matching for signature verification.
 func (s *ONDCAuthService) SignResponse(payload []byte) (string, error) {
-	if len(s.privateKey) == 0 {
-		return "", errors.NewDomainError(65020, "internal error", "private key not loaded")
+	key, err := s.signingKey()
+	if err != nil {
+		return "", err
 	}
 
-	if s.subscriberID == "" || s.ukID == "" {
+	if s.subscriberID == "" || key.UkID == "" {
 		return "", errors.NewDomainError(65020, "internal error", "subscriber identity not configured")
 	}
 
-	hash := blake2b.Sum256(payload)
-	signature := ed25519.Sign(s.privateKey, hash[:])
-	signatureBase64 := base64.StdEncoding.EncodeToString(signature)
+	created := time.Now().UTC()
+	params := SignatureParams{
+		KeyID:     fmt.Sprintf("%s|%s|%s", s.subscriberID, key.UkID, SignatureAlgorithm),
+		Algorithm: SignatureAlgorithm,
+		Created:   created,
+		Expires:   created.Add(s.signatureTTL()),
+		Digest:    CalculateDigest(payload),
+		Headers:   SignedHeaders,
+	}
 
-	keyID := fmt.Sprintf("%s|%s|ed25519", s.subscriberID, s.ukID)
-	authHeader := fmt.Sprintf(`keyId="%s", signature="%s"`, keyID, signatureBase64)
+	signature := ed25519.Sign(key.PrivateKey, []byte(BuildSigningString(params.Created, params.Expires, params.Digest)))
+	params.Signature = base64.StdEncoding.EncodeToString(signature)
 
-	return authHeader, nil
+	return params.Header(), nil
 }
 
-// parseAuthHeader parses Authorization header as key-value pairs
-// Returns error if required fields (keyId, signature) are missing
-func (s *ONDCAuthService) parseAuthHeader(header string) (map[string]string, error) {
-	params := make(map[string]string)
-	parts := strings.Split(header, ",")
-
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		idx := strings.Index(part, "=")
-		if idx == -1 {
-			continue
-		}
+// SignRequestID signs a registry subscription request_id (site verification page)
+// Returns base64(ed25519(request_id)); the registry verifies it with the subscribed signing key,
+// so ukID selects that keyring key (empty for the current signing key)
+func (s *ONDCAuthService) SignRequestID(requestID, ukID string) (string, error) {
+	key, err := s.signingKey()
+	if ukID != "" && s.keyring != nil {
+		key, err = s.keyring.Key(ukID)
+	}
+	if err != nil {
+		return "", err
+	}
 
-		key := strings.TrimSpace(part[:idx])
-		value := strings.TrimSpace(part[idx+1:])
-		value = strings.Trim(value, `"`)
+	signature := ed25519.Sign(key.PrivateKey, []byte(requestID))
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
 
-		if key != "" && value != "" {
-			params[key] = value
-		}
+// SigningPublicKey returns the base64 ed25519 public key of the current signing key
+// Returns an empty string if no key is currently active
+func (s *ONDCAuthService) SigningPublicKey() string {
+	key, err := s.signingKey()
+	if err != nil {
+		return ""
 	}
+	return base64.StdEncoding.EncodeToString(key.PublicKey)
+}
 
-	if _, ok := params["keyId"]; !ok || params["keyId"] == "" {
-		return nil, fmt.Errorf("missing required keyId")
+// SigningKeys returns the public keys in the keyring for registry updates
+func (s *ONDCAuthService) SigningKeys() []PublicKeyInfo {
+	if s.keyring == nil {
+		return nil
 	}
+	return s.keyring.PublicKeys()
+}
 
-	if _, ok := params["signature"]; !ok || params["signature"] == "" {
-		return nil, fmt.Errorf("missing required signature")
+// WatchKeyring hot-reloads a directory keyring until ctx is cancelled (no-op for a single key pair)
+func (s *ONDCAuthService) WatchKeyring(ctx context.Context) error {
+	if s.keyring == nil {
+		return nil
 	}
-
-	return params, nil
+	return s.keyring.Watch(ctx)
 }
 
-// parseKeyID extracts subscriber_id, ukID, and algorithm from keyID
-func (s *ONDCAuthService) parseKeyID(keyID string) (subscriberID, ukID, algorithm string, err error) {
-	parts := strings.Split(keyID, "|")
-	if len(parts) != 3 {
-		return "", "", "", fmt.Errorf("invalid keyID format")
+// signingKey returns the active keyring key used for outbound signatures
+func (s *ONDCAuthService) signingKey() (*SigningKey, error) {
+	if s.keyring == nil {
+		return nil, errors.NewDomainError(65020, "internal error", "private key not loaded")
 	}
+	return s.keyring.SigningKey()
+}
 
-	return parts[0], parts[1], parts[2], nil
+// signatureTTL returns the validity window for outbound signatures
+func (s *ONDCAuthService) signatureTTL() time.Duration {
+	if s.config.SignatureTTL <= 0 {
+		return defaultSignatureTTL
+	}
+	return time.Duration(s.config.SignatureTTL) * time.Second
 }
@@ -14,8 +14,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/blake2b"
 )
 
 // MockRegistryClient is a mock for ONDC registry client
@@ -28,6 +28,12 @@ func (m *MockRegistryClient) LookupPublicKey(ctx context.Context, subscriberID,
 	return args.String(0), args.Error(1)
 }
 
+// signTestPayload signs the ONDC signing string for payload and returns the base64 signature
+func signTestPayload(privateKey ed25519.PrivateKey, created, expires int64, payload []byte) string {
+	signingString := BuildSigningString(time.Unix(created, 0), time.Unix(expires, 0), CalculateDigest(payload))
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte(signingString)))
+}
+
 func createTestService(t *testing.T, cfg config.ONDCConfig) (*ONDCAuthService, *MockRegistryClient) {
 	mockRegistry := new(MockRegistryClient)
 	logger := zap.NewNop()
@@ -62,6 +68,13 @@ func createTestService(t *testing.T, cfg config.ONDCConfig) (*ONDCAuthService, *
 	return service, mockRegistry
 }
 
+// testSigningPublicKey returns the public key of the service's current signing key
+func testSigningPublicKey(t *testing.T, service *ONDCAuthService) ed25519.PublicKey {
+	key, err := service.signingKey()
+	require.NoError(t, err)
+	return key.PublicKey
+}
+
 func TestONDCAuthService_NewONDCAuthService_KeyLoadFailure(t *testing.T) {
 	mockRegistry := new(MockRegistryClient)
 	logger := zap.NewNop()
@@ -95,11 +108,12 @@ func TestONDCAuthService_VerifyRequestSignature_Success(t *testing.T) {
 		Return(publicKeyBase64, nil)
 
 	payload := []byte(`{"context":{"timestamp":"2024-01-15T10:00:00Z"}}`)
-	hash := blake2b.Sum256(payload)
-	signature := ed25519.Sign(privateKey, hash[:])
-	signatureBase64 := base64.StdEncoding.EncodeToString(signature)
+	created := time.Now().Unix()
+	expires := created + 3600
+	signatureBase64 := signTestPayload(privateKey, created, expires, payload)
 
-	authHeader := fmt.Sprintf(`keyId="buyer.com|UKID1|ed25519", signature="%s"`, signatureBase64)
+	authHeader := fmt.Sprintf(`Signature keyId="buyer.com|UKID1|ed25519",algorithm="ed25519",created="%d",expires="%d",headers="(created) (expires) digest",signature="%s"`,
+		created, expires, signatureBase64)
 
 	err = service.VerifyRequestSignature(context.Background(), authHeader, payload)
 
@@ -182,11 +196,12 @@ func TestONDCAuthService_VerifyRequestSignature_ExtraFields(t *testing.T) {
 		Return(publicKeyBase64, nil)
 
 	payload := []byte(`{"context":{"timestamp":"2024-01-15T10:00:00Z"}}`)
-	hash := blake2b.Sum256(payload)
-	signature := ed25519.Sign(privateKey, hash[:])
-	signatureBase64 := base64.StdEncoding.EncodeToString(signature)
+	created := time.Now().Unix()
+	expires := created + 3600
+	signatureBase64 := signTestPayload(privateKey, created, expires, payload)
 
-	authHeader := fmt.Sprintf(`keyId="buyer.com|UKID1|ed25519", signature="%s", extraField="value", anotherField="test"`, signatureBase64)
+	authHeader := fmt.Sprintf(`keyId="buyer.com|UKID1|ed25519", signature="%s", created="%d", expires="%d", extraField="value", anotherField="test"`,
+		signatureBase64, created, expires)
 
 	err = service.VerifyRequestSignature(context.Background(), authHeader, payload)
 
@@ -204,7 +219,7 @@ func TestONDCAuthService_VerifyRequestSignature_InvalidBase64Signature(t *testin
 	mockRegistry.On("LookupPublicKey", mock.Anything, "buyer.com", "UKID1").
 		Return(publicKeyBase64, nil)
 
-	authHeader := `keyId="buyer.com|UKID1|ed25519", signature="invalid-base64!!!"`
+	authHeader := `keyId="buyer.com|UKID1|ed25519", created="1", expires="2", signature="invalid-base64!!!"`
 	payload := []byte(`{"context":{"timestamp":"2024-01-15T10:00:00Z"}}`)
 
 	err = service.VerifyRequestSignature(context.Background(), authHeader, payload)
@@ -248,7 +263,7 @@ func TestONDCAuthService_VerifyRequestSignature_MissingSignature(t *testing.T) {
 func TestONDCAuthService_VerifyRequestSignature_InvalidAlgorithm(t *testing.T) {
 	service, _ := createTestService(t, config.ONDCConfig{})
 
-	authHeader := `keyId="buyer.com|UKID1|rsa256", signature="signature"`
+	authHeader := `keyId="buyer.com|UKID1|rsa256", created="1", expires="2", signature="signature"`
 	payload := []byte(`{"context":{"timestamp":"2024-01-15T10:00:00Z"}}`)
 
 	err := service.VerifyRequestSignature(context.Background(), authHeader, payload)
@@ -266,7 +281,7 @@ func TestONDCAuthService_VerifyRequestSignature_RegistryError(t *testing.T) {
 	mockRegistry.On("LookupPublicKey", mock.Anything, "buyer.com", "UKID1").
 		Return("", errors.NewDomainError(65011, "registry unavailable", "dependency"))
 
-	authHeader := `keyId="buyer.com|UKID1|ed25519", signature="base64_signature"`
+	authHeader := `keyId="buyer.com|UKID1|ed25519", created="1", expires="2", signature="base64_signature"`
 	payload := []byte(`{"context":{"timestamp":"2024-01-15T10:00:00Z"}}`)
 
 	err := service.VerifyRequestSignature(context.Background(), authHeader, payload)
@@ -276,6 +291,24 @@ func TestONDCAuthService_VerifyRequestSignature_RegistryError(t *testing.T) {
 	mockRegistry.AssertExpectations(t)
 }
 
+func TestONDCAuthService_VerifyRequestSignature_UnknownKeyIsAuthFailure(t *testing.T) {
+	service, mockRegistry := createTestService(t, config.ONDCConfig{})
+
+	mockRegistry.On("LookupPublicKey", mock.Anything, "buyer.com", "UKID1").
+		Return("", errors.NewDomainError(65002, "authentication failed", "subscriber key not found in registry"))
+
+	authHeader := `keyId="buyer.com|UKID1|ed25519", created="1", expires="2", signature="base64_signature"`
+	payload := []byte(`{"context":{"timestamp":"2024-01-15T10:00:00Z"}}`)
+
+	err := service.VerifyRequestSignature(context.Background(), authHeader, payload)
+
+	assert.Error(t, err)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65002, domainErr.Code)
+	mockRegistry.AssertExpectations(t)
+}
+
 func TestONDCAuthService_VerifyRequestSignature_InvalidSignature(t *testing.T) {
 	service, mockRegistry := createTestService(t, config.ONDCConfig{})
 
@@ -286,7 +319,7 @@ func TestONDCAuthService_VerifyRequestSignature_InvalidSignature(t *testing.T) {
 	mockRegistry.On("LookupPublicKey", mock.Anything, "buyer.com", "UKID1").
 		Return(publicKeyBase64, nil)
 
-	authHeader := `keyId="buyer.com|UKID1|ed25519", signature="invalid_signature"`
+	authHeader := `keyId="buyer.com|UKID1|ed25519", created="1", expires="2", signature="invalid_signature"`
 	payload := []byte(`{"context":{"timestamp":"2024-01-15T10:00:00Z"}}`)
 
 	err = service.VerifyRequestSignature(context.Background(), authHeader, payload)
@@ -306,7 +339,7 @@ func TestONDCAuthService_VerifyRequestSignature_InvalidRegistryKeySize(t *testin
 	mockRegistry.On("LookupPublicKey", mock.Anything, "buyer.com", "UKID1").
 		Return(invalidKeyBase64, nil)
 
-	authHeader := `keyId="buyer.com|UKID1|ed25519", signature="some_signature"`
+	authHeader := `keyId="buyer.com|UKID1|ed25519", created="1", expires="2", signature="some_signature"`
 	payload := []byte(`{"context":{"timestamp":"2024-01-15T10:00:00Z"}}`)
 
 	err := service.VerifyRequestSignature(context.Background(), authHeader, payload)
@@ -336,9 +369,7 @@ func TestONDCAuthService_VerifyRequestSignature_WithOptionalFields(t *testing.T)
 		Return(publicKeyBase64, nil)
 
 	payload := []byte(`{"context":{"timestamp":"2024-01-15T10:00:00Z"}}`)
-	hash := blake2b.Sum256(payload)
-	signature := ed25519.Sign(privateKey, hash[:])
-	signatureBase64 := base64.StdEncoding.EncodeToString(signature)
+	signatureBase64 := signTestPayload(privateKey, 1234567890, 1234567999, payload)
 
 	authHeader := fmt.Sprintf(`keyId="buyer.com|UKID1|ed25519", signature="%s", created="1234567890", expires="1234567999"`, signatureBase64)
 
@@ -417,7 +448,7 @@ func TestONDCAuthService_SignResponse_VerifyRoundTrip(t *testing.T) {
 	authHeader, err := service.SignResponse(payload)
 	assert.NoError(t, err)
 
-	publicKeyBase64 := base64.StdEncoding.EncodeToString(service.publicKey)
+	publicKeyBase64 := service.SigningPublicKey()
 	mockRegistry.On("LookupPublicKey", mock.Anything, "seller.com", "SELLER_UKID").
 		Return(publicKeyBase64, nil)
 
@@ -426,21 +457,160 @@ func TestONDCAuthService_SignResponse_VerifyRoundTrip(t *testing.T) {
 	mockRegistry.AssertExpectations(t)
 }
 
-func TestONDCAuthService_ParseKeyID_InvalidFormat(t *testing.T) {
-	service, _ := createTestService(t, config.ONDCConfig{})
+func TestONDCAuthService_VerifySignatureTimestamps_Valid(t *testing.T) {
+	service, _ := createTestService(t, config.ONDCConfig{TimestampWindow: 300})
+
+	now := time.Now().Unix()
+	authHeader := fmt.Sprintf(`Signature keyId="buyer.com|UKID1|ed25519",algorithm="ed25519",created="%d",expires="%d",signature="sig"`, now, now+3600)
+
+	err := service.VerifySignatureTimestamps(authHeader)
+
+	assert.NoError(t, err)
+}
 
-	_, _, _, err := service.parseKeyID("invalid")
+func TestONDCAuthService_VerifySignatureTimestamps_Stale(t *testing.T) {
+	service, _ := createTestService(t, config.ONDCConfig{TimestampWindow: 300})
+
+	created := time.Now().Add(-10 * time.Minute).Unix()
+	authHeader := fmt.Sprintf(`Signature keyId="buyer.com|UKID1|ed25519",created="%d",expires="%d",signature="sig"`, created, created+3600)
+
+	err := service.VerifySignatureTimestamps(authHeader)
+
+	assert.Error(t, err)
+	domainErr, _ := err.(*errors.DomainError)
+	assert.Equal(t, 65002, domainErr.Code)
+	assert.Contains(t, domainErr.Details, "outside acceptable window")
+}
+
+func TestONDCAuthService_VerifySignatureTimestamps_Expired(t *testing.T) {
+	service, _ := createTestService(t, config.ONDCConfig{TimestampWindow: 300})
+
+	now := time.Now().Unix()
+	authHeader := fmt.Sprintf(`Signature keyId="buyer.com|UKID1|ed25519",created="%d",expires="%d",signature="sig"`, now-60, now-1)
+
+	err := service.VerifySignatureTimestamps(authHeader)
+
+	assert.Error(t, err)
+	domainErr, _ := err.(*errors.DomainError)
+	assert.Contains(t, domainErr.Details, "expired")
+}
+
+func TestONDCAuthService_VerifySignatureTimestamps_MissingCreated(t *testing.T) {
+	service, _ := createTestService(t, config.ONDCConfig{TimestampWindow: 300})
+
+	authHeader := `Signature keyId="buyer.com|UKID1|ed25519",signature="sig"`
+
+	err := service.VerifySignatureTimestamps(authHeader)
 
 	assert.Error(t, err)
+	domainErr, _ := err.(*errors.DomainError)
+	assert.Equal(t, 65002, domainErr.Code)
 }
 
-func TestONDCAuthService_ParseKeyID_Valid(t *testing.T) {
+func TestONDCAuthService_ExtractSubscriberID(t *testing.T) {
 	service, _ := createTestService(t, config.ONDCConfig{})
 
-	subscriberID, ukID, algorithm, err := service.parseKeyID("subscriber|ukid|ed25519")
+	subscriberID, err := service.ExtractSubscriberID(`Signature keyId="buyer.com|UKID1|ed25519",created="1",expires="2",signature="sig"`)
 
 	assert.NoError(t, err)
-	assert.Equal(t, "subscriber", subscriberID)
-	assert.Equal(t, "ukid", ukID)
-	assert.Equal(t, "ed25519", algorithm)
+	assert.Equal(t, "buyer.com", subscriberID)
+}
+
+func TestONDCAuthService_SignResponse_SpecHeaderFormat(t *testing.T) {
+	service, _ := createTestService(t, config.ONDCConfig{SubscriberID: "lsp.com", UkID: "UKID1", SignatureTTL: 600, TimestampWindow: 300})
+
+	authHeader, err := service.SignResponse([]byte(`{"message":{}}`))
+	assert.NoError(t, err)
+
+	params, err := ParseSignatureHeader(authHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, "lsp.com|UKID1|ed25519", params.KeyID)
+	assert.Equal(t, "ed25519", params.Algorithm)
+	assert.Equal(t, []string{"(created)", "(expires)", "digest"}, params.Headers)
+	assert.Equal(t, 600*time.Second, params.Expires.Sub(params.Created))
+	assert.NoError(t, service.VerifySignatureTimestamps(authHeader))
+}
+
+func TestONDCAuthService_VerifyRequestSignature_TamperedBody(t *testing.T) {
+	service, mockRegistry := createTestService(t, config.ONDCConfig{})
+
+	authHeader, err := service.SignResponse([]byte(`{"message":{"order_id":"123"}}`))
+	assert.NoError(t, err)
+
+	mockRegistry.On("LookupPublicKey", mock.Anything, "seller.com", "SELLER_UKID").
+		Return(service.SigningPublicKey(), nil)
+
+	err = service.VerifyRequestSignature(context.Background(), authHeader, []byte(`{"message":{"order_id":"124"}}`))
+
+	assert.Error(t, err)
+	domainErr, _ := err.(*errors.DomainError)
+	assert.Equal(t, 65002, domainErr.Code)
+	assert.Contains(t, domainErr.Details, "signature verification failed")
+}
+
+func TestONDCAuthService_VerifyRequestSignature_MissingExpires(t *testing.T) {
+	service, mockRegistry := createTestService(t, config.ONDCConfig{})
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	mockRegistry.On("LookupPublicKey", mock.Anything, "buyer.com", "UKID1").
+		Return(base64.StdEncoding.EncodeToString(publicKey), nil)
+
+	payload := []byte(`{"message":{}}`)
+	signatureBase64 := signTestPayload(privateKey, 1, 2, payload)
+	authHeader := fmt.Sprintf(`Signature keyId="buyer.com|UKID1|ed25519",created="1",signature="%s"`, signatureBase64)
+
+	err = service.VerifyRequestSignature(context.Background(), authHeader, payload)
+
+	assert.Error(t, err)
+	domainErr, _ := err.(*errors.DomainError)
+	assert.Equal(t, 65002, domainErr.Code)
+	assert.Contains(t, domainErr.Details, "invalid expires parameter")
+}
+
+func TestONDCAuthService_KeyringDir_SignsWithActiveKey(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC()
+	oldKey := writeTestKeyFile(t, dir, "UK1", now.Add(-time.Hour), now.Add(time.Hour), true)
+	newKey := writeTestKeyFile(t, dir, "UK2", now.Add(-time.Minute), time.Time{}, true)
+
+	mockRegistry := new(MockRegistryClient)
+	service, err := NewONDCAuthService(mockRegistry, config.ONDCConfig{
+		SubscriberID: "seller.com",
+		UkID:         "IGNORED",
+		KeyringDir:   dir,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	payload := []byte(`{"message":{"order_id":"123"}}`)
+	authHeader, err := service.SignResponse(payload)
+	require.NoError(t, err)
+
+	params, err := ParseSignatureHeader(authHeader)
+	require.NoError(t, err)
+	assert.Equal(t, "seller.com|UK2|ed25519", params.KeyID)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(newKey), service.SigningPublicKey())
+
+	mockRegistry.On("LookupPublicKey", mock.Anything, "seller.com", "UK2").
+		Return(base64.StdEncoding.EncodeToString(newKey), nil)
+	assert.NoError(t, service.VerifyRequestSignature(context.Background(), authHeader, payload))
+
+	keys := service.SigningKeys()
+	require.Len(t, keys, 2)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(oldKey), keys[0].SigningPublicKey)
+	assert.False(t, keys[0].Signing)
+	assert.True(t, keys[1].Signing)
+}
+
+func TestONDCAuthService_KeyringDir_NoActiveKey(t *testing.T) {
+	dir := t.TempDir()
+	writeTestKeyFile(t, dir, "UK1", time.Time{}, time.Time{}, false)
+
+	service, err := NewONDCAuthService(new(MockRegistryClient), config.ONDCConfig{
+		SubscriberID: "seller.com",
+		KeyringDir:   dir,
+	}, zap.NewNop())
+
+	assert.Error(t, err)
+	assert.Nil(t, service)
 }
@@ -0,0 +1,424 @@
+package ondc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRegistryLookupPath = "/v2.0/lookup"
+	defaultRegistryTimeout    = 5 * time.Second
+	maxRegistryResponseBytes  = 1 << 20
+	// refreshAheadFraction is the portion of an entry's lifetime after which
+	// a background refresh is triggered while the cached key is still served
+	refreshAheadFraction = 0.8
+)
+
+// RegistrySubscriber is a subscriber record returned by the registry lookup API
+// lookup decodes it through registryRecord so one malformed record does not fail the whole response
+type RegistrySubscriber struct {
+	SubscriberID     string    `json:"subscriber_id"`
+	SubscriberURL    string    `json:"subscriber_url"`
+	Type             string    `json:"type"`
+	Domain           string    `json:"domain"`
+	City             string    `json:"city"`
+	Country          string    `json:"country"`
+	UkID             string    `json:"ukId"`
+	UniqueKeyID      string    `json:"unique_key_id"` // Older registries use unique_key_id instead of ukId
+	SigningPublicKey string    `json:"signing_public_key"`
+	EncrPublicKey    string    `json:"encr_public_key"`
+	Status           string    `json:"status"`
+	ValidFrom        time.Time `json:"valid_from"`
+	ValidUntil       time.Time `json:"valid_until"`
+}
+
+// KeyID returns the ukId of the record, falling back to unique_key_id
+func (r RegistrySubscriber) KeyID() string {
+	if r.UkID != "" {
+		return r.UkID
+	}
+	return r.UniqueKeyID
+}
+
+// IsValidAt reports whether the record's key is valid at the given time
+// Zero valid_from/valid_until are treated as unbounded
+func (r RegistrySubscriber) IsValidAt(t time.Time) bool {
+	if !r.ValidFrom.IsZero() && t.Before(r.ValidFrom) {
+		return false
+	}
+	if !r.ValidUntil.IsZero() && !t.Before(r.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// registryRecord is a lookup record as sent by the registry
+// Registries format valid_from/valid_until inconsistently, so they are decoded as strings and parsed by parseRegistryTime
+type registryRecord struct {
+	SubscriberID     string `json:"subscriber_id"`
+	SubscriberURL    string `json:"subscriber_url"`
+	Type             string `json:"type"`
+	Domain           string `json:"domain"`
+	City             string `json:"city"`
+	Country          string `json:"country"`
+	UkID             string `json:"ukId"`
+	UniqueKeyID      string `json:"unique_key_id"`
+	SigningPublicKey string `json:"signing_public_key"`
+	EncrPublicKey    string `json:"encr_public_key"`
+	Status           string `json:"status"`
+	ValidFrom        string `json:"valid_from"`
+	ValidUntil       string `json:"valid_until"`
+}
+
+// registryTimeLayouts are the valid_from/valid_until formats seen on ONDC registries; layouts without a zone are UTC
+var registryTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// registryLookupRequest is the request body for the registry lookup API
+type registryLookupRequest struct {
+	SubscriberID string `json:"subscriber_id"`
+	UkID         string `json:"ukId"`
+}
+
+// RequestSigner signs outbound requests with the active keyring key (ONDC Authorization header)
+// It is the signer used for outbound callbacks (callback.ONDCSigner)
+type RequestSigner interface {
+	SignRequest(ctx context.Context, method, url string, body []byte, headers map[string]string) (string, error)
+}
+
+// registryCacheEntry holds a cached lookup result
+// Negative entries (unknown subscriber/ukId) carry err instead of publicKey
+type registryCacheEntry struct {
+	publicKey string
+	err       error
+	fetchedAt time.Time
+	expiresAt time.Time
+}
+
+// HTTPRegistryClient looks up subscriber signing keys from the ONDC network registry
+// Keys are cached for RegistryCacheTTL (bounded by valid_until), unknown keys are
+// negatively cached for RegistryMissTTL, and hot entries are refreshed ahead of expiry.
+type HTTPRegistryClient struct {
+	httpClient *http.Client
+	config     config.ONDCConfig
+	logger     *zap.Logger
+	now        func() time.Time
+	signer     RequestSigner
+
+	mu         sync.Mutex
+	cache      map[string]*registryCacheEntry
+	refreshing map[string]bool
+}
+
+// Ensure HTTPRegistryClient implements RegistryClient interface
+var _ RegistryClient = (*HTTPRegistryClient)(nil)
+
+// NewHTTPRegistryClient creates a new ONDC registry client
+func NewHTTPRegistryClient(cfg config.ONDCConfig, logger *zap.Logger) *HTTPRegistryClient {
+	timeout := time.Duration(cfg.RegistryTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultRegistryTimeout
+	}
+
+	return &HTTPRegistryClient{
+		httpClient: &http.Client{Timeout: timeout},
+		config:     cfg,
+		logger:     logger,
+		now:        time.Now,
+		cache:      make(map[string]*registryCacheEntry),
+		refreshing: make(map[string]bool),
+	}
+}
+
+// SetSigner sets the signer of lookup requests
+// The signer wraps the ONDC auth service, which itself verifies signatures through this client,
+// so it is set after both are constructed and before the client is used
+func (c *HTTPRegistryClient) SetSigner(signer RequestSigner) {
+	c.signer = signer
+}
+
+// LookupPublicKey returns the base64 ed25519 signing public key for subscriberID/ukID
+// Returns DomainError 65002 when the key is unknown or outside its validity window,
+// and DomainError 65011 when the registry is unreachable.
+func (c *HTTPRegistryClient) LookupPublicKey(ctx context.Context, subscriberID, ukID string) (string, error) {
+	key := registryCacheKey(subscriberID, ukID)
+
+	if entry, ok := c.getCached(key); ok {
+		if c.shouldRefresh(entry) {
+			c.refreshAsync(subscriberID, ukID)
+		}
+		return entry.publicKey, entry.err
+	}
+
+	return c.fetchAndCache(ctx, subscriberID, ukID)
+}
+
+// getCached returns a non-expired cache entry
+func (c *HTTPRegistryClient) getCached(key string) (*registryCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.cache[key]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// shouldRefresh reports whether a positive entry is close enough to expiry to refresh ahead
+func (c *HTTPRegistryClient) shouldRefresh(entry *registryCacheEntry) bool {
+	if entry.err != nil {
+		return false
+	}
+	lifetime := entry.expiresAt.Sub(entry.fetchedAt)
+	refreshAt := entry.fetchedAt.Add(time.Duration(float64(lifetime) * refreshAheadFraction))
+	return !c.now().Before(refreshAt)
+}
+
+// refreshAsync refreshes a cache entry in the background, at most once per key at a time
+// Uses a detached context because the triggering request may finish before the refresh does
+func (c *HTTPRegistryClient) refreshAsync(subscriberID, ukID string) {
+	key := registryCacheKey(subscriberID, ukID)
+
+	c.mu.Lock()
+	if c.refreshing[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[key] = true
+	c.mu.Unlock()
+
+	go func() {
+		defer c.clearRefreshing(key)
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.httpClient.Timeout)
+		defer cancel()
+
+		if _, err := c.fetchAndCache(ctx, subscriberID, ukID); err != nil {
+			c.logger.Warn("registry refresh-ahead failed, serving cached key",
+				zap.String("subscriber_id", subscriberID),
+				zap.String("uk_id", ukID),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+func (c *HTTPRegistryClient) clearRefreshing(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.refreshing, key)
+}
+
+// fetchAndCache queries the registry and caches the outcome
+// Dependency failures are not cached so the next request retries the registry
+func (c *HTTPRegistryClient) fetchAndCache(ctx context.Context, subscriberID, ukID string) (string, error) {
+	records, err := c.lookup(ctx, subscriberID, ukID)
+	if err != nil {
+		return "", err
+	}
+
+	record, err := c.selectSigningKey(records, subscriberID, ukID)
+	if err != nil {
+		c.storeNegative(registryCacheKey(subscriberID, ukID), err)
+		return "", err
+	}
+
+	c.storePositive(registryCacheKey(subscriberID, ukID), record)
+	return record.SigningPublicKey, nil
+}
+
+// selectSigningKey picks the record matching subscriberID/ukID that is valid now
+func (c *HTTPRegistryClient) selectSigningKey(records []RegistrySubscriber, subscriberID, ukID string) (*RegistrySubscriber, error) {
+	found := false
+	for i := range records {
+		record := records[i]
+		if record.SubscriberID != subscriberID || record.KeyID() != ukID || record.SigningPublicKey == "" {
+			continue
+		}
+		found = true
+		if record.IsValidAt(c.now()) {
+			return &record, nil
+		}
+	}
+
+	if found {
+		return nil, errors.NewDomainError(65002, "authentication failed", "registry key outside validity window")
+	}
+	return nil, errors.NewDomainError(65002, "authentication failed", "subscriber key not found in registry")
+}
+
+func (c *HTTPRegistryClient) storePositive(key string, record *RegistrySubscriber) {
+	now := c.now()
+	expiresAt := now.Add(time.Duration(c.config.RegistryCacheTTL) * time.Second)
+	if !record.ValidUntil.IsZero() && record.ValidUntil.Before(expiresAt) {
+		expiresAt = record.ValidUntil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache[key] = &registryCacheEntry{publicKey: record.SigningPublicKey, fetchedAt: now, expiresAt: expiresAt}
+}
+
+func (c *HTTPRegistryClient) storeNegative(key string, err error) {
+	now := c.now()
+	expiresAt := now.Add(time.Duration(c.config.RegistryMissTTL) * time.Second)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache[key] = &registryCacheEntry{err: err, fetchedAt: now, expiresAt: expiresAt}
+}
+
+// lookup calls the registry lookup API and decodes the subscriber records
+func (c *HTTPRegistryClient) lookup(ctx context.Context, subscriberID, ukID string) ([]RegistrySubscriber, error) {
+	req, err := c.buildLookupRequest(ctx, subscriberID, ukID)
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "internal error", "failed to build registry request")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65011, "registry unavailable", "registry request failed").WithRetryable(true)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRegistryResponseBytes))
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65011, "registry unavailable", "failed to read registry response").WithRetryable(true)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.NewDomainError(65011, "registry unavailable", fmt.Sprintf("registry returned status %d", resp.StatusCode)).WithRetryable(true)
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, errors.WrapDomainError(err, 65011, "registry unavailable", "invalid registry response")
+	}
+
+	records := make([]RegistrySubscriber, 0, len(raw))
+	for i, entry := range raw {
+		record, err := parseRegistryRecord(entry)
+		if err != nil {
+			c.logger.Warn("skipping malformed registry record",
+				zap.String("subscriber_id", subscriberID),
+				zap.String("uk_id", ukID),
+				zap.Int("index", i),
+				zap.Error(err),
+			)
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// parseRegistryRecord decodes one lookup record
+func parseRegistryRecord(data json.RawMessage) (RegistrySubscriber, error) {
+	var record registryRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return RegistrySubscriber{}, err
+	}
+
+	validFrom, err := parseRegistryTime(record.ValidFrom)
+	if err != nil {
+		return RegistrySubscriber{}, fmt.Errorf("invalid valid_from: %w", err)
+	}
+	validUntil, err := parseRegistryTime(record.ValidUntil)
+	if err != nil {
+		return RegistrySubscriber{}, fmt.Errorf("invalid valid_until: %w", err)
+	}
+
+	return RegistrySubscriber{
+		SubscriberID:     record.SubscriberID,
+		SubscriberURL:    record.SubscriberURL,
+		Type:             record.Type,
+		Domain:           record.Domain,
+		City:             record.City,
+		Country:          record.Country,
+		UkID:             record.UkID,
+		UniqueKeyID:      record.UniqueKeyID,
+		SigningPublicKey: record.SigningPublicKey,
+		EncrPublicKey:    record.EncrPublicKey,
+		Status:           record.Status,
+		ValidFrom:        validFrom,
+		ValidUntil:       validUntil,
+	}, nil
+}
+
+// parseRegistryTime parses a registry timestamp; an empty value is the zero time (unbounded)
+func parseRegistryTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range registryTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time format %q", value)
+}
+
+func (c *HTTPRegistryClient) buildLookupRequest(ctx context.Context, subscriberID, ukID string) (*http.Request, error) {
+	body, err := json.Marshal(registryLookupRequest{SubscriberID: subscriberID, UkID: ukID})
+	if err != nil {
+		return nil, err
+	}
+
+	lookupURL := c.lookupURL()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, lookupURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Registries authenticate lookups with the same HTTP signature as any other ONDC request
+	if c.signer == nil {
+		c.logger.Warn("registry lookup sent without HTTP signature (signer not provided)", zap.String("url", lookupURL))
+		return req, nil
+	}
+
+	digest := CalculateDigest(body)
+	req.Header.Set("Digest", digest)
+	authHeader, err := c.signer.SignRequest(ctx, http.MethodPost, lookupURL, body, map[string]string{
+		"Content-Type": "application/json",
+		"Digest":       digest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign registry request: %w", err)
+	}
+	req.Header.Set("Authorization", authHeader)
+	return req, nil
+}
+
+func (c *HTTPRegistryClient) lookupURL() string {
+	path := c.config.RegistryLookupPath
+	if path == "" {
+		path = defaultRegistryLookupPath
+	}
+	return strings.TrimRight(c.config.NetworkRegistryURL, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+func registryCacheKey(subscriberID, ukID string) string {
+	return subscriberID + "|" + ukID
+}
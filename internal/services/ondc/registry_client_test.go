@@ -0,0 +1,331 @@
+package ondc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeRegistry is an httptest stand-in for the ONDC registry lookup API
+type fakeRegistry struct {
+	mu         sync.Mutex
+	records    []RegistrySubscriber
+	status     int
+	calls      int32
+	lastBody   registryLookupRequest
+	lastPath   string
+	lastAuth   string
+	lastDigest string
+}
+
+func (f *fakeRegistry) setRecords(records []RegistrySubscriber) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records = records
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&f.calls, 1)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lastPath = r.URL.Path
+	f.lastAuth = r.Header.Get("Authorization")
+	f.lastDigest = r.Header.Get("Digest")
+	_ = json.NewDecoder(r.Body).Decode(&f.lastBody)
+
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(f.records)
+}
+
+func (f *fakeRegistry) callCount() int {
+	return int(atomic.LoadInt32(&f.calls))
+}
+
+func newTestRegistryClient(t *testing.T, registry *fakeRegistry) (*HTTPRegistryClient, *time.Time) {
+	server := httptest.NewServer(registry)
+	t.Cleanup(server.Close)
+
+	cfg := config.ONDCConfig{
+		NetworkRegistryURL: server.URL,
+		RegistryCacheTTL:   100,
+		RegistryMissTTL:    10,
+	}
+	client := NewHTTPRegistryClient(cfg, zap.NewNop())
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+	return client, &now
+}
+
+func testSubscriber(ukID, key string) RegistrySubscriber {
+	return RegistrySubscriber{
+		SubscriberID:     "buyer.com",
+		UkID:             ukID,
+		SigningPublicKey: key,
+		ValidFrom:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ValidUntil:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_SelectsKeyByUkID(t *testing.T) {
+	registry := &fakeRegistry{records: []RegistrySubscriber{
+		testSubscriber("UK1", "key-1"),
+		testSubscriber("UK2", "key-2"),
+	}}
+	client, _ := newTestRegistryClient(t, registry)
+
+	key, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "key-2", key)
+	assert.Equal(t, "/v2.0/lookup", registry.lastPath)
+	assert.Equal(t, "buyer.com", registry.lastBody.SubscriberID)
+	assert.Equal(t, "UK2", registry.lastBody.UkID)
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_UniqueKeyIDFallback(t *testing.T) {
+	record := testSubscriber("", "key-legacy")
+	record.UniqueKeyID = "UK1"
+	registry := &fakeRegistry{records: []RegistrySubscriber{record}}
+	client, _ := newTestRegistryClient(t, registry)
+
+	key, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "key-legacy", key)
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_ConfiguredPath(t *testing.T) {
+	registry := &fakeRegistry{records: []RegistrySubscriber{testSubscriber("UK1", "key-1")}}
+	client, _ := newTestRegistryClient(t, registry)
+	client.config.RegistryLookupPath = "/vlookup"
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/vlookup", registry.lastPath)
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_OutsideValidityWindow(t *testing.T) {
+	expired := testSubscriber("UK1", "key-1")
+	expired.ValidUntil = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	registry := &fakeRegistry{records: []RegistrySubscriber{expired}}
+	client, _ := newTestRegistryClient(t, registry)
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+
+	assert.Error(t, err)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65002, domainErr.Code)
+	assert.Contains(t, domainErr.Details, "validity window")
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_NotYetValid(t *testing.T) {
+	future := testSubscriber("UK1", "key-1")
+	future.ValidFrom = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	registry := &fakeRegistry{records: []RegistrySubscriber{future}}
+	client, _ := newTestRegistryClient(t, registry)
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+
+	assert.Error(t, err)
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_CachesPositiveResult(t *testing.T) {
+	registry := &fakeRegistry{records: []RegistrySubscriber{testSubscriber("UK1", "key-1")}}
+	client, now := newTestRegistryClient(t, registry)
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+	assert.NoError(t, err)
+
+	*now = now.Add(10 * time.Second)
+	key, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", key)
+	assert.Equal(t, 1, registry.callCount())
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_RefetchesAfterTTL(t *testing.T) {
+	registry := &fakeRegistry{records: []RegistrySubscriber{testSubscriber("UK1", "key-1")}}
+	client, now := newTestRegistryClient(t, registry)
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+	assert.NoError(t, err)
+
+	registry.setRecords([]RegistrySubscriber{testSubscriber("UK1", "key-rotated")})
+	*now = now.Add(101 * time.Second)
+	key, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "key-rotated", key)
+	assert.Equal(t, 2, registry.callCount())
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_NegativeCache(t *testing.T) {
+	registry := &fakeRegistry{records: []RegistrySubscriber{}}
+	client, now := newTestRegistryClient(t, registry)
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+	assert.Error(t, err)
+
+	_, err = client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+	assert.Error(t, err)
+	assert.Equal(t, 1, registry.callCount())
+
+	registry.setRecords([]RegistrySubscriber{testSubscriber("UK1", "key-1")})
+	*now = now.Add(11 * time.Second)
+	key, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", key)
+	assert.Equal(t, 2, registry.callCount())
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_RegistryErrorNotCached(t *testing.T) {
+	registry := &fakeRegistry{status: http.StatusBadGateway}
+	client, _ := newTestRegistryClient(t, registry)
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+	assert.Error(t, err)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65011, domainErr.Code)
+	assert.True(t, domainErr.Retryable)
+
+	_, err = client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+	assert.Error(t, err)
+	assert.Equal(t, 2, registry.callCount())
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_RefreshAhead(t *testing.T) {
+	registry := &fakeRegistry{records: []RegistrySubscriber{testSubscriber("UK1", "key-1")}}
+	client, now := newTestRegistryClient(t, registry)
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+	assert.NoError(t, err)
+
+	registry.setRecords([]RegistrySubscriber{testSubscriber("UK1", "key-rotated")})
+	*now = now.Add(90 * time.Second)
+	key, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+
+	// Stale-but-valid key is served while the refresh runs in the background
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", key)
+
+	assert.Eventually(t, func() bool {
+		key, _ := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+		return key == "key-rotated"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_CacheBoundedByValidUntil(t *testing.T) {
+	record := testSubscriber("UK1", "key-1")
+	record.ValidUntil = time.Date(2025, 1, 1, 12, 0, 30, 0, time.UTC)
+	registry := &fakeRegistry{records: []RegistrySubscriber{record}}
+	client, now := newTestRegistryClient(t, registry)
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+	assert.NoError(t, err)
+
+	*now = now.Add(31 * time.Second)
+	_, err = client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, registry.callCount())
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_SkipsMalformedRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"subscriber_id":"buyer.com","ukId":"UK1","signing_public_key":"key-bad","valid_from":"not a time"},
+			{"subscriber_id":"buyer.com","ukId":"UK1","signing_public_key":"key-bad-type","valid_until":12345},
+			{"subscriber_id":"buyer.com","ukId":"UK1","signing_public_key":"key-1","valid_from":"2024-01-01 00:00:00","valid_until":"2026-01-01T00:00:00.000Z"}
+		]`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewHTTPRegistryClient(config.ONDCConfig{NetworkRegistryURL: server.URL, RegistryCacheTTL: 100}, zap.NewNop())
+	client.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	key, err := client.LookupPublicKey(context.Background(), "buyer.com", "UK1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", key)
+}
+
+// fakeRequestSigner records the body it signs
+type fakeRequestSigner struct {
+	body    []byte
+	headers map[string]string
+	err     error
+}
+
+func (f *fakeRequestSigner) SignRequest(ctx context.Context, method, url string, body []byte, headers map[string]string) (string, error) {
+	f.body = body
+	f.headers = headers
+	return `Signature keyId="bpp.example.com|k1|ed25519"`, f.err
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_SignsRequest(t *testing.T) {
+	registry := &fakeRegistry{records: []RegistrySubscriber{testSubscriber("k1", "key-1")}}
+	client, _ := newTestRegistryClient(t, registry)
+	signer := &fakeRequestSigner{}
+	client.SetSigner(signer)
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "k1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, `Signature keyId="bpp.example.com|k1|ed25519"`, registry.lastAuth)
+	assert.JSONEq(t, `{"subscriber_id":"buyer.com","ukId":"k1"}`, string(signer.body))
+	assert.Equal(t, CalculateDigest(signer.body), registry.lastDigest)
+	assert.Equal(t, registry.lastDigest, signer.headers["Digest"])
+}
+
+func TestHTTPRegistryClient_LookupPublicKey_SigningFailure(t *testing.T) {
+	registry := &fakeRegistry{records: []RegistrySubscriber{testSubscriber("k1", "key-1")}}
+	client, _ := newTestRegistryClient(t, registry)
+	client.SetSigner(&fakeRequestSigner{err: assert.AnError})
+
+	_, err := client.LookupPublicKey(context.Background(), "buyer.com", "k1")
+
+	domainErr, ok := err.(*errors.DomainError)
+	if assert.True(t, ok) {
+		assert.Equal(t, 65020, domainErr.Code)
+	}
+	assert.Equal(t, 0, registry.callCount())
+}
+
+func TestParseRegistryTime(t *testing.T) {
+	want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, value := range []string{"2024-01-01T00:00:00Z", "2024-01-01T00:00:00.000Z", "2024-01-01T05:30:00+05:30", "2024-01-01T00:00:00", "2024-01-01 00:00:00", "2024-01-01"} {
+		parsed, err := parseRegistryTime(value)
+		assert.NoError(t, err, value)
+		assert.True(t, want.Equal(parsed), value)
+	}
+
+	parsed, err := parseRegistryTime("")
+	assert.NoError(t, err)
+	assert.True(t, parsed.IsZero())
+
+	_, err = parseRegistryTime("01/01/2024")
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package ondc
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// SignatureAlgorithm is the only signing algorithm accepted on the ONDC network
+	SignatureAlgorithm = "ed25519"
+	// DigestAlgorithm is the digest algorithm used in the ONDC signing string
+	DigestAlgorithm = "BLAKE-512"
+)
+
+// SignedHeaders is the ONDC "headers" parameter value (order matters for the signing string)
+var SignedHeaders = []string{"(created)", "(expires)", "digest"}
+
+// SignatureParams contains the parameters of an ONDC HTTP signature
+// Header format:
+//
+//	Signature keyId="{subscriber_id}|{ukId}|ed25519",algorithm="ed25519",created="{unix}",
+//	expires="{unix}",headers="(created) (expires) digest",signature="{base64}"
+type SignatureParams struct {
+	KeyID     string    // Format: "subscriber_id|ukId|ed25519"
+	Algorithm string    // Always "ed25519"
+	Created   time.Time // Signature creation time
+	Expires   time.Time // Signature expiration time
+	Digest    string    // "BLAKE-512=<base64(blake2b-512(body))>"
+	Headers   []string  // Signed pseudo-headers: ["(created)", "(expires)", "digest"]
+	Signature string    // Base64 encoded ed25519 signature over the signing string
+}
+
+// CalculateDigest returns the ONDC body digest: "BLAKE-512=<base64(blake2b-512(body))>"
+func CalculateDigest(body []byte) string {
+	hash := blake2b.Sum512(body)
+	return DigestAlgorithm + "=" + base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// BuildSigningString builds the ONDC signing string from a digest produced by CalculateDigest
+// Format: "(created): {created}\n(expires): {expires}\ndigest: BLAKE-512={digest}"
+func BuildSigningString(created, expires time.Time, digest string) string {
+	return fmt.Sprintf("(created): %d\n(expires): %d\ndigest: %s",
+		created.Unix(),
+		expires.Unix(),
+		digest,
+	)
+}
+
+// Header renders the params as an ONDC Authorization header value
+func (p SignatureParams) Header() string {
+	algorithm := p.Algorithm
+	if algorithm == "" {
+		algorithm = SignatureAlgorithm
+	}
+	headers := p.Headers
+	if len(headers) == 0 {
+		headers = SignedHeaders
+	}
+
+	return fmt.Sprintf(`Signature keyId="%s",algorithm="%s",created="%d",expires="%d",headers="%s",signature="%s"`,
+		p.KeyID,
+		algorithm,
+		p.Created.Unix(),
+		p.Expires.Unix(),
+		strings.Join(headers, " "),
+		p.Signature,
+	)
+}
+
+// KeyParts splits keyId into subscriber_id, ukId and algorithm
+func (p SignatureParams) KeyParts() (subscriberID, ukID, algorithm string, err error) {
+	parts := strings.Split(p.KeyID, "|")
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("invalid keyId format")
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
+
+// ParseSignatureHeader parses an ONDC Authorization (or X-Gateway-Authorization) header
+// keyId, signature, created and expires are required
+func ParseSignatureHeader(header string) (*SignatureParams, error) {
+	values, err := parseHeaderParams(header)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &SignatureParams{
+		KeyID:     values["keyId"],
+		Algorithm: values["algorithm"],
+		Signature: values["signature"],
+		Headers:   strings.Fields(values["headers"]),
+	}
+
+	if params.Created, err = parseUnixParam(values, "created"); err != nil {
+		return nil, err
+	}
+	if params.Expires, err = parseUnixParam(values, "expires"); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// parseHeaderParams parses a signature header as key-value pairs
+// Returns error if required fields (keyId, signature) are missing
+func parseHeaderParams(header string) (map[string]string, error) {
+	params := make(map[string]string)
+	header = strings.TrimPrefix(strings.TrimSpace(header), "Signature ")
+	parts := strings.Split(header, ",")
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		idx := strings.Index(part, "=")
+		if idx == -1 {
+			continue
+		}
+
+		key := strings.TrimSpace(part[:idx])
+		value := strings.TrimSpace(part[idx+1:])
+		value = strings.Trim(value, `"`)
+
+		if key != "" && value != "" {
+			params[key] = value
+		}
+	}
+
+	if params["keyId"] == "" {
+		return nil, fmt.Errorf("missing required keyId")
+	}
+
+	if params["signature"] == "" {
+		return nil, fmt.Errorf("missing required signature")
+	}
+
+	return params, nil
+}
+
+// parseUnixParam parses a unix-seconds signature parameter such as created/expires
+func parseUnixParam(params map[string]string, name string) (time.Time, error) {
+	value, ok := params[name]
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid %s parameter: missing", name)
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s parameter: %w", name, err)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
@@ -0,0 +1,105 @@
+package ondc
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"regexp"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestCalculateDigest(t *testing.T) {
+	body := []byte(`{"context":{"action":"on_search"}}`)
+	hash := blake2b.Sum512(body)
+
+	digest := CalculateDigest(body)
+
+	assert.Equal(t, "BLAKE-512="+base64.StdEncoding.EncodeToString(hash[:]), digest)
+}
+
+func TestBuildSigningString(t *testing.T) {
+	signingString := BuildSigningString(time.Unix(1641287875, 0), time.Unix(1641291475, 0), "BLAKE-512=abc")
+
+	assert.Equal(t, "(created): 1641287875\n(expires): 1641291475\ndigest: BLAKE-512=abc", signingString)
+}
+
+func TestSignatureParams_Header(t *testing.T) {
+	params := SignatureParams{
+		KeyID:     "lsp.com|UK1|ed25519",
+		Created:   time.Unix(1641287875, 0),
+		Expires:   time.Unix(1641291475, 0),
+		Signature: "c2ln",
+	}
+
+	header := params.Header()
+
+	assert.Equal(t, `Signature keyId="lsp.com|UK1|ed25519",algorithm="ed25519",created="1641287875",expires="1641291475",headers="(created) (expires) digest",signature="c2ln"`, header)
+}
+
+func TestParseSignatureHeader_RoundTrip(t *testing.T) {
+	params := SignatureParams{
+		KeyID:     "lsp.com|UK1|ed25519",
+		Algorithm: SignatureAlgorithm,
+		Created:   time.Unix(1641287875, 0).UTC(),
+		Expires:   time.Unix(1641291475, 0).UTC(),
+		Headers:   SignedHeaders,
+		Signature: "c2ln",
+	}
+
+	parsed, err := ParseSignatureHeader(params.Header())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &params, parsed)
+}
+
+func TestParseSignatureHeader_MissingExpires(t *testing.T) {
+	_, err := ParseSignatureHeader(`Signature keyId="lsp.com|UK1|ed25519",created="1641287875",signature="c2ln"`)
+
+	assert.Error(t, err)
+}
+
+func TestParseSignatureHeader_MissingSignature(t *testing.T) {
+	_, err := ParseSignatureHeader(`Signature keyId="lsp.com|UK1|ed25519",created="1",expires="2"`)
+
+	assert.Error(t, err)
+}
+
+func TestSignatureParams_KeyParts(t *testing.T) {
+	subscriberID, ukID, algorithm, err := SignatureParams{KeyID: "subscriber|ukid|ed25519"}.KeyParts()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "subscriber", subscriberID)
+	assert.Equal(t, "ukid", ukID)
+	assert.Equal(t, "ed25519", algorithm)
+
+	_, _, _, err = SignatureParams{KeyID: "invalid"}.KeyParts()
+	assert.Error(t, err)
+}
+
+// TestSignResponse_IndependentVerification verifies a generated header without using the package
+// parser, the way a counterparty implementing the ONDC spec would
+func TestSignResponse_IndependentVerification(t *testing.T) {
+	service, _ := createTestService(t, config.ONDCConfig{SubscriberID: "lsp.com", UkID: "UK1"})
+	body := []byte(`{"context":{"action":"on_search"},"message":{}}`)
+
+	header, err := service.SignResponse(body)
+	assert.NoError(t, err)
+
+	pattern := regexp.MustCompile(`^Signature keyId="lsp\.com\|UK1\|ed25519",algorithm="ed25519",created="(\d+)",expires="(\d+)",headers="\(created\) \(expires\) digest",signature="([^"]+)"$`)
+	matches := pattern.FindStringSubmatch(header)
+	if !assert.Len(t, matches, 4, "header must match the ONDC format: %s", header) {
+		return
+	}
+
+	hash := blake2b.Sum512(body)
+	signingString := "(created): " + matches[1] + "\n(expires): " + matches[2] + "\ndigest: BLAKE-512=" + base64.StdEncoding.EncodeToString(hash[:])
+	signature, err := base64.StdEncoding.DecodeString(matches[3])
+	assert.NoError(t, err)
+
+	assert.True(t, ed25519.Verify(testSigningPublicKey(t, service), []byte(signingString), signature))
+}
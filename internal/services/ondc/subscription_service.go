@@ -0,0 +1,373 @@
+package ondc
+
+import (
+	"crypto/aes"
+	"crypto/ecdh"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// SubscribeOpsSellerNew is the registry ops_no for a new Seller NP entity registration
+	SubscribeOpsSellerNew = 2
+	// subscribeParticipantType is the registry network participant type for a Seller NP (BPP)
+	subscribeParticipantType = "sellerApp"
+	// subscribeTimestampFormat matches the millisecond RFC3339 timestamps used by the registry
+	subscribeTimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// SubscriptionSigner provides the signing identity used for registry onboarding
+type SubscriptionSigner interface {
+	SignResponse(payload []byte) (string, error)
+	SignRequestID(requestID, ukID string) (string, error)
+	SigningKeys() []PublicKeyInfo
+}
+
+// SubscribeRequest is the registry /subscribe payload
+type SubscribeRequest struct {
+	Context SubscribeContext `json:"context"`
+	Message SubscribeMessage `json:"message"`
+}
+
+// SubscribeContext carries the registry operation
+type SubscribeContext struct {
+	Operation SubscribeOperation `json:"operation"`
+}
+
+// SubscribeOperation identifies the registry operation (ops_no)
+type SubscribeOperation struct {
+	OpsNo int `json:"ops_no"`
+}
+
+// SubscribeMessage is the /subscribe message body
+type SubscribeMessage struct {
+	RequestID          string               `json:"request_id"`
+	Timestamp          string               `json:"timestamp"`
+	Entity             SubscribeEntity      `json:"entity"`
+	NetworkParticipant []NetworkParticipant `json:"network_participant"`
+}
+
+// SubscribeEntity describes the legal entity and its key pair
+// Business fields (gst, pan, signatory, contact) are supplied by ops; identity and keys come from config
+type SubscribeEntity struct {
+	GST                          json.RawMessage  `json:"gst,omitempty"`
+	PAN                          json.RawMessage  `json:"pan,omitempty"`
+	NameOfAuthorisedSignatory    string           `json:"name_of_authorised_signatory,omitempty"`
+	AddressOfAuthorisedSignatory string           `json:"address_of_authorised_signatory,omitempty"`
+	EmailID                      string           `json:"email_id,omitempty"`
+	MobileNo                     json.Number      `json:"mobile_no,omitempty"`
+	Country                      string           `json:"country"`
+	SubscriberID                 string           `json:"subscriber_id"`
+	UniqueKeyID                  string           `json:"unique_key_id"`
+	CallbackURL                  string           `json:"callback_url"`
+	KeyPair                      SubscribeKeyPair `json:"key_pair"`
+}
+
+// SubscribeKeyPair contains the public keys registered for the subscriber
+type SubscribeKeyPair struct {
+	SigningPublicKey    string `json:"signing_public_key"`
+	EncryptionPublicKey string `json:"encryption_public_key"`
+	ValidFrom           string `json:"valid_from"`
+	ValidUntil          string `json:"valid_until"`
+}
+
+// NetworkParticipant describes a domain/city registration for the subscriber
+type NetworkParticipant struct {
+	SubscriberURL string   `json:"subscriber_url"`
+	Domain        string   `json:"domain"`
+	Type          string   `json:"type"`
+	MSN           bool     `json:"msn"`
+	CityCode      []string `json:"city_code"`
+}
+
+// SubscriptionService handles ONDC registry onboarding
+// - decrypts the /on_subscribe challenge (X25519 shared key, AES-256-ECB)
+// - renders /ondc-site-verification.html with the signed request_id
+// - builds the /subscribe payload for ops
+type SubscriptionService struct {
+	signer        SubscriptionSigner
+	config        config.ONDCConfig
+	logger        *zap.Logger
+	encryptionKey *ecdh.PrivateKey
+	registryKey   *ecdh.PublicKey
+}
+
+// NewSubscriptionService creates a new subscription service
+// Encryption keys are optional so the gateway can run before onboarding; operations that need
+// them return an error until they are configured
+func NewSubscriptionService(signer SubscriptionSigner, cfg config.ONDCConfig, logger *zap.Logger) (*SubscriptionService, error) {
+	service := &SubscriptionService{
+		signer: signer,
+		config: cfg,
+		logger: logger,
+	}
+
+	if cfg.EncPrivateKeyPath != "" {
+		keyBytes, err := os.ReadFile(cfg.EncPrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption private key file: %w", err)
+		}
+		if service.encryptionKey, err = parseX25519PrivateKey(string(keyBytes)); err != nil {
+			return nil, fmt.Errorf("failed to parse encryption private key: %w", err)
+		}
+	}
+
+	if cfg.RegistryEncKey != "" {
+		registryKey, err := parseX25519PublicKey(cfg.RegistryEncKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse registry encryption public key: %w", err)
+		}
+		service.registryKey = registryKey
+	}
+
+	return service, nil
+}
+
+// DecryptChallenge decrypts the registry /on_subscribe challenge
+// shared_key = X25519(our encryption private key, registry encryption public key)
+// answer = AES-256-ECB-decrypt(shared_key, base64(challenge)) with PKCS#7 padding removed
+func (s *SubscriptionService) DecryptChallenge(challenge string) (string, error) {
+	if s.encryptionKey == nil || s.registryKey == nil {
+		return "", errors.NewDomainError(65020, "internal error", "subscription encryption keys not configured")
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimSpace(challenge))
+	if err != nil {
+		return "", errors.NewDomainError(65001, "invalid request", "challenge is not valid base64")
+	}
+
+	sharedKey, err := s.encryptionKey.ECDH(s.registryKey)
+	if err != nil {
+		return "", errors.WrapDomainError(err, 65020, "internal error", "failed to derive shared key")
+	}
+
+	plaintext, err := decryptAES256ECB(sharedKey, ciphertext)
+	if err != nil {
+		return "", errors.WrapDomainError(err, 65001, "invalid request", "failed to decrypt challenge")
+	}
+
+	return string(plaintext), nil
+}
+
+// SiteVerificationHTML renders the ondc-site-verification.html page
+// The meta content is the request_id from /subscribe signed with the ed25519 key being subscribed
+// (ONDC_SUBSCRIBE_UK_ID, the current signing key when empty)
+func (s *SubscriptionService) SiteVerificationHTML() (string, error) {
+	if s.config.SubscribeReqID == "" {
+		return "", errors.NewDomainError(65006, "not found", "subscription request_id not configured")
+	}
+
+	signedRequestID, err := s.signer.SignRequestID(s.config.SubscribeReqID, s.config.SubscribeUkID)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(`<html>
+    <head>
+        <meta name='ondc-site-verification' content='%s' />
+    </head>
+    <body>
+        ONDC Site Verification Page
+    </body>
+</html>
+`, signedRequestID), nil
+}
+
+// BuildSubscribeRequest builds the registry /subscribe payload
+// entity carries the ops-supplied business details; subscriber identity, keys and network
+// participant details are filled from config so they always match what the gateway serves
+// ukID selects the keyring key to register (empty for the current signing key), so a rotated key
+// can be registered before it becomes active; its validity window is used when the key file sets one
+func (s *SubscriptionService) BuildSubscribeRequest(entity SubscribeEntity, requestID, ukID string, opsNo int, now time.Time, validity time.Duration) (*SubscribeRequest, error) {
+	if s.encryptionKey == nil {
+		return nil, errors.NewDomainError(65020, "internal error", "encryption private key not configured")
+	}
+
+	signingKey, err := s.subscribeKey(ukID)
+	if err != nil {
+		return nil, err
+	}
+
+	validFrom := now
+	if !signingKey.ValidFrom.IsZero() {
+		validFrom = signingKey.ValidFrom
+	}
+	validUntil := now.Add(validity)
+	if !signingKey.ValidUntil.IsZero() {
+		validUntil = signingKey.ValidUntil
+	}
+
+	encryptionPublicKey, err := x509.MarshalPKIXPublicKey(s.encryptionKey.PublicKey())
+	if err != nil {
+		return nil, errors.WrapDomainError(err, 65020, "internal error", "failed to encode encryption public key")
+	}
+
+	if entity.Country == "" {
+		entity.Country = s.config.Country
+	}
+	entity.SubscriberID = s.config.SubscriberID
+	entity.UniqueKeyID = signingKey.UkID
+	entity.CallbackURL = SubscribeCallbackURL(s.config.SubscribePrefix)
+	entity.KeyPair = SubscribeKeyPair{
+		SigningPublicKey:    signingKey.SigningPublicKey,
+		EncryptionPublicKey: base64.StdEncoding.EncodeToString(encryptionPublicKey),
+		ValidFrom:           validFrom.UTC().Format(subscribeTimestampFormat),
+		ValidUntil:          validUntil.UTC().Format(subscribeTimestampFormat),
+	}
+
+	participant := NetworkParticipant{
+		SubscriberURL: s.config.SubscriberURL,
+		Domain:        s.config.Domain,
+		Type:          subscribeParticipantType,
+		MSN:           false,
+		CityCode:      []string{},
+	}
+	if s.config.CityCode != "" {
+		participant.CityCode = []string{s.config.CityCode}
+	}
+
+	return &SubscribeRequest{
+		Context: SubscribeContext{Operation: SubscribeOperation{OpsNo: opsNo}},
+		Message: SubscribeMessage{
+			RequestID:          requestID,
+			Timestamp:          now.UTC().Format(subscribeTimestampFormat),
+			Entity:             entity,
+			NetworkParticipant: []NetworkParticipant{participant},
+		},
+	}, nil
+}
+
+// SubscribeCallbackURL is the callback_url registered by /subscribe for a route prefix
+// The registry calls subscriber_url + callback_url + "/on_subscribe", so it must be the prefix
+// OnSubscribePath serves the challenge under ("/" when served at the root)
+func SubscribeCallbackURL(routePrefix string) string {
+	return path.Join("/", routePrefix)
+}
+
+// OnSubscribePath is the route /on_subscribe is served on for a route prefix
+func OnSubscribePath(routePrefix string) string {
+	return path.Join(SubscribeCallbackURL(routePrefix), "on_subscribe")
+}
+
+// SigningKeys returns the signing public keys the gateway holds, for registry key updates
+func (s *SubscriptionService) SigningKeys() []PublicKeyInfo {
+	return s.signer.SigningKeys()
+}
+
+// subscribeKey finds the keyring key to register: the requested ukId, or the current signing key
+func (s *SubscriptionService) subscribeKey(ukID string) (PublicKeyInfo, error) {
+	for _, key := range s.signer.SigningKeys() {
+		if (ukID == "" && key.Signing) || (ukID != "" && key.UkID == ukID) {
+			return key, nil
+		}
+	}
+
+	if ukID == "" {
+		return PublicKeyInfo{}, errors.NewDomainError(65020, "internal error", "no active signing key")
+	}
+	return PublicKeyInfo{}, errors.NewDomainError(65006, "not found", fmt.Sprintf("signing key %s not in keyring", ukID))
+}
+
+// SignSubscribeRequest marshals the payload and returns the exact bytes with their Authorization header
+func (s *SubscriptionService) SignSubscribeRequest(request *SubscribeRequest) ([]byte, string, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, "", errors.WrapDomainError(err, 65020, "internal error", "failed to marshal subscribe payload")
+	}
+
+	authHeader, err := s.signer.SignResponse(payload)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return payload, authHeader, nil
+}
+
+// decryptAES256ECB decrypts ECB-mode ciphertext and strips PKCS#7 padding
+func decryptAES256ECB(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	blockSize := block.BlockSize()
+	if len(ciphertext) == 0 || len(ciphertext)%blockSize != 0 {
+		return nil, fmt.Errorf("ciphertext is not a multiple of the block size")
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	for start := 0; start < len(ciphertext); start += blockSize {
+		block.Decrypt(plaintext[start:start+blockSize], ciphertext[start:start+blockSize])
+	}
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > blockSize {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if int(b) != padding {
+			return nil, fmt.Errorf("invalid padding")
+		}
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// parseX25519PrivateKey parses a base64 X25519 private key (raw 32 bytes or PKCS#8 DER)
+func parseX25519PrivateKey(encoded string) (*ecdh.PrivateKey, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(der) == 32 {
+		return ecdh.X25519().NewPrivateKey(der)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := key.(*ecdh.PrivateKey)
+	if !ok || privateKey.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("not an X25519 private key")
+	}
+
+	return privateKey, nil
+}
+
+// parseX25519PublicKey parses a base64 X25519 public key (raw 32 bytes or SPKI DER)
+func parseX25519PublicKey(encoded string) (*ecdh.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(der) == 32 {
+		return ecdh.X25519().NewPublicKey(der)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, ok := key.(*ecdh.PublicKey)
+	if !ok || publicKey.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("not an X25519 public key")
+	}
+
+	return publicKey, nil
+}
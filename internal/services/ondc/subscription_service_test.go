@@ -0,0 +1,280 @@
+package ondc
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"uois-gateway/internal/config"
+	"uois-gateway/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// encryptAES256ECB is the registry side of the challenge (PKCS#7 padded AES-256-ECB)
+func encryptAES256ECB(t *testing.T, key, plaintext []byte) []byte {
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	ciphertext := make([]byte, len(padded))
+	for start := 0; start < len(padded); start += aes.BlockSize {
+		block.Encrypt(ciphertext[start:start+aes.BlockSize], padded[start:start+aes.BlockSize])
+	}
+	return ciphertext
+}
+
+type subscriptionFixture struct {
+	service     *SubscriptionService
+	auth        *ONDCAuthService
+	registryKey *ecdh.PrivateKey
+	ourKey      *ecdh.PrivateKey
+}
+
+func newSubscriptionFixture(t *testing.T, cfg config.ONDCConfig) *subscriptionFixture {
+	ourKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	registryKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	// Our key is stored as PKCS#8 DER (ONDC utility format), the registry key as SPKI DER
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(ourKey)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "encryption.key")
+	require.NoError(t, os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(pkcs8)), 0600))
+
+	spki, err := x509.MarshalPKIXPublicKey(registryKey.PublicKey())
+	require.NoError(t, err)
+
+	cfg.EncPrivateKeyPath = keyPath
+	cfg.RegistryEncKey = base64.StdEncoding.EncodeToString(spki)
+
+	auth, _ := createTestService(t, cfg)
+	service, err := NewSubscriptionService(auth, cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	return &subscriptionFixture{service: service, auth: auth, registryKey: registryKey, ourKey: ourKey}
+}
+
+func TestSubscriptionService_DecryptChallenge(t *testing.T) {
+	fixture := newSubscriptionFixture(t, config.ONDCConfig{})
+
+	sharedKey, err := fixture.registryKey.ECDH(fixture.ourKey.PublicKey())
+	require.NoError(t, err)
+	challenge := base64.StdEncoding.EncodeToString(encryptAES256ECB(t, sharedKey, []byte("ondc-challenge-string-1234")))
+
+	answer, err := fixture.service.DecryptChallenge(challenge)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ondc-challenge-string-1234", answer)
+}
+
+func TestSubscriptionService_DecryptChallenge_WrongKey(t *testing.T) {
+	fixture := newSubscriptionFixture(t, config.ONDCConfig{})
+
+	otherKey := make([]byte, 32)
+	_, _ = rand.Read(otherKey)
+	challenge := base64.StdEncoding.EncodeToString(encryptAES256ECB(t, otherKey, []byte("challenge")))
+
+	_, err := fixture.service.DecryptChallenge(challenge)
+
+	assert.Error(t, err)
+}
+
+func TestSubscriptionService_DecryptChallenge_InvalidBase64(t *testing.T) {
+	fixture := newSubscriptionFixture(t, config.ONDCConfig{})
+
+	_, err := fixture.service.DecryptChallenge("not base64!!")
+
+	assert.Error(t, err)
+	domainErr, ok := err.(*errors.DomainError)
+	assert.True(t, ok)
+	assert.Equal(t, 65001, domainErr.Code)
+}
+
+func TestSubscriptionService_DecryptChallenge_NotConfigured(t *testing.T) {
+	auth, _ := createTestService(t, config.ONDCConfig{})
+	service, err := NewSubscriptionService(auth, config.ONDCConfig{}, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = service.DecryptChallenge("AAAA")
+
+	assert.Error(t, err)
+	domainErr, _ := err.(*errors.DomainError)
+	assert.Equal(t, 65020, domainErr.Code)
+}
+
+func TestNewSubscriptionService_RawKeys(t *testing.T) {
+	ourKey, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	registryKey, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	keyPath := filepath.Join(t.TempDir(), "encryption.key")
+	require.NoError(t, os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(ourKey.Bytes())), 0600))
+
+	cfg := config.ONDCConfig{
+		EncPrivateKeyPath: keyPath,
+		RegistryEncKey:    base64.StdEncoding.EncodeToString(registryKey.PublicKey().Bytes()),
+	}
+	auth, _ := createTestService(t, cfg)
+
+	service, err := NewSubscriptionService(auth, cfg, zap.NewNop())
+
+	assert.NoError(t, err)
+	assert.Equal(t, ourKey.Bytes(), service.encryptionKey.Bytes())
+}
+
+func TestNewSubscriptionService_InvalidRegistryKey(t *testing.T) {
+	auth, _ := createTestService(t, config.ONDCConfig{})
+
+	_, err := NewSubscriptionService(auth, config.ONDCConfig{RegistryEncKey: "bm90LWEta2V5"}, zap.NewNop())
+
+	assert.Error(t, err)
+}
+
+func TestSubscriptionService_SiteVerificationHTML(t *testing.T) {
+	fixture := newSubscriptionFixture(t, config.ONDCConfig{SubscribeReqID: "req-123"})
+
+	html, err := fixture.service.SiteVerificationHTML()
+	require.NoError(t, err)
+
+	matches := regexp.MustCompile(`<meta name='ondc-site-verification' content='([^']+)' />`).FindStringSubmatch(html)
+	require.Len(t, matches, 2)
+	signature, err := base64.StdEncoding.DecodeString(matches[1])
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(testSigningPublicKey(t, fixture.auth), []byte("req-123"), signature))
+}
+
+func TestSubscriptionService_SiteVerificationHTML_SubscribedKey(t *testing.T) {
+	dir := t.TempDir()
+	writeTestKeyFile(t, dir, "UK1", time.Time{}, time.Time{}, true)
+	staged := writeTestKeyFile(t, dir, "UK2", time.Now().Add(24*time.Hour).Truncate(time.Second), time.Time{}, true)
+
+	fixture := newSubscriptionFixture(t, config.ONDCConfig{SubscribeReqID: "req-123", SubscribeUkID: "UK2", KeyringDir: dir})
+
+	html, err := fixture.service.SiteVerificationHTML()
+	require.NoError(t, err)
+
+	matches := regexp.MustCompile(`content='([^']+)'`).FindStringSubmatch(html)
+	require.Len(t, matches, 2)
+	signature, err := base64.StdEncoding.DecodeString(matches[1])
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(staged, []byte("req-123"), signature))
+
+	fixture = newSubscriptionFixture(t, config.ONDCConfig{SubscribeReqID: "req-123", SubscribeUkID: "UK9", KeyringDir: dir})
+	_, err = fixture.service.SiteVerificationHTML()
+	domainErr, _ := err.(*errors.DomainError)
+	require.NotNil(t, domainErr)
+	assert.Equal(t, 65006, domainErr.Code)
+}
+
+func TestSubscriptionService_SiteVerificationHTML_NoRequestID(t *testing.T) {
+	fixture := newSubscriptionFixture(t, config.ONDCConfig{})
+
+	_, err := fixture.service.SiteVerificationHTML()
+
+	assert.Error(t, err)
+	domainErr, _ := err.(*errors.DomainError)
+	assert.Equal(t, 65006, domainErr.Code)
+}
+
+func TestSubscriptionService_BuildSubscribeRequest(t *testing.T) {
+	fixture := newSubscriptionFixture(t, config.ONDCConfig{
+		SubscriberID:    "lsp.example.com",
+		SubscriberURL:   "https://lsp.example.com",
+		UkID:            "UK1",
+		Domain:          "nic2004:60232",
+		Country:         "IND",
+		CityCode:        "std:080",
+		SubscribePrefix: "/registry",
+	})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	entity := SubscribeEntity{
+		GST:                       json.RawMessage(`{"legal_entity_name":"LSP Pvt Ltd"}`),
+		NameOfAuthorisedSignatory: "Ops Lead",
+		EmailID:                   "ops@lsp.example.com",
+		MobileNo:                  "9999999999",
+		SubscriberID:              "ignored.example.com",
+	}
+
+	request, err := fixture.service.BuildSubscribeRequest(entity, "req-123", "", SubscribeOpsSellerNew, now, 365*24*time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, request.Context.Operation.OpsNo)
+	assert.Equal(t, "req-123", request.Message.RequestID)
+	assert.Equal(t, "2025-01-01T00:00:00.000Z", request.Message.Timestamp)
+	assert.Equal(t, "lsp.example.com", request.Message.Entity.SubscriberID)
+	assert.Equal(t, "UK1", request.Message.Entity.UniqueKeyID)
+	assert.Equal(t, "IND", request.Message.Entity.Country)
+	assert.Equal(t, "/registry", request.Message.Entity.CallbackURL)
+	assert.Equal(t, fixture.auth.SigningPublicKey(), request.Message.Entity.KeyPair.SigningPublicKey)
+	assert.Equal(t, "2026-01-01T00:00:00.000Z", request.Message.Entity.KeyPair.ValidUntil)
+
+	encryptionKey, err := parseX25519PublicKey(request.Message.Entity.KeyPair.EncryptionPublicKey)
+	require.NoError(t, err)
+	assert.True(t, encryptionKey.Equal(fixture.ourKey.PublicKey()))
+
+	require.Len(t, request.Message.NetworkParticipant, 1)
+	assert.Equal(t, NetworkParticipant{
+		SubscriberURL: "https://lsp.example.com",
+		Domain:        "nic2004:60232",
+		Type:          "sellerApp",
+		CityCode:      []string{"std:080"},
+	}, request.Message.NetworkParticipant[0])
+}
+
+func TestSubscribeCallbackURL(t *testing.T) {
+	assert.Equal(t, "/", SubscribeCallbackURL(""))
+	assert.Equal(t, "/on_subscribe", OnSubscribePath(""))
+	assert.Equal(t, "/registry", SubscribeCallbackURL("registry/"))
+	assert.Equal(t, "/registry/on_subscribe", OnSubscribePath("/registry"))
+}
+
+func TestSubscriptionService_SignSubscribeRequest(t *testing.T) {
+	fixture := newSubscriptionFixture(t, config.ONDCConfig{SubscriberID: "lsp.example.com", UkID: "UK1", TimestampWindow: 300})
+	request, err := fixture.service.BuildSubscribeRequest(SubscribeEntity{}, "req-123", "", SubscribeOpsSellerNew, time.Now(), time.Hour)
+	require.NoError(t, err)
+
+	payload, authHeader, err := fixture.service.SignSubscribeRequest(request)
+	require.NoError(t, err)
+
+	params, err := ParseSignatureHeader(authHeader)
+	require.NoError(t, err)
+	signature, _ := base64.StdEncoding.DecodeString(params.Signature)
+	signingString := BuildSigningString(params.Created, params.Expires, CalculateDigest(payload))
+	assert.True(t, ed25519.Verify(testSigningPublicKey(t, fixture.auth), []byte(signingString), signature))
+}
+
+func TestSubscriptionService_BuildSubscribeRequest_StagedKeyringKey(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeTestKeyFile(t, dir, "UK1", time.Time{}, time.Time{}, true)
+	staged := writeTestKeyFile(t, dir, "UK2", time.Now().Add(24*time.Hour).Truncate(time.Second), time.Time{}, true)
+
+	fixture := newSubscriptionFixture(t, config.ONDCConfig{SubscriberID: "lsp.example.com", KeyringDir: dir})
+
+	request, err := fixture.service.BuildSubscribeRequest(SubscribeEntity{}, "req-123", "UK2", SubscribeOpsSellerNew, now, time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, "UK2", request.Message.Entity.UniqueKeyID)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(staged), request.Message.Entity.KeyPair.SigningPublicKey)
+	assert.NotEqual(t, now.Format(subscribeTimestampFormat), request.Message.Entity.KeyPair.ValidFrom)
+	assert.Equal(t, "2025-01-01T01:00:00.000Z", request.Message.Entity.KeyPair.ValidUntil)
+
+	_, err = fixture.service.BuildSubscribeRequest(SubscribeEntity{}, "req-123", "UK9", SubscribeOpsSellerNew, now, time.Hour)
+	assert.Error(t, err)
+	domainErr, _ := err.(*errors.DomainError)
+	assert.Equal(t, 65006, domainErr.Code)
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"uois-gateway/internal/models"
+	"uois-gateway/pkg/errors"
+)
+
+// otpTokenPattern is the accepted OTP format: 4 to 8 digits
+var otpTokenPattern = regexp.MustCompile(`^[0-9]{4,8}$`)
+
+// ExtractFulfillmentAuthorizations validates the PCC (start.authorization) and DCC (end.authorization)
+// of /update fulfillments and returns them, at most one per stage
+// Only OTP codes are supported; valid_from and valid_to are required and the window must not have ended at now
+func ExtractFulfillmentAuthorizations(fulfillments []interface{}, now time.Time) ([]models.FulfillmentAuthorization, *errors.DomainError) {
+	var authorizations []models.FulfillmentAuthorization
+	seen := make(map[string]bool)
+
+	for _, f := range fulfillments {
+		fulfillment, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, stop := range []struct{ key, stage string }{
+			{"start", models.AuthorizationStagePickup},
+			{"end", models.AuthorizationStageDelivery},
+		} {
+			location, _ := fulfillment[stop.key].(map[string]interface{})
+			raw, present := location["authorization"]
+			if !present {
+				continue
+			}
+			if seen[stop.stage] {
+				return nil, errors.NewDomainError(65001, "invalid request", fmt.Sprintf("duplicate %s.authorization", stop.key))
+			}
+			seen[stop.stage] = true
+
+			authorization, err := parseAuthorization(raw, stop.key, stop.stage, now)
+			if err != nil {
+				return nil, err
+			}
+			authorizations = append(authorizations, *authorization)
+		}
+	}
+
+	return authorizations, nil
+}
+
+func parseAuthorization(raw interface{}, key, stage string, now time.Time) (*models.FulfillmentAuthorization, *errors.DomainError) {
+	field := key + ".authorization"
+	authorization, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, errors.NewDomainError(65001, "invalid request", field+" must be an object")
+	}
+
+	authType, _ := authorization["type"].(string)
+	if authType != models.AuthorizationTypeOTP {
+		return nil, errors.NewDomainError(65001, "invalid request", fmt.Sprintf("unsupported %s.type: %q. Supported types: OTP", field, authType))
+	}
+
+	token, _ := authorization["token"].(string)
+	if !otpTokenPattern.MatchString(token) {
+		return nil, errors.NewDomainError(65001, "invalid request", field+".token must be a 4-8 digit OTP")
+	}
+
+	validFrom, err := parseAuthorizationTime(authorization, field, "valid_from")
+	if err != nil {
+		return nil, err
+	}
+	validTo, err := parseAuthorizationTime(authorization, field, "valid_to")
+	if err != nil {
+		return nil, err
+	}
+	if !validTo.After(validFrom) {
+		return nil, errors.NewDomainError(65001, "invalid request", field+".valid_to must be after valid_from")
+	}
+	if !validTo.After(now) {
+		return nil, errors.NewDomainError(65001, "invalid request", field+" has expired")
+	}
+
+	return &models.FulfillmentAuthorization{
+		Stage:     stage,
+		Type:      authType,
+		Token:     token,
+		ValidFrom: validFrom,
+		ValidTo:   validTo,
+	}, nil
+}
+
+func parseAuthorizationTime(authorization map[string]interface{}, field, key string) (time.Time, *errors.DomainError) {
+	value, _ := authorization[key].(string)
+	if value == "" {
+		return time.Time{}, errors.NewDomainError(65001, "invalid request", fmt.Sprintf("%s.%s is required", field, key))
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.NewDomainError(65001, "invalid request", fmt.Sprintf("invalid %s.%s: %s", field, key, value))
+	}
+	return parsed.UTC(), nil
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"uois-gateway/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractFulfillmentAuthorizations(t *testing.T) {
+	now := time.Date(2023, 6, 6, 12, 30, 0, 0, time.UTC)
+	otp := func(token, from, to string) map[string]interface{} {
+		return map[string]interface{}{"type": "OTP", "token": token, "valid_from": from, "valid_to": to}
+	}
+
+	fulfillments := []interface{}{
+		map[string]interface{}{
+			"id":    "F1",
+			"start": map[string]interface{}{"authorization": otp("1234", "2023-06-06T12:00:00.000Z", "2023-06-06T14:00:00.000Z")},
+			"end":   map[string]interface{}{"authorization": otp("567890", "2023-06-06T12:00:00.000Z", "2023-06-06T18:00:00.000Z")},
+		},
+	}
+
+	authorizations, err := ExtractFulfillmentAuthorizations(fulfillments, now)
+
+	require.Nil(t, err)
+	require.Len(t, authorizations, 2)
+	assert.Equal(t, models.FulfillmentAuthorization{
+		Stage:     models.AuthorizationStagePickup,
+		Type:      models.AuthorizationTypeOTP,
+		Token:     "1234",
+		ValidFrom: time.Date(2023, 6, 6, 12, 0, 0, 0, time.UTC),
+		ValidTo:   time.Date(2023, 6, 6, 14, 0, 0, 0, time.UTC),
+	}, authorizations[0])
+	assert.Equal(t, models.AuthorizationStageDelivery, authorizations[1].Stage)
+	assert.Equal(t, "567890", authorizations[1].Token)
+
+	none, err := ExtractFulfillmentAuthorizations([]interface{}{map[string]interface{}{"id": "F1"}}, now)
+	assert.Nil(t, err)
+	assert.Empty(t, none)
+}
+
+func TestExtractFulfillmentAuthorizations_Invalid(t *testing.T) {
+	now := time.Date(2023, 6, 6, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		authorization interface{}
+	}{
+		{name: "not an object", authorization: "1234"},
+		{name: "unsupported type", authorization: map[string]interface{}{"type": "QR", "token": "1234", "valid_from": "2023-06-06T12:00:00Z", "valid_to": "2023-06-06T14:00:00Z"}},
+		{name: "non numeric token", authorization: map[string]interface{}{"type": "OTP", "token": "12ab", "valid_from": "2023-06-06T12:00:00Z", "valid_to": "2023-06-06T14:00:00Z"}},
+		{name: "short token", authorization: map[string]interface{}{"type": "OTP", "token": "123", "valid_from": "2023-06-06T12:00:00Z", "valid_to": "2023-06-06T14:00:00Z"}},
+		{name: "missing valid_from", authorization: map[string]interface{}{"type": "OTP", "token": "1234", "valid_to": "2023-06-06T14:00:00Z"}},
+		{name: "malformed valid_to", authorization: map[string]interface{}{"type": "OTP", "token": "1234", "valid_from": "2023-06-06T12:00:00Z", "valid_to": "06/06/2023"}},
+		{name: "inverted window", authorization: map[string]interface{}{"type": "OTP", "token": "1234", "valid_from": "2023-06-06T14:00:00Z", "valid_to": "2023-06-06T12:00:00Z"}},
+		{name: "expired", authorization: map[string]interface{}{"type": "OTP", "token": "1234", "valid_from": "2023-06-06T10:00:00Z", "valid_to": "2023-06-06T12:00:00Z"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fulfillments := []interface{}{
+				map[string]interface{}{"end": map[string]interface{}{"authorization": tt.authorization}},
+			}
+			_, err := ExtractFulfillmentAuthorizations(fulfillments, now)
+			require.NotNil(t, err)
+			assert.Equal(t, 65001, err.Code)
+		})
+	}
+}
+
+func TestExtractFulfillmentAuthorizations_DuplicateStage(t *testing.T) {
+	now := time.Date(2023, 6, 6, 12, 30, 0, 0, time.UTC)
+	start := map[string]interface{}{"authorization": map[string]interface{}{"type": "OTP", "token": "1234", "valid_from": "2023-06-06T12:00:00Z", "valid_to": "2023-06-06T14:00:00Z"}}
+
+	_, err := ExtractFulfillmentAuthorizations([]interface{}{
+		map[string]interface{}{"start": start},
+		map[string]interface{}{"start": start},
+	}, now)
+
+	require.NotNil(t, err)
+	assert.Equal(t, 65001, err.Code)
+}
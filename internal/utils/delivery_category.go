@@ -0,0 +1,83 @@
+package utils
+
+// Delivery category and TAT extraction for the delivery policy (internal/services/deliverypolicy)
+
+// ExtractCategoryID extracts category ID from intent map
+func ExtractCategoryID(intent map[string]interface{}) string {
+	if intent == nil {
+		return ""
+	}
+	category, ok := intent["category"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	categoryID, ok := category["id"].(string)
+	if !ok {
+		return ""
+	}
+	return categoryID
+}
+
+// ExtractTimeDuration extracts time.duration from intent map
+func ExtractTimeDuration(intent map[string]interface{}) string {
+	if intent == nil {
+		return ""
+	}
+	provider, ok := intent["provider"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	timeInfo, ok := provider["time"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	duration, ok := timeInfo["duration"].(string)
+	if !ok {
+		return ""
+	}
+	return duration
+}
+
+// ExtractCategoryIDFromOrder extracts category ID from order items
+func ExtractCategoryIDFromOrder(order map[string]interface{}) string {
+	if order == nil {
+		return ""
+	}
+	items, ok := order["items"].([]interface{})
+	if !ok || len(items) == 0 {
+		return ""
+	}
+	item, ok := items[0].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	categoryID, ok := item["category_id"].(string)
+	if !ok {
+		return ""
+	}
+	return categoryID
+}
+
+// ExtractTimeDurationFromOrder extracts time.duration from order items
+func ExtractTimeDurationFromOrder(order map[string]interface{}) string {
+	if order == nil {
+		return ""
+	}
+	items, ok := order["items"].([]interface{})
+	if !ok || len(items) == 0 {
+		return ""
+	}
+	item, ok := items[0].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	timeInfo, ok := item["time"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	duration, ok := timeInfo["duration"].(string)
+	if !ok {
+		return ""
+	}
+	return duration
+}
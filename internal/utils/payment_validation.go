@@ -2,14 +2,18 @@ package utils
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 
+	"uois-gateway/internal/models"
 	"uois-gateway/pkg/errors"
 )
 
 // ValidatePaymentType validates payment type according to Dispatch business rules
-// Dispatch does NOT support COD (ON-FULFILLMENT) payment type
+// COD (ON-FULFILLMENT) is accepted only when codAllowed, i.e. both the client and the
+// delivery category have opted in to cash on delivery
 // Returns DomainError if payment type is invalid or not supported
-func ValidatePaymentType(paymentInfo map[string]interface{}) *errors.DomainError {
+func ValidatePaymentType(paymentInfo map[string]interface{}, codAllowed bool) *errors.DomainError {
 	if paymentInfo == nil || len(paymentInfo) == 0 {
 		return nil // Payment is optional, no validation needed if not present
 	}
@@ -19,28 +23,57 @@ func ValidatePaymentType(paymentInfo map[string]interface{}) *errors.DomainError
 		return nil // Payment type not provided, optional field
 	}
 
-	// Reject COD (ON-FULFILLMENT) - Dispatch does not support COD
-	if paymentType == "ON-FULFILLMENT" {
-		return errors.NewDomainError(
-			65004,
-			"not serviceable",
-			"Payment type ON-FULFILLMENT (COD) is not supported. Supported payment types: ON-ORDER, POST-FULFILLMENT",
-		)
+	// COD (ON-FULFILLMENT) is opt-in per client and delivery category
+	if paymentType == models.PaymentTypeOnFulfillment {
+		if !codAllowed {
+			return errors.NewDomainError(
+				65004,
+				"not serviceable",
+				"Payment type ON-FULFILLMENT (COD) is not enabled for this client or delivery category. Supported payment types: ON-ORDER, POST-FULFILLMENT",
+			)
+		}
+		return nil
 	}
 
-	// Validate enum values - only ON-ORDER and POST-FULFILLMENT are supported
+	// Validate enum values - ON-ORDER and POST-FULFILLMENT are always supported
 	validTypes := map[string]bool{
-		"ON-ORDER":         true,
-		"POST-FULFILLMENT": true,
+		models.PaymentTypeOnOrder:         true,
+		models.PaymentTypePostFulfillment: true,
 	}
 
 	if !validTypes[paymentType] {
 		return errors.NewDomainError(
 			65001,
 			"invalid request",
-			fmt.Sprintf("Invalid payment type: %s. Supported types: ON-ORDER, POST-FULFILLMENT", paymentType),
+			fmt.Sprintf("Invalid payment type: %s. Supported types: ON-ORDER, ON-FULFILLMENT, POST-FULFILLMENT", paymentType),
 		)
 	}
 
 	return nil // Valid payment type
 }
+
+// IsCODPayment reports whether the payment is cash on delivery (ON-FULFILLMENT)
+func IsCODPayment(paymentInfo map[string]interface{}) bool {
+	paymentType, _ := paymentInfo["type"].(string)
+	return paymentType == models.PaymentTypeOnFulfillment
+}
+
+// ExtractCollectionAmount returns the @ondc/org/collection_amount of a COD payment
+// Required for ON-FULFILLMENT and must be a positive decimal; returns "" for other payment types
+func ExtractCollectionAmount(paymentInfo map[string]interface{}) (string, *errors.DomainError) {
+	if !IsCODPayment(paymentInfo) {
+		return "", nil
+	}
+
+	amount, _ := paymentInfo["@ondc/org/collection_amount"].(string)
+	if amount == "" {
+		return "", errors.NewDomainError(65001, "invalid request", "payment.@ondc/org/collection_amount is required for ON-FULFILLMENT (COD)")
+	}
+
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value <= 0 {
+		return "", errors.NewDomainError(65001, "invalid request", fmt.Sprintf("invalid payment.@ondc/org/collection_amount: %s", amount))
+	}
+
+	return amount, nil
+}
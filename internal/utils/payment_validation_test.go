@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePaymentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		paymentInfo map[string]interface{}
+		codAllowed  bool
+		wantCode    int
+	}{
+		{name: "no payment", paymentInfo: nil},
+		{name: "on order", paymentInfo: map[string]interface{}{"type": "ON-ORDER"}},
+		{name: "post fulfillment", paymentInfo: map[string]interface{}{"type": "POST-FULFILLMENT"}},
+		{name: "cod not enabled", paymentInfo: map[string]interface{}{"type": "ON-FULFILLMENT"}, wantCode: 65004},
+		{name: "cod enabled", paymentInfo: map[string]interface{}{"type": "ON-FULFILLMENT"}, codAllowed: true},
+		{name: "unknown type", paymentInfo: map[string]interface{}{"type": "PREPAID"}, codAllowed: true, wantCode: 65001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePaymentType(tt.paymentInfo, tt.codAllowed)
+			if tt.wantCode == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tt.wantCode, err.Code)
+		})
+	}
+}
+
+func TestExtractCollectionAmount(t *testing.T) {
+	amount, err := ExtractCollectionAmount(map[string]interface{}{"type": "ON-FULFILLMENT", "@ondc/org/collection_amount": "300.00"})
+	assert.Nil(t, err)
+	assert.Equal(t, "300.00", amount)
+
+	amount, err = ExtractCollectionAmount(map[string]interface{}{"type": "ON-ORDER", "@ondc/org/collection_amount": "300.00"})
+	assert.Nil(t, err)
+	assert.Empty(t, amount)
+
+	for _, invalid := range []interface{}{nil, "", "abc", "0", "-10", "NaN", 300} {
+		_, err := ExtractCollectionAmount(map[string]interface{}{"type": "ON-FULFILLMENT", "@ondc/org/collection_amount": invalid})
+		require.NotNil(t, err, "collection_amount %v", invalid)
+		assert.Equal(t, 65001, err.Code)
+	}
+}
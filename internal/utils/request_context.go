@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// DefaultRequestTTL applies when an ONDC request carries no usable context.ttl (ONDC default PT30S)
+const DefaultRequestTTL = 30 * time.Second
+
+type requestKeyContextKey struct{}
+
+// WithRequestKey attaches the request's idempotency key to ctx
+// Downstream clients forward it so a retried command is applied at most once
+func WithRequestKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestKeyContextKey{}, key)
+}
+
+// RequestKeyFromContext returns the idempotency key attached by WithRequestKey, or ""
+func RequestKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(requestKeyContextKey{}).(string)
+	return key
+}
+
+// WithONDCDeadline bounds ctx by the request's remaining TTL (context.timestamp + context.ttl)
+// A Buyer NP stops waiting once the TTL elapses, so downstream work past it is wasted.
+// The window starts at now when the timestamp is missing or in the future.
+func WithONDCDeadline(ctx context.Context, timestamp time.Time, ttl string) (context.Context, context.CancelFunc) {
+	return context.WithDeadline(ctx, ONDCDeadline(timestamp, ttl, time.Now()))
+}
+
+// ONDCDeadline is when a request with the given context.timestamp and context.ttl expires
+func ONDCDeadline(timestamp time.Time, ttl string, now time.Time) time.Time {
+	duration, ok := ParseISO8601Duration(ttl)
+	if !ok {
+		duration = DefaultRequestTTL
+	}
+	if timestamp.IsZero() || timestamp.After(now) {
+		timestamp = now
+	}
+	return timestamp.Add(duration)
+}
+
+// ParseISO8601Duration parses an ONDC ISO 8601 time duration (PT30S, PT15M, PT1H30M)
+func ParseISO8601Duration(value string) (time.Duration, bool) {
+	if !strings.HasPrefix(value, "PT") || len(value) == len("PT") {
+		return 0, false
+	}
+
+	durationStr := strings.TrimPrefix(value, "PT")
+	durationStr = strings.ReplaceAll(durationStr, "H", "h")
+	durationStr = strings.ReplaceAll(durationStr, "M", "m")
+	durationStr = strings.ReplaceAll(durationStr, "S", "s")
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil || duration <= 0 {
+		return 0, false
+	}
+	return duration, true
+}
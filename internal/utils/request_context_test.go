@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestKey(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, RequestKeyFromContext(ctx))
+	assert.Equal(t, ctx, WithRequestKey(ctx, ""))
+
+	ctx = WithRequestKey(ctx, "ondc:cancel:txn-1:msg-1")
+	assert.Equal(t, "ondc:cancel:txn-1:msg-1", RequestKeyFromContext(ctx))
+}
+
+func TestONDCDeadline(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		ttl       string
+		want      time.Time
+	}{
+		{"ttl from timestamp", now.Add(-10 * time.Second), "PT30S", now.Add(20 * time.Second)},
+		{"minutes", now, "PT15M", now.Add(15 * time.Minute)},
+		{"missing ttl uses default", now, "", now.Add(DefaultRequestTTL)},
+		{"invalid ttl uses default", now, "30 seconds", now.Add(DefaultRequestTTL)},
+		{"future timestamp starts now", now.Add(time.Hour), "PT30S", now.Add(30 * time.Second)},
+		{"missing timestamp starts now", time.Time{}, "PT1M", now.Add(time.Minute)},
+		{"elapsed ttl", now.Add(-time.Minute), "PT30S", now.Add(-30 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ONDCDeadline(tt.timestamp, tt.ttl, now))
+		})
+	}
+}
+
+func TestWithONDCDeadline(t *testing.T) {
+	ctx, cancel := WithONDCDeadline(context.Background(), time.Now(), "PT5S")
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(5*time.Second), deadline, time.Second)
+}
+
+func TestParseISO8601Duration(t *testing.T) {
+	d, ok := ParseISO8601Duration("PT1H30M")
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Minute, d)
+
+	_, ok = ParseISO8601Duration("PT")
+	assert.False(t, ok)
+	_, ok = ParseISO8601Duration("P1D")
+	assert.False(t, ok)
+}
@@ -0,0 +1,8 @@
+-- Allow several delivery log rows per request (one per attempt, plus DLQ redrives)
+-- request_id was the primary key, so only the first attempt of a callback could be recorded
+ALTER TABLE audit.callback_delivery_logs DROP CONSTRAINT IF EXISTS callback_delivery_logs_pkey;
+ALTER TABLE audit.callback_delivery_logs ADD COLUMN IF NOT EXISTS id BIGSERIAL PRIMARY KEY;
+
+-- Callback request IDs are not always UUIDs (e.g. "req-<nanos>" when the context has none)
+ALTER TABLE audit.callback_delivery_logs ALTER COLUMN request_id DROP DEFAULT;
+ALTER TABLE audit.callback_delivery_logs ALTER COLUMN request_id TYPE TEXT USING request_id::TEXT;
@@ -0,0 +1,26 @@
+-- Delivery category policy rules (DELIVERY_POLICY_SOURCE=db)
+-- A request is accepted when any enabled rule for its category matches; empty arrays match anything
+CREATE TABLE IF NOT EXISTS ondc_reference.delivery_category_rules (
+    id BIGSERIAL PRIMARY KEY,
+    category_id TEXT NOT NULL,
+    city_codes TEXT[] NOT NULL DEFAULT '{}',
+    client_ids TEXT[] NOT NULL DEFAULT '{}',
+    max_tat TEXT,
+    require_tat BOOLEAN NOT NULL DEFAULT FALSE,
+    windows JSONB NOT NULL DEFAULT '[]', -- [{"start":"08:00","end":"20:00"}], local time (DELIVERY_POLICY_TIMEZONE)
+    priority INT NOT NULL DEFAULT 0,     -- Lower first; on_search offers the first matching category
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_delivery_category_rules_enabled ON ondc_reference.delivery_category_rules(enabled, priority);
+
+-- Seed the built-in rules so switching to the db source keeps current behavior
+INSERT INTO ondc_reference.delivery_category_rules (category_id, max_tat, require_tat, priority)
+SELECT 'Immediate Delivery', 'PT60M', FALSE, 10
+WHERE NOT EXISTS (SELECT 1 FROM ondc_reference.delivery_category_rules WHERE category_id = 'Immediate Delivery');
+
+INSERT INTO ondc_reference.delivery_category_rules (category_id, max_tat, require_tat, priority)
+SELECT 'Standard Delivery', 'PT60M', TRUE, 20
+WHERE NOT EXISTS (SELECT 1 FROM ondc_reference.delivery_category_rules WHERE category_id = 'Standard Delivery');
@@ -0,0 +1,26 @@
+-- Cash on delivery (ON-FULFILLMENT) support
+-- COD is opt-in per delivery category (here) and per client (client_registry metadata cod_enabled)
+ALTER TABLE ondc_reference.delivery_category_rules
+    ADD COLUMN IF NOT EXISTS cod_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Operational state written by the gateway while orders run (ondc_reference only holds reference data)
+CREATE SCHEMA IF NOT EXISTS operations;
+
+-- One reconciliation record per COD order: cash collected by the LSP until remitted to the buyer app
+CREATE TABLE IF NOT EXISTS operations.cod_reconciliation (
+    id BIGSERIAL PRIMARY KEY,
+    order_id VARCHAR(255) NOT NULL,          -- ONDC order.id
+    client_id VARCHAR(255) NOT NULL,
+    dispatch_order_id VARCHAR(255),
+    transaction_id VARCHAR(255),
+    collection_amount NUMERIC(12, 2) NOT NULL, -- @ondc/org/collection_amount agreed in /init
+    currency VARCHAR(3) NOT NULL DEFAULT 'INR',
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'COLLECTED', 'REMITTED')),
+    settlement_reference VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (client_id, order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cod_reconciliation_status ON operations.cod_reconciliation(status);
+CREATE INDEX IF NOT EXISTS idx_cod_reconciliation_dispatch_order_id ON operations.cod_reconciliation(dispatch_order_id);
@@ -64,7 +64,7 @@ func GetHTTPStatus(err error) int {
 	}
 
 	switch domainErr.Code {
-	case 65001, 65003, 65004, 65005, 65007:
+	case 65001, 65003, 65004, 65005, 65007, 60009:
 		return 400
 	case 65002:
 		return 401
@@ -72,6 +72,7 @@ func TestGetHTTPStatus(t *testing.T) {
 		{"Quote Invalid", 65005, 400},
 		{"Order Not Found", 65006, 404},
 		{"Invalid State", 65007, 400},
+		{"Invalid Cancellation Reason", 60009, 400},
 		{"Dependency Timeout", 65010, 503},
 		{"Dependency Unavailable", 65011, 503},
 		{"Rate Limit", 65012, 429},